@@ -82,6 +82,19 @@ type Provider interface {
 	Validate() (problems []string)
 }
 
+// UserPermsProvider is implemented by authz providers that are the source of truth of repository
+// permissions for Sourcegraph users themselves (instead of for their accounts on an external
+// service), such as permissions that site admins explicitly grant on Sourcegraph. Callers must use
+// UserRepoPerms instead of FetchAccount and RepoPerms for such authz providers.
+type UserPermsProvider interface {
+	Provider
+
+	// UserRepoPerms is like RepoPerms, except that it accepts the Sourcegraph user instead of an
+	// external account of the user. The user parameter may be nil, in which case the set of
+	// permissions for an unauthenticated user is returned.
+	UserRepoPerms(ctx context.Context, user *types.User, repos map[Repo]struct{}) (map[api.RepoName]map[Perm]bool, error)
+}
+
 type Repo struct {
 	// RepoName is the unique name of the repo on Sourcegraph.
 	RepoName api.RepoName
@@ -1,13 +1,94 @@
 package authz
 
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
 const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	// Fine-grained access token scopes. Each grants access to a part of the GraphQL API (on behalf
+	// of the user account). The "user:all" scope implies all of them.
+	ScopeSearchRead       = "search:read"       // Ability to perform searches and read saved searches.
+	ScopeRepoRead         = "repo:read"         // Ability to read repositories and their contents.
+	ScopeSettingsWrite    = "settings:write"    // Ability to read and update settings.
+	ScopeDiscussionsWrite = "discussions:write" // Ability to read and create discussion threads and comments.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeSettingsWrite,
+	ScopeDiscussionsWrite,
+}
+
+// APIScopes is a list of the fine-grained access token scopes.
+var APIScopes = []string{
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeSettingsWrite,
+	ScopeDiscussionsWrite,
+}
+
+// IsAPIScope reports whether scope is one of the fine-grained access token scopes.
+func IsAPIScope(scope string) bool {
+	for _, s := range APIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessTokenRestrictions describes what the access token that authenticated a request is
+// permitted to do.
+type AccessTokenRestrictions struct {
+	Scopes        []string       // the access token's scopes
+	RepoAllowlist []api.RepoName // if non-empty, the only repositories that the access token may access
+}
+
+// HasScope reports whether the access token has the scope, either directly or because it has the
+// "user:all" scope (which implies all fine-grained scopes).
+func (r *AccessTokenRestrictions) HasScope(scope string) bool {
+	for _, s := range r.Scopes {
+		if s == scope || (s == ScopeUserAll && IsAPIScope(scope)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRepo reports whether the access token may access the repository.
+func (r *AccessTokenRestrictions) AllowsRepo(repo api.RepoName) bool {
+	if len(r.RepoAllowlist) == 0 {
+		return true
+	}
+	for _, allowed := range r.RepoAllowlist {
+		if allowed == repo {
+			return true
+		}
+	}
+	return false
+}
+
+type accessTokenRestrictionsKey struct{}
+
+// WithAccessTokenRestrictions returns a copy of the context with the restrictions of the access
+// token that authenticated the request.
+func WithAccessTokenRestrictions(ctx context.Context, r *AccessTokenRestrictions) context.Context {
+	return context.WithValue(ctx, accessTokenRestrictionsKey{}, r)
+}
+
+// AccessTokenRestrictionsFromContext returns the restrictions of the access token that
+// authenticated the request, or nil if the request was not authenticated with an access token.
+func AccessTokenRestrictionsFromContext(ctx context.Context) *AccessTokenRestrictions {
+	r, _ := ctx.Value(accessTokenRestrictionsKey{}).(*AccessTokenRestrictions)
+	return r
 }
@@ -0,0 +1,56 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestAccessTokenRestrictions(t *testing.T) {
+	t.Run("HasScope", func(t *testing.T) {
+		tests := []struct {
+			scopes []string
+			scope  string
+			want   bool
+		}{
+			{scopes: []string{ScopeUserAll}, scope: ScopeUserAll, want: true},
+			{scopes: []string{ScopeUserAll}, scope: ScopeSearchRead, want: true},
+			{scopes: []string{ScopeUserAll}, scope: ScopeSiteAdminSudo, want: false},
+			{scopes: []string{ScopeSearchRead}, scope: ScopeSearchRead, want: true},
+			{scopes: []string{ScopeSearchRead}, scope: ScopeRepoRead, want: false},
+			{scopes: []string{ScopeSearchRead}, scope: ScopeUserAll, want: false},
+			{scopes: nil, scope: ScopeSearchRead, want: false},
+		}
+		for _, test := range tests {
+			r := &AccessTokenRestrictions{Scopes: test.scopes}
+			if got := r.HasScope(test.scope); got != test.want {
+				t.Errorf("scopes %q: HasScope(%q) = %v, want %v", test.scopes, test.scope, got, test.want)
+			}
+		}
+	})
+
+	t.Run("AllowsRepo", func(t *testing.T) {
+		r := &AccessTokenRestrictions{}
+		if !r.AllowsRepo("a") {
+			t.Error("want all repos to be allowed with an empty allowlist")
+		}
+		r.RepoAllowlist = []api.RepoName{"a"}
+		if !r.AllowsRepo("a") {
+			t.Error("want allowlisted repo to be allowed")
+		}
+		if r.AllowsRepo("b") {
+			t.Error("want other repo to not be allowed")
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		if r := AccessTokenRestrictionsFromContext(context.Background()); r != nil {
+			t.Errorf("got %+v, want nil", r)
+		}
+		want := &AccessTokenRestrictions{Scopes: []string{ScopeRepoRead}}
+		if got := AccessTokenRestrictionsFromContext(WithAccessTokenRestrictions(context.Background(), want)); got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 )
 
@@ -24,6 +25,8 @@ type AccessToken struct {
 	CreatorUserID int32
 	CreatedAt     time.Time
 	LastUsedAt    *time.Time
+	ExpiresAt     *time.Time     // if set, the access token is invalid after this time
+	RepoAllowlist []api.RepoName // if non-empty, the only repositories that the access token may access
 }
 
 // ErrAccessTokenNotFound occurs when a database operation expects a specific access token to exist
@@ -46,11 +49,14 @@ type accessTokens struct{}
 // space; also bcrypt is slow and would add noticeable latency to each request that supplied a
 // token.
 //
+// If expiresAt is non-nil, the access token is invalid after that time. If repoAllowlist is
+// non-empty, the access token may only be used to access those repositories.
+//
 // 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
 // specified user (i.e., that the actor is either the user or a site admin).
-func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
+func (s *accessTokens) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time, repoAllowlist []api.RepoName) (id int64, token string, err error) {
 	if Mocks.AccessTokens.Create != nil {
-		return Mocks.AccessTokens.Create(subjectUserID, scopes, note, creatorUserID)
+		return Mocks.AccessTokens.Create(subjectUserID, scopes, note, creatorUserID, expiresAt, repoAllowlist)
 	}
 
 	var b [20]byte
@@ -76,39 +82,48 @@ creator_user AS (
   SELECT id FROM users WHERE id=$5 AND deleted_at IS NULL FOR UPDATE
 ),
 insert_values AS (
-  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id
+  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::timestamp with time zone AS expires_at, $7::text[] AS repo_allowlist
   FROM subject_user, creator_user
 )
-INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id) SELECT * FROM insert_values RETURNING id
+INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, expires_at, repo_allowlist) SELECT * FROM insert_values RETURNING id
 `,
-		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID,
+		subjectUserID, pq.Array(scopes), toSHA256Bytes(b[:]), note, creatorUserID, expiresAt, pq.Array(repoNamesToStrings(repoAllowlist)),
 	).Scan(&id); err != nil {
 		return 0, "", err
 	}
 	return id, token, nil
 }
 
-// Lookup looks up the access token. If it's valid and contains the required scope, it returns the
-// subject's user ID. Otherwise ErrAccessTokenNotFound is returned.
+// Lookup looks up the access token. If it's valid (i.e., not deleted or expired) and contains at
+// least one of the acceptable scopes, it returns the access token. Otherwise
+// ErrAccessTokenNotFound is returned.
 //
 // Calling Lookup also updates the access token's last-used-at date.
 //
-// 🚨 SECURITY: This returns a user ID if and only if the tokenHexEncoded corresponds to a valid,
-// non-deleted access token.
-func (s *accessTokens) Lookup(ctx context.Context, tokenHexEncoded string, requiredScope string) (subjectUserID int32, err error) {
+// 🚨 SECURITY: This returns an access token if and only if the tokenHexEncoded corresponds to a
+// valid, non-deleted, non-expired access token. The caller must enforce the returned access
+// token's scopes and repository allowlist.
+func (s *accessTokens) Lookup(ctx context.Context, tokenHexEncoded string, acceptableScopes ...string) (*AccessToken, error) {
 	if Mocks.AccessTokens.Lookup != nil {
-		return Mocks.AccessTokens.Lookup(tokenHexEncoded, requiredScope)
+		return Mocks.AccessTokens.Lookup(tokenHexEncoded, acceptableScopes)
 	}
 
-	if requiredScope == "" {
-		return 0, errors.New("no scope provided in access token lookup")
+	if len(acceptableScopes) == 0 {
+		return nil, errors.New("no scope provided in access token lookup")
+	}
+	for _, scope := range acceptableScopes {
+		if scope == "" {
+			return nil, errors.New("empty scope provided in access token lookup")
+		}
 	}
 
 	token, err := hex.DecodeString(tokenHexEncoded)
 	if err != nil {
-		return 0, errors.Wrap(err, "AccessTokens.Lookup")
+		return nil, errors.Wrap(err, "AccessTokens.Lookup")
 	}
 
+	var t AccessToken
+	var repoAllowlist []string
 	if err := dbconn.Global.QueryRowContext(ctx,
 		// Ensure that subject and creator users still exist.
 		`
@@ -116,19 +131,21 @@ UPDATE access_tokens t SET last_used_at=now()
 FROM access_tokens t2
 JOIN users subject_user ON t2.subject_user_id=subject_user.id
 JOIN users creator_user ON t2.creator_user_id=creator_user.id
-WHERE t.value_sha256=$1 AND t.deleted_at IS NULL AND
+WHERE t.id=t2.id AND t.value_sha256=$1 AND t.deleted_at IS NULL AND
+  (t.expires_at IS NULL OR t.expires_at > now()) AND
   subject_user.deleted_at IS NULL AND creator_user.deleted_at IS NULL AND
-  $2 = ANY (t.scopes)
-RETURNING t.subject_user_id
+  t.scopes && $2::text[]
+RETURNING t.id, t.subject_user_id, t.scopes, t.note, t.creator_user_id, t.created_at, t.last_used_at, t.expires_at, t.repo_allowlist
 `,
-		toSHA256Bytes(token), requiredScope,
-	).Scan(&subjectUserID); err != nil {
+		toSHA256Bytes(token), pq.Array(acceptableScopes),
+	).Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt, pq.Array(&repoAllowlist)); err != nil {
 		if err == sql.ErrNoRows {
-			return 0, ErrAccessTokenNotFound
+			return nil, ErrAccessTokenNotFound
 		}
-		return 0, err
+		return nil, err
 	}
-	return subjectUserID, nil
+	t.RepoAllowlist = stringsToRepoNames(repoAllowlist)
+	return &t, nil
 }
 
 // GetByID retrieves the access token (if any) given its ID.
@@ -181,7 +198,7 @@ func (s *accessTokens) List(ctx context.Context, opt AccessTokensListOptions) ([
 
 func (s *accessTokens) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*AccessToken, error) {
 	q := sqlf.Sprintf(`
-SELECT id, subject_user_id, scopes, note, creator_user_id, created_at, last_used_at FROM access_tokens
+SELECT id, subject_user_id, scopes, note, creator_user_id, created_at, last_used_at, expires_at, repo_allowlist FROM access_tokens
 WHERE (%s)
 ORDER BY now() - created_at < interval '5 minutes' DESC, -- show recently created tokens first
 last_used_at DESC NULLS FIRST, -- ensure newly created tokens show first
@@ -200,9 +217,11 @@ created_at DESC
 	var results []*AccessToken
 	for rows.Next() {
 		var t AccessToken
-		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.CreatedAt, &t.LastUsedAt); err != nil {
+		var repoAllowlist []string
+		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt, pq.Array(&repoAllowlist)); err != nil {
 			return nil, err
 		}
+		t.RepoAllowlist = stringsToRepoNames(repoAllowlist)
 		results = append(results, &t)
 	}
 	return results, nil
@@ -263,9 +282,31 @@ func toSHA256Bytes(input []byte) []byte {
 	return b[:]
 }
 
+func repoNamesToStrings(repoNames []api.RepoName) []string {
+	if repoNames == nil {
+		return nil
+	}
+	strs := make([]string, len(repoNames))
+	for i, name := range repoNames {
+		strs[i] = string(name)
+	}
+	return strs
+}
+
+func stringsToRepoNames(strs []string) []api.RepoName {
+	if len(strs) == 0 {
+		return nil
+	}
+	repoNames := make([]api.RepoName, len(strs))
+	for i, s := range strs {
+		repoNames[i] = api.RepoName(s)
+	}
+	return repoNames
+}
+
 type MockAccessTokens struct {
-	Create     func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
+	Create     func(subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time, repoAllowlist []api.RepoName) (id int64, token string, err error)
 	DeleteByID func(id int64, subjectUserID int32) error
-	Lookup     func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error)
+	Lookup     func(tokenHexEncoded string, acceptableScopes []string) (*AccessToken, error)
 	GetByID    func(id int64) (*AccessToken, error)
 }
@@ -3,7 +3,9 @@ package db
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
 )
 
@@ -32,7 +34,7 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -51,12 +53,12 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Errorf("got %q, want %q", got.Note, want)
 	}
 
-	gotSubjectUserID, err := AccessTokens.Lookup(ctx, tv0, "a")
+	gotToken, err := AccessTokens.Lookup(ctx, tv0, "a")
 	if err != nil {
 		t.Fatal(err)
 	}
-	if want := subject.ID; gotSubjectUserID != want {
-		t.Errorf("got %v, want %v", gotSubjectUserID, want)
+	if want := subject.ID; gotToken.SubjectUserID != want {
+		t.Errorf("got %v, want %v", gotToken.SubjectUserID, want)
 	}
 
 	ts, err := AccessTokens.List(ctx, AccessTokensListOptions{SubjectUserID: subject.ID})
@@ -106,11 +108,11 @@ func TestAccessTokens_List(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID)
+	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID)
+	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -184,26 +186,36 @@ func TestAccessTokens_Lookup(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	for _, scope := range []string{"a", "b"} {
-		gotSubjectUserID, err := AccessTokens.Lookup(ctx, tv0, scope)
+		gotToken, err := AccessTokens.Lookup(ctx, tv0, scope)
 		if err != nil {
 			t.Fatal(err)
 		}
-		if want := subject.ID; gotSubjectUserID != want {
-			t.Errorf("got %v, want %v", gotSubjectUserID, want)
+		if want := subject.ID; gotToken.SubjectUserID != want {
+			t.Errorf("got %v, want %v", gotToken.SubjectUserID, want)
 		}
 	}
 
+	// Lookup with any of multiple acceptable scopes.
+	if _, err := AccessTokens.Lookup(ctx, tv0, "x", "b"); err != nil {
+		t.Fatal(err)
+	}
+
 	// Lookup with a nonexistent scope and ensure it fails.
 	if _, err := AccessTokens.Lookup(ctx, tv0, "x"); err == nil {
 		t.Fatal(err)
 	}
 
+	// Lookup with no scope and ensure it fails.
+	if _, err := AccessTokens.Lookup(ctx, tv0); err == nil {
+		t.Fatal(err)
+	}
+
 	// Lookup with an empty scope and ensure it fails.
 	if _, err := AccessTokens.Lookup(ctx, tv0, ""); err == nil {
 		t.Fatal(err)
@@ -251,7 +263,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, nil, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -262,7 +274,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal("Lookup: want error looking up token for deleted subject user")
 		}
 
-		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID, nil, nil); err == nil {
 			t.Fatal("Create: want error creating token for deleted subject user")
 		}
 	})
@@ -287,7 +299,7 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, nil, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -298,8 +310,61 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal("Lookup: want error looking up token for deleted creator user")
 		}
 
-		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID, nil, nil); err == nil {
 			t.Fatal("Create: want error creating token for deleted creator user")
 		}
 	})
 }
+
+// 🚨 SECURITY: This tests that expired access tokens are invalid and that the repository allowlist
+// is returned by Lookup (so that callers can enforce it).
+func TestAccessTokens_Lookup_restrictions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	subject, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	_, expiredToken, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", subject.ID, &past, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AccessTokens.Lookup(ctx, expiredToken, "a"); err != ErrAccessTokenNotFound {
+		t.Errorf("got error %v, want %v", err, ErrAccessTokenNotFound)
+	}
+
+	future := time.Now().Add(time.Hour)
+	repoAllowlist := []api.RepoName{"github.com/foo/bar"}
+	_, tv, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n1", subject.ID, &future, repoAllowlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := AccessTokens.Lookup(ctx, tv, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(future.Round(time.Microsecond)) {
+		t.Errorf("got expiresAt %v, want %v", got.ExpiresAt, future)
+	}
+	if !reflect.DeepEqual(got.RepoAllowlist, repoAllowlist) {
+		t.Errorf("got repo allowlist %q, want %q", got.RepoAllowlist, repoAllowlist)
+	}
+
+	ts, err := AccessTokens.List(ctx, AccessTokensListOptions{SubjectUserID: subject.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 2; len(ts) != want {
+		t.Fatalf("got %d access tokens, want %d", len(ts), want)
+	}
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// discussionCommentReactions provides access to the
+// `discussion_comment_reactions` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type discussionCommentReactions struct{}
+
+// DiscussionReactionEmojis is the list of emoji that users may react to
+// discussion comments with.
+var DiscussionReactionEmojis = []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"}
+
+// Add adds the user's reaction with the given emoji to the comment. It is a
+// no-op if the user has already reacted with the emoji.
+func (*discussionCommentReactions) Add(ctx context.Context, commentID int64, userID int32, emoji string) error {
+	if Mocks.DiscussionCommentReactions.Add != nil {
+		return Mocks.DiscussionCommentReactions.Add(ctx, commentID, userID, emoji)
+	}
+	if !isDiscussionReactionEmoji(emoji) {
+		return fmt.Errorf("unsupported reaction %q", emoji)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `INSERT INTO discussion_comment_reactions(comment_id, user_id, emoji) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, commentID, userID, emoji)
+	return err
+}
+
+// Remove removes the user's reaction with the given emoji from the comment. It
+// is a no-op if the user has not reacted with the emoji.
+func (*discussionCommentReactions) Remove(ctx context.Context, commentID int64, userID int32, emoji string) error {
+	if Mocks.DiscussionCommentReactions.Remove != nil {
+		return Mocks.DiscussionCommentReactions.Remove(ctx, commentID, userID, emoji)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `DELETE FROM discussion_comment_reactions WHERE comment_id=$1 AND user_id=$2 AND emoji=$3`, commentID, userID, emoji)
+	return err
+}
+
+// List lists the reactions to the comment, oldest first.
+func (*discussionCommentReactions) List(ctx context.Context, commentID int64) ([]*types.DiscussionCommentReaction, error) {
+	if Mocks.DiscussionCommentReactions.List != nil {
+		return Mocks.DiscussionCommentReactions.List(ctx, commentID)
+	}
+	if commentID == 0 {
+		return nil, errors.New("commentID must be specified")
+	}
+	rows, err := dbconn.Global.QueryContext(ctx, `
+		SELECT
+			r.comment_id,
+			r.user_id,
+			r.emoji,
+			r.created_at
+		FROM discussion_comment_reactions r
+		WHERE comment_id=$1
+		ORDER BY created_at ASC, user_id ASC`, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reactions := []*types.DiscussionCommentReaction{}
+	defer rows.Close()
+	for rows.Next() {
+		reaction := &types.DiscussionCommentReaction{}
+		if err := rows.Scan(
+			&reaction.CommentID,
+			&reaction.UserID,
+			&reaction.Emoji,
+			&reaction.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, reaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+func isDiscussionReactionEmoji(emoji string) bool {
+	for _, e := range DiscussionReactionEmojis {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
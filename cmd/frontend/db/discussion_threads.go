@@ -146,6 +146,15 @@ type DiscussionThreadsUpdateOptions struct {
 	// Delete, when true, specifies that the thread should be deleted. This
 	// operation cannot be undone.
 	Delete bool
+
+	// Resolve, when non-nil, specifies whether the thread is resolved or not.
+	// When resolving a thread, ResolvedByUserID must be the user resolving it.
+	Resolve          *bool
+	ResolvedByUserID int32
+
+	// AssigneeUserIDs, when non-nil, specifies the new list of users assigned
+	// to the thread.
+	AssigneeUserIDs *[]int32
 }
 
 func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *DiscussionThreadsUpdateOptions) (*types.DiscussionThread, error) {
@@ -170,6 +179,32 @@ func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *Di
 			return nil, err
 		}
 	}
+	if opts.Resolve != nil {
+		anyUpdate = true
+		var (
+			resolvedAt       *time.Time
+			resolvedByUserID *int32
+		)
+		if *opts.Resolve {
+			if opts.ResolvedByUserID == 0 {
+				return nil, errors.New("ResolvedByUserID must be specified when resolving a thread")
+			}
+			resolvedAt, resolvedByUserID = &now, &opts.ResolvedByUserID
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET resolved_at=$1, resolved_by_user_id=$2 WHERE id=$3 AND deleted_at IS NULL", resolvedAt, resolvedByUserID, threadID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.AssigneeUserIDs != nil {
+		anyUpdate = true
+		assigneeUserIDs := *opts.AssigneeUserIDs
+		if assigneeUserIDs == nil {
+			assigneeUserIDs = []int32{}
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET assignee_user_ids=$1 WHERE id=$2 AND deleted_at IS NULL", pq.Array(assigneeUserIDs), threadID); err != nil {
+			return nil, err
+		}
+	}
 	if opts.Delete {
 		anyUpdate = true
 		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL", now, threadID); err != nil {
@@ -242,6 +277,19 @@ type DiscussionThreadsListOptions struct {
 	// Reported, when true, specifies that only threads with at least one
 	// reported comment should be returned.
 	Reported bool
+
+	// Resolved, when non-nil, specifies that only threads that are (or are
+	// not) resolved should be returned.
+	Resolved *bool
+
+	// AssigneeUserIDs, when len() > 0, specifies that only threads assigned to
+	// at least one of these users should be returned.
+	AssigneeUserIDs    []int32
+	NotAssigneeUserIDs []int32
+
+	// Unassigned, when true, specifies that only threads without assignees
+	// should be returned.
+	Unassigned bool
 }
 
 // SetFromQuery sets the options based on the search query string.
@@ -299,7 +347,10 @@ func (opts *DiscussionThreadsListOptions) SetFromQuery(ctx context.Context, quer
 		return &t
 	}
 
-	var reported bool
+	var (
+		reported             bool
+		resolved, unresolved = true, false
+	)
 	operators := map[string]func(value string){
 		// syntax: `title:"some title"` or "title:sometitle"
 		// Primarily exists for the negation mode.
@@ -377,6 +428,37 @@ func (opts *DiscussionThreadsListOptions) SetFromQuery(ctx context.Context, quer
 		"reported": func(value string) {
 			reported, _ = strconv.ParseBool(value)
 		},
+
+		// syntax: "is:resolved" or "is:unresolved" or "is:unassigned"
+		"is": func(value string) {
+			switch strings.ToLower(value) {
+			case "resolved":
+				opts.Resolved = &resolved
+			case "unresolved", "open":
+				opts.Resolved = &unresolved
+			case "unassigned":
+				opts.Unassigned = true
+			}
+		},
+		"-is": func(value string) {
+			switch strings.ToLower(value) {
+			case "resolved":
+				opts.Resolved = &unresolved
+			case "unresolved", "open":
+				opts.Resolved = &resolved
+			}
+		},
+
+		// syntax: "assignee:slimsag" or "assignee:@slimsag" or `assignee:"slimsag @jack"`
+		"assignee": func(value string) {
+			opts.AssigneeUserIDs = userIDsList(value)
+			if len(opts.AssigneeUserIDs) == 0 {
+				opts.AssigneeUserIDs = []int32{-1}
+			}
+		},
+		"-assignee": func(value string) {
+			opts.NotAssigneeUserIDs = userIDsList(value)
+		},
 	}
 	remaining, operations := searchquery.Parse(query)
 	for _, operation := range operations {
@@ -536,6 +618,22 @@ func (*discussionThreads) getListSQL(opts *DiscussionThreadsListOptions) (conds
 	if opts.CreatedAfter != nil {
 		conds = append(conds, sqlf.Sprintf("created_at > %v", *opts.CreatedAfter))
 	}
+	if opts.Resolved != nil {
+		if *opts.Resolved {
+			conds = append(conds, sqlf.Sprintf("resolved_at IS NOT NULL"))
+		} else {
+			conds = append(conds, sqlf.Sprintf("resolved_at IS NULL"))
+		}
+	}
+	if len(opts.AssigneeUserIDs) > 0 {
+		conds = append(conds, sqlf.Sprintf("assignee_user_ids && %v", pq.Array(opts.AssigneeUserIDs)))
+	}
+	if len(opts.NotAssigneeUserIDs) > 0 {
+		conds = append(conds, sqlf.Sprintf("NOT (assignee_user_ids && %v)", pq.Array(opts.NotAssigneeUserIDs)))
+	}
+	if opts.Unassigned {
+		conds = append(conds, sqlf.Sprintf("assignee_user_ids = '{}'"))
+	}
 
 	if opts.TargetRepoID != nil || opts.TargetRepoPath != nil || opts.NotTargetRepoID != nil || opts.NotTargetRepoPath != nil {
 		targetRepoConds := []*sqlf.Query{}
@@ -627,7 +725,10 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 			t.target_repo_id,
 			t.created_at,
 			t.archived_at,
-			t.updated_at
+			t.updated_at,
+			t.resolved_at,
+			t.resolved_by_user_id,
+			t.assignee_user_ids
 		FROM discussion_threads t `+query, args...)
 	if err != nil {
 		return nil, err
@@ -637,8 +738,9 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 	defer rows.Close()
 	for rows.Next() {
 		var (
-			thread       types.DiscussionThread
-			targetRepoID *int64
+			thread          types.DiscussionThread
+			targetRepoID    *int64
+			assigneeUserIDs pq.Int64Array // pq.Array can't scan into []int32
 		)
 		err := rows.Scan(
 			&thread.ID,
@@ -648,10 +750,17 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 			&thread.CreatedAt,
 			&thread.ArchivedAt,
 			&thread.UpdatedAt,
+			&thread.ResolvedAt,
+			&thread.ResolvedByUserID,
+			&assigneeUserIDs,
 		)
 		if err != nil {
 			return nil, err
 		}
+		thread.AssigneeUserIDs = make([]int32, len(assigneeUserIDs))
+		for i, id := range assigneeUserIDs {
+			thread.AssigneeUserIDs[i] = int32(id)
+		}
 		if targetRepoID != nil {
 			thread.TargetRepo, err = t.getTargetRepo(ctx, *targetRepoID)
 			if err != nil {
@@ -124,6 +124,96 @@ func TestDiscussionThreads_Update(t *testing.T) {
 	}
 }
 
+func TestDiscussionThreads_ResolveAssign(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@a.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository to comply with the postgres repo constraint.
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the thread.
+	thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+		AuthorUserID: user.ID,
+		Title:        "Hello world!",
+		TargetRepo: &types.DiscussionThreadTargetRepo{
+			RepoID: repo.ID,
+			Path:   strPtr("foo/bar/mux.go"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Resolve the thread and assign it to the user.
+	gotThread, err := DiscussionThreads.Update(ctx, thread.ID, &DiscussionThreadsUpdateOptions{
+		Resolve:          boolPtr(true),
+		ResolvedByUserID: user.ID,
+		AssigneeUserIDs:  &[]int32{user.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt == nil || gotThread.ResolvedByUserID == nil || *gotThread.ResolvedByUserID != user.ID {
+		t.Fatalf("expected thread to be resolved by user %d, got %+v", user.ID, gotThread)
+	}
+	if want := []int32{user.ID}; !reflect.DeepEqual(gotThread.AssigneeUserIDs, want) {
+		t.Fatalf("got assignees %v, want %v", gotThread.AssigneeUserIDs, want)
+	}
+
+	// Filter by resolution and assignee.
+	for _, test := range []struct {
+		opts *DiscussionThreadsListOptions
+		want int
+	}{
+		{&DiscussionThreadsListOptions{Resolved: boolPtr(true)}, 1},
+		{&DiscussionThreadsListOptions{Resolved: boolPtr(false)}, 0},
+		{&DiscussionThreadsListOptions{AssigneeUserIDs: []int32{user.ID}}, 1},
+		{&DiscussionThreadsListOptions{NotAssigneeUserIDs: []int32{user.ID}}, 0},
+		{&DiscussionThreadsListOptions{Unassigned: true}, 0},
+	} {
+		count, err := DiscussionThreads.Count(ctx, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != test.want {
+			t.Errorf("%+v: got count %d, want %d", test.opts, count, test.want)
+		}
+	}
+
+	// Unresolve the thread and remove its assignees.
+	gotThread, err = DiscussionThreads.Update(ctx, thread.ID, &DiscussionThreadsUpdateOptions{
+		Resolve:         boolPtr(false),
+		AssigneeUserIDs: &[]int32{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt != nil || gotThread.ResolvedByUserID != nil {
+		t.Fatal("expected thread to be unresolved")
+	}
+	if len(gotThread.AssigneeUserIDs) != 0 {
+		t.Fatalf("got assignees %v, want none", gotThread.AssigneeUserIDs)
+	}
+}
+
 func TestDiscussionThreads_Count(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -0,0 +1,325 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// ExplicitRepoPermission describes a permission on a repository (or on all repositories whose
+// names match a pattern) that a site admin explicitly granted to a user or to all members of an
+// organization.
+type ExplicitRepoPermission struct {
+	ID          int32
+	UserID      int32        // the user granted the permission (0 if granted to an organization)
+	OrgID       int32        // the organization whose members are granted the permission (0 if granted to a user)
+	RepoID      api.RepoID   // the repository (0 if RepoPattern is set)
+	RepoName    api.RepoName // the name of the repository with ID RepoID (only set when read from the database)
+	RepoPattern string       // the pattern of repository names (empty if RepoID is set); see MatchRepoPattern
+	Perm        authz.Perm
+	CreatedAt   time.Time
+}
+
+// Matches reports whether the permission applies to the repository with the given name.
+func (p *ExplicitRepoPermission) Matches(repoName api.RepoName) bool {
+	if p.RepoPattern != "" {
+		return MatchRepoPattern(p.RepoPattern, repoName)
+	}
+	return strings.EqualFold(string(p.RepoName), string(repoName))
+}
+
+// MatchRepoPattern reports whether the repository name matches the pattern, in which "*" matches
+// any sequence of characters (including "/") and all other characters match themselves. Matching
+// is case-insensitive, because repository names are.
+func MatchRepoPattern(pattern string, repoName api.RepoName) bool {
+	parts := strings.Split(strings.ToLower(pattern), "*")
+	name := strings.ToLower(string(repoName))
+	if len(parts) == 1 {
+		return name == parts[0]
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i == -1 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
+
+// ErrExplicitRepoPermissionNotFound occurs when a database operation expects a specific explicit
+// repository permission to exist but it does not exist.
+var ErrExplicitRepoPermissionNotFound = errors.New("explicit repository permission not found")
+
+// explicitRepoPermissions provides access to the `explicit_repo_permissions` table.
+//
+// For more information, see ./schema.md.
+type explicitRepoPermissions struct{}
+
+func (p *ExplicitRepoPermission) validate() error {
+	if (p.UserID == 0) == (p.OrgID == 0) {
+		return errors.New("explicit repository permission must be granted to exactly one of a user or an organization")
+	}
+	if (p.RepoID == 0) == (p.RepoPattern == "") {
+		return errors.New("explicit repository permission must apply to exactly one of a repository or a repository name pattern")
+	}
+	if p.Perm != authz.Read {
+		return fmt.Errorf("unsupported repository permission %q (only %q is supported)", p.Perm, authz.Read)
+	}
+	return nil
+}
+
+const explicitRepoPermissionInsertQuery = `
+INSERT INTO explicit_repo_permissions(user_id, org_id, repo_id, repo_pattern, permission)
+VALUES(NULLIF($1, 0), NULLIF($2, 0), NULLIF($3, 0), NULLIF($4, ''), $5)
+ON CONFLICT (COALESCE(user_id, 0), COALESCE(org_id, 0), COALESCE(repo_id, 0), COALESCE(repo_pattern, ''), permission) DO NOTHING`
+
+// Create grants the permission. If an identical permission was already granted, it returns the
+// existing permission.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *explicitRepoPermissions) Create(ctx context.Context, p *ExplicitRepoPermission) (*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.Create != nil {
+		return Mocks.ExplicitRepoPermissions.Create(ctx, p)
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, explicitRepoPermissionInsertQuery, p.UserID, p.OrgID, p.RepoID, p.RepoPattern, string(p.Perm)); err != nil {
+		return nil, err
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{
+		sqlf.Sprintf("COALESCE(p.user_id, 0)=%d AND COALESCE(p.org_id, 0)=%d", p.UserID, p.OrgID),
+		sqlf.Sprintf("COALESCE(p.repo_id, 0)=%d AND COALESCE(p.repo_pattern, '')=%s", p.RepoID, p.RepoPattern),
+		sqlf.Sprintf("p.permission=%s", string(p.Perm)),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		// The permission was revoked concurrently, or its user, organization or repository was
+		// deleted.
+		return nil, ErrExplicitRepoPermissionNotFound
+	}
+	return results[0], nil
+}
+
+// Import grants all of the permissions in a single transaction, so that either all or none of them
+// are granted. Permissions that were already granted are ignored. It returns the number of newly
+// granted permissions.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*explicitRepoPermissions) Import(ctx context.Context, perms []*ExplicitRepoPermission) (created int, err error) {
+	if Mocks.ExplicitRepoPermissions.Import != nil {
+		return Mocks.ExplicitRepoPermissions.Import(ctx, perms)
+	}
+
+	for i, p := range perms {
+		if err := p.validate(); err != nil {
+			return 0, fmt.Errorf("permission %d: %s", i, err)
+		}
+	}
+
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		for _, p := range perms {
+			res, err := tx.ExecContext(ctx, explicitRepoPermissionInsertQuery, p.UserID, p.OrgID, p.RepoID, p.RepoPattern, string(p.Perm))
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			created += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// GetByID returns the permission with the given ID. If no such permission exists, it returns
+// ErrExplicitRepoPermissionNotFound.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *explicitRepoPermissions) GetByID(ctx context.Context, id int32) (*ExplicitRepoPermission, error) {
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("p.id=%d", id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrExplicitRepoPermissionNotFound
+	}
+	return results[0], nil
+}
+
+// Delete revokes the permission with the given ID. If no such permission exists, it returns
+// ErrExplicitRepoPermissionNotFound.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*explicitRepoPermissions) Delete(ctx context.Context, id int32) error {
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM explicit_repo_permissions WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrExplicitRepoPermissionNotFound
+	}
+	return nil
+}
+
+// explicitRepoPermissionsFromClause is the FROM and WHERE clause of queries that list explicit
+// repository permissions, with a placeholder for additional conditions. It excludes permissions of
+// deleted users, organizations and repositories (which are soft-deleted, so their permissions are
+// not deleted by the foreign key constraints).
+const explicitRepoPermissionsFromClause = `FROM explicit_repo_permissions p
+LEFT OUTER JOIN users ON users.id=p.user_id
+LEFT OUTER JOIN orgs ON orgs.id=p.org_id
+LEFT OUTER JOIN repo ON repo.id=p.repo_id
+WHERE users.deleted_at IS NULL AND orgs.deleted_at IS NULL AND repo.deleted_at IS NULL AND (%s)`
+
+// ExplicitRepoPermissionsListOptions contains options for listing explicit repository permissions.
+type ExplicitRepoPermissionsListOptions struct {
+	UserID int32      // only list permissions granted to this user (not including those granted to the user's organizations)
+	OrgID  int32      // only list permissions granted to this organization
+	RepoID api.RepoID // only list permissions on this repository (not including those whose pattern matches it)
+	*LimitOffset
+}
+
+func (o ExplicitRepoPermissionsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("p.user_id=%d", o.UserID))
+	}
+	if o.OrgID != 0 {
+		conds = append(conds, sqlf.Sprintf("p.org_id=%d", o.OrgID))
+	}
+	if o.RepoID != 0 {
+		conds = append(conds, sqlf.Sprintf("p.repo_id=%d", o.RepoID))
+	}
+	return conds
+}
+
+// List lists the permissions that satisfy the options, most recently granted first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *explicitRepoPermissions) List(ctx context.Context, opt ExplicitRepoPermissionsListOptions) ([]*ExplicitRepoPermission, error) {
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+// Count counts the permissions that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*explicitRepoPermissions) Count(ctx context.Context, opt ExplicitRepoPermissionsListOptions) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) "+explicitRepoPermissionsFromClause, sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListForUser lists the permissions of the given type that apply to the user, i.e., those granted
+// to the user and those granted to the organizations that the user is a member of.
+//
+// 🚨 SECURITY: The caller must ensure that the permissions are not leaked to users other than the
+// user and site admins.
+func (s *explicitRepoPermissions) ListForUser(ctx context.Context, userID int32, perm authz.Perm) ([]*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.ListForUser != nil {
+		return Mocks.ExplicitRepoPermissions.ListForUser(ctx, userID, perm)
+	}
+
+	return s.list(ctx, []*sqlf.Query{
+		sqlf.Sprintf("p.user_id=%d OR p.org_id IN (SELECT org_id FROM org_members WHERE user_id=%d)", userID, userID),
+		sqlf.Sprintf("p.permission=%s", string(perm)),
+	}, nil)
+}
+
+func (*explicitRepoPermissions) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*ExplicitRepoPermission, error) {
+	q := sqlf.Sprintf(`
+SELECT p.id, COALESCE(p.user_id, 0), COALESCE(p.org_id, 0), COALESCE(p.repo_id, 0), COALESCE(repo.name, ''), COALESCE(p.repo_pattern, ''), p.permission, p.created_at
+`+explicitRepoPermissionsFromClause+`
+ORDER BY p.created_at DESC, p.id DESC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*ExplicitRepoPermission
+	for rows.Next() {
+		var p ExplicitRepoPermission
+		if err := rows.Scan(&p.ID, &p.UserID, &p.OrgID, &p.RepoID, &p.RepoName, &p.RepoPattern, &p.Perm, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &p)
+	}
+	return results, rows.Err()
+}
+
+// RefreshExplicitRepoPermissionGrantees refreshes the synced repository permissions (see
+// RefreshUserPermissions) of the users and the members of the organizations that the permissions are
+// granted to, so that granting or revoking them takes effect without waiting for the next periodic
+// sync.
+func RefreshExplicitRepoPermissionGrantees(ctx context.Context, perms []*ExplicitRepoPermission) {
+	if enabled, _ := BackgroundPermissionsSync(); !enabled {
+		return
+	}
+
+	userIDs := make(map[int32]struct{})
+	orgIDs := make(map[int32]struct{})
+	for _, p := range perms {
+		if p.UserID != 0 {
+			userIDs[p.UserID] = struct{}{}
+		}
+		if p.OrgID != 0 {
+			orgIDs[p.OrgID] = struct{}{}
+		}
+	}
+	for orgID := range orgIDs {
+		members, err := OrgMembers.GetByOrgID(ctx, orgID)
+		if err != nil {
+			log15.Warn("Unable to list organization members for repository permissions sync.", "orgID", orgID, "error", err)
+			continue
+		}
+		for _, m := range members {
+			userIDs[m.UserID] = struct{}{}
+		}
+	}
+	for userID := range userIDs {
+		RefreshUserPermissions(userID)
+	}
+}
+
+type MockExplicitRepoPermissions struct {
+	Create      func(ctx context.Context, p *ExplicitRepoPermission) (*ExplicitRepoPermission, error)
+	Import      func(ctx context.Context, perms []*ExplicitRepoPermission) (int, error)
+	ListForUser func(ctx context.Context, userID int32, perm authz.Perm) ([]*ExplicitRepoPermission, error)
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestMatchRepoPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern  string
+		repoName api.RepoName
+		want     bool
+	}{
+		{"gitolite.example.com/foo", "gitolite.example.com/foo", true},
+		{"gitolite.example.com/foo", "gitolite.example.com/foobar", false},
+		{"gitolite.example.com/*", "gitolite.example.com/foo/bar", true},
+		{"gitolite.example.com/*", "github.com/foo/bar", false},
+		{"*/team-a/*", "gitolite.example.com/team-a/api", true},
+		{"*/team-a/*", "gitolite.example.com/team-b/api", false},
+		{"*-api", "gitolite.example.com/team-a/billing-api", true},
+		{"a*b*a", "aba", true},
+		{"a*b*a", "ab", false},
+		{"Gitolite.Example.com/*", "gitolite.example.com/Foo", true},
+		{"*", "anything", true},
+	} {
+		if got := MatchRepoPattern(tc.pattern, tc.repoName); got != tc.want {
+			t.Errorf("MatchRepoPattern(%q, %q) = %v, want %v", tc.pattern, tc.repoName, got, tc.want)
+		}
+	}
+}
+
+func TestExplicitRepoPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+
+	alice, err := Users.Create(ctx, NewUser{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := Users.Create(ctx, NewUser{Username: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	org, err := Orgs.Create(ctx, "acme", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OrgMembers.Create(ctx, org.ID, bob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "gitolite.example.com/foo", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "gitolite.example.com/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Granting an identical permission twice returns the existing permission.
+	p1, err := ExplicitRepoPermissions.Create(ctx, &ExplicitRepoPermission{UserID: alice.ID, RepoID: repo.ID, Perm: authz.Read})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1.RepoName != repo.Name {
+		t.Errorf("got repo name %q, want %q", p1.RepoName, repo.Name)
+	}
+	if p, err := ExplicitRepoPermissions.Create(ctx, &ExplicitRepoPermission{UserID: alice.ID, RepoID: repo.ID, Perm: authz.Read}); err != nil {
+		t.Fatal(err)
+	} else if p.ID != p1.ID {
+		t.Errorf("got permission ID %d, want existing permission ID %d", p.ID, p1.ID)
+	}
+
+	// Invalid permissions are rejected.
+	for _, p := range []*ExplicitRepoPermission{
+		{UserID: alice.ID, OrgID: org.ID, RepoID: repo.ID, Perm: authz.Read},
+		{UserID: alice.ID, Perm: authz.Read},
+		{UserID: alice.ID, RepoID: repo.ID, RepoPattern: "*", Perm: authz.Read},
+		{UserID: alice.ID, RepoID: repo.ID, Perm: "write"},
+	} {
+		if _, err := ExplicitRepoPermissions.Create(ctx, p); err == nil {
+			t.Errorf("got no error for invalid permission %+v", p)
+		}
+	}
+
+	created, err := ExplicitRepoPermissions.Import(ctx, []*ExplicitRepoPermission{
+		{UserID: alice.ID, RepoID: repo.ID, Perm: authz.Read}, // already granted
+		{OrgID: org.ID, RepoPattern: "gitolite.example.com/*", Perm: authz.Read},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 1 {
+		t.Errorf("got %d created permissions, want 1", created)
+	}
+
+	// Bob is granted permissions through the organization.
+	perms, err := ExplicitRepoPermissions.ListForUser(ctx, bob.ID, authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms) != 1 || perms[0].OrgID != org.ID || !perms[0].Matches("gitolite.example.com/bar") {
+		t.Errorf("got permissions %+v, want the organization's pattern permission", perms)
+	}
+
+	if n, err := ExplicitRepoPermissions.Count(ctx, ExplicitRepoPermissionsListOptions{UserID: alice.ID}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got count %d, want 1", n)
+	}
+
+	if err := ExplicitRepoPermissions.Delete(ctx, p1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExplicitRepoPermissions.GetByID(ctx, p1.ID); err != ErrExplicitRepoPermissionNotFound {
+		t.Errorf("got error %v, want %v", err, ErrExplicitRepoPermissionNotFound)
+	}
+	if err := ExplicitRepoPermissions.Delete(ctx, p1.ID); err != ErrExplicitRepoPermissionNotFound {
+		t.Errorf("got error %v, want %v", err, ErrExplicitRepoPermissionNotFound)
+	}
+}
@@ -1,6 +1,11 @@
 package db
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
 
 func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 	tests := map[string]struct {
@@ -36,3 +41,27 @@ func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExternalServicesStore_ListBitbucketServerConnections(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	for _, es := range []*types.ExternalService{
+		{Kind: "BITBUCKETSERVER", DisplayName: "Bitbucket Server", Config: `{"url": "https://bitbucket.example.com", "username": "admin", "token": "abc", "repositoryQuery": ["none"]}`},
+		{Kind: "GITHUB", DisplayName: "GitHub", Config: `{"url": "https://github.com", "repositoryQuery": ["none"], "token": "abc"}`},
+	} {
+		if err := (&ExternalServicesStore{}).Create(ctx, es); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conns, err := (&ExternalServicesStore{}).ListBitbucketServerConnections(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conns) != 1 || conns[0].Url != "https://bitbucket.example.com" {
+		t.Errorf("got connections %+v, want the Bitbucket Server connection", conns)
+	}
+}
@@ -6,11 +6,13 @@ var Mocks MockStores
 type MockStores struct {
 	AccessTokens MockAccessTokens
 
-	DiscussionThreads         MockDiscussionThreads
-	DiscussionComments        MockDiscussionComments
-	DiscussionMailReplyTokens MockDiscussionMailReplyTokens
+	DiscussionThreads          MockDiscussionThreads
+	DiscussionComments         MockDiscussionComments
+	DiscussionCommentReactions MockDiscussionCommentReactions
+	DiscussionMailReplyTokens  MockDiscussionMailReplyTokens
 
 	Repos         MockRepos
+	RepoGroups    MockRepoGroups
 	Orgs          MockOrgs
 	OrgMembers    MockOrgMembers
 	SavedSearches MockSavedSearches
@@ -18,6 +20,9 @@ type MockStores struct {
 	Users         MockUsers
 	UserEmails    MockUserEmails
 
+	UserPermissions         MockUserPermissions
+	ExplicitRepoPermissions MockExplicitRepoPermissions
+
 	Phabricator MockPhabricator
 
 	ExternalAccounts MockExternalAccounts
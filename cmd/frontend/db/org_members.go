@@ -33,6 +33,9 @@ func (*orgMembers) Create(ctx context.Context, orgID, userID int32) (*types.OrgM
 		}
 		return nil, err
 	}
+	// The user may have gained access to repositories through explicit
+	// permissions granted to the org.
+	RefreshUserPermissions(userID)
 	return &m, nil
 }
 
@@ -49,7 +52,13 @@ func (m *orgMembers) GetByOrgIDAndUserID(ctx context.Context, orgID, userID int3
 
 func (*orgMembers) Remove(ctx context.Context, orgID, userID int32) error {
 	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
-	return err
+	if err != nil {
+		return err
+	}
+	// The user may have lost access to repositories through explicit
+	// permissions granted to the org.
+	RefreshUserPermissions(userID)
+	return nil
 }
 
 // GetByOrgID returns a list of all members of a given organization.
@@ -0,0 +1,391 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
+)
+
+// repoGroups stores repository groups. The members of a group are either set
+// explicitly (with SetRepos) or, if the group has a rule, computed by
+// repo-updater after each sync.
+type repoGroups struct{}
+
+var errRepoGroupNameAlreadyExists = errors.New("a repository group with this name already exists")
+
+type repoGroupNotFoundError struct {
+	args []interface{}
+}
+
+func (err repoGroupNotFoundError) Error() string {
+	return fmt.Sprintf("repository group not found: %v", err.args)
+}
+
+func (repoGroupNotFoundError) NotFound() bool {
+	return true
+}
+
+// RepoGroupsListOptions contains options for listing repository groups.
+type RepoGroupsListOptions struct {
+	*LimitOffset
+}
+
+func validateRepoGroupRule(rule *api.RepoGroupRule) error {
+	if rule == nil {
+		return nil
+	}
+	if _, err := regexp.Compile(rule.NamePattern); err != nil {
+		return errors.Wrap(err, "invalid repository group name pattern")
+	}
+	return nil
+}
+
+func repoGroupRuleColumn(rule *api.RepoGroupRule) (*string, error) {
+	if rule == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
+
+func repoGroupWriteErr(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Constraint {
+		case "repo_groups_name_unique":
+			return errRepoGroupNameAlreadyExists
+		case "repo_groups_name_not_empty":
+			return errors.New("repository group name must not be empty")
+		}
+	}
+	return err
+}
+
+// Create creates a repository group with the given explicit members. The
+// members of a group with a rule are computed by the next sync instead.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoGroups) Create(ctx context.Context, group *types.RepoGroup, repoIDs []api.RepoID) error {
+	if err := validateRepoGroupRule(group.Rule); err != nil {
+		return err
+	}
+	rule, err := repoGroupRuleColumn(group.Rule)
+	if err != nil {
+		return err
+	}
+
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = group.CreatedAt
+
+	// The group and its members are created in one transaction so that a
+	// failure to add the members doesn't leave behind an empty group.
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			"INSERT INTO repo_groups(name, description, rule, created_at, updated_at) VALUES($1, $2, $3, $4, $5) RETURNING id",
+			group.Name, group.Description, rule, group.CreatedAt, group.UpdatedAt,
+		).Scan(&group.ID)
+		if err != nil {
+			return repoGroupWriteErr(err)
+		}
+		return setRepoGroupMembers(ctx, tx, group.ID, repoIDs)
+	})
+}
+
+// RepoGroupUpdate contains optional fields to update.
+type RepoGroupUpdate struct {
+	Name        *string
+	Description *string
+	Rule        *api.RepoGroupRule // if set, the group becomes (or stays) dynamic
+}
+
+// Update updates a repository group. The members of a group whose rule
+// changes are recomputed after the next sync.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoGroups) Update(ctx context.Context, id int32, update *RepoGroupUpdate) error {
+	if err := validateRepoGroupRule(update.Rule); err != nil {
+		return err
+	}
+
+	var sets []*sqlf.Query
+	if update.Name != nil {
+		sets = append(sets, sqlf.Sprintf("name=%s", *update.Name))
+	}
+	if update.Description != nil {
+		sets = append(sets, sqlf.Sprintf("description=%s", *update.Description))
+	}
+	if update.Rule != nil {
+		rule, err := repoGroupRuleColumn(update.Rule)
+		if err != nil {
+			return err
+		}
+		sets = append(sets, sqlf.Sprintf("rule=%s", rule))
+	}
+	sets = append(sets, sqlf.Sprintf("updated_at=now()"))
+
+	q := sqlf.Sprintf("UPDATE repo_groups SET %s WHERE id=%d AND deleted_at IS NULL", sqlf.Join(sets, ", "), id)
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return repoGroupWriteErr(err)
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return repoGroupNotFoundError{[]interface{}{id}}
+	}
+	return nil
+}
+
+// SetRepos makes the repository group explicit (removing its rule, if any) and
+// replaces its members with the given repositories.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoGroups) SetRepos(ctx context.Context, id int32, repoIDs []api.RepoID) error {
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE repo_groups SET rule=NULL, updated_at=now() WHERE id=$1 AND deleted_at IS NULL", id)
+		if err != nil {
+			return err
+		}
+		nrows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if nrows == 0 {
+			return repoGroupNotFoundError{[]interface{}{id}}
+		}
+
+		return setRepoGroupMembers(ctx, tx, id, repoIDs)
+	})
+}
+
+// setRepoGroupMembers replaces the members of the repository group with the
+// given repositories.
+func setRepoGroupMembers(ctx context.Context, tx *sql.Tx, id int32, repoIDs []api.RepoID) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM repo_group_members WHERE repo_group_id=$1", id); err != nil {
+		return err
+	}
+	if len(repoIDs) == 0 {
+		return nil
+	}
+	values := make([]*sqlf.Query, len(repoIDs))
+	for i, repoID := range repoIDs {
+		values[i] = sqlf.Sprintf("(%d, %d)", id, repoID)
+	}
+	q := sqlf.Sprintf("INSERT INTO repo_group_members(repo_group_id, repo_id) VALUES %s ON CONFLICT DO NOTHING", sqlf.Join(values, ", "))
+	_, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// Delete deletes a repository group.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoGroups) Delete(ctx context.Context, id int32) error {
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE repo_groups SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return repoGroupNotFoundError{[]interface{}{id}}
+	}
+	return nil
+}
+
+// GetByID returns the repository group with the given ID.
+//
+// Repository groups are visible to all users, like the ones defined in
+// settings.
+func (s *repoGroups) GetByID(ctx context.Context, id int32) (*types.RepoGroup, error) {
+	if Mocks.RepoGroups.GetByID != nil {
+		return Mocks.RepoGroups.GetByID(ctx, id)
+	}
+	return s.getBySQL(ctx, sqlf.Sprintf("id=%d", id), id)
+}
+
+// GetByName returns the repository group with the given name.
+func (s *repoGroups) GetByName(ctx context.Context, name string) (*types.RepoGroup, error) {
+	return s.getBySQL(ctx, sqlf.Sprintf("name=%s", name), name)
+}
+
+func (s *repoGroups) getBySQL(ctx context.Context, cond *sqlf.Query, args ...interface{}) (*types.RepoGroup, error) {
+	groups, err := s.list(ctx, []*sqlf.Query{cond}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, repoGroupNotFoundError{args}
+	}
+	return groups[0], nil
+}
+
+// List lists repository groups, ordered by name.
+func (s *repoGroups) List(ctx context.Context, opt RepoGroupsListOptions) ([]*types.RepoGroup, error) {
+	if Mocks.RepoGroups.List != nil {
+		return Mocks.RepoGroups.List(ctx, opt)
+	}
+	return s.list(ctx, nil, opt.LimitOffset)
+}
+
+func (*repoGroups) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*types.RepoGroup, error) {
+	conds = append(conds, sqlf.Sprintf("deleted_at IS NULL"))
+	q := sqlf.Sprintf(`
+		SELECT id, name, description, rule, created_at, updated_at
+		FROM repo_groups
+		WHERE (%s)
+		ORDER BY name ASC
+		%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*types.RepoGroup
+	for rows.Next() {
+		var (
+			g    types.RepoGroup
+			rule []byte
+		)
+		if err := rows.Scan(&g.ID, &g.Name, &g.Description, &rule, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			g.Rule = new(api.RepoGroupRule)
+			if err := json.Unmarshal(rule, g.Rule); err != nil {
+				return nil, errors.Wrapf(err, "repository group %d has an invalid rule", g.ID)
+			}
+		}
+		results = append(results, &g)
+	}
+	return results, rows.Err()
+}
+
+// ListRepos returns the current members of the repository group with the
+// given ID that the current user may access.
+func (s *repoGroups) ListRepos(ctx context.Context, id int32) ([]*types.Repo, error) {
+	members, err := s.listMembers(ctx, sqlf.Sprintf("g.id=%d", id))
+	if err != nil {
+		return nil, err
+	}
+	for _, repos := range members {
+		return repos, nil
+	}
+	return nil, nil
+}
+
+// ListMembers returns the current members of all repository groups that the
+// current user may access, keyed by group name. Groups without (accessible)
+// members are included with a nil slice.
+func (s *repoGroups) ListMembers(ctx context.Context) (map[string][]*types.Repo, error) {
+	if Mocks.RepoGroups.ListMembers != nil {
+		return Mocks.RepoGroups.ListMembers(ctx)
+	}
+	return s.listMembers(ctx, sqlf.Sprintf("TRUE"))
+}
+
+func (*repoGroups) listMembers(ctx context.Context, cond *sqlf.Query) (map[string][]*types.Repo, error) {
+	q := sqlf.Sprintf(`
+		SELECT g.name, r.id, r.name, r.external_id, r.external_service_type, r.external_service_id
+		FROM repo_groups g
+		LEFT JOIN repo_group_members m ON m.repo_group_id = g.id
+		LEFT JOIN repo r ON r.id = m.repo_id AND r.deleted_at IS NULL
+		WHERE g.deleted_at IS NULL AND (%s)
+		ORDER BY g.name ASC, r.name ASC`,
+		cond,
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type member struct {
+		group  string
+		repoID api.RepoID
+	}
+	var (
+		members []member
+		repos   []*types.Repo
+		seen    = map[api.RepoID]bool{}
+	)
+	for rows.Next() {
+		var (
+			group    string
+			repoID   sql.NullInt64
+			repoName sql.NullString
+			spec     dbExternalRepoSpec
+		)
+		if err := rows.Scan(&group, &repoID, &repoName, &spec.id, &spec.serviceType, &spec.serviceID); err != nil {
+			return nil, err
+		}
+		if !repoID.Valid {
+			members = append(members, member{group: group}) // group without members
+			continue
+		}
+		id := api.RepoID(repoID.Int64)
+		members = append(members, member{group: group, repoID: id})
+		if !seen[id] {
+			seen[id] = true
+			repos = append(repos, &types.Repo{ID: id, Name: api.RepoName(repoName.String), ExternalRepo: spec.toAPISpec()})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: This enforces repository permissions, so that users can't
+	// learn the names of repositories they may not access from the groups
+	// they are members of.
+	repos, err = authzFilter(ctx, repos, authz.Read)
+	if err != nil {
+		return nil, err
+	}
+	accessible := make(map[api.RepoID]*types.Repo, len(repos))
+	for _, repo := range repos {
+		accessible[repo.ID] = repo
+	}
+
+	result := map[string][]*types.Repo{}
+	for _, m := range members {
+		repo, ok := accessible[m.repoID]
+		if !ok {
+			result[m.group] = result[m.group] // group without accessible members
+			continue
+		}
+		result[m.group] = append(result[m.group], repo)
+	}
+	return result, nil
+}
+
+// MockRepoGroups mocks the repository groups store.
+type MockRepoGroups struct {
+	GetByID     func(ctx context.Context, id int32) (*types.RepoGroup, error)
+	List        func(ctx context.Context, opt RepoGroupsListOptions) ([]*types.RepoGroup, error)
+	ListMembers func(ctx context.Context) (map[string][]*types.Repo, error)
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func TestRepoGroups(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+	repos := mustCreate(ctx, t,
+		&types.Repo{Name: "github.com/foo/a"},
+		&types.Repo{Name: "github.com/foo/b"},
+	)
+
+	explicit := &types.RepoGroup{Name: "explicit", Description: "d"}
+	if err := RepoGroups.Create(ctx, explicit, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := RepoGroups.Create(ctx, &types.RepoGroup{Name: "Explicit"}, nil); err != errRepoGroupNameAlreadyExists {
+		t.Errorf("got error %v, want %v", err, errRepoGroupNameAlreadyExists)
+	}
+	if err := RepoGroups.Create(ctx, &types.RepoGroup{Name: "bad", Rule: &api.RepoGroupRule{NamePattern: "("}}, nil); err == nil {
+		t.Error("got nil error for invalid name pattern")
+	}
+
+	dynamic := &types.RepoGroup{Name: "dynamic", Rule: &api.RepoGroupRule{Owners: []string{"foo"}}}
+	if err := RepoGroups.Create(ctx, dynamic, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RepoGroups.SetRepos(ctx, explicit.ID, []api.RepoID{repos[1].ID, repos[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := RepoGroups.ListMembers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]api.RepoName{
+		"dynamic":  nil,
+		"explicit": {"github.com/foo/a", "github.com/foo/b"},
+	}
+	got := map[string][]api.RepoName{}
+	for name, repos := range members {
+		got[name] = repoNames(repos)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got members %v, want %v", got, want)
+	}
+
+	// 🚨 SECURITY: Members that the current user may not access are omitted.
+	mockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perm) ([]*types.Repo, error) {
+		var filtered []*types.Repo
+		for _, repo := range repos {
+			if repo.Name != "github.com/foo/b" {
+				filtered = append(filtered, repo)
+			}
+		}
+		return filtered, nil
+	}
+	userCtx := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	members, err = RepoGroups.ListMembers(userCtx)
+	mockAuthzFilter = nil
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := repoNames(members["explicit"]), []api.RepoName{"github.com/foo/a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got accessible members %v, want %v", got, want)
+	}
+	if got, ok := members["dynamic"]; !ok || got != nil {
+		t.Errorf("got dynamic group members %v (present: %v), want present with no members", got, ok)
+	}
+
+	description := "new description"
+	if err := RepoGroups.Update(ctx, dynamic.ID, &RepoGroupUpdate{Description: &description}); err != nil {
+		t.Fatal(err)
+	}
+	g, err := RepoGroups.GetByName(ctx, "dynamic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Description != description || !reflect.DeepEqual(g.Rule, dynamic.Rule) {
+		t.Errorf("got group %+v, want description %q and rule %+v", g, description, dynamic.Rule)
+	}
+
+	// Setting the members of a dynamic group makes it explicit.
+	if err := RepoGroups.SetRepos(ctx, dynamic.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if g, err = RepoGroups.GetByID(ctx, dynamic.ID); err != nil {
+		t.Fatal(err)
+	} else if g.Rule != nil {
+		t.Errorf("got rule %+v, want nil", g.Rule)
+	}
+
+	if err := RepoGroups.Delete(ctx, explicit.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RepoGroups.GetByID(ctx, explicit.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+	// The name of a deleted group can be reused.
+	if err := RepoGroups.Create(ctx, &types.RepoGroup{Name: "explicit"}, []api.RepoID{repos[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A group whose members can't be added is not created.
+	if err := RepoGroups.Create(ctx, &types.RepoGroup{Name: "missing"}, []api.RepoID{repos[0].ID, 1e6}); err == nil {
+		t.Error("got nil error for nonexistent member")
+	}
+	if _, err := RepoGroups.GetByName(ctx, "missing"); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
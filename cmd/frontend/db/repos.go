@@ -122,6 +122,17 @@ FROM repo
 WHERE deleted_at IS NULL AND enabled = true AND %s`
 
 func (s *repos) getBySQL(ctx context.Context, querySuffix *sqlf.Query) ([]*types.Repo, error) {
+	// 🚨 SECURITY: When the current user's repository permissions are synced in the background,
+	// filter by them in the query (so that limits apply to the permitted repositories).
+	// authzFilter below still enforces repository permissions.
+	permsCond, err := syncedPermissionsCondition(ctx, authz.Read)
+	if err != nil {
+		return nil, err
+	}
+	if permsCond != nil {
+		querySuffix = sqlf.Sprintf("%s AND %s", permsCond, querySuffix)
+	}
+
 	q := sqlf.Sprintf(getRepoByQueryFmtstr, querySuffix)
 	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
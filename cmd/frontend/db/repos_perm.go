@@ -28,6 +28,21 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perm) ([]*typ
 		return repos, nil
 	}
 
+	// 🚨 SECURITY: An access token with a repository allowlist may only access those repositories
+	// (even if its user is a site admin).
+	if r := authz.AccessTokenRestrictionsFromContext(ctx); r != nil && len(r.RepoAllowlist) > 0 {
+		allowedRepos := make([]*types.Repo, 0, len(repos))
+		for _, repo := range repos {
+			if r.AllowsRepo(repo.Name) {
+				allowedRepos = append(allowedRepos, repo)
+			}
+		}
+		if len(allowedRepos) == 0 {
+			return allowedRepos, nil
+		}
+		repos = allowedRepos
+	}
+
 	var currentUser *types.User
 	if actor.FromContext(ctx).IsAuthenticated() {
 		var err error
@@ -40,9 +55,24 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perm) ([]*typ
 		}
 	}
 
-	filteredRepoNames, err := getFilteredRepoNames(ctx, currentUser, authz.ToRepos(repos), p)
-	if err != nil {
-		return nil, err
+	// When repository permissions are synced in the background, use the user's synced permissions
+	// (if they are up to date) instead of querying the authz providers.
+	var (
+		filteredRepoNames map[api.RepoName]struct{}
+		synced            bool
+		err               error
+	)
+	if enabled, interval := BackgroundPermissionsSync(); enabled && currentUser != nil {
+		filteredRepoNames, synced, err = getSyncedRepoNames(ctx, currentUser, repos, p, interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !synced {
+		filteredRepoNames, err = getFilteredRepoNames(ctx, currentUser, authz.ToRepos(repos), p)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	filteredRepos := make([]*types.Repo, 0, len(filteredRepoNames))
@@ -91,7 +121,8 @@ func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos ma
 			break
 		}
 
-		// determine external account to use
+		// determine external account to use (unless the authz provider doesn't use external accounts)
+		userPermsProvider, usesUser := authzProvider.(authz.UserPermsProvider)
 		var providerAcct *extsvc.ExternalAccount
 		for _, acct := range accts {
 			if acct.ServiceID == authzProvider.ServiceID() && acct.ServiceType == authzProvider.ServiceType() {
@@ -99,7 +130,7 @@ func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos ma
 				break
 			}
 		}
-		if providerAcct == nil && currentUser != nil { // no existing external account for authz provider
+		if providerAcct == nil && currentUser != nil && !usesUser { // no existing external account for authz provider
 			if pr, err := authzProvider.FetchAccount(ctx, currentUser, accts); err == nil {
 				providerAcct = pr
 				if providerAcct != nil {
@@ -117,7 +148,12 @@ func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos ma
 		myUnverified, nextUnverified := authzProvider.Repos(ctx, unverified)
 
 		// check the perms on those repos
-		perms, err := authzProvider.RepoPerms(ctx, providerAcct, myUnverified)
+		var perms map[api.RepoName]map[authz.Perm]bool
+		if usesUser {
+			perms, err = userPermsProvider.UserRepoPerms(ctx, currentUser, myUnverified)
+		} else {
+			perms, err = authzProvider.RepoPerms(ctx, providerAcct, myUnverified)
+		}
 		if err != nil {
 			return nil, err
 		}
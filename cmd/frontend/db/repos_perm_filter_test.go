@@ -2,15 +2,19 @@ package db
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 type authzFilter_Test struct {
@@ -647,6 +651,171 @@ func Test_authzFilter_createsNewUsers(t *testing.T) {
 	}
 }
 
+func Test_authzFilter_accessTokenRepoAllowlist(t *testing.T) {
+	authz.SetProviders(true, nil)
+	defer authz.SetProviders(true, nil)
+	Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	defer func() { Mocks.Users.GetByCurrentAuthUser = nil }()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	repos := makeRepos("a", "b", "c")
+
+	// Without an allowlist, the site admin may access all repositories.
+	filteredRepos, err := authzFilter(authz.WithAccessTokenRestrictions(ctx, &authz.AccessTokenRestrictions{}), repos, authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(filteredRepos, repos) {
+		t.Errorf("got %d repos, want all %d", len(filteredRepos), len(repos))
+	}
+
+	// With an allowlist, even a site admin may only access the allowlisted repositories.
+	ctx = authz.WithAccessTokenRestrictions(ctx, &authz.AccessTokenRestrictions{RepoAllowlist: []api.RepoName{"a", "c", "x"}})
+	filteredRepos, err = authzFilter(ctx, repos, authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*types.Repo{repos[0], repos[2]}; !reflect.DeepEqual(filteredRepos, want) {
+		t.Errorf("got %+v, want %+v", filteredRepos, want)
+	}
+}
+
+func Test_authzFilter_syncedPermissions(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		PermissionsBackgroundSync: &schema.PermissionsBackgroundSync{Enabled: true, Interval: 60},
+	}})
+	defer conf.Mock(nil)
+	authz.SetProviders(true, []authz.Provider{&MockAuthzProvider{
+		serviceID:   "mock",
+		serviceType: "mock",
+		perms: map[extsvc.ExternalAccount]map[api.RepoName]map[authz.Perm]bool{
+			{}: {"b": {authz.Read: true}},
+		},
+		repos: map[api.RepoName]struct{}{"a": {}, "b": {}},
+	}})
+	defer authz.SetProviders(true, nil)
+	Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	Mocks.ExternalAccounts.List = func(ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) { return nil, nil }
+	defer func() {
+		Mocks.Users.GetByCurrentAuthUser = nil
+		Mocks.ExternalAccounts.List = nil
+		Mocks.UserPermissions = MockUserPermissions{}
+	}()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	syncedAt := time.Now().Add(-time.Minute)
+	repos := []*types.Repo{makeRepo("a", 1), makeRepo("b", 2), makeRepo("c", 3), makeRepo("d", 4)}
+	repos[3].CreatedAt = time.Now() // created since the last sync (unclaimed, so allowed by default)
+
+	// The synced permissions include repositories that aren't claimed by any authz provider (if
+	// they are allowed by default).
+	Mocks.UserPermissions.FilterRepoIDs = func(ctx context.Context, userID int32, perm authz.Perm, repoIDs []api.RepoID) ([]api.RepoID, error) {
+		if userID != 1 || perm != authz.Read {
+			t.Fatalf("unexpected FilterRepoIDs(%d, %q)", userID, perm)
+		}
+		if want := []api.RepoID{1, 2, 3}; !reflect.DeepEqual(repoIDs, want) {
+			t.Errorf("got repo IDs %v, want %v (repositories created since the sync)", repoIDs, want)
+		}
+		return []api.RepoID{1, 3}, nil
+	}
+
+	tests := map[string]struct {
+		syncedAt         time.Time
+		expFilteredRepos []*types.Repo
+	}{
+		"synced permissions are used instead of the authz provider": {
+			syncedAt:         syncedAt,
+			expFilteredRepos: []*types.Repo{repos[0], repos[2], repos[3]},
+		},
+		"never synced permissions fall back to the authz provider": {
+			expFilteredRepos: []*types.Repo{repos[1], repos[2], repos[3]},
+		},
+		"out-of-date synced permissions fall back to the authz provider": {
+			syncedAt:         time.Now().Add(-61 * time.Minute),
+			expFilteredRepos: []*types.Repo{repos[1], repos[2], repos[3]},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			Mocks.UserPermissions.SyncedAt = func(ctx context.Context, userID int32, perm authz.Perm) (time.Time, error) {
+				if userID != 1 || perm != authz.Read {
+					t.Fatalf("unexpected SyncedAt(%d, %q)", userID, perm)
+				}
+				return test.syncedAt, nil
+			}
+			filteredRepos, err := authzFilter(ctx, repos, authz.Read)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(filteredRepos, test.expFilteredRepos) {
+				t.Errorf("got %+v, want %+v", filteredRepos, test.expFilteredRepos)
+			}
+		})
+	}
+}
+
+func Test_authzFilter_userPermsProvider(t *testing.T) {
+	p := &mockUserPermsProvider{
+		MockAuthzProvider: MockAuthzProvider{
+			serviceID:   "explicit",
+			serviceType: "explicit",
+			repos:       map[api.RepoName]struct{}{"a": {}, "b": {}},
+		},
+		perms: map[int32]map[api.RepoName]map[authz.Perm]bool{
+			1: {"b": {authz.Read: true}},
+		},
+	}
+	authz.SetProviders(false, []authz.Provider{p})
+	defer authz.SetProviders(true, nil)
+	Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	Mocks.ExternalAccounts.List = func(ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) { return nil, nil }
+	Mocks.ExternalAccounts.AssociateUserAndSave = func(userID int32, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) error {
+		t.Fatalf("unexpected AssociateUserAndSave(%d, %+v)", userID, spec)
+		return nil
+	}
+	defer func() {
+		Mocks.Users.GetByCurrentAuthUser = nil
+		Mocks.ExternalAccounts.List = nil
+		Mocks.ExternalAccounts.AssociateUserAndSave = nil
+	}()
+
+	repos := makeRepos("a", "b", "c")
+	tests := map[string]struct {
+		ctx              context.Context
+		expFilteredRepos []*types.Repo
+	}{
+		"permitted user": {
+			ctx:              actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			expFilteredRepos: []*types.Repo{repos[1]},
+		},
+		"other user": {
+			ctx:              actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+			expFilteredRepos: []*types.Repo{},
+		},
+		"unauthenticated": {
+			ctx:              context.Background(),
+			expFilteredRepos: []*types.Repo{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			filteredRepos, err := authzFilter(test.ctx, repos, authz.Read)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(filteredRepos, test.expFilteredRepos) {
+				t.Errorf("got %+v, want %+v", filteredRepos, test.expFilteredRepos)
+			}
+		})
+	}
+}
+
 func acct(userID int32, serviceType, serviceID, accountID string) *extsvc.ExternalAccount {
 	return &extsvc.ExternalAccount{
 		UserID: userID,
@@ -762,3 +931,31 @@ func makeReposFromIDs(ids ...api.RepoID) []*types.Repo {
 	}
 	return repos
 }
+
+// mockUserPermsProvider is a mock authz.UserPermsProvider whose permissions are keyed by user ID.
+type mockUserPermsProvider struct {
+	MockAuthzProvider
+	perms map[int32]map[api.RepoName]map[authz.Perm]bool
+}
+
+func (m *mockUserPermsProvider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.ExternalAccount) (*extsvc.ExternalAccount, error) {
+	return nil, errors.New("FetchAccount must not be called for an authz.UserPermsProvider")
+}
+
+func (m *mockUserPermsProvider) RepoPerms(ctx context.Context, acct *extsvc.ExternalAccount, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	return nil, errors.New("RepoPerms must not be called for an authz.UserPermsProvider")
+}
+
+func (m *mockUserPermsProvider) UserRepoPerms(ctx context.Context, user *types.User, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	if user == nil {
+		return nil, nil
+	}
+	repos, _ = m.Repos(ctx, repos)
+	perms := make(map[api.RepoName]map[authz.Perm]bool)
+	for repo := range repos {
+		if repoPerms, ok := m.perms[user.ID][repo.RepoName]; ok {
+			perms[repo.RepoName] = repoPerms
+		}
+	}
+	return perms, nil
+}
@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// defaultPermissionsSyncInterval is the interval between syncs of each user's repository permissions
+// if none is configured.
+const defaultPermissionsSyncInterval = 60 * time.Minute
+
+// BackgroundPermissionsSync reports whether repository permissions are synced in the background
+// (per the "permissions.backgroundSync" site configuration property) and, if so, the interval
+// between syncs of each user's permissions.
+func BackgroundPermissionsSync() (enabled bool, interval time.Duration) {
+	cfg := conf.Get().PermissionsBackgroundSync
+	if cfg == nil || !cfg.Enabled {
+		return false, 0
+	}
+	if cfg.Interval <= 0 {
+		return true, defaultPermissionsSyncInterval
+	}
+	return true, time.Duration(cfg.Interval) * time.Minute
+}
+
+// getSyncedRepoNames is like getFilteredRepoNames, except that it uses the user's permissions as
+// last synced by SyncUserPermissions instead of querying the authz providers. Only repositories
+// created since the last sync (which aren't included in the synced permissions) are checked with the
+// authz providers.
+//
+// If the user's permissions have never been synced or have not been synced within the sync interval
+// (e.g., because the background sync is failing or falling behind), it returns synced == false and
+// the caller must fall back to getFilteredRepoNames.
+func getSyncedRepoNames(ctx context.Context, currentUser *types.User, repos []*types.Repo, p authz.Perm, interval time.Duration) (accepted map[api.RepoName]struct{}, synced bool, err error) {
+	syncedAt, synced, err := userPermissionsSyncedAt(ctx, currentUser, p, interval)
+	if err != nil || !synced {
+		return nil, false, err
+	}
+
+	var (
+		repoIDs   = make([]api.RepoID, 0, len(repos))
+		repoNames = make(map[api.RepoID]api.RepoName, len(repos))
+		newRepos  []*types.Repo
+	)
+	for _, repo := range repos {
+		if repo.CreatedAt.After(syncedAt) {
+			newRepos = append(newRepos, repo)
+			continue
+		}
+		repoIDs = append(repoIDs, repo.ID)
+		repoNames[repo.ID] = repo.Name
+	}
+
+	permitted, err := UserPermissions.FilterRepoIDs(ctx, currentUser.ID, p, repoIDs)
+	if err != nil {
+		return nil, false, err
+	}
+	accepted = make(map[api.RepoName]struct{}, len(permitted))
+	for _, id := range permitted {
+		accepted[repoNames[id]] = struct{}{}
+	}
+
+	if len(newRepos) > 0 {
+		newAccepted, err := getFilteredRepoNames(ctx, currentUser, authz.ToRepos(newRepos), p)
+		if err != nil {
+			return nil, false, err
+		}
+		for name := range newAccepted {
+			accepted[name] = struct{}{}
+		}
+	}
+	return accepted, true, nil
+}
+
+// userPermissionsSyncedAt returns when the user's permissions of the given type were last synced,
+// and whether they were synced within the sync interval (and can therefore be used instead of
+// querying the authz providers).
+func userPermissionsSyncedAt(ctx context.Context, user *types.User, p authz.Perm, interval time.Duration) (syncedAt time.Time, upToDate bool, err error) {
+	syncedAt, err = UserPermissions.SyncedAt(ctx, user.ID, p)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return syncedAt, !syncedAt.IsZero() && time.Since(syncedAt) <= interval, nil
+}
+
+// syncedPermissionsCondition returns a condition on the repo table that matches the repositories on
+// which the current user has the given permission per the user's synced permissions (plus those
+// created since the last sync), so that repository list queries can filter by the synced
+// permissions in SQL. It returns nil if authzFilter doesn't use synced permissions for the current
+// user.
+//
+// 🚨 SECURITY: This only narrows the query. The caller must still call authzFilter on the results.
+func syncedPermissionsCondition(ctx context.Context, p authz.Perm) (*sqlf.Query, error) {
+	if mockAuthzFilter != nil || isInternalActor(ctx) || !actor.FromContext(ctx).IsAuthenticated() {
+		return nil, nil
+	}
+	enabled, interval := BackgroundPermissionsSync()
+	if !enabled {
+		return nil, nil
+	}
+	currentUser, err := Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currentUser.SiteAdmin {
+		return nil, nil
+	}
+	syncedAt, synced, err := userPermissionsSyncedAt(ctx, currentUser, p, interval)
+	if err != nil || !synced {
+		return nil, err
+	}
+	return UserPermissions.reposCondition(currentUser.ID, p, syncedAt), nil
+}
+
+// permissionsSyncReposPageSize is the number of repositories to list at a time when syncing a user's
+// permissions.
+const permissionsSyncReposPageSize = 1000
+
+// computeUserPermissions computes the IDs of the repositories on which the user has read
+// permission by querying the authz providers. Repositories that aren't claimed by any authz provider
+// are included if authz providers allow access by default.
+func computeUserPermissions(ctx context.Context, user *types.User) ([]api.RepoID, error) {
+	// 🚨 SECURITY: List all repositories (not just those the current actor may access), because
+	// the permissions are computed for the given user and not the current actor.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	var repos []*types.Repo
+	for offset := 0; ; offset += permissionsSyncReposPageSize {
+		page, err := Repos.List(ctx, ReposListOptions{
+			Enabled:     true,
+			Disabled:    true,
+			LimitOffset: &LimitOffset{Limit: permissionsSyncReposPageSize, Offset: offset},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "listing repositories")
+		}
+		repos = append(repos, page...)
+		if len(page) < permissionsSyncReposPageSize {
+			break
+		}
+	}
+
+	accepted, err := getFilteredRepoNames(ctx, user, authz.ToRepos(repos), authz.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "computing repository permissions")
+	}
+	repoIDs := make([]api.RepoID, 0, len(accepted))
+	for _, repo := range repos {
+		if _, ok := accepted[repo.Name]; ok {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+	}
+	return repoIDs, nil
+}
+
+// SyncUserPermissions computes the user's read permissions on all repositories by querying the
+// authz providers and stores them in the database, where authzFilter uses them (instead of querying
+// the authz providers) when background permissions syncing is enabled. If the user's permissions
+// are already being synced by another process, it does nothing.
+func SyncUserPermissions(ctx context.Context, user *types.User) error {
+	_, err := UserPermissions.Sync(ctx, user.ID, authz.Read, func(ctx context.Context) ([]api.RepoID, error) {
+		return computeUserPermissions(ctx, user)
+	})
+	return err
+}
+
+// SyncStaleUserPermissions syncs the read permissions of a user whose permissions were last synced
+// before syncedBefore (or never), excluding the given users and users whose permissions are being
+// synced by another process. It returns the ID of the user whose permissions it synced (or failed to
+// sync), or 0 if there are no more such users.
+func SyncStaleUserPermissions(ctx context.Context, syncedBefore time.Time, excludeUserIDs []int32) (userID int32, err error) {
+	return UserPermissions.SyncStale(ctx, authz.Read, syncedBefore, excludeUserIDs, func(ctx context.Context, userID int32) ([]api.RepoID, error) {
+		user, err := Users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return computeUserPermissions(ctx, user)
+	})
+}
+
+// RefreshUserPermissions syncs the user's repository permissions in the background if background
+// permissions syncing is enabled. It is called when a user signs in, so that changes to the user's
+// permissions on the code host take effect without waiting for the next periodic sync.
+func RefreshUserPermissions(userID int32) {
+	if enabled, _ := BackgroundPermissionsSync(); !enabled {
+		return
+	}
+	goroutine.Go(func() {
+		ctx := context.Background()
+		user, err := Users.GetByID(ctx, userID)
+		if err != nil {
+			log15.Warn("Unable to get user for repository permissions sync.", "userID", userID, "error", err)
+			return
+		}
+		if user.SiteAdmin {
+			return // site admins may access all repositories
+		}
+		if err := SyncUserPermissions(ctx, user); err != nil {
+			log15.Warn("Unable to sync user repository permissions.", "userID", userID, "error", err)
+		}
+	})
+}
@@ -14,4 +14,6 @@ type MockSavedSearches struct {
 	Update                    func(ctx context.Context, savedSearch *types.SavedSearch) (*types.SavedSearch, error)
 	Delete                    func(ctx context.Context, id int32) error
 	GetByID                   func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error)
+	CreateWebhookDelivery     func(ctx context.Context, delivery *types.SavedSearchWebhookDelivery) error
+	ListWebhookDeliveries     func(ctx context.Context, savedSearchID int32, opt *LimitOffset) ([]*types.SavedSearchWebhookDelivery, error)
 }
@@ -381,3 +381,48 @@ func TestListSavedSearchesByUserID(t *testing.T) {
 	}
 
 }
+
+func TestSavedSearchesWebhookDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+	_, err := Users.Create(ctx, NewUser{DisplayName: "test", Email: "test@test.com", Username: "test", Password: "test", EmailVerificationCode: "c2"})
+	if err != nil {
+		t.Fatal("can't create user", err)
+	}
+	userID := int32(1)
+	webhookURL := "https://example.com/hook"
+	ss, err := SavedSearches.Create(ctx, &types.SavedSearch{
+		Query:         "test",
+		Description:   "test",
+		NotifyWebhook: true,
+		WebhookURL:    &webhookURL,
+		UserID:        &userID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statusCode := int32(500)
+	errMsg := "HTTP 500"
+	deliveries := []*types.SavedSearchWebhookDelivery{
+		{SavedSearchID: ss.ID, URL: webhookURL, ResultCount: 3, Attempts: 5, StatusCode: &statusCode, Error: &errMsg},
+		{SavedSearchID: ss.ID, URL: webhookURL, Test: true, Attempts: 1},
+	}
+	for _, d := range deliveries {
+		if err := SavedSearches.CreateWebhookDelivery(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := SavedSearches.ListWebhookDeliveries(ctx, ss.ID, &LimitOffset{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*types.SavedSearchWebhookDelivery{deliveries[1], deliveries[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got deliveries %+v, want %+v", got, want)
+	}
+}
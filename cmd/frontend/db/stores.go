@@ -1,20 +1,24 @@
 package db
 
 var (
-	AccessTokens              = &accessTokens{}
-	ExternalServices          = &ExternalServicesStore{}
-	DiscussionThreads         = &discussionThreads{}
-	DiscussionComments        = &discussionComments{}
-	DiscussionMailReplyTokens = &discussionMailReplyTokens{}
-	Repos                     = &repos{}
-	Phabricator               = &phabricator{}
-	QueryRunnerState          = &queryRunnerState{}
-	Orgs                      = &orgs{}
-	OrgMembers                = &orgMembers{}
-	SavedSearches             = &savedSearches{}
-	Settings                  = &settings{}
-	Users                     = &users{}
-	UserEmails                = &userEmails{}
+	AccessTokens               = &accessTokens{}
+	ExternalServices           = &ExternalServicesStore{}
+	ExplicitRepoPermissions    = &explicitRepoPermissions{}
+	DiscussionThreads          = &discussionThreads{}
+	DiscussionComments         = &discussionComments{}
+	DiscussionCommentReactions = &discussionCommentReactions{}
+	DiscussionMailReplyTokens  = &discussionMailReplyTokens{}
+	Repos                      = &repos{}
+	RepoGroups                 = &repoGroups{}
+	Phabricator                = &phabricator{}
+	QueryRunnerState           = &queryRunnerState{}
+	Orgs                       = &orgs{}
+	OrgMembers                 = &orgMembers{}
+	SavedSearches              = &savedSearches{}
+	Settings                   = &settings{}
+	Users                      = &users{}
+	UserEmails                 = &userEmails{}
+	UserPermissions            = &userPermissions{}
 
 	SurveyResponses = &surveyResponses{}
 
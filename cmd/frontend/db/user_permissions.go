@@ -0,0 +1,246 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// userPermissions provides access to the `user_permissions` and `user_repo_permissions` tables,
+// which store the repositories on which each user has a permission, as last synced from the authz
+// providers.
+//
+// For more information, see ./schema.md.
+type userPermissions struct{}
+
+// SyncedAt returns when the user's permissions of the given type were last synced, or the zero time
+// if they have never been synced.
+func (*userPermissions) SyncedAt(ctx context.Context, userID int32, perm authz.Perm) (time.Time, error) {
+	if Mocks.UserPermissions.SyncedAt != nil {
+		return Mocks.UserPermissions.SyncedAt(ctx, userID, perm)
+	}
+
+	var syncedAt *time.Time
+	err := dbconn.Global.QueryRowContext(ctx,
+		"SELECT synced_at FROM user_permissions WHERE user_id=$1 AND permission=$2",
+		userID, string(perm),
+	).Scan(&syncedAt)
+	if err == sql.ErrNoRows || (err == nil && syncedAt == nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *syncedAt, nil
+}
+
+// FilterRepoIDs returns the subset of repoIDs on which the user has the given permission, per the
+// user's synced permissions.
+//
+// 🚨 SECURITY: The caller must ensure that the permissions are not leaked to users other than the
+// user and site admins.
+func (*userPermissions) FilterRepoIDs(ctx context.Context, userID int32, perm authz.Perm, repoIDs []api.RepoID) ([]api.RepoID, error) {
+	if Mocks.UserPermissions.FilterRepoIDs != nil {
+		return Mocks.UserPermissions.FilterRepoIDs(ctx, userID, perm, repoIDs)
+	}
+	if len(repoIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(repoIDs))
+	for i, id := range repoIDs {
+		ids[i] = int64(id)
+	}
+	rows, err := dbconn.Global.QueryContext(ctx,
+		"SELECT repo_id FROM user_repo_permissions WHERE user_id=$1 AND permission=$2 AND repo_id = ANY($3)",
+		userID, string(perm), pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permitted []api.RepoID
+	for rows.Next() {
+		var id api.RepoID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		permitted = append(permitted, id)
+	}
+	return permitted, rows.Err()
+}
+
+// reposCondition returns a condition on the repo table that matches the repositories on which the
+// user has the given permission, per the user's permissions synced at syncedAt. Repositories created
+// after syncedAt are also matched, because they are not included in the synced permissions.
+func (*userPermissions) reposCondition(userID int32, perm authz.Perm, syncedAt time.Time) *sqlf.Query {
+	return sqlf.Sprintf(
+		"(repo.id IN (SELECT repo_id FROM user_repo_permissions WHERE user_id=%s AND permission=%s) OR repo.created_at > %s)",
+		userID, string(perm), syncedAt,
+	)
+}
+
+// userPermissionsSyncLease is how long a sync may hold the claim on a user's permissions. A claim
+// older than this is considered abandoned (e.g., because the process holding it died), and the
+// permissions can be claimed by another sync.
+const userPermissionsSyncLease = 10 * time.Minute
+
+// Sync replaces the user's synced permissions of the given type with the repositories returned by
+// compute, and records the time of the sync (the time at which compute was called).
+//
+// The user's permissions are claimed while compute runs, which is outside of any transaction
+// because compute may query code hosts for a long time. If they are already claimed (because they
+// are being synced by another process), compute is not called and Sync returns false. Sync also
+// returns false if compute outlived the claim, in which case its result is discarded.
+func (*userPermissions) Sync(ctx context.Context, userID int32, perm authz.Perm, compute func(ctx context.Context) ([]api.RepoID, error)) (synced bool, err error) {
+	if err := insertUserPermissions(ctx, perm, sqlf.Sprintf("id=%s", userID)); err != nil {
+		return false, err
+	}
+	var startedAt time.Time
+	err = dbconn.Global.QueryRowContext(ctx, `
+UPDATE user_permissions SET sync_started_at=now()
+WHERE user_id=$1 AND permission=$2
+  AND (sync_started_at IS NULL OR sync_started_at < now() - $3 * interval '1 second')
+RETURNING sync_started_at`,
+		userID, string(perm), userPermissionsSyncLease.Seconds(),
+	).Scan(&startedAt)
+	if err == sql.ErrNoRows {
+		return false, nil // claimed by another process
+	}
+	if err != nil {
+		return false, err
+	}
+	return syncUserPermissions(ctx, userID, perm, startedAt, compute)
+}
+
+// SyncStale is like Sync, except that it syncs the permissions of a non-site-admin user whose
+// permissions of the given type were last synced before syncedBefore (or never), excluding the
+// given users. Users whose permissions have never been synced are synced first, followed by those
+// that were synced least recently. Users whose permissions are claimed (because they are being
+// synced by another process) are skipped, so that multiple processes can sync stale permissions
+// concurrently without duplicating work.
+//
+// It returns the ID of the user whose permissions were synced (or whose compute call failed), or 0
+// if there are no users with stale permissions.
+func (*userPermissions) SyncStale(ctx context.Context, perm authz.Perm, syncedBefore time.Time, excludeUserIDs []int32, compute func(ctx context.Context, userID int32) ([]api.RepoID, error)) (userID int32, err error) {
+	exclude := make([]int64, len(excludeUserIDs))
+	for i, id := range excludeUserIDs {
+		exclude[i] = int64(id)
+	}
+	// Create the rows of users whose permissions have never been synced, so that they can be
+	// claimed.
+	if err := insertUserPermissions(ctx, perm, sqlf.Sprintf("NOT site_admin")); err != nil {
+		return 0, err
+	}
+	var startedAt time.Time
+	err = dbconn.Global.QueryRowContext(ctx, `
+UPDATE user_permissions SET sync_started_at=now()
+WHERE permission=$1 AND user_id=(
+  SELECT p.user_id FROM user_permissions p
+  JOIN users ON users.id=p.user_id
+  WHERE p.permission=$1 AND (p.synced_at IS NULL OR p.synced_at < $2) AND p.user_id <> ALL($3)
+    AND (p.sync_started_at IS NULL OR p.sync_started_at < now() - $4 * interval '1 second')
+    AND users.deleted_at IS NULL AND NOT users.site_admin
+  ORDER BY p.synced_at ASC NULLS FIRST, p.user_id ASC
+  LIMIT 1
+  FOR UPDATE OF p SKIP LOCKED
+)
+RETURNING user_id, sync_started_at`,
+		string(perm), syncedBefore, pq.Array(exclude), userPermissionsSyncLease.Seconds(),
+	).Scan(&userID, &startedAt)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	_, err = syncUserPermissions(ctx, userID, perm, startedAt, func(ctx context.Context) ([]api.RepoID, error) {
+		return compute(ctx, userID)
+	})
+	return userID, err
+}
+
+// insertUserPermissions creates (never synced) permissions of the given type for the users matching
+// cond that don't have any yet, so that they can be claimed for syncing.
+func insertUserPermissions(ctx context.Context, perm authz.Perm, cond *sqlf.Query) error {
+	q := sqlf.Sprintf(`
+INSERT INTO user_permissions(user_id, permission)
+SELECT id, %s::text FROM users
+WHERE deleted_at IS NULL AND %s AND NOT EXISTS (SELECT 1 FROM user_permissions p WHERE p.user_id=users.id AND p.permission=%s)
+ON CONFLICT DO NOTHING`,
+		string(perm), cond, string(perm),
+	)
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// syncUserPermissions calls compute for the user's permissions of the given type, which the caller
+// claimed at startedAt, and stores the result if the claim is still held. The claim is released in
+// either case. It reports whether the result was stored.
+func syncUserPermissions(ctx context.Context, userID int32, perm authz.Perm, startedAt time.Time, compute func(ctx context.Context) ([]api.RepoID, error)) (synced bool, err error) {
+	computeCtx, cancel := context.WithTimeout(ctx, userPermissionsSyncLease)
+	repoIDs, err := compute(computeCtx)
+	cancel()
+	if err != nil {
+		if _, err := dbconn.Global.ExecContext(ctx,
+			"UPDATE user_permissions SET sync_started_at=NULL WHERE user_id=$1 AND permission=$2 AND sync_started_at=$3",
+			userID, string(perm), startedAt,
+		); err != nil {
+			log15.Warn("Unable to release claim on user permissions.", "userID", userID, "error", err)
+		}
+		return false, err
+	}
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		var err error
+		synced, err = setUserPermissions(ctx, tx, userID, perm, startedAt, repoIDs)
+		return err
+	})
+	return synced, err
+}
+
+// setUserPermissions replaces the user's synced permissions of the given type with the given set of
+// repositories, records startedAt as the time of the sync and releases the claim on the
+// permissions. If the permissions are no longer claimed at startedAt (because the claim expired and
+// another sync claimed them), it does nothing and returns false.
+func setUserPermissions(ctx context.Context, tx *sql.Tx, userID int32, perm authz.Perm, startedAt time.Time, repoIDs []api.RepoID) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		"UPDATE user_permissions SET synced_at=sync_started_at, sync_started_at=NULL WHERE user_id=$1 AND permission=$2 AND sync_started_at=$3",
+		userID, string(perm), startedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	ids := make([]int64, len(repoIDs))
+	for i, id := range repoIDs {
+		ids[i] = int64(id)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_repo_permissions WHERE user_id=$1 AND permission=$2", userID, string(perm)); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO user_repo_permissions(user_id, permission, repo_id)
+SELECT $1::integer, $2::text, repo.id FROM repo WHERE repo.id = ANY($3)`,
+		userID, string(perm), pq.Array(ids),
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type MockUserPermissions struct {
+	SyncedAt      func(ctx context.Context, userID int32, perm authz.Perm) (time.Time, error)
+	FilterRepoIDs func(ctx context.Context, userID int32, perm authz.Perm, repoIDs []api.RepoID) ([]api.RepoID, error)
+}
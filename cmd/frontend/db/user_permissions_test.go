@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestUserPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+
+	var userIDs []int32
+	for i := 0; i < 3; i++ {
+		user, err := Users.Create(ctx, NewUser{Username: fmt.Sprintf("u%d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	if err := Users.SetIsSiteAdmin(ctx, userIDs[0], true); err != nil {
+		t.Fatal(err)
+	}
+	var repoIDs []api.RepoID
+	for i := 0; i < 3; i++ {
+		name := api.RepoName(fmt.Sprintf("r%d", i))
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+		repo, err := Repos.GetByName(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repoIDs = append(repoIDs, repo.ID)
+	}
+
+	filter := func(userID int32) []api.RepoID {
+		t.Helper()
+		ids, err := UserPermissions.FilterRepoIDs(ctx, userID, authz.Read, repoIDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+
+	// Never synced.
+	syncedAt, err := UserPermissions.SyncedAt(ctx, userIDs[1], authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !syncedAt.IsZero() {
+		t.Errorf("got synced at %v, want zero", syncedAt)
+	}
+
+	for _, ids := range [][]api.RepoID{repoIDs[:2], repoIDs[1:]} {
+		ids := ids
+		synced, err := UserPermissions.Sync(ctx, userIDs[1], authz.Read, func(context.Context) ([]api.RepoID, error) { return ids, nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !synced {
+			t.Error("got synced == false, want true")
+		}
+	}
+	if got, want := filter(userIDs[1]), repoIDs[1:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got permitted repo IDs %v, want %v", got, want)
+	}
+	if syncedAt, err := UserPermissions.SyncedAt(ctx, userIDs[1], authz.Read); err != nil {
+		t.Fatal(err)
+	} else if syncedAt.IsZero() {
+		t.Error("got zero synced at, want non-zero")
+	}
+
+	// Permissions being synced are claimed, so concurrent syncs of the same user do nothing.
+	synced, err := UserPermissions.Sync(ctx, userIDs[1], authz.Read, func(ctx context.Context) ([]api.RepoID, error) {
+		synced, err := UserPermissions.Sync(ctx, userIDs[1], authz.Read, func(context.Context) ([]api.RepoID, error) {
+			t.Error("compute called for claimed permissions")
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if synced {
+			t.Error("got concurrent synced == true, want false")
+		}
+		return repoIDs[1:], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !synced {
+		t.Error("got synced == false, want true")
+	}
+
+	// Site admins are never stale, and users whose permissions have never been synced are synced
+	// first.
+	syncStale := func(syncedBefore time.Time, exclude []int32, err error) int32 {
+		t.Helper()
+		userID, gotErr := UserPermissions.SyncStale(ctx, authz.Read, syncedBefore, exclude, func(ctx context.Context, userID int32) ([]api.RepoID, error) {
+			return repoIDs[:1], err
+		})
+		if gotErr != err {
+			t.Fatalf("got error %v, want %v", gotErr, err)
+		}
+		return userID
+	}
+	if got, want := syncStale(time.Now().Add(-time.Hour), nil, nil), userIDs[2]; got != want {
+		t.Errorf("got synced user %d, want %d", got, want)
+	}
+	if got, want := filter(userIDs[2]), repoIDs[:1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got permitted repo IDs %v, want %v", got, want)
+	}
+	if got := syncStale(time.Now().Add(-time.Hour), nil, nil); got != 0 {
+		t.Errorf("got synced user %d, want none", got)
+	}
+
+	// Failed syncs leave the permissions unchanged, and excluded users are skipped.
+	errSync := errors.New("x")
+	if got, want := syncStale(time.Now().Add(time.Hour), nil, errSync), userIDs[1]; got != want {
+		t.Errorf("got synced user %d, want %d", got, want)
+	}
+	if got, want := filter(userIDs[1]), repoIDs[1:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got permitted repo IDs %v, want %v", got, want)
+	}
+	if got, want := syncStale(time.Now().Add(time.Hour), []int32{userIDs[1]}, nil), userIDs[2]; got != want {
+		t.Errorf("got synced user %d, want %d", got, want)
+	}
+
+	// Users whose permissions are being synced are skipped.
+	userID, err := UserPermissions.SyncStale(ctx, authz.Read, time.Now().Add(time.Hour), nil, func(ctx context.Context, userID int32) ([]api.RepoID, error) {
+		if got, want := syncStale(time.Now().Add(time.Hour), nil, nil), userIDs[2]; got != want {
+			t.Errorf("got concurrently synced user %d, want %d", got, want)
+		}
+		return repoIDs[1:], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != userIDs[1] {
+		t.Errorf("got synced user %d, want %d", userID, userIDs[1])
+	}
+
+	// Repository list queries filter by the synced permissions.
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		PermissionsBackgroundSync: &schema.PermissionsBackgroundSync{Enabled: true, Interval: 60},
+	}})
+	defer conf.Mock(nil)
+	userCtx := actor.WithActor(ctx, &actor.Actor{UID: userIDs[1]})
+	repos, err := Repos.List(userCtx, ReposListOptions{Enabled: true, LimitOffset: &LimitOffset{Limit: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].ID != repoIDs[1] {
+		t.Errorf("got repos %+v, want only repo %d", repos, repoIDs[1])
+	}
+}
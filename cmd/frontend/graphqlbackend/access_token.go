@@ -71,3 +71,22 @@ func (r *accessTokenResolver) LastUsedAt() *string {
 	t := r.accessToken.LastUsedAt.Format(time.RFC3339)
 	return &t
 }
+
+func (r *accessTokenResolver) ExpiresAt() *string {
+	if r.accessToken.ExpiresAt == nil {
+		return nil
+	}
+	t := r.accessToken.ExpiresAt.Format(time.RFC3339)
+	return &t
+}
+
+func (r *accessTokenResolver) RepoAllowlist() *[]string {
+	if len(r.accessToken.RepoAllowlist) == 0 {
+		return nil
+	}
+	names := make([]string, len(r.accessToken.RepoAllowlist))
+	for i, name := range r.accessToken.RepoAllowlist {
+		names[i] = string(name)
+	}
+	return &names
+}
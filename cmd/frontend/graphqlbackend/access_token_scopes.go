@@ -0,0 +1,236 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+)
+
+// accessTokenScopeFields declares the top-level query and mutation fields that each fine-grained
+// access token scope permits. A request authenticated with an access token that lacks the
+// "user:all" scope may only select the fields permitted by the access token's scopes.
+//
+// Nested fields are not restricted by this list. Resolvers of nested fields that expose data that
+// the scope of a permitted top-level field does not imply (such as a user's emails, which are
+// reachable from many places) MUST call checkAccessTokenScope.
+//
+// 🚨 SECURITY: Fields that are not listed here may only be used with the "user:all" scope. Be
+// careful when adding fields: any data reachable from them is accessible to tokens with the scope.
+// In particular, do not add fields that return arbitrary nodes or settings subjects (such as
+// Query.node and Query.settingsSubject).
+var accessTokenScopeFields = map[string][]string{
+	authz.ScopeSearchRead: {
+		"Query.search",
+		"Query.savedSearches",
+		"Query.repoGroups",
+	},
+	authz.ScopeRepoRead: {
+		"Query.repository",
+		"Query.repositories",
+	},
+	authz.ScopeSettingsWrite: {
+		"Query.viewerSettings",
+		"Query.viewerConfiguration",
+		"Mutation.settingsMutation",
+		"Mutation.configurationMutation",
+	},
+	authz.ScopeDiscussionsWrite: {
+		"Query.discussionThreads",
+		"Query.discussionComments",
+		"Mutation.discussions",
+	},
+}
+
+// accessTokenScopeError is the error returned by resolvers when the access token that
+// authenticated the request lacks the scope that the field requires.
+type accessTokenScopeError struct {
+	scope string
+}
+
+func (e *accessTokenScopeError) Error() string {
+	return fmt.Sprintf("not permitted by the access token's scopes (requires the %q scope)", e.scope)
+}
+
+// checkAccessTokenScope returns an error if the request was authenticated with an access token that
+// does not have the scope (either directly or because it has the "user:all" scope). Resolvers call
+// it to declare the scope that their field requires. Fields that no fine-grained scope permits
+// require authz.ScopeUserAll.
+func checkAccessTokenScope(ctx context.Context, scope string) error {
+	if r := authz.AccessTokenRestrictionsFromContext(ctx); r != nil && !r.HasScope(scope) {
+		return &accessTokenScopeError{scope: scope}
+	}
+	return nil
+}
+
+// CheckAccessTokenScopes checks that the GraphQL query only selects top-level fields that are
+// permitted by the scopes of the access token that authenticated the request (if any). It returns
+// the errors to respond with if the query is not permitted.
+//
+// The check validates the query against a copy of the schema whose Query and Mutation types only
+// have the permitted fields, so it handles aliases, fragments, and multiple operations the same way
+// as the GraphQL server itself.
+func CheckAccessTokenScopes(ctx context.Context, queryString string) []*gqlerrors.QueryError {
+	r := authz.AccessTokenRestrictionsFromContext(ctx)
+	if r == nil || r.HasScope(authz.ScopeUserAll) {
+		return nil
+	}
+
+	// Let the GraphQL server report errors in queries that are invalid regardless of scopes.
+	if errs := GraphQLSchema.Validate(queryString); len(errs) > 0 {
+		return nil
+	}
+
+	schema, err := scopedSchema(r.Scopes)
+	if err != nil {
+		return []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}
+	}
+	errs := schema.Validate(queryString)
+	for _, err := range errs {
+		err.Message += " (not permitted by the access token's scopes)"
+	}
+	return errs
+}
+
+var (
+	scopedSchemasMu sync.Mutex
+	scopedSchemas   = map[string]*graphql.Schema{} // keyed by the sorted, comma-separated scopes
+)
+
+// scopedSchema returns the schema with only the Query and Mutation fields that are permitted by
+// the access token scopes.
+func scopedSchema(scopes []string) (*graphql.Schema, error) {
+	var apiScopes []string
+	allowedFields := map[string]bool{}
+	for _, scope := range scopes {
+		if fields, ok := accessTokenScopeFields[scope]; ok {
+			apiScopes = append(apiScopes, scope)
+			for _, field := range fields {
+				allowedFields[field] = true
+			}
+		}
+	}
+	sort.Strings(apiScopes)
+	key := strings.Join(apiScopes, ",")
+
+	scopedSchemasMu.Lock()
+	defer scopedSchemasMu.Unlock()
+	if schema, ok := scopedSchemas[key]; ok {
+		return schema, nil
+	}
+	schema, err := graphql.ParseSchema(restrictSchemaFields(Schema, allowedFields), nil)
+	if err != nil {
+		return nil, err
+	}
+	scopedSchemas[key] = schema
+	return schema, nil
+}
+
+// restrictSchemaFields returns the schema with only the allowed fields (of the form
+// "Query.search") of the Query and Mutation types. It scans the schema's tokens (instead of relying
+// on its formatting), so that arguments, default values, and directives are never mistaken for
+// fields. A field's doc comment is removed along with the field.
+func restrictSchemaFields(schema string, allowedFields map[string]bool) string {
+	var (
+		b        strings.Builder
+		copied   int    // the offset up to which the schema has been written to b (or skipped)
+		typeName string // the name of the root type whose fields are being filtered (if any)
+		field    string // the name of the current field of the root type (if any)
+		depth    int    // the nesting depth of braces, brackets, and parentheses
+		prev     string // the previous token
+		prevPrev string // the token before the previous token
+		prevEnd  int    // the offset of the end of the previous token
+	)
+	// fieldEnd returns the offset where the current field ends: at the end of the line of its last
+	// token, so that the next field's doc comment belongs to the next field.
+	fieldEnd := func(next int) int {
+		if i := strings.IndexByte(schema[prevEnd:next], '\n'); i >= 0 {
+			return prevEnd + i + 1
+		}
+		return next
+	}
+	// endField writes the current field to b (unless it is not allowed) up to the offset.
+	endField := func(end int) {
+		if field == "" || allowedFields[typeName+"."+field] {
+			b.WriteString(schema[copied:end])
+		}
+		copied = end
+		field = ""
+	}
+	isNameChar := func(c byte) bool {
+		return c == '_' || ('0' <= c && c <= '9') || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')
+	}
+
+	for i := 0; i < len(schema); {
+		start := i
+		token := schema[i : i+1]
+		switch c := schema[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+			continue
+
+		case c == '#': // comment
+			if j := strings.IndexByte(schema[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(schema)
+			}
+			continue
+
+		case strings.HasPrefix(schema[i:], `"""`): // block string
+			if j := strings.Index(schema[i+3:], `"""`); j >= 0 {
+				i += 3 + j + 3
+			} else {
+				i = len(schema)
+			}
+			token = `""`
+
+		case c == '"': // string
+			for i++; i < len(schema) && schema[i] != '"' && schema[i] != '\n'; i++ {
+				if schema[i] == '\\' {
+					i++
+				}
+			}
+			i++
+			token = `""`
+
+		case isNameChar(c):
+			for i < len(schema) && isNameChar(schema[i]) {
+				i++
+			}
+			token = schema[start:i]
+			if typeName != "" && depth == 1 && prev != ":" && prev != "@" && prev != "[" && !('0' <= c && c <= '9') {
+				// A name at the top level of a root type's body that is not (part of) a type or a
+				// directive starts a field.
+				endField(fieldEnd(start))
+				field = token
+			}
+
+		case c == '{' || c == '(' || c == '[':
+			if c == '{' && depth == 0 && prevPrev == "type" && (prev == "Query" || prev == "Mutation") {
+				typeName = prev
+			}
+			depth++
+			i++
+
+		case c == '}' || c == ')' || c == ']':
+			depth--
+			if c == '}' && depth == 0 && typeName != "" {
+				endField(fieldEnd(start))
+				typeName = ""
+			}
+			i++
+
+		default:
+			i++
+		}
+		prevPrev, prev, prevEnd = prev, token, i
+	}
+	b.WriteString(schema[copied:])
+	return b.String()
+}
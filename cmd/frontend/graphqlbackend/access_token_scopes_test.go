@@ -0,0 +1,147 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestAccessTokenScopeFields(t *testing.T) {
+	// Check that all fields permitted by scopes exist, to catch typos.
+	none := restrictSchemaFields(Schema, nil)
+	for scope, fields := range accessTokenScopeFields {
+		if !authz.IsAPIScope(scope) {
+			t.Errorf("scope %q is not an API scope", scope)
+		}
+		for _, field := range fields {
+			if restrictSchemaFields(Schema, map[string]bool{field: true}) == none {
+				t.Errorf("scope %q: field %q does not exist in the schema", scope, field)
+			}
+		}
+	}
+}
+
+// 🚨 SECURITY: This tests that access tokens with fine-grained scopes may only use the permitted
+// parts of the GraphQL API.
+func TestCheckAccessTokenScopes(t *testing.T) {
+	withScopes := func(scopes ...string) context.Context {
+		return authz.WithAccessTokenRestrictions(context.Background(), &authz.AccessTokenRestrictions{Scopes: scopes})
+	}
+	tests := map[string]struct {
+		ctx       context.Context
+		query     string
+		wantError bool
+	}{
+		"no access token": {
+			ctx:   context.Background(),
+			query: `{ currentUser { username } }`,
+		},
+		"user:all": {
+			ctx:   withScopes(authz.ScopeUserAll),
+			query: `{ currentUser { username } }`,
+		},
+		"permitted field": {
+			ctx:   withScopes(authz.ScopeSearchRead),
+			query: `{ search(query: "x") { __typename } }`,
+		},
+		"permitted fields of multiple scopes": {
+			ctx:   withScopes(authz.ScopeSearchRead, authz.ScopeSettingsWrite),
+			query: `{ search(query: "x") { __typename } viewerSettings { __typename } }`,
+		},
+		"field not permitted": {
+			ctx:       withScopes(authz.ScopeSearchRead),
+			query:     `{ search(query: "x") { __typename } currentUser { username } }`,
+			wantError: true,
+		},
+		"field not permitted, with alias": {
+			ctx:       withScopes(authz.ScopeSearchRead),
+			query:     `{ search: currentUser { username } }`,
+			wantError: true,
+		},
+		"field not permitted, in fragment": {
+			ctx:       withScopes(authz.ScopeSearchRead),
+			query:     `query { ...F } fragment F on Query { currentUser { username } }`,
+			wantError: true,
+		},
+		"settingsSubject not permitted by settings:write": {
+			ctx:       withScopes(authz.ScopeSettingsWrite),
+			query:     `{ settingsSubject(id: "x") { __typename } }`,
+			wantError: true,
+		},
+		"mutation not permitted": {
+			ctx:       withScopes(authz.ScopeSearchRead),
+			query:     `mutation { reloadSite { alwaysNil } }`,
+			wantError: true,
+		},
+		"sudo scope does not permit fields": {
+			ctx:       withScopes(authz.ScopeSiteAdminSudo),
+			query:     `{ search(query: "x") { __typename } }`,
+			wantError: true,
+		},
+		"invalid query is left to the GraphQL server": {
+			ctx:   withScopes(authz.ScopeSearchRead),
+			query: `{ doesNotExist }`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := CheckAccessTokenScopes(test.ctx, test.query)
+			if (len(errs) > 0) != test.wantError {
+				t.Errorf("got errors %v, want error %v", errs, test.wantError)
+			}
+		})
+	}
+}
+
+func TestRestrictSchemaFields(t *testing.T) {
+	schema := `schema { query: Query }
+type Query {
+  # Doc comment of a.
+  a(x: Int = 1, y: [String!] = ["b: Int"]): Int @deprecated(reason: "use c")
+  # Doc comment of b.
+  b: Int
+	c(
+		# Doc comment of x.
+		x: Foo = {d: 1}
+	): [Int!]! }
+type Foo {
+  d: Int
+}
+`
+	got := restrictSchemaFields(schema, map[string]bool{"Query.b": true})
+	want := `schema { query: Query }
+type Query {
+  # Doc comment of b.
+  b: Int
+}
+type Foo {
+  d: Int
+}
+`
+	if got != want {
+		t.Errorf("got schema\n%s\nwant\n%s", got, want)
+	}
+}
+
+// 🚨 SECURITY: This tests that access tokens with fine-grained scopes may not read a user's private
+// data, which is reachable from fields that the scopes permit.
+func TestUserResolver_accessTokenScopes(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	ctx = authz.WithAccessTokenRestrictions(ctx, &authz.AccessTokenRestrictions{Scopes: authz.APIScopes})
+	user := &UserResolver{user: &types.User{ID: 1}}
+	if _, err := user.Emails(ctx); err == nil {
+		t.Error("Emails: got nil error, want access token scope error")
+	}
+	if _, err := user.Session(ctx); err == nil {
+		t.Error("Session: got nil error, want access token scope error")
+	}
+	if _, err := user.AccessTokens(ctx, nil); err == nil {
+		t.Error("AccessTokens: got nil error, want access token scope error")
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
@@ -13,13 +15,16 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User          graphql.ID
+	Scopes        []string
+	Note          string
+	ExpiresAt     *string
+	RepoAllowlist *[]string
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
@@ -46,14 +51,14 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	var hasUserScope bool // whether the token has "user:all" or a fine-grained scope
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
-		switch scope {
-		case authz.ScopeUserAll:
-			hasUserAllScope = true
-		case authz.ScopeSiteAdminSudo:
+		switch {
+		case scope == authz.ScopeUserAll || authz.IsAPIScope(scope):
+			hasUserScope = true
+		case scope == authz.ScopeSiteAdminSudo:
 			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
 			if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 				return nil, err
@@ -67,11 +72,36 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, fmt.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	if !hasUserScope {
+		return nil, fmt.Errorf("all access tokens must have scope %q or at least one of the scopes %q", authz.ScopeUserAll, authz.APIScopes)
 	}
 
-	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
+	// Validate the expiry date and repository allowlist.
+	var expiresAt *time.Time
+	if args.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *args.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid access token expiry date %q (must be in RFC 3339 format)", *args.ExpiresAt)
+		}
+		if !t.After(time.Now()) {
+			return nil, fmt.Errorf("access token expiry date %q must be in the future", *args.ExpiresAt)
+		}
+		expiresAt = &t
+	}
+	var repoAllowlist []api.RepoName
+	if args.RepoAllowlist != nil {
+		if len(*args.RepoAllowlist) == 0 {
+			return nil, errors.New("access token repository allowlist must not be empty (omit it to allow access to all repositories)")
+		}
+		for _, name := range *args.RepoAllowlist {
+			if strings.TrimSpace(name) == "" {
+				return nil, errors.New("access token repository allowlist must not contain empty repository names")
+			}
+			repoAllowlist = append(repoAllowlist, api.RepoName(name))
+		}
+	}
+
+	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID, expiresAt, repoAllowlist)
 	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
 }
 
@@ -147,6 +177,11 @@ func (r *siteResolver) AccessTokens(ctx context.Context, args *struct {
 func (r *UserResolver) AccessTokens(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) (*accessTokenConnectionResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's access tokens,
+	// because users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
 	// 🚨 SECURITY: Only site admins and the user can list a user's access tokens.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
@@ -12,12 +13,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 )
 
 // 🚨 SECURITY: This tests that users can't create tokens for users they aren't allowed to do so for.
 func TestMutation_CreateAccessToken(t *testing.T) {
 	mockAccessTokensCreate := func(t *testing.T, wantCreatorUserID int32, wantScopes []string) {
-		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (int64, string, error) {
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time, repoAllowlist []api.RepoName) (int64, string, error) {
 			if want := int32(1); subjectUserID != want {
 				t.Errorf("got %v, want %v", subjectUserID, want)
 			}
@@ -76,6 +78,56 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticated as user, using fine-grained scopes with expiry and repository allowlist", func(t *testing.T) {
+		resetMocks()
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, gotExpiresAt *time.Time, repoAllowlist []api.RepoName) (int64, string, error) {
+			if want := []string{authz.ScopeRepoRead, authz.ScopeSearchRead}; !reflect.DeepEqual(scopes, want) {
+				t.Errorf("got %q, want %q", scopes, want)
+			}
+			if gotExpiresAt == nil || !gotExpiresAt.Equal(expiresAt) {
+				t.Errorf("got expiresAt %v, want %v", gotExpiresAt, expiresAt)
+			}
+			if want := []api.RepoName{"github.com/foo/bar"}; !reflect.DeepEqual(repoAllowlist, want) {
+				t.Errorf("got repo allowlist %q, want %q", repoAllowlist, want)
+			}
+			return 1, "t", nil
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		expiresAtStr := expiresAt.Format(time.RFC3339)
+		repoAllowlist := []string{"github.com/foo/bar"}
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:          uid1GQLID,
+			Scopes:        []string{authz.ScopeSearchRead, authz.ScopeRepoRead},
+			Note:          "n",
+			ExpiresAt:     &expiresAtStr,
+			RepoAllowlist: &repoAllowlist,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		pastStr := time.Now().Add(-time.Hour).Format(time.RFC3339)
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:      uid1GQLID,
+			Scopes:    []string{authz.ScopeSearchRead},
+			Note:      "n",
+			ExpiresAt: &pastStr,
+		}); err == nil {
+			t.Error("want error for expiry date in the past")
+		}
+
+		emptyAllowlist := []string{}
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:          uid1GQLID,
+			Scopes:        []string{authz.ScopeSearchRead},
+			Note:          "n",
+			RepoAllowlist: &emptyAllowlist,
+		}); err == nil {
+			t.Error("want error for empty repository allowlist")
+		}
+	})
+
 	t.Run("authenticated as user, using site-admin-only scopes", func(t *testing.T) {
 		resetMocks()
 		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
@@ -9,6 +9,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -117,10 +118,64 @@ func (r *discussionCommentResolver) CanDelete(ctx context.Context) bool {
 	return true
 }
 
+func (r *discussionCommentResolver) Reactions(ctx context.Context) ([]*discussionCommentReactionGroupResolver, error) {
+	reactions, err := db.DiscussionCommentReactions.List(ctx, r.c.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionCommentReactions.List")
+	}
+	currentUser, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the reactions by emoji, in the order that each emoji was first
+	// used.
+	var groups []*discussionCommentReactionGroupResolver
+	byEmoji := map[string]*discussionCommentReactionGroupResolver{}
+	for _, reaction := range reactions {
+		group, ok := byEmoji[reaction.Emoji]
+		if !ok {
+			group = &discussionCommentReactionGroupResolver{emoji: reaction.Emoji}
+			byEmoji[reaction.Emoji] = group
+			groups = append(groups, group)
+		}
+		group.userIDs = append(group.userIDs, reaction.UserID)
+		if currentUser != nil && currentUser.user.ID == reaction.UserID {
+			group.viewerHasReacted = true
+		}
+	}
+	return groups, nil
+}
+
+type discussionCommentReactionGroupResolver struct {
+	emoji            string
+	userIDs          []int32
+	viewerHasReacted bool
+}
+
+func (r *discussionCommentReactionGroupResolver) Emoji() string { return r.emoji }
+func (r *discussionCommentReactionGroupResolver) Count() int32  { return int32(len(r.userIDs)) }
+func (r *discussionCommentReactionGroupResolver) Users(ctx context.Context) ([]*UserResolver, error) {
+	users := make([]*UserResolver, 0, len(r.userIDs))
+	for _, userID := range r.userIDs {
+		user, err := UserByIDInt32(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+func (r *discussionCommentReactionGroupResolver) ViewerHasReacted() bool { return r.viewerHasReacted }
+
 func (*schemaResolver) DiscussionComments(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 	AuthorUserID *graphql.ID
 }) (*discussionCommentsConnectionResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "discussions:write" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeDiscussionsWrite); err != nil {
+		return nil, err
+	}
 	if err := viewerCanUseDiscussions(ctx); err != nil {
 		return nil, err
 	}
@@ -151,7 +206,7 @@ func checkSignedInAndEmailVerified(ctx context.Context) (*UserResolver, error) {
 	if currentUser == nil {
 		return nil, errors.New("no current user")
 	}
-	emails, err := currentUser.Emails(ctx)
+	emails, err := currentUser.emails(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "Emails")
 	}
@@ -202,11 +257,13 @@ func (r *discussionsMutationResolver) AddCommentToThread(ctx context.Context, ar
 
 func (r *discussionsMutationResolver) UpdateComment(ctx context.Context, args *struct {
 	Input *struct {
-		CommentID    graphql.ID
-		Contents     *string
-		Delete       *bool
-		Report       *string
-		ClearReports *bool
+		CommentID      graphql.ID
+		Contents       *string
+		Delete         *bool
+		Report         *string
+		ClearReports   *bool
+		AddReaction    *string
+		RemoveReaction *string
 	}
 }) (*discussionThreadResolver, error) {
 	commentID, err := unmarshalDiscussionID(args.Input.CommentID)
@@ -273,6 +330,19 @@ func (r *discussionsMutationResolver) UpdateComment(ctx context.Context, args *s
 	}
 	threadID := comment.ThreadID
 
+	// 🚨 SECURITY: Any signed in user may react to a comment, but only on
+	// their own behalf.
+	if args.Input.AddReaction != nil {
+		if err := db.DiscussionCommentReactions.Add(ctx, commentID, currentUser.user.ID, *args.Input.AddReaction); err != nil {
+			return nil, errors.Wrap(err, "DiscussionCommentReactions.Add")
+		}
+	}
+	if args.Input.RemoveReaction != nil {
+		if err := db.DiscussionCommentReactions.Remove(ctx, commentID, currentUser.user.ID, *args.Input.RemoveReaction); err != nil {
+			return nil, errors.Wrap(err, "DiscussionCommentReactions.Remove")
+		}
+	}
+
 	updatedComment, err := db.DiscussionComments.Update(ctx, commentID, &db.DiscussionCommentsUpdateOptions{
 		Contents:     args.Input.Contents,
 		Delete:       delete,
@@ -10,6 +10,8 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -18,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -269,9 +272,11 @@ func (r *discussionsMutationResolver) CreateThread(ctx context.Context, args *st
 
 func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *struct {
 	Input *struct {
-		ThreadID graphql.ID
-		Archive  *bool
-		Delete   *bool
+		ThreadID  graphql.ID
+		Archive   *bool
+		Delete    *bool
+		Resolve   *bool
+		Assignees *[]graphql.ID
 	}
 }) (*discussionThreadResolver, error) {
 	// 🚨 SECURITY: Only signed in users may update a discussion thread.
@@ -296,9 +301,42 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 	if err != nil {
 		return nil, err
 	}
+
+	var (
+		assigneeUserIDs    *[]int32
+		newAssigneeUserIDs []int32
+	)
+	if args.Input.Assignees != nil {
+		oldThread, err := db.DiscussionThreads.Get(ctx, threadID)
+		if err != nil {
+			return nil, errors.Wrap(err, "DiscussionThreads.Get")
+		}
+		ids := make([]int32, 0, len(*args.Input.Assignees))
+		for _, id := range *args.Input.Assignees {
+			userID, err := UnmarshalUserID(id)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := db.Users.GetByID(ctx, userID); err != nil {
+				return nil, err
+			}
+			if containsInt32(ids, userID) {
+				continue
+			}
+			ids = append(ids, userID)
+			if !containsInt32(oldThread.AssigneeUserIDs, userID) {
+				newAssigneeUserIDs = append(newAssigneeUserIDs, userID)
+			}
+		}
+		assigneeUserIDs = &ids
+	}
+
 	thread, err := db.DiscussionThreads.Update(ctx, threadID, &db.DiscussionThreadsUpdateOptions{
-		Archive: args.Input.Archive,
-		Delete:  delete,
+		Archive:          args.Input.Archive,
+		Delete:           delete,
+		Resolve:          args.Input.Resolve,
+		ResolvedByUserID: currentUser.user.ID,
+		AssigneeUserIDs:  assigneeUserIDs,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "DiscussionThreads.Update")
@@ -307,10 +345,27 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 		// deleted
 		return nil, nil
 	}
+	if args.Input.Resolve != nil && *args.Input.Resolve {
+		discussions.NotifyThreadResolved(thread, currentUser.user)
+	}
+	discussions.NotifyThreadAssigned(thread, currentUser.user, newAssigneeUserIDs)
 	return &discussionThreadResolver{t: thread}, nil
 }
 
+func containsInt32(list []int32, v int32) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
 func (*schemaResolver) Discussions(ctx context.Context) (*discussionsMutationResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "discussions:write" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeDiscussionsWrite); err != nil {
+		return nil, err
+	}
 	if err := viewerCanUseDiscussions(ctx); err != nil {
 		return nil, err
 	}
@@ -327,6 +382,10 @@ func (*schemaResolver) DiscussionThreads(ctx context.Context, args *struct {
 	TargetRepositoryGitCloneURL *string
 	TargetRepositoryPath        *string
 }) (*discussionThreadsConnectionResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "discussions:write" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeDiscussionsWrite); err != nil {
+		return nil, err
+	}
 	if err := viewerCanUseDiscussions(ctx); err != nil {
 		return nil, err
 	}
@@ -456,56 +515,69 @@ func (r *discussionThreadTargetRepoResolver) RelativePath(ctx context.Context, a
 		return r.t.Path, nil // File exists at that path.
 	}
 
-	var rev string
+	_, path, err := r.relativeFileDiff(ctx, repo, args.Rev)
+	return path, err
+}
+
+// relativeFileDiff returns the diff of the thread's file between the revision
+// (or branch) the thread was created on and rev, and the path of the file in
+// rev (accounting for renames). The diff is nil if the file is unchanged, and
+// the path is nil if the file was removed.
+//
+// Precondition: r.t.Path != nil && (r.t.Revision != nil || r.t.Branch != nil)
+func (r *discussionThreadTargetRepoResolver) relativeFileDiff(ctx context.Context, repo *repositoryResolver, rev string) (*diff.FileDiff, *string, error) {
+	var base string
 	if r.t.Revision != nil {
-		rev = *r.t.Revision
+		base = *r.t.Revision
 	} else if r.t.Branch != nil {
-		rev = *r.t.Branch
+		base = *r.t.Branch
 	}
 	comparison, err := repo.Comparison(ctx, &repositoryComparisonInput{
-		Base: &rev,
-		Head: &args.Rev,
+		Base: &base,
+		Head: &rev,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	currentPath := *r.t.Path
-	fileDiffs, err := comparison.FileDiffs(&struct{ First *int32 }{}).Nodes(ctx)
+	fileDiffs, err := comparison.FileDiffs(&struct{ First *int32 }{}).compute(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	for _, fileDiff := range fileDiffs {
-		oldPath := fileDiff.OldPath()
-		newPath := fileDiff.NewPath()
-
-		if oldPath == nil && newPath != nil {
-			// newPath was added. We don't need to do anything because this
-			// could only indicate the file we're tracking was added.
-		} else if oldPath != nil && newPath == nil {
-			// oldPath was removed
-			if currentPath == *oldPath {
-				// The file we are tracking was removed!
-				return nil, nil
-			}
-		} else if oldPath != nil && newPath != nil {
-			// oldPath was renamed to newPath
-			if currentPath == *oldPath {
-				// The file we are tracking was renamed.
-				currentPath = *newPath
-			}
+		if isFileCopy(fileDiff) {
+			// The file we are tracking was copied, but it still exists at its
+			// original path.
+			continue
+		}
+		if oldPath := diffPathOrNull(fileDiff.OrigName); oldPath != nil && *oldPath == *r.t.Path {
+			// The file we are tracking was modified, renamed or removed.
+			return fileDiff, diffPathOrNull(fileDiff.NewName), nil
+		}
+	}
+	return nil, r.t.Path, nil
+}
+
+// isFileCopy reports whether the file diff describes a copy of a file (as
+// reported by `git diff --find-copies`).
+func isFileCopy(fileDiff *diff.FileDiff) bool {
+	for _, line := range fileDiff.Extended {
+		if strings.HasPrefix(line, "copy from ") {
+			return true
 		}
 	}
-	return &currentPath, nil
+	return false
 }
 
 type discussionSelectionRangeResolver struct {
 	startLine, startCharacter, endLine, endCharacter int32
+	outdated                                         bool
 }
 
 func (r *discussionSelectionRangeResolver) StartLine() int32      { return r.startLine }
 func (r *discussionSelectionRangeResolver) StartCharacter() int32 { return r.startCharacter }
 func (r *discussionSelectionRangeResolver) EndLine() int32        { return r.endLine }
 func (r *discussionSelectionRangeResolver) EndCharacter() int32   { return r.endCharacter }
+func (r *discussionSelectionRangeResolver) Outdated() bool        { return r.outdated }
 
 func discussionSelectionRelativeTo(oldSel *types.DiscussionThreadTargetRepo, newContent string) *discussionSelectionRangeResolver {
 	mustFindLines := 4
@@ -569,13 +641,6 @@ func (r *discussionThreadTargetRepoResolver) RelativeSelection(ctx context.Conte
 	if !r.t.HasSelection() {
 		return nil, nil
 	}
-	path, err := r.RelativePath(ctx, args)
-	if err != nil {
-		return nil, err
-	}
-	if path == nil {
-		return nil, nil
-	}
 	repo, err := repositoryByIDInt32(ctx, r.t.RepoID)
 	if err != nil {
 		return nil, err
@@ -593,24 +658,101 @@ func (r *discussionThreadTargetRepoResolver) RelativeSelection(ctx context.Conte
 	if r.t.Revision != nil && *r.t.Revision == string(commit.OID()) {
 		return oldSel, nil // nothing to do (requested relative revision is identical to the stored revision)
 	}
-	if r.t.Branch != nil {
-		branchCommit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: *r.t.Branch})
+	if r.t.Revision == nil {
+		if r.t.Branch != nil {
+			branchCommit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: *r.t.Branch})
+			if err != nil {
+				return nil, err
+			}
+			if branchCommit.OID() == commit.OID() {
+				return oldSel, nil // nothing to do (requested relative revision is identical to the stored branch revision)
+			}
+		}
+
+		// The exact revision the thread was created on is unknown (branches
+		// move), so the diff can't be used to relocate the selection. Search
+		// for the stored lines instead.
+		path, err := r.RelativePath(ctx, args)
 		if err != nil {
 			return nil, err
 		}
-		if branchCommit.OID() == commit.OID() {
-			return oldSel, nil // nothing to do (requested relative revision is identical to the stored branch revision)
+		if path == nil {
+			return nil, nil
 		}
+		newContent, err := discussionFileContent(ctx, commit, *path)
+		if err != nil {
+			return nil, err
+		}
+		sel := discussionSelectionRelativeTo(r.t, newContent)
+		if sel != nil {
+			sel.outdated = !selectionLinesEqual(newContent, sel, *r.t.Lines)
+		}
+		return sel, nil
+	}
+
+	if r.t.Path == nil {
+		return nil, nil
 	}
-	file, err := commit.File(ctx, &struct{ Path string }{Path: *path})
+	fileDiff, path, err := r.relativeFileDiff(ctx, repo, args.Rev)
 	if err != nil {
 		return nil, err
 	}
-	newContent, err := file.Content(ctx)
+	if path == nil {
+		return nil, nil // the file was removed
+	}
+	if fileDiff == nil {
+		return oldSel, nil // the file is unchanged
+	}
+
+	// Follow the selected lines through the diff hunks.
+	relocated, modified := discussions.RelocateSelection(discussions.LineRange{
+		StartLine: int(*r.t.StartLine),
+		EndLine:   int(*r.t.EndLine),
+	}, fileDiff.Hunks)
+	sel := &discussionSelectionRangeResolver{
+		startLine:      int32(relocated.StartLine),
+		startCharacter: *r.t.StartCharacter,
+		endLine:        int32(relocated.EndLine),
+		endCharacter:   *r.t.EndCharacter,
+		outdated:       modified,
+	}
+	if modified && r.t.Lines != nil {
+		// The diff reports moved lines as removed and added elsewhere. If the
+		// stored lines exist unchanged elsewhere in the file, the code was
+		// moved rather than modified.
+		newContent, err := discussionFileContent(ctx, commit, *path)
+		if err != nil {
+			return nil, err
+		}
+		if moved := discussionSelectionRelativeTo(r.t, newContent); moved != nil && selectionLinesEqual(newContent, moved, *r.t.Lines) {
+			return moved, nil
+		}
+	}
+	return sel, nil
+}
+
+// discussionFileContent returns the content of the file at path in commit.
+func discussionFileContent(ctx context.Context, commit *gitCommitResolver, path string) (string, error) {
+	file, err := commit.File(ctx, &struct{ Path string }{Path: path})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	return discussionSelectionRelativeTo(r.t, newContent), nil
+	return file.Content(ctx)
+}
+
+// selectionLinesEqual reports whether the lines of the selection in content
+// are equal to lines.
+func selectionLinesEqual(content string, sel *discussionSelectionRangeResolver, lines []string) bool {
+	allLines := strings.Split(content, "\n")
+	if sel.startLine < 0 || int(sel.endLine) > len(allLines) || int(sel.endLine-sel.startLine) != len(lines) {
+		return false
+	}
+	for i, line := range allLines[sel.startLine:sel.endLine] {
+		if line != lines[i] {
+			return false
+		}
+	}
+	return true
 }
 
 type discussionThreadTargetResolver struct {
@@ -667,6 +809,39 @@ func (d *discussionThreadResolver) ArchivedAt(ctx context.Context) *string {
 	return strptr(d.t.ArchivedAt.Format(time.RFC3339))
 }
 
+func (d *discussionThreadResolver) ResolvedAt(ctx context.Context) *string {
+	if d.t.ResolvedAt == nil {
+		return nil
+	}
+	return strptr(d.t.ResolvedAt.Format(time.RFC3339))
+}
+
+func (d *discussionThreadResolver) ResolvedBy(ctx context.Context) (*UserResolver, error) {
+	if d.t.ResolvedAt == nil || d.t.ResolvedByUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *d.t.ResolvedByUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (d *discussionThreadResolver) Assignees(ctx context.Context) ([]*UserResolver, error) {
+	assignees := make([]*UserResolver, 0, len(d.t.AssigneeUserIDs))
+	for _, userID := range d.t.AssigneeUserIDs {
+		user, err := UserByIDInt32(ctx, userID)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				continue // deleted user
+			}
+			return nil, err
+		}
+		assignees = append(assignees, user)
+	}
+	return assignees, nil
+}
+
 func (d *discussionThreadResolver) Comments(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) *discussionCommentsConnectionResolver {
@@ -94,3 +94,25 @@ func TestDiscussionSelectionRelativeTo(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectionLinesEqual(t *testing.T) {
+	content := "0\n1\n2\n3"
+	tests := []struct {
+		name  string
+		sel   *discussionSelectionRangeResolver
+		lines []string
+		want  bool
+	}{
+		{name: "equal", sel: &discussionSelectionRangeResolver{startLine: 1, endLine: 3}, lines: []string{"1", "2"}, want: true},
+		{name: "changed", sel: &discussionSelectionRangeResolver{startLine: 1, endLine: 3}, lines: []string{"1", "two"}, want: false},
+		{name: "different_length", sel: &discussionSelectionRangeResolver{startLine: 1, endLine: 2}, lines: []string{"1", "2"}, want: false},
+		{name: "out_of_range", sel: &discussionSelectionRangeResolver{startLine: 3, endLine: 5}, lines: []string{"3", "4"}, want: false},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			if got := selectionLinesEqual(content, tst.sel, tst.lines); got != tst.want {
+				t.Errorf("got %v, want %v", got, tst.want)
+			}
+		})
+	}
+}
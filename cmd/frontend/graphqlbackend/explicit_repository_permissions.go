@@ -0,0 +1,224 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+)
+
+// explicitRepositoryPermissionResolver resolves a repository permission explicitly granted by a site
+// admin.
+//
+// 🚨 SECURITY: When instantiating an explicitRepositoryPermissionResolver value, the caller MUST
+// check that the actor is a site admin.
+type explicitRepositoryPermissionResolver struct {
+	perm db.ExplicitRepoPermission
+}
+
+func marshalExplicitRepositoryPermissionID(id int32) graphql.ID {
+	return relay.MarshalID("ExplicitRepositoryPermission", id)
+}
+
+func unmarshalExplicitRepositoryPermissionID(id graphql.ID) (permID int32, err error) {
+	err = relay.UnmarshalSpec(id, &permID)
+	return
+}
+
+func (r *explicitRepositoryPermissionResolver) ID() graphql.ID {
+	return marshalExplicitRepositoryPermissionID(r.perm.ID)
+}
+
+func (r *explicitRepositoryPermissionResolver) User(ctx context.Context) (*UserResolver, error) {
+	if r.perm.UserID == 0 {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, r.perm.UserID)
+}
+
+func (r *explicitRepositoryPermissionResolver) Organization(ctx context.Context) (*OrgResolver, error) {
+	if r.perm.OrgID == 0 {
+		return nil, nil
+	}
+	return OrgByIDInt32(ctx, r.perm.OrgID)
+}
+
+func (r *explicitRepositoryPermissionResolver) Repository(ctx context.Context) (*repositoryResolver, error) {
+	if r.perm.RepoID == 0 {
+		return nil, nil
+	}
+	return repositoryByIDInt32(ctx, r.perm.RepoID)
+}
+
+func (r *explicitRepositoryPermissionResolver) RepositoryPattern() *string {
+	if r.perm.RepoPattern == "" {
+		return nil
+	}
+	return &r.perm.RepoPattern
+}
+
+func (r *explicitRepositoryPermissionResolver) Permission() string { return string(r.perm.Perm) }
+
+func (r *explicitRepositoryPermissionResolver) CreatedAt() string {
+	return r.perm.CreatedAt.Format(time.RFC3339)
+}
+
+type grantRepositoryPermissionInput struct {
+	User              *graphql.ID
+	Organization      *graphql.ID
+	Repository        *graphql.ID
+	RepositoryPattern *string
+}
+
+func (r *schemaResolver) GrantRepositoryPermission(ctx context.Context, args *grantRepositoryPermissionInput) (*explicitRepositoryPermissionResolver, error) {
+	// 🚨 SECURITY: Only site admins can grant repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	p := db.ExplicitRepoPermission{Perm: authz.Read}
+	var err error
+	if args.User != nil {
+		if p.UserID, err = UnmarshalUserID(*args.User); err != nil {
+			return nil, err
+		}
+	}
+	if args.Organization != nil {
+		if p.OrgID, err = UnmarshalOrgID(*args.Organization); err != nil {
+			return nil, err
+		}
+	}
+	if args.Repository != nil {
+		if p.RepoID, err = unmarshalRepositoryID(*args.Repository); err != nil {
+			return nil, err
+		}
+	}
+	if args.RepositoryPattern != nil {
+		p.RepoPattern = *args.RepositoryPattern
+	}
+
+	perm, err := db.ExplicitRepoPermissions.Create(ctx, &p)
+	if err != nil {
+		return nil, err
+	}
+	db.RefreshExplicitRepoPermissionGrantees(ctx, []*db.ExplicitRepoPermission{perm})
+	return &explicitRepositoryPermissionResolver{perm: *perm}, nil
+}
+
+func (r *schemaResolver) RevokeRepositoryPermission(ctx context.Context, args *struct {
+	ID graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can revoke repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalExplicitRepositoryPermissionID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	perm, err := db.ExplicitRepoPermissions.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.ExplicitRepoPermissions.Delete(ctx, perm.ID); err != nil {
+		return nil, err
+	}
+	db.RefreshExplicitRepoPermissionGrantees(ctx, []*db.ExplicitRepoPermission{perm})
+	return &EmptyResponse{}, nil
+}
+
+func (r *siteResolver) ExplicitRepositoryPermissions(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	User         *graphql.ID
+	Organization *graphql.ID
+	Repository   *graphql.ID
+}) (*explicitRepositoryPermissionConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can list explicitly granted repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.ExplicitRepoPermissionsListOptions
+	var err error
+	if args.User != nil {
+		if opt.UserID, err = UnmarshalUserID(*args.User); err != nil {
+			return nil, err
+		}
+	}
+	if args.Organization != nil {
+		if opt.OrgID, err = UnmarshalOrgID(*args.Organization); err != nil {
+			return nil, err
+		}
+	}
+	if args.Repository != nil {
+		if opt.RepoID, err = unmarshalRepositoryID(*args.Repository); err != nil {
+			return nil, err
+		}
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &explicitRepositoryPermissionConnectionResolver{opt: opt}, nil
+}
+
+// explicitRepositoryPermissionConnectionResolver resolves a list of explicitly granted repository
+// permissions.
+//
+// 🚨 SECURITY: When instantiating an explicitRepositoryPermissionConnectionResolver value, the
+// caller MUST check that the actor is a site admin.
+type explicitRepositoryPermissionConnectionResolver struct {
+	opt db.ExplicitRepoPermissionsListOptions
+
+	// cache results because they are used by multiple fields
+	once  sync.Once
+	perms []*db.ExplicitRepoPermission
+	err   error
+}
+
+func (r *explicitRepositoryPermissionConnectionResolver) compute(ctx context.Context) ([]*db.ExplicitRepoPermission, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.perms, r.err = db.ExplicitRepoPermissions.List(ctx, opt2)
+	})
+	return r.perms, r.err
+}
+
+func (r *explicitRepositoryPermissionConnectionResolver) Nodes(ctx context.Context) ([]*explicitRepositoryPermissionResolver, error) {
+	perms, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(perms) > r.opt.Limit {
+		perms = perms[:r.opt.Limit]
+	}
+
+	l := make([]*explicitRepositoryPermissionResolver, len(perms))
+	for i, perm := range perms {
+		l[i] = &explicitRepositoryPermissionResolver{perm: *perm}
+	}
+	return l, nil
+}
+
+func (r *explicitRepositoryPermissionConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.ExplicitRepoPermissions.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *explicitRepositoryPermissionConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	perms, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(perms) > r.opt.Limit), nil
+}
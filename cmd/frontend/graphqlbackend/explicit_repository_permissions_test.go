@@ -0,0 +1,81 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+// 🚨 SECURITY: This tests that only site admins can grant repository permissions.
+func TestMutation_GrantRepositoryPermission(t *testing.T) {
+	const org2GQLID = "T3JnOjI="
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: false}, nil
+		}
+		db.Mocks.ExplicitRepoPermissions.Create = func(ctx context.Context, p *db.ExplicitRepoPermission) (*db.ExplicitRepoPermission, error) {
+			t.Fatal("unexpected Create")
+			return nil, nil
+		}
+		defer resetMocks()
+
+		pattern := "gitolite.example.com/*"
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&schemaResolver{}).GrantRepositoryPermission(ctx, &grantRepositoryPermissionInput{RepositoryPattern: &pattern})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("got err %v, want %v", err, want)
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
+	t.Run("authenticated as site admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		db.Mocks.ExplicitRepoPermissions.Create = func(ctx context.Context, p *db.ExplicitRepoPermission) (*db.ExplicitRepoPermission, error) {
+			if p.OrgID != 2 || p.RepoPattern != "gitolite.example.com/*" || p.Perm != authz.Read {
+				t.Errorf("got permission %+v, want read permission of org 2 on pattern", p)
+			}
+			created := *p
+			created.ID = 1
+			return &created, nil
+		}
+		defer resetMocks()
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  GraphQLSchema,
+				Query: `
+				mutation {
+					grantRepositoryPermission(organization: "` + org2GQLID + `", repositoryPattern: "gitolite.example.com/*") {
+						id
+						repositoryPattern
+						permission
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"grantRepositoryPermission": {
+						"id": "RXhwbGljaXRSZXBvc2l0b3J5UGVybWlzc2lvbjox",
+						"repositoryPattern": "gitolite.example.com/*",
+						"permission": "read"
+					}
+				}
+			`,
+			},
+		})
+	})
+}
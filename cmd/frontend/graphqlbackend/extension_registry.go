@@ -80,6 +80,9 @@ type ExtensionRegistryPublishExtensionArgs struct {
 	Manifest    string
 	Bundle      *string
 	SourceMap   *string
+	Version     *string
+	Channel     *string
+	Signature   *string
 	Force       bool
 }
 
@@ -118,6 +121,14 @@ type RegistryExtension interface {
 	IsLocal() bool
 	IsWorkInProgress() bool
 	ViewerCanAdminister(ctx context.Context) (bool, error)
+	Signature(context.Context) (ExtensionReleaseSignature, error)
+}
+
+// ExtensionReleaseSignature is the interface for the GraphQL type ExtensionReleaseSignature.
+type ExtensionReleaseSignature interface {
+	BundleSHA256() string
+	KeyFingerprint() string
+	IsTrusted() bool
 }
 
 // ExtensionManifest is the interface for the GraphQL type ExtensionManifest.
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -47,6 +48,11 @@ func (r *siteResolver) ExternalAccounts(ctx context.Context, args *struct {
 func (r *UserResolver) ExternalAccounts(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) (*externalAccountConnectionResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's external accounts,
+	// because users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
 	// 🚨 SECURITY: Only site admins and the user can list a user's external accounts.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
 		return nil, err
@@ -12,6 +12,7 @@ import (
 	"github.com/graph-gophers/graphql-go/trace"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -228,6 +229,11 @@ func (r *schemaResolver) Repository(ctx context.Context, args *struct {
 	// TODO(chris): Remove URI in favor of Name.
 	URI *string
 }) (*repositoryResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "repo:read" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeRepoRead); err != nil {
+		return nil, err
+	}
+
 	var name api.RepoName
 	if args.URI != nil {
 		// Deprecated query by "URI"
@@ -2,35 +2,297 @@ package graphqlbackend
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 )
 
 type repoGroup struct {
 	name         string
 	repositories []api.RepoName
+	group        *types.RepoGroup // the stored group, or nil if the group is defined in settings
+}
+
+const repoGroupIDKind = "RepoGroup"
+
+func marshalRepoGroupID(id int32) graphql.ID { return relay.MarshalID(repoGroupIDKind, id) }
+
+func unmarshalRepoGroupID(id graphql.ID) (repoGroupID int32, err error) {
+	if kind := relay.UnmarshalKind(id); kind != repoGroupIDKind {
+		err = fmt.Errorf("expected graphql ID to have kind %q; got %q", repoGroupIDKind, kind)
+		return
+	}
+	err = relay.UnmarshalSpec(id, &repoGroupID)
+	return
+}
+
+func (g repoGroup) ID() *graphql.ID {
+	if g.group == nil {
+		return nil
+	}
+	id := marshalRepoGroupID(g.group.ID)
+	return &id
 }
 
 func (g repoGroup) Name() string { return g.name }
 
+func (g repoGroup) Description() *string {
+	if g.group == nil || g.group.Description == "" {
+		return nil
+	}
+	return &g.group.Description
+}
+
 func (g repoGroup) Repositories() []string { return repoNamesToStrings(g.repositories) }
 
+func (g repoGroup) Rule() *repoGroupRuleResolver {
+	if g.group == nil || g.group.Rule == nil {
+		return nil
+	}
+	return &repoGroupRuleResolver{rule: g.group.Rule}
+}
+
+type repoGroupRuleResolver struct {
+	rule *api.RepoGroupRule
+}
+
+func (r *repoGroupRuleResolver) NamePattern() *string {
+	if r.rule.NamePattern == "" {
+		return nil
+	}
+	return &r.rule.NamePattern
+}
+
+func (r *repoGroupRuleResolver) ExternalServiceIDs() []graphql.ID {
+	ids := make([]graphql.ID, len(r.rule.ExternalServiceIDs))
+	for i, id := range r.rule.ExternalServiceIDs {
+		ids[i] = marshalExternalServiceID(id)
+	}
+	return ids
+}
+
+func (r *repoGroupRuleResolver) Topics() []string { return nonNilStrings(r.rule.Topics) }
+
+func (r *repoGroupRuleResolver) Owners() []string { return nonNilStrings(r.rule.Owners) }
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
 func (r *schemaResolver) RepoGroups(ctx context.Context) ([]*repoGroup, error) {
+	// 🚨 SECURITY: Access tokens need the "search:read" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeSearchRead); err != nil {
+		return nil, err
+	}
+
 	groupsByName, err := resolveRepoGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	stored, err := db.RepoGroups.List(ctx, db.RepoGroupsListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	storedByName := make(map[string]*types.RepoGroup, len(stored))
+	for _, g := range stored {
+		storedByName[g.Name] = g
+	}
+	settingsGroups, err := resolveSettingsRepoGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	groups := make([]*repoGroup, 0, len(groupsByName))
 	for name, repos := range groupsByName {
 		repoPaths := make([]api.RepoName, len(repos))
 		for i, repo := range repos {
 			repoPaths[i] = repo.Name
 		}
-		groups = append(groups, &repoGroup{
+		g := &repoGroup{
 			name:         name,
 			repositories: repoPaths,
-		})
+		}
+		if _, ok := settingsGroups[name]; !ok {
+			g.group = storedByName[name]
+		}
+		groups = append(groups, g)
 	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].name < groups[j].name })
 	return groups, nil
 }
+
+type repoGroupRuleInput struct {
+	NamePattern      *string
+	ExternalServices *[]graphql.ID
+	Topics           *[]string
+	Owners           *[]string
+}
+
+func (in *repoGroupRuleInput) rule() (*api.RepoGroupRule, error) {
+	if in == nil {
+		return nil, nil
+	}
+	rule := &api.RepoGroupRule{}
+	if in.NamePattern != nil {
+		rule.NamePattern = *in.NamePattern
+	}
+	if in.ExternalServices != nil {
+		for _, id := range *in.ExternalServices {
+			externalServiceID, err := unmarshalExternalServiceID(id)
+			if err != nil {
+				return nil, err
+			}
+			rule.ExternalServiceIDs = append(rule.ExternalServiceIDs, externalServiceID)
+		}
+	}
+	if in.Topics != nil {
+		rule.Topics = *in.Topics
+	}
+	if in.Owners != nil {
+		rule.Owners = *in.Owners
+	}
+	return rule, nil
+}
+
+func repoGroupRepoIDs(ctx context.Context, names []string) ([]api.RepoID, error) {
+	ids := make([]api.RepoID, len(names))
+	for i, name := range names {
+		repo, err := db.Repos.GetByName(ctx, api.RepoName(name))
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = repo.ID
+	}
+	return ids, nil
+}
+
+var errRepoGroupMembers = errors.New("a repository group can either have a rule or a list of repositories, not both")
+
+func repoGroupByID(ctx context.Context, id int32) (*repoGroup, error) {
+	group, err := db.RepoGroups.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := db.RepoGroups.ListRepos(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]api.RepoName, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name
+	}
+	return &repoGroup{name: group.Name, repositories: names, group: group}, nil
+}
+
+func (*schemaResolver) CreateRepoGroup(ctx context.Context, args *struct {
+	Input *struct {
+		Name         string
+		Description  *string
+		Repositories *[]string
+		Rule         *repoGroupRuleInput
+	}
+}) (*repoGroup, error) {
+	// 🚨 SECURITY: Only site admins may create repository groups.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if args.Input.Repositories != nil && args.Input.Rule != nil {
+		return nil, errRepoGroupMembers
+	}
+	rule, err := args.Input.Rule.rule()
+	if err != nil {
+		return nil, err
+	}
+	group := &types.RepoGroup{Name: args.Input.Name, Rule: rule}
+	if args.Input.Description != nil {
+		group.Description = *args.Input.Description
+	}
+	var repoIDs []api.RepoID
+	if args.Input.Repositories != nil {
+		if repoIDs, err = repoGroupRepoIDs(ctx, *args.Input.Repositories); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := db.RepoGroups.Create(ctx, group, repoIDs); err != nil {
+		return nil, err
+	}
+	return repoGroupByID(ctx, group.ID)
+}
+
+func (*schemaResolver) UpdateRepoGroup(ctx context.Context, args *struct {
+	Input *struct {
+		ID           graphql.ID
+		Name         *string
+		Description  *string
+		Repositories *[]string
+		Rule         *repoGroupRuleInput
+	}
+}) (*repoGroup, error) {
+	// 🚨 SECURITY: Only site admins may update repository groups.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalRepoGroupID(args.Input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if args.Input.Repositories != nil && args.Input.Rule != nil {
+		return nil, errRepoGroupMembers
+	}
+	rule, err := args.Input.Rule.rule()
+	if err != nil {
+		return nil, err
+	}
+
+	update := &db.RepoGroupUpdate{
+		Name:        args.Input.Name,
+		Description: args.Input.Description,
+		Rule:        rule,
+	}
+	if err := db.RepoGroups.Update(ctx, id, update); err != nil {
+		return nil, err
+	}
+	if args.Input.Repositories != nil {
+		repoIDs, err := repoGroupRepoIDs(ctx, *args.Input.Repositories)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.RepoGroups.SetRepos(ctx, id, repoIDs); err != nil {
+			return nil, err
+		}
+	}
+	return repoGroupByID(ctx, id)
+}
+
+func (*schemaResolver) DeleteRepoGroup(ctx context.Context, args *struct {
+	RepoGroup graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins may delete repository groups.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := unmarshalRepoGroupID(args.RepoGroup)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.RepoGroups.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
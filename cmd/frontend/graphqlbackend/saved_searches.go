@@ -3,9 +3,13 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -58,6 +62,9 @@ func savedSearchByID(ctx context.Context, id graphql.ID) (*savedSearchResolver,
 			UserID:          ss.Config.UserID,
 			OrgID:           ss.Config.OrgID,
 			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			NotifyWebhook:   ss.Config.NotifyWebhook,
+			WebhookURL:      ss.Config.WebhookURL,
+			WebhookSecret:   ss.Config.WebhookSecret,
 		},
 	}
 	return savedSearch, nil
@@ -95,11 +102,48 @@ func (r savedSearchResolver) OrgID() *graphql.ID {
 }
 func (r savedSearchResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
 
+func (r savedSearchResolver) NotifyWebhook() bool { return r.s.NotifyWebhook }
+
+func (r savedSearchResolver) WebhookURL() *string { return r.s.WebhookURL }
+
+func (r savedSearchResolver) WebhookDeliveries(ctx context.Context, args *struct {
+	First int32
+}) ([]*savedSearchWebhookDeliveryResolver, error) {
+	deliveries, err := db.SavedSearches.ListWebhookDeliveries(ctx, r.s.ID, &db.LimitOffset{Limit: int(args.First)})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*savedSearchWebhookDeliveryResolver, len(deliveries))
+	for i, d := range deliveries {
+		resolvers[i] = &savedSearchWebhookDeliveryResolver{d: d}
+	}
+	return resolvers, nil
+}
+
+type savedSearchWebhookDeliveryResolver struct {
+	d *types.SavedSearchWebhookDelivery
+}
+
+func (r *savedSearchWebhookDeliveryResolver) URL() string        { return r.d.URL }
+func (r *savedSearchWebhookDeliveryResolver) Test() bool         { return r.d.Test }
+func (r *savedSearchWebhookDeliveryResolver) ResultCount() int32 { return r.d.ResultCount }
+func (r *savedSearchWebhookDeliveryResolver) Attempts() int32    { return r.d.Attempts }
+func (r *savedSearchWebhookDeliveryResolver) StatusCode() *int32 { return r.d.StatusCode }
+func (r *savedSearchWebhookDeliveryResolver) Error() *string     { return r.d.Error }
+func (r *savedSearchWebhookDeliveryResolver) CreatedAt() string {
+	return r.d.CreatedAt.Format(time.RFC3339)
+}
+
 func toSavedSearchResolver(entry types.SavedSearch) *savedSearchResolver {
 	return &savedSearchResolver{entry}
 }
 
 func (r *schemaResolver) SavedSearches(ctx context.Context) ([]*savedSearchResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "search:read" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeSearchRead); err != nil {
+		return nil, err
+	}
+
 	var savedSearches []*savedSearchResolver
 	currentUser, err := CurrentUser(ctx)
 	if currentUser == nil {
@@ -140,12 +184,15 @@ func (r *schemaResolver) SendSavedSearchTestNotification(ctx context.Context, ar
 }
 
 func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	Description   string
+	Query         string
+	NotifyOwner   bool
+	NotifySlack   bool
+	NotifyWebhook bool
+	WebhookURL    *string
+	WebhookSecret *string
+	OrgID         *graphql.ID
+	UserID        *graphql.ID
 }) (*savedSearchResolver, error) {
 	var userID *int32
 	var orgID *int32
@@ -172,13 +219,20 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		return nil, errors.New("failed to create saved search: no Org ID or User ID associated with saved search")
 	}
 
+	if err := validateSavedSearchWebhook(args.NotifyWebhook, args.WebhookURL); err != nil {
+		return nil, err
+	}
+
 	ss, err := db.SavedSearches.Create(ctx, &types.SavedSearch{
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		Description:   args.Description,
+		Query:         args.Query,
+		Notify:        args.NotifyOwner,
+		NotifySlack:   args.NotifySlack,
+		NotifyWebhook: args.NotifyWebhook,
+		WebhookURL:    args.WebhookURL,
+		WebhookSecret: nonEmptyStringPtr(args.WebhookSecret),
+		UserID:        userID,
+		OrgID:         orgID,
 	})
 	if err != nil {
 		return nil, err
@@ -188,13 +242,16 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 }
 
 func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
-	ID          graphql.ID
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	ID            graphql.ID
+	Description   string
+	Query         string
+	NotifyOwner   bool
+	NotifySlack   bool
+	NotifyWebhook bool
+	WebhookURL    *string
+	WebhookSecret *string
+	OrgID         *graphql.ID
+	UserID        *graphql.ID
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to update a saved search for the specified user or org.
@@ -225,14 +282,31 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		return nil, err
 	}
 
+	if err := validateSavedSearchWebhook(args.NotifyWebhook, args.WebhookURL); err != nil {
+		return nil, err
+	}
+	webhookSecret := nonEmptyStringPtr(args.WebhookSecret)
+	if args.WebhookSecret == nil {
+		// Keep the existing secret, so that clients don't need to know it to
+		// update the saved search.
+		existing, err := db.SavedSearches.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		webhookSecret = existing.Config.WebhookSecret
+	}
+
 	ss, err := db.SavedSearches.Update(ctx, &types.SavedSearch{
-		ID:          id,
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		ID:            id,
+		Description:   args.Description,
+		Query:         args.Query,
+		Notify:        args.NotifyOwner,
+		NotifySlack:   args.NotifySlack,
+		NotifyWebhook: args.NotifyWebhook,
+		WebhookURL:    args.WebhookURL,
+		WebhookSecret: webhookSecret,
+		UserID:        userID,
+		OrgID:         orgID,
 	})
 	if err != nil {
 		return nil, err
@@ -241,6 +315,32 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 	return toSavedSearchResolver(*ss), nil
 }
 
+// validateSavedSearchWebhook checks that saved searches with webhook
+// notifications have a valid webhook URL.
+func validateSavedSearchWebhook(notifyWebhook bool, webhookURL *string) error {
+	if !notifyWebhook && webhookURL == nil {
+		return nil
+	}
+	if webhookURL == nil || *webhookURL == "" {
+		return errors.New("webhook notifications require a webhook URL")
+	}
+	u, err := url.Parse(*webhookURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook URL %q: must be an http or https URL", *webhookURL)
+	}
+	return nil
+}
+
+func nonEmptyStringPtr(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
+
 func (r *schemaResolver) DeleteSavedSearch(ctx context.Context, args *struct {
 	ID graphql.ID
 }) (*EmptyResponse, error) {
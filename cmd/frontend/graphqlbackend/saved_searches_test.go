@@ -61,12 +61,15 @@ func TestCreateSavedSearch(t *testing.T) {
 	}
 	userID := marshalUserID(key)
 	savedSearches, err := (&schemaResolver{}).CreateSavedSearch(ctx, &struct {
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		Description   string
+		Query         string
+		NotifyOwner   bool
+		NotifySlack   bool
+		NotifyWebhook bool
+		WebhookURL    *string
+		WebhookSecret *string
+		OrgID         *graphql.ID
+		UserID        *graphql.ID
 	}{Description: "test query", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 
 	if err != nil {
@@ -105,15 +108,21 @@ func TestUpdateSavedSearch(t *testing.T) {
 		updateSavedSearchCalled = true
 		return &types.SavedSearch{ID: key, Description: savedSearch.Description, Query: savedSearch.Query, Notify: savedSearch.Notify, NotifySlack: savedSearch.NotifySlack, UserID: savedSearch.UserID, OrgID: savedSearch.OrgID}, nil
 	}
+	db.Mocks.SavedSearches.GetByID = func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		return &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{Key: "1", UserID: &key}}, nil
+	}
 	userID := marshalUserID(key)
 	savedSearches, err := (&schemaResolver{}).UpdateSavedSearch(ctx, &struct {
-		ID          graphql.ID
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		ID            graphql.ID
+		Description   string
+		Query         string
+		NotifyOwner   bool
+		NotifySlack   bool
+		NotifyWebhook bool
+		WebhookURL    *string
+		WebhookSecret *string
+		OrgID         *graphql.ID
+		UserID        *graphql.ID
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -169,3 +178,25 @@ func TestDeleteSavedSearch(t *testing.T) {
 		t.Errorf("Database method db.SavedSearches.Delete not called")
 	}
 }
+
+func TestValidateSavedSearchWebhook(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		notifyWebhook bool
+		webhookURL    *string
+		wantErr       bool
+	}{
+		{notifyWebhook: false, webhookURL: nil},
+		{notifyWebhook: true, webhookURL: str("https://example.com/hook")},
+		{notifyWebhook: false, webhookURL: str("http://example.com/hook")},
+		{notifyWebhook: true, webhookURL: nil, wantErr: true},
+		{notifyWebhook: true, webhookURL: str(""), wantErr: true},
+		{notifyWebhook: true, webhookURL: str("ftp://example.com"), wantErr: true},
+	}
+	for _, test := range tests {
+		err := validateSavedSearchWebhook(test.notifyWebhook, test.webhookURL)
+		if (err != nil) != test.wantErr {
+			t.Errorf("validateSavedSearchWebhook(%v, %v): got error %v, want error %v", test.notifyWebhook, test.webhookURL, err, test.wantErr)
+		}
+	}
+}
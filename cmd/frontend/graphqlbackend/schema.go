@@ -47,6 +47,12 @@ type Mutation {
     updateExternalService(input: UpdateExternalServiceInput!): ExternalService!
     # Delete an external service. Only site admins may perform this mutation.
     deleteExternalService(externalService: ID!): EmptyResponse!
+    # Creates a repository group. Only site admins may perform this mutation.
+    createRepoGroup(input: CreateRepoGroupInput!): RepoGroup!
+    # Updates a repository group. Only site admins may perform this mutation.
+    updateRepoGroup(input: UpdateRepoGroupInput!): RepoGroup!
+    # Deletes a repository group. Only site admins may perform this mutation.
+    deleteRepoGroup(repoGroup: ID!): EmptyResponse!
     # DEPRECATED: All repositories are accessible or deleted. To prevent a
     # repository from being accessed on Sourcegraph add it to the external
     # service exclude configuration. This mutation will be removed in 3.6.
@@ -161,9 +167,30 @@ type Mutation {
     # - "user:all": Full control of all resources accessible to the user account.
     # - "site-admin:sudo": Ability to perform any action as any other user. (Only site admins may create tokens
     #   with this scope.)
+    # - "search:read": Ability to perform searches and read saved searches.
+    # - "repo:read": Ability to read repositories and their contents.
+    # - "settings:write": Ability to read and update settings.
+    # - "discussions:write": Ability to read and create discussion threads and comments.
+    #
+    # Access tokens must have the "user:all" scope or at least one of the "search:read", "repo:read",
+    # "settings:write", and "discussions:write" scopes. Access tokens without the "user:all" scope may only be
+    # used with the GraphQL API, and only for the queries and mutations that their scopes permit.
     #
     # Only the user or site admins may perform this mutation.
-    createAccessToken(user: ID!, scopes: [String!]!, note: String!): CreateAccessTokenResult!
+    createAccessToken(
+        # The subject user of the access token.
+        user: ID!
+        # The scopes of the access token.
+        scopes: [String!]!
+        # A descriptive note for the access token.
+        note: String!
+        # The date (in RFC 3339 format) after which the access token is no longer valid. If null, the access
+        # token does not expire.
+        expiresAt: String
+        # The names of the only repositories that the access token may be used to access. If null, the access
+        # token may be used to access all repositories that the subject user can access.
+        repoAllowlist: [String!]
+    ): CreateAccessTokenResult!
     # Deletes and immediately revokes the specified access token, specified by either its ID or by the token
     # itself.
     #
@@ -174,6 +201,31 @@ type Mutation {
     #
     # Only site admins or the user who is associated with the external account may perform this mutation.
     deleteExternalAccount(externalAccount: ID!): EmptyResponse!
+    # Grants read access to a repository (or to all repositories whose names match a pattern) to a user or to all
+    # members of an organization. Exactly one of user and organization and exactly one of repository and
+    # repositoryPattern must be given. If an identical permission was already granted, the existing permission is
+    # returned.
+    #
+    # Explicitly granted permissions are only enforced if the "permissions.explicit" site configuration property
+    # is enabled, and only for repositories of the external service types that it lists.
+    #
+    # Only site admins may perform this mutation.
+    grantRepositoryPermission(
+        # The user to grant the permission to.
+        user: ID
+        # The organization whose members to grant the permission to.
+        organization: ID
+        # The repository to grant the permission on.
+        repository: ID
+        # The pattern of the names of the repositories to grant the permission on, in which "*" matches any
+        # sequence of characters (including "/"), such as "gitolite.example.com/team/*". Matching is
+        # case-insensitive.
+        repositoryPattern: String
+    ): ExplicitRepositoryPermission!
+    # Revokes an explicitly granted repository permission.
+    #
+    # Only site admins may perform this mutation.
+    revokeRepositoryPermission(id: ID!): EmptyResponse!
     # Invite the user with the given username to join the organization. The invited user account must already
     # exist.
     #
@@ -296,6 +348,15 @@ type Mutation {
         # with this new value.
         input: String!
     ): Boolean!
+    # Restores a past version of the site configuration (from SiteConfiguration.history) by saving its
+    # contents as the new site configuration. The past version must pass validation. Returns whether or not a
+    # restart is required for the update to be applied.
+    #
+    # Only site admins may perform this mutation.
+    restoreSiteConfiguration(
+        # The ID of the site configuration version to restore.
+        id: Int!
+    ): Boolean!
     # Manages discussions.
     discussions: DiscussionsMutation
     # Sets whether the user with the specified user ID is a site admin.
@@ -326,6 +387,12 @@ type Mutation {
         query: String!
         notifyOwner: Boolean!
         notifySlack: Boolean!
+        # Whether to POST notifications of new results to webhookURL.
+        notifyWebhook: Boolean = false
+        # The URL that webhook notifications are sent to.
+        webhookURL: String
+        # The secret used to sign webhook payloads (see SavedSearch.webhookURL).
+        webhookSecret: String
         orgID: ID
         userID: ID
     ): SavedSearch!
@@ -336,6 +403,13 @@ type Mutation {
         query: String!
         notifyOwner: Boolean!
         notifySlack: Boolean!
+        # Whether to POST notifications of new results to webhookURL.
+        notifyWebhook: Boolean = false
+        # The URL that webhook notifications are sent to.
+        webhookURL: String
+        # The secret used to sign webhook payloads. If null, the existing secret is kept; if empty, payloads
+        # are no longer signed.
+        webhookSecret: String
         orgID: ID
         userID: ID
     ): SavedSearch!
@@ -363,6 +437,50 @@ input UpdateExternalServiceInput {
     config: String
 }
 
+# A new repository group. At most one of repositories and rule may be given.
+input CreateRepoGroupInput {
+    # The name of the group, as used in the repogroup: search filter.
+    name: String!
+    # The description of the group.
+    description: String
+    # The names of the repositories in the group.
+    repositories: [String!]
+    # The rule that determines the repositories in the group. The group is
+    # populated after the next repository sync.
+    rule: RepoGroupRuleInput
+}
+
+# Fields to update for an existing repository group. At most one of
+# repositories and rule may be given.
+input UpdateRepoGroupInput {
+    # The id of the repository group to update.
+    id: ID!
+    # The updated name, if provided.
+    name: String
+    # The updated description, if provided.
+    description: String
+    # The updated list of repository names, if provided. This removes the
+    # group's rule.
+    repositories: [String!]
+    # The updated rule, if provided. The group's repositories are updated after
+    # the next repository sync.
+    rule: RepoGroupRuleInput
+}
+
+# A rule that determines the repositories in a repository group. A repository
+# is in the group if it satisfies all of the given criteria.
+input RepoGroupRuleInput {
+    # A regular expression that the repository name must match.
+    namePattern: String
+    # External services, one of which the repository must come from.
+    externalServices: [ID!]
+    # Code host topics, one of which the repository must have.
+    topics: [String!]
+    # Code host owners (users, organizations, groups or projects), one of
+    # which must own the repository.
+    owners: [String!]
+}
+
 # A selection within a file.
 input DiscussionThreadTargetRepoSelectionInput {
     # The line that the selection started on (zero-based, inclusive).
@@ -473,6 +591,13 @@ input DiscussionThreadUpdateInput {
     # When non-null, indicates that the thread should be deleted. Only admins
     # can perform this action.
     Delete: Boolean
+
+    # When non-null, indicates that the thread should be marked as resolved
+    # (true) or unresolved (false).
+    Resolve: Boolean
+
+    # When non-null, replaces the list of users assigned to the thread.
+    Assignees: [ID!]
 }
 
 # Describes an update mutation to an existing comment in a thread.
@@ -494,6 +619,15 @@ input DiscussionCommentUpdateInput {
     #
     # An error will be returned if the comment's canClearReports field is false.
     clearReports: Boolean
+
+    # When non-null, adds a reaction with the specified emoji to the comment on
+    # behalf of the current user. See DiscussionComment.reactions for the
+    # supported emoji.
+    addReaction: String
+
+    # When non-null, removes the current user's reaction with the specified
+    # emoji from the comment.
+    removeReaction: String
 }
 
 # Mutations for discussions.
@@ -848,6 +982,54 @@ type Search {
     # cached and thus quicker to query. Useful for e.g. querying sparkline
     # data.
     stats: SearchResultsStats!
+    # Runs the search to completion and counts the matches, grouped by the given key. Unlike results,
+    # the result bodies are not returned, so aggregations are not subject to the default result limit.
+    aggregate(
+        # What to group the matches by.
+        by: SearchAggregationKey!
+        # When grouping by PATH, the number of leading path components to group by.
+        pathDepth: Int = 1
+    ): SearchAggregation!
+}
+
+# A key that search results can be aggregated by.
+enum SearchAggregationKey {
+    # The repository of the result.
+    REPOSITORY
+    # The leading components of the path of a file match (see Search.aggregate's pathDepth).
+    PATH
+    # The language of a file match, as detected from its file name.
+    LANGUAGE
+    # The author of a diff or commit search result. Only valid for type:diff and type:commit queries.
+    AUTHOR
+}
+
+# Aggregated counts of the matches of a search.
+type SearchAggregation {
+    # The groups, ordered by descending match count. Results that have no value for the aggregation key
+    # (such as repository matches when grouping by language) are omitted.
+    groups: [SearchAggregationGroup!]!
+    # The total number of matches in all groups. Each diff or commit search result counts as one match.
+    matchCount: Int!
+    # The total number of results in all groups.
+    resultCount: Int!
+    # Whether the counts are incomplete (and are lower bounds), because a result limit was hit or some
+    # repositories timed out, were cloning, or are missing.
+    limitHit: Boolean!
+    # Repositories that are busy cloning onto gitserver and were not counted.
+    cloning: [Repository!]!
+    # Repositories or commits that could not be searched in time and were not counted.
+    timedout: [Repository!]!
+}
+
+# A group of search matches that share the same value for the aggregation key.
+type SearchAggregationGroup {
+    # The value of the aggregation key. For LANGUAGE, this is the empty string for files whose language is unknown.
+    key: String!
+    # The number of matches in the group. Each diff or commit search result counts as one match.
+    matchCount: Int!
+    # The number of results (files, repositories, diffs or commits) in the group.
+    resultCount: Int!
 }
 
 # A search result.
@@ -991,6 +1173,35 @@ type SavedSearch implements Node {
     orgID: ID
     # The Slack webhook URL associated with this saved search, if any.
     slackWebhookURL: String
+    # Whether or not to POST notifications of new results to webhookURL.
+    notifyWebhook: Boolean!
+    # The URL of the generic webhook that notifications are sent to, if any. Each notification is a JSON
+    # payload, signed with the webhook secret (if set) in the X-Sourcegraph-Signature header as
+    # "sha256=" followed by the hex-encoded HMAC-SHA256 of the request body.
+    webhookURL: String
+    # The most recent webhook notification deliveries, newest first.
+    webhookDeliveries(
+        # Returns the first n deliveries.
+        first: Int = 20
+    ): [SavedSearchWebhookDelivery!]!
+}
+
+# An attempt to deliver a webhook notification for a saved search.
+type SavedSearchWebhookDelivery {
+    # The URL that the notification was sent to.
+    url: String!
+    # Whether this was a test notification.
+    test: Boolean!
+    # The number of new results in the notification.
+    resultCount: Int!
+    # The number of times delivery was attempted (failed deliveries are retried with backoff).
+    attempts: Int!
+    # The HTTP status code of the last attempt, if a response was received.
+    statusCode: Int
+    # The error of the last attempt, or null if the notification was delivered.
+    error: String
+    # When the notification was sent.
+    createdAt: String!
 }
 
 # A search query description.
@@ -1003,10 +1214,30 @@ type SearchQueryDescription {
 
 # A group of repositories.
 type RepoGroup {
+    # The unique ID of the group, or null if the group is defined in settings.
+    id: ID
     # The name.
     name: String!
+    # The description.
+    description: String
     # The repositories.
     repositories: [String!]!
+    # The rule that determines the repositories, or null if they are listed
+    # explicitly.
+    rule: RepoGroupRule
+}
+
+# A rule that determines the repositories in a repository group. A repository
+# is in the group if it satisfies all of the non-empty criteria.
+type RepoGroupRule {
+    # A regular expression that the repository name must match.
+    namePattern: String
+    # The IDs of external services, one of which the repository must come from.
+    externalServiceIDs: [ID!]!
+    # Code host topics, one of which the repository must have.
+    topics: [String!]!
+    # Code host owners, one of which must own the repository.
+    owners: [String!]!
 }
 
 # A diff between two diffable Git objects.
@@ -1083,6 +1314,7 @@ type ExternalServiceConnection {
 enum ExternalServiceKind {
     AWSCODECOMMIT
     BITBUCKETSERVER
+    GITEA
     GITHUB
     GITLAB
     GITOLITE
@@ -2202,6 +2434,11 @@ type AccessToken implements Node {
     createdAt: String!
     # The date when the access token was last used to authenticate a request.
     lastUsedAt: String
+    # The date after which the access token is no longer valid, or null if it does not expire.
+    expiresAt: String
+    # The names of the only repositories that the access token may be used to access, or null if it may be
+    # used to access all repositories that its subject user can access.
+    repoAllowlist: [String!]
 }
 
 # A list of access tokens.
@@ -2281,6 +2518,37 @@ type ExternalAccount implements Node {
     accountData: JSONValue
 }
 
+# A repository permission explicitly granted by a site admin to a user or to all members of an organization.
+type ExplicitRepositoryPermission {
+    # The unique ID for the permission.
+    id: ID!
+    # The user who is granted the permission, or null if it is granted to an organization.
+    user: User
+    # The organization whose members are granted the permission, or null if it is granted to a user.
+    organization: Org
+    # The repository that the permission applies to, or null if it applies to the repositories matching
+    # repositoryPattern.
+    repository: Repository
+    # The pattern of the names of the repositories that the permission applies to, or null if it applies to a
+    # single repository.
+    repositoryPattern: String
+    # The type of permission (currently always "read").
+    permission: String!
+    # The date when the permission was granted.
+    createdAt: String!
+}
+
+# A list of explicitly granted repository permissions.
+type ExplicitRepositoryPermissionConnection {
+    # A list of explicitly granted repository permissions.
+    nodes: [ExplicitRepositoryPermission!]!
+    # The total count of explicitly granted repository permissions in the connection. This total count may be
+    # larger than the number of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
 # An active user session.
 type Session {
     # Whether the user can sign out of this session on Sourcegraph.
@@ -2428,6 +2696,12 @@ type DiscussionSelectionRange {
 
     # The character (not byte) of the end line that the selection ended on (zero-based, exclusive).
     endCharacter: Int!
+
+    # Whether the selected lines were modified (changed, removed, or had lines
+    # inserted between them) since the revision that the thread was created on.
+    # Changes to the surrounding lines only move the selection and do not make
+    # it outdated.
+    outdated: Boolean!
 }
 
 # A selection within a file.
@@ -2498,16 +2772,23 @@ type DiscussionThreadTargetRepo {
     relativePath(rev: String!): String
 
     # Where the selection would be relative to the given Git revision specifier
-    # (branch/commit/etc).
-    #
-    # The implementation relies on a hueristic which is generally good enough,
-    # but under certain circumstances may not be as accurate as e.g. determining
-    # this placement by walking through the Git history.
-    #
-    # If determining the relative placement is not possible (file was renamed
-    # or removed, the selection no longer exists in the file, or the hueristic
-    # failed) null is returned and it should be assumed the selection does not
-    # exist in this revision.
+    # (branch/commit/etc), accounting for file renames.
+    #
+    # If the thread was created on an exact revision, the selection is moved
+    # through the hunks of the diff between that revision and the given one.
+    # If the selected lines were modified but exist unchanged elsewhere in the
+    # file (e.g. because the code was moved), that location is returned
+    # instead.
+    #
+    # Otherwise (e.g. the thread was created on a branch, which may have moved
+    # since), the implementation relies on a hueristic which searches for the
+    # stored lines and their context. It is generally good enough, but under
+    # certain circumstances may not be as accurate.
+    #
+    # If determining the relative placement is not possible (file was removed,
+    # the selection no longer exists in the file, or the hueristic failed) null
+    # is returned and it should be assumed the selection does not exist in this
+    # revision.
     relativeSelection(rev: String!): DiscussionSelectionRange
 }
 
@@ -2549,6 +2830,17 @@ type DiscussionThread {
     # The date when the discussion thread was archived (or null if it has not).
     archivedAt: String
 
+    # The date when the discussion thread was resolved (or null if it is
+    # unresolved).
+    resolvedAt: String
+
+    # The user who resolved the discussion thread (or null if it is unresolved
+    # or the user has been deleted).
+    resolvedBy: User
+
+    # The users assigned to the discussion thread.
+    assignees: [User!]!
+
     # The comments in the discussion thread.
     comments(
         # Returns the first n comments from the list.
@@ -2610,6 +2902,25 @@ type DiscussionComment {
     #
     # This is always false when discussions.abuseProtection in the site config is set to false.
     canClearReports: Boolean!
+
+    # The reactions to the comment, grouped by emoji. Only the emoji 👍, 👎,
+    # 😄, 🎉, 😕, ❤️, 🚀 and 👀 are supported.
+    reactions: [DiscussionCommentReactionGroup!]!
+}
+
+# The reactions to a discussion comment with a single emoji.
+type DiscussionCommentReactionGroup {
+    # The emoji of the reactions.
+    emoji: String!
+
+    # The number of users who reacted with the emoji.
+    count: Int!
+
+    # The users who reacted with the emoji, in the order they reacted.
+    users: [User!]!
+
+    # Whether the current user reacted with the emoji.
+    viewerHasReacted: Boolean!
 }
 
 # A list of discussion threads.
@@ -2724,6 +3035,21 @@ type Site implements SettingsSubject {
         # Include only external accounts with this client ID.
         clientID: String
     ): ExternalAccountConnection!
+    # A list of all repository permissions explicitly granted by site admins (see
+    # Mutation.grantRepositoryPermission), most recently granted first.
+    #
+    # Only site admins may access this field.
+    explicitRepositoryPermissions(
+        # Returns the first n permissions from the list.
+        first: Int
+        # Include only permissions granted to this user (not including those granted to the user's
+        # organizations).
+        user: ID
+        # Include only permissions granted to this organization.
+        organization: ID
+        # Include only permissions on this repository (not including those whose pattern matches it).
+        repository: ID
+    ): ExplicitRepositoryPermissionConnection!
     # The build version of the Sourcegraph software that is running on this site (of the form
     # NNNNN_YYYY-MM-DD_XXXXX, like 12345_2018-01-01_abcdef).
     buildVersion: String!
@@ -2784,6 +3110,55 @@ type SiteConfiguration {
     # This includes both JSON Schema validation problems and other messages that perform more advanced checks
     # on the configuration (that can't be expressed in the JSON Schema).
     validationMessages: [String!]!
+    # The past versions of the site configuration, newest first.
+    history(
+        # Returns the first n versions from the list.
+        first: Int
+        # Skips the first n (newest) versions, to page through the list.
+        offset: Int
+    ): SiteConfigurationVersionConnection!
+    # The fields whose values differ between two versions of the site configuration.
+    diff(
+        # The ID of the older site configuration version.
+        from: Int!
+        # The ID of the newer site configuration version.
+        to: Int!
+    ): [SiteConfigurationFieldDiff!]!
+}
+
+# A list of site configuration versions.
+type SiteConfigurationVersionConnection {
+    # A list of site configuration versions.
+    nodes: [SiteConfigurationVersion!]!
+    # The total count of site configuration versions in the connection. This total count may be larger than the
+    # number of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A past version of the site configuration.
+type SiteConfigurationVersion {
+    # The unique identifier of this site configuration version.
+    id: Int!
+    # The raw JSON contents of this site configuration version.
+    contents: String!
+    # The user who saved this site configuration version, or null if it was not saved by a user (or the user
+    # no longer exists).
+    author: User
+    # The date when this site configuration version was saved.
+    createdAt: String!
+}
+
+# A site configuration field whose value differs between two site configuration versions.
+type SiteConfigurationFieldDiff {
+    # The name of the field, such as "maxReposToSearch" or (for critical configuration fields)
+    # "critical::externalURL".
+    field: String!
+    # The value of the field in the older version, or null if it is unset.
+    before: JSONValue
+    # The value of the field in the newer version, or null if it is unset.
+    after: JSONValue
 }
 
 # Information about software updates for Sourcegraph.
@@ -3190,7 +3565,20 @@ type ExtensionRegistryMutation {
         # The JavaScript bundle's "//# sourceMappingURL=" directive, if any, is ignored. When the bundle is served,
         # the source map provided here is referenced instead.
         sourceMap: String
-        # Force publish even if there are warnings (such as invalid JSON warnings).
+        # The semantic version of the release (e.g., "1.2.3" or "1.3.0-beta.1"), or null for an unversioned
+        # release. Each version may be published only once. Sites can only pin an extension to a version range
+        # if its releases have versions.
+        version: String
+        # The release channel: "stable" (the default) or "beta". Beta releases are only used by sites that
+        # request the beta channel for the extension.
+        channel: String
+        # The publisher's signature of the release, made with an SSH private key over the extension ID (without
+        # the registry name) and the SHA-256 hash of the bundle. It uses the same signing scheme as license keys.
+        # A bundle is required to sign a release. If the site configuration requires signed extensions, the
+        # release must be signed by one of the site's trusted publisher keys.
+        signature: String
+        # Force publish even if there are warnings (such as invalid JSON or extension manifest JSON Schema
+        # warnings).
         force: Boolean = false
     ): ExtensionRegistryCreateExtensionResult!
 }
@@ -3252,6 +3640,19 @@ type RegistryExtension implements Node {
     isWorkInProgress: Boolean!
     # Whether the viewer has admin privileges on this registry extension.
     viewerCanAdminister: Boolean!
+    # The publisher's signature of the release whose manifest is returned, or null if the release is unsigned.
+    signature: ExtensionReleaseSignature
+}
+
+# The verified signature of an extension release by its publisher.
+type ExtensionReleaseSignature {
+    # The SHA-256 hash (hex-encoded) of the release's bundled JavaScript source file, as published, without
+    # its "//# sourceMappingURL=" directives.
+    bundleSHA256: String!
+    # The SHA-256 fingerprint of the public key that signed the release (e.g., "SHA256:...").
+    keyFingerprint: String!
+    # Whether the key is one of the trusted publisher keys in site configuration.
+    isTrusted: Boolean!
 }
 
 # A description of the extension, how to run or access it, and when to activate it.
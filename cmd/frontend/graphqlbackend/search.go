@@ -59,6 +59,8 @@ func (r *schemaResolver) Search(args *struct {
 	Suggestions(context.Context, *searchSuggestionsArgs) ([]*searchSuggestionResolver, error)
 	//lint:ignore U1000 is used by graphql via reflection
 	Stats(context.Context) (*searchResultsStats, error)
+	//lint:ignore U1000 is used by graphql via reflection
+	Aggregate(context.Context, *searchAggregationArgs) (*searchAggregationResolver, error)
 }, error) {
 	tr, _ := trace.New(context.Background(), "graphql.schemaResolver", "Search")
 	defer tr.Finish()
@@ -112,6 +114,19 @@ func asString(v *searchquerytypes.Value) string {
 type searchResolver struct {
 	query *query.Query // the parsed search query
 
+	// exhaustive is whether the search should run to completion, ignoring the
+	// default result limit and timeout (used for aggregations).
+	exhaustive bool
+
+	// countOnly is whether only the number of matches of the results is
+	// needed (see search.PatternInfo.CountOnly).
+	countOnly bool
+
+	// onFileMatch, if non-nil, is called with each file match of a text
+	// search as soon as it is found, instead of the file match being
+	// included in the results. It is not called concurrently.
+	onFileMatch func(*fileMatchResolver)
+
 	// Cached resolveRepositories results.
 	reposMu                   sync.Mutex
 	repoRevs, missingRepoRevs []*search.RepositoryRevisions
@@ -134,6 +149,9 @@ func (r *searchResolver) countIsSet() bool {
 const defaultMaxSearchResults = 30
 
 func (r *searchResolver) maxResults() int32 {
+	if r.exhaustive {
+		return maxExhaustiveSearchResults
+	}
 	count, _ := r.query.StringValues(query.FieldCount)
 	if len(count) > 0 {
 		n, _ := strconv.Atoi(count[0])
@@ -158,6 +176,27 @@ func resolveRepoGroups(ctx context.Context) (map[string][]*types.Repo, error) {
 		return mockResolveRepoGroups()
 	}
 
+	// Repo groups can be stored in the database by site admins.
+	groups, err := db.RepoGroups.ListMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Groups defined in settings take precedence, because they are more
+	// specific to the viewer.
+	settingsGroups, err := resolveSettingsRepoGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for name, repos := range settingsGroups {
+		groups[name] = repos
+	}
+	return groups, nil
+}
+
+// resolveSettingsRepoGroups returns the repo groups that are defined in the
+// viewer's settings (and the built-in ones on Sourcegraph.com).
+func resolveSettingsRepoGroups(ctx context.Context) (map[string][]*types.Repo, error) {
 	groups := map[string][]*types.Repo{}
 
 	// Repo groups can be defined in the search.repoGroups settings field.
@@ -538,7 +577,7 @@ func (r *searchResolver) suggestFilePaths(ctx context.Context, limit int) ([]*se
 		return nil, err
 	}
 
-	fileResults, _, err := searchFilesInRepos(ctx, &args)
+	fileResults, _, err := searchFilesInRepos(ctx, &args, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -666,28 +705,30 @@ func sortSearchSuggestions(s []*searchSuggestionResolver) {
 	})
 }
 
-// langIncludeExcludePatterns returns regexps for the include/exclude path patterns given the lang:
-// and -lang: filter values in a search query. For example, a query containing "lang:go" should
-// include files whose paths match /\.go$/.
-func langIncludeExcludePatterns(values, negatedValues []string) (includePatterns, excludePatterns []string, err error) {
-	lookup := func(value string) *filelang.Language {
-		value = strings.ToLower(value)
-		for _, lang := range filelang.Langs {
-			if strings.ToLower(lang.Name) == value {
+// lookupLanguage returns the language whose name or alias is value (ignoring
+// case), or nil if there is none.
+func lookupLanguage(value string) *filelang.Language {
+	value = strings.ToLower(value)
+	for _, lang := range filelang.Langs {
+		if strings.ToLower(lang.Name) == value {
+			return lang
+		}
+		for _, alias := range lang.Aliases {
+			if alias == value {
 				return lang
 			}
-			for _, alias := range lang.Aliases {
-				if alias == value {
-					return lang
-				}
-			}
 		}
-		return nil
 	}
+	return nil
+}
 
+// langIncludeExcludePatterns returns regexps for the include/exclude path patterns given the lang:
+// and -lang: filter values in a search query. For example, a query containing "lang:go" should
+// include files whose paths match /\.go$/.
+func langIncludeExcludePatterns(values, negatedValues []string) (includePatterns, excludePatterns []string, err error) {
 	do := func(values []string, patterns *[]string) error {
 		for _, value := range values {
-			lang := lookup(value)
+			lang := lookupLanguage(value)
 			if lang == nil {
 				return fmt.Errorf("unknown language: %q", value)
 			}
@@ -119,6 +119,10 @@ func (r *searcherResolver) Stats(ctx context.Context) (stats *searchResultsStats
 	return nil, errors.New("search stats not implemented")
 }
 
+func (r *searcherResolver) Aggregate(ctx context.Context, args *searchAggregationArgs) (*searchAggregationResolver, error) {
+	return nil, errors.New("search aggregation not implemented")
+}
+
 func toSearchResultResolvers(ctx context.Context, sCtx *searchContext, r *search.Result) ([]*searchResultResolver, error) {
 	results := make([]*searchResultResolver, 0, len(r.Files))
 
@@ -0,0 +1,204 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory/filelang"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+)
+
+// maxExhaustiveSearchResults is the result limit of exhaustive searches. It is
+// high enough that in practice searches run to completion, but it keeps a
+// runaway query from exhausting the frontend's memory.
+const maxExhaustiveSearchResults = 1 << 20
+
+// Keys that search results can be aggregated by (the SearchAggregationKey
+// GraphQL enum).
+const (
+	aggregateByRepository = "REPOSITORY"
+	aggregateByPath       = "PATH"
+	aggregateByLanguage   = "LANGUAGE"
+	aggregateByAuthor     = "AUTHOR"
+)
+
+type searchAggregationArgs struct {
+	By        string
+	PathDepth int32
+}
+
+// Aggregate runs the search to completion and returns the number of matches
+// grouped by args.By. The backends only count the matches of file results
+// (instead of returning their contents), and diff and commit results count as
+// one match per commit. If the counts are incomplete for any reason, the
+// aggregation's limitHit is set.
+func (r *searchResolver) Aggregate(ctx context.Context, args *searchAggregationArgs) (*searchAggregationResolver, error) {
+	if args.By == aggregateByAuthor && !r.isCommitSearch() {
+		return nil, errors.New("aggregating by author requires a diff or commit search (type:diff or type:commit)")
+	}
+	if args.By == aggregateByPath && args.PathDepth < 1 {
+		return nil, errors.New("pathDepth must be at least 1")
+	}
+
+	exhaustive := &searchResolver{query: r.query, exhaustive: true, countOnly: true}
+	rr, err := exhaustive.doResults(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if rr.alert != nil && len(rr.results) == 0 {
+		return nil, fmt.Errorf("%s: %s", rr.alert.title, rr.alert.description)
+	}
+
+	agg := aggregateSearchResults(rr.results, args.By, int(args.PathDepth))
+	agg.searchResultsCommon = rr.searchResultsCommon
+	agg.limitHit = agg.limitHit || searchResultsIncomplete(&rr.searchResultsCommon)
+	return agg, nil
+}
+
+// searchResultsIncomplete reports whether some results may be missing from a
+// search's results, so that counts of them would not be exact.
+func searchResultsIncomplete(c *searchResultsCommon) bool {
+	return c.LimitHit() || len(c.partial) > 0 || len(c.timedout) > 0 || len(c.cloning) > 0 || len(c.missing) > 0 || c.indexUnavailable
+}
+
+// isCommitSearch reports whether the query only searches diffs or commits.
+func (r *searchResolver) isCommitSearch() bool {
+	types, _ := r.query.StringValues(query.FieldType)
+	if len(types) == 0 {
+		return false
+	}
+	for _, t := range types {
+		if t != "diff" && t != "commit" {
+			return false
+		}
+	}
+	return true
+}
+
+// searchAggregationResolver is a resolver for the GraphQL type
+// `SearchAggregation`.
+type searchAggregationResolver struct {
+	searchResultsCommon
+
+	groups      []*searchAggregationGroupResolver
+	matchCount  int32
+	resultCount int32
+	limitHit    bool
+}
+
+func (r *searchAggregationResolver) Groups() []*searchAggregationGroupResolver { return r.groups }
+func (r *searchAggregationResolver) MatchCount() int32                         { return r.matchCount }
+func (r *searchAggregationResolver) ResultCount() int32                        { return r.resultCount }
+func (r *searchAggregationResolver) LimitHit() bool                            { return r.limitHit }
+
+// searchAggregationGroupResolver is a resolver for the GraphQL type
+// `SearchAggregationGroup`.
+type searchAggregationGroupResolver struct {
+	key         string
+	matchCount  int32
+	resultCount int32
+}
+
+func (g *searchAggregationGroupResolver) Key() string        { return g.key }
+func (g *searchAggregationGroupResolver) MatchCount() int32  { return g.matchCount }
+func (g *searchAggregationGroupResolver) ResultCount() int32 { return g.resultCount }
+
+var languagesByFilename = filelang.Langs.CompileByFilename()
+
+// aggregateSearchResults counts the matches in results, grouped by the given
+// aggregation key. Results that have no value for the key (such as repository
+// results when aggregating by language) are ignored.
+func aggregateSearchResults(results []*searchResultResolver, by string, pathDepth int) *searchAggregationResolver {
+	agg := &searchAggregationResolver{}
+	groups := map[string]*searchAggregationGroupResolver{}
+	add := func(key string, matches int32) {
+		g, ok := groups[key]
+		if !ok {
+			g = &searchAggregationGroupResolver{key: key}
+			groups[key] = g
+		}
+		g.matchCount += matches
+		g.resultCount++
+		agg.matchCount += matches
+		agg.resultCount++
+	}
+
+	for _, result := range results {
+		switch {
+		case result.fileMatch != nil:
+			fm := result.fileMatch
+			agg.limitHit = agg.limitHit || fm.JLimitHit
+			matches := fileMatchCount(fm)
+			switch by {
+			case aggregateByRepository:
+				add(string(fm.repo.Name), matches)
+			case aggregateByPath:
+				add(pathPrefix(fm.JPath, pathDepth), matches)
+			case aggregateByLanguage:
+				var lang string
+				if langs := languagesByFilename(path.Base(fm.JPath)); len(langs) > 0 {
+					lang = langs[0].Name
+				}
+				add(lang, matches)
+			}
+
+		case result.diff != nil:
+			// Each commit counts as one match, because the highlighted
+			// matches in its diff or message are limited.
+			c := result.diff
+			switch by {
+			case aggregateByRepository:
+				add(c.commit.repo.Name(), 1)
+			case aggregateByAuthor:
+				add(fmt.Sprintf("%s <%s>", c.commit.author.person.name, c.commit.author.person.email), 1)
+			}
+
+		case result.repo != nil:
+			if by == aggregateByRepository {
+				add(result.repo.Name(), 1)
+			}
+		}
+	}
+
+	agg.groups = make([]*searchAggregationGroupResolver, 0, len(groups))
+	for _, g := range groups {
+		agg.groups = append(agg.groups, g)
+	}
+	sort.Slice(agg.groups, func(i, j int) bool {
+		if agg.groups[i].matchCount != agg.groups[j].matchCount {
+			return agg.groups[i].matchCount > agg.groups[j].matchCount
+		}
+		return agg.groups[i].key < agg.groups[j].key
+	})
+	return agg
+}
+
+// fileMatchCount returns the number of matches in a file match. Files that
+// match only by path or symbol count as one match (or one per symbol).
+func fileMatchCount(fm *fileMatchResolver) int32 {
+	n := fm.JMatchCount
+	for _, lm := range fm.JLineMatches {
+		n += int32(len(lm.JOffsetAndLengths))
+	}
+	if n == 0 {
+		n = int32(len(fm.symbols))
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// pathPrefix returns the first depth components of p, with a trailing slash if
+// p has more components than that (i.e. if the prefix is a directory).
+func pathPrefix(p string, depth int) string {
+	parts := strings.SplitN(p, "/", depth+1)
+	if len(parts) <= depth {
+		return p
+	}
+	return strings.Join(parts[:depth], "/") + "/"
+}
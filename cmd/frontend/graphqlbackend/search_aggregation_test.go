@@ -0,0 +1,160 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestAggregateSearchResults(t *testing.T) {
+	repoA := &types.Repo{Name: "github.com/foo/a"}
+	repoB := &types.Repo{Name: "github.com/foo/b"}
+
+	commit := func(repo *types.Repo, name, email string, highlights int) *searchResultResolver {
+		return &searchResultResolver{diff: &commitSearchResultResolver{
+			commit: &gitCommitResolver{
+				repo:   &repositoryResolver{repo: repo},
+				author: signatureResolver{person: &personResolver{name: name, email: email}},
+			},
+			diffPreview: &highlightedString{highlights: make([]*highlightedRange, highlights)},
+		}}
+	}
+	countOnlyFileResults := []*searchResultResolver{
+		{fileMatch: &fileMatchResolver{JPath: "a.go", repo: repoA, JMatchCount: 250}},
+		{fileMatch: &fileMatchResolver{JPath: "b.go", repo: repoA}}, // matched by path
+	}
+
+	fileResults := []*searchResultResolver{
+		{fileMatch: &fileMatchResolver{
+			JPath: "cmd/a/main.go",
+			repo:  repoA,
+			JLineMatches: []*lineMatch{
+				{JOffsetAndLengths: [][2]int32{{0, 1}, {4, 1}}},
+				{JOffsetAndLengths: [][2]int32{{2, 1}}},
+			},
+		}},
+		{fileMatch: &fileMatchResolver{JPath: "cmd/b/main.go", repo: repoB, JLimitHit: true}},
+		{fileMatch: &fileMatchResolver{JPath: "README.md", repo: repoB}},
+		{fileMatch: &fileMatchResolver{JPath: "LICENSE.unknown-extension", repo: repoB}},
+		{repo: &repositoryResolver{repo: repoA}},
+	}
+
+	type group struct {
+		Key         string
+		MatchCount  int32
+		ResultCount int32
+	}
+	tests := map[string]struct {
+		results    []*searchResultResolver
+		by         string
+		pathDepth  int
+		want       []group
+		wantCounts [2]int32 // matches, results
+	}{
+		"repository": {
+			results:    fileResults,
+			by:         aggregateByRepository,
+			want:       []group{{"github.com/foo/a", 4, 2}, {"github.com/foo/b", 3, 3}},
+			wantCounts: [2]int32{7, 5},
+		},
+		"path depth 1": {
+			results:    fileResults,
+			by:         aggregateByPath,
+			pathDepth:  1,
+			want:       []group{{"cmd/", 4, 2}, {"LICENSE.unknown-extension", 1, 1}, {"README.md", 1, 1}},
+			wantCounts: [2]int32{6, 4},
+		},
+		"path depth 2": {
+			results:    fileResults,
+			by:         aggregateByPath,
+			pathDepth:  2,
+			want:       []group{{"cmd/a/", 3, 1}, {"LICENSE.unknown-extension", 1, 1}, {"README.md", 1, 1}, {"cmd/b/", 1, 1}},
+			wantCounts: [2]int32{6, 4},
+		},
+		"count only": {
+			results:    countOnlyFileResults,
+			by:         aggregateByRepository,
+			want:       []group{{"github.com/foo/a", 251, 2}},
+			wantCounts: [2]int32{251, 2},
+		},
+		"language": {
+			results:    fileResults,
+			by:         aggregateByLanguage,
+			want:       []group{{"Go", 4, 2}, {"", 1, 1}, {"Markdown", 1, 1}},
+			wantCounts: [2]int32{6, 4},
+		},
+		"author": {
+			results: []*searchResultResolver{
+				commit(repoA, "Alice", "alice@example.com", 2),
+				commit(repoB, "Alice", "alice@example.com", 0),
+				commit(repoB, "Bob", "bob@example.com", 5),
+			},
+			by:         aggregateByAuthor,
+			want:       []group{{"Alice <alice@example.com>", 2, 2}, {"Bob <bob@example.com>", 1, 1}},
+			wantCounts: [2]int32{3, 3},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			agg := aggregateSearchResults(test.results, test.by, test.pathDepth)
+			got := make([]group, len(agg.groups))
+			for i, g := range agg.groups {
+				got[i] = group{g.key, g.matchCount, g.resultCount}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got groups %v, want %v", got, test.want)
+			}
+			if counts := [2]int32{agg.matchCount, agg.resultCount}; counts != test.wantCounts {
+				t.Errorf("got counts %v, want %v", counts, test.wantCounts)
+			}
+		})
+	}
+
+	if agg := aggregateSearchResults(fileResults, aggregateByRepository, 0); !agg.limitHit {
+		t.Error("got limitHit false, want true (a file match hit its limit)")
+	}
+}
+
+func TestSearchResultsIncomplete(t *testing.T) {
+	repo := &types.Repo{Name: "github.com/foo/a"}
+	tests := map[string]struct {
+		common searchResultsCommon
+		want   bool
+	}{
+		"complete":          {common: searchResultsCommon{searched: []*types.Repo{repo}}},
+		"limit hit":         {common: searchResultsCommon{limitHit: true}, want: true},
+		"partial":           {common: searchResultsCommon{partial: map[api.RepoName]struct{}{repo.Name: {}}}, want: true},
+		"timed out":         {common: searchResultsCommon{timedout: []*types.Repo{repo}}, want: true},
+		"cloning":           {common: searchResultsCommon{cloning: []*types.Repo{repo}}, want: true},
+		"missing":           {common: searchResultsCommon{missing: []*types.Repo{repo}}, want: true},
+		"index unavailable": {common: searchResultsCommon{indexUnavailable: true}, want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := searchResultsIncomplete(&test.common); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"a/b/c.go", 1, "a/"},
+		{"a/b/c.go", 2, "a/b/"},
+		{"a/b/c.go", 3, "a/b/c.go"},
+		{"a/b/c.go", 4, "a/b/c.go"},
+		{"c.go", 1, "c.go"},
+	}
+	for _, test := range tests {
+		if got := pathPrefix(test.path, test.depth); got != test.want {
+			t.Errorf("pathPrefix(%q, %d) = %q, want %q", test.path, test.depth, got, test.want)
+		}
+	}
+}
@@ -297,7 +297,7 @@ outer:
 		newExpr := addQueryRegexpField(r.query, query.FieldRepo, repoParentPattern)
 		alert.proposedQueries = append(alert.proposedQueries, &searchQueryDescription{
 			description: "in repositories under " + repoParent + more,
-			query:       syntax.ExprString(newExpr),
+			query:       syntax.QueryString(newExpr, r.query.Syntax.Tree),
 		})
 	}
 	if len(alert.proposedQueries) == 0 || ctx.Err() == context.DeadlineExceeded {
@@ -315,7 +315,7 @@ outer:
 			newExpr := addQueryRegexpField(r.query, query.FieldRepo, "^"+regexp.QuoteMeta(pathToPropose)+"$")
 			alert.proposedQueries = append(alert.proposedQueries, &searchQueryDescription{
 				description: "in the repository " + strings.TrimPrefix(pathToPropose, "github.com/"),
-				query:       syntax.ExprString(newExpr),
+				query:       syntax.QueryString(newExpr, r.query.Syntax.Tree),
 			})
 		}
 	}
@@ -345,7 +345,7 @@ func (r *searchResolver) alertForMissingRepoRevs(missingRepoRevs []*search.Repos
 }
 
 func omitQueryFields(r *searchResolver, field string) string {
-	return syntax.ExprString(omitQueryExprWithField(r.query, field))
+	return syntax.QueryString(omitQueryExprWithField(r.query, field), r.query.Syntax.Tree)
 }
 
 func omitQueryExprWithField(query *query.Query, field string) []*syntax.Expr {
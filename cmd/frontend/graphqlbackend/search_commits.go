@@ -241,6 +241,12 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 
 	// if the result is incomplete, git log timed out and the client should be notified of that
 	timedOut = !complete
+	if op.info.Expr != nil {
+		rawResults, err = filterCommitsByExpr(rawResults, op.info, op.diff)
+		if err != nil {
+			return nil, false, false, err
+		}
+	}
 	if len(rawResults) > maxResults {
 		limitHit = true
 		rawResults = rawResults[:maxResults]
@@ -252,6 +258,10 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 		commit := rawResult.Commit
 		commitResolver := toGitCommitResolver(repoResolver, &commit)
 		results[i] = &commitSearchResultResolver{commit: commitResolver}
+		if op.info.CountOnly {
+			// Only the commits are counted, so don't keep their contents.
+			continue
+		}
 
 		addRefs := func(dst *[]*gitRefResolver, src []string) {
 			for _, ref := range src {
@@ -319,6 +329,38 @@ func searchCommitsInRepo(ctx context.Context, op commitSearchOp) (results []*com
 	return results, limitHit, timedOut, nil
 }
 
+// filterCommitsByExpr evaluates the boolean expression of patterns info.Expr
+// on commits that git matched against the union of its patterns. The
+// expression is evaluated against the lines of the commit message, or the
+// added and removed lines of the matching diff hunks.
+func filterCommitsByExpr(results []*git.LogCommitSearchResult, info *search.PatternInfo, diff bool) ([]*git.LogCommitSearchResult, error) {
+	m, err := info.Expr.Compile(info.IsCaseSensitive)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := results[:0]
+	for _, r := range results {
+		var units []string
+		if diff {
+			if r.Diff == nil {
+				continue
+			}
+			for _, line := range strings.Split(r.Diff.Raw, "\n") {
+				if (strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++")) || (strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---")) {
+					units = append(units, line[1:])
+				}
+			}
+		} else {
+			units = strings.Split(r.Commit.Message, "\n")
+		}
+		if m.Match(units) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
+
 func cleanDiffPreview(highlights []*highlightedRange, rawDiffResult string) (string, []*highlightedRange) {
 	// A map of line number to number of lines that have been ignored before the particular line number.
 	var lineByCountIgnored = make(map[int]int32)
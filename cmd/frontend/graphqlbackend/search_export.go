@@ -0,0 +1,166 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+)
+
+// searchExportPageSize is the number of repositories that are searched at a
+// time when exporting search results. Searching a page of repositories at a
+// time bounds the number of results that are held in memory.
+const searchExportPageSize = 100
+
+// SearchExportRow is a single match of an exported search.
+type SearchExportRow struct {
+	Repo       string `json:"repo"`
+	Commit     string `json:"commit,omitempty"`
+	Path       string `json:"path,omitempty"`
+	LineNumber int32  `json:"lineNumber,omitempty"` // 1-based, or 0 if the match is not on a line
+	Preview    string `json:"preview,omitempty"`
+
+	// Commit metadata, only set for diff and commit search results.
+	AuthorName  string `json:"authorName,omitempty"`
+	AuthorEmail string `json:"authorEmail,omitempty"`
+	AuthorDate  string `json:"authorDate,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+}
+
+// SearchExportRowFields are the names of the fields of a SearchExportRow, in
+// the order returned by (*SearchExportRow).Values.
+var SearchExportRowFields = []string{"repo", "commit", "path", "lineNumber", "preview", "authorName", "authorEmail", "authorDate", "subject"}
+
+// Values returns the values of the fields of r, in the order of
+// SearchExportRowFields.
+func (r *SearchExportRow) Values() []string {
+	var lineNumber string
+	if r.LineNumber > 0 {
+		lineNumber = fmt.Sprint(r.LineNumber)
+	}
+	return []string{r.Repo, r.Commit, r.Path, lineNumber, r.Preview, r.AuthorName, r.AuthorEmail, r.AuthorDate, r.Subject}
+}
+
+// ExportSearchResults runs the search query to completion and calls fn with
+// each match. Text search file matches are passed to fn as soon as searcher
+// sends them. Unlike the GraphQL search API, the default result count limit
+// does not apply, and the repositories are searched a page at a time so that
+// arbitrarily large result sets can be exported.
+//
+// It returns whether the results are incomplete (e.g. because a repository
+// timed out or a file had too many matches).
+//
+// 🚨 SECURITY: Repository permissions are enforced by the repository
+// resolution of the search, so only repositories that the actor in ctx can
+// read are searched.
+func ExportSearchResults(ctx context.Context, rawQuery string, fn func(*SearchExportRow) error) (limitHit bool, err error) {
+	q, err := query.ParseAndCheck(rawQuery)
+	if err != nil {
+		return false, err
+	}
+
+	r := &searchResolver{query: q, exhaustive: true}
+	repos, _, _, overLimit, err := r.resolveRepositories(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if overLimit {
+		alert, err := r.alertForOverRepoLimit(ctx)
+		if err != nil {
+			return false, err
+		}
+		return false, errors.New(alert.title)
+	}
+
+	for len(repos) > 0 {
+		n := searchExportPageSize
+		if n > len(repos) {
+			n = len(repos)
+		}
+		var page []*search.RepositoryRevisions
+		page, repos = repos[:n], repos[n:]
+
+		// Pre-populate the resolved repositories, so that only this page is
+		// searched. File matches are written as soon as they are found.
+		pageCtx, cancel := context.WithCancel(ctx)
+		var fnErr error
+		pr := &searchResolver{query: q, exhaustive: true, repoRevs: page, onFileMatch: func(fm *fileMatchResolver) {
+			if fnErr != nil {
+				return
+			}
+			rows, hit := searchExportRows(&searchResultResolver{fileMatch: fm})
+			limitHit = limitHit || hit
+			for _, row := range rows {
+				if fnErr = fn(row); fnErr != nil {
+					cancel()
+					return
+				}
+			}
+		}}
+		rr, err := pr.doResults(pageCtx, "")
+		cancel()
+		if fnErr != nil {
+			return limitHit, fnErr
+		}
+		if err != nil {
+			return limitHit, err
+		}
+		limitHit = limitHit || rr.searchResultsCommon.LimitHit() || len(rr.searchResultsCommon.timedout) > 0
+
+		for _, result := range rr.results {
+			rows, hit := searchExportRows(result)
+			limitHit = limitHit || hit
+			for _, row := range rows {
+				if err := fn(row); err != nil {
+					return limitHit, err
+				}
+			}
+		}
+	}
+	return limitHit, nil
+}
+
+// searchExportRows returns the rows of a search result (one per line match
+// for file matches, and one per result otherwise), and whether the result is
+// incomplete.
+func searchExportRows(result *searchResultResolver) (rows []*SearchExportRow, limitHit bool) {
+	switch {
+	case result.fileMatch != nil:
+		fm := result.fileMatch
+		base := SearchExportRow{Repo: string(fm.repo.Name), Commit: string(fm.commitID), Path: fm.JPath}
+		if len(fm.JLineMatches) == 0 {
+			return []*SearchExportRow{&base}, fm.JLimitHit
+		}
+		rows = make([]*SearchExportRow, len(fm.JLineMatches))
+		for i, lm := range fm.JLineMatches {
+			row := base
+			row.LineNumber = lm.JLineNumber + 1
+			row.Preview = lm.JPreview
+			rows[i] = &row
+		}
+		return rows, fm.JLimitHit
+
+	case result.diff != nil:
+		c := result.diff
+		row := &SearchExportRow{
+			Repo:        c.commit.repo.Name(),
+			Commit:      string(c.commit.oid),
+			AuthorName:  c.commit.author.person.name,
+			AuthorEmail: c.commit.author.person.email,
+			AuthorDate:  c.commit.author.Date(),
+			Subject:     c.commit.Subject(),
+		}
+		if c.diffPreview != nil {
+			row.Preview = c.diffPreview.value
+		} else if c.messagePreview != nil {
+			row.Preview = c.messagePreview.value
+		}
+		return []*SearchExportRow{row}, false
+
+	case result.repo != nil:
+		return []*SearchExportRow{{Repo: result.repo.Name()}}, false
+	}
+	return nil, false
+}
@@ -0,0 +1,73 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestSearchExportRows(t *testing.T) {
+	repo := &types.Repo{Name: "github.com/foo/bar"}
+
+	tests := map[string]struct {
+		result *searchResultResolver
+		want   []*SearchExportRow
+	}{
+		"file match": {
+			result: &searchResultResolver{fileMatch: &fileMatchResolver{
+				repo:     repo,
+				commitID: "c0ffee",
+				JPath:    "a/b.go",
+				JLineMatches: []*lineMatch{
+					{JLineNumber: 0, JPreview: "package b"},
+					{JLineNumber: 9, JPreview: "func b() {}"},
+				},
+			}},
+			want: []*SearchExportRow{
+				{Repo: "github.com/foo/bar", Commit: "c0ffee", Path: "a/b.go", LineNumber: 1, Preview: "package b"},
+				{Repo: "github.com/foo/bar", Commit: "c0ffee", Path: "a/b.go", LineNumber: 10, Preview: "func b() {}"},
+			},
+		},
+		"path match": {
+			result: &searchResultResolver{fileMatch: &fileMatchResolver{repo: repo, commitID: "c0ffee", JPath: "a/b.go"}},
+			want:   []*SearchExportRow{{Repo: "github.com/foo/bar", Commit: "c0ffee", Path: "a/b.go"}},
+		},
+		"commit": {
+			result: &searchResultResolver{diff: &commitSearchResultResolver{
+				commit: &gitCommitResolver{
+					repo:    &repositoryResolver{repo: repo},
+					oid:     "c0ffee",
+					message: "Fix bug\n\nDetails",
+					author: signatureResolver{
+						person: &personResolver{name: "Alice", email: "alice@example.com"},
+						date:   time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+					},
+				},
+				messagePreview: &highlightedString{value: "Fix bug"},
+			}},
+			want: []*SearchExportRow{{
+				Repo:        "github.com/foo/bar",
+				Commit:      "c0ffee",
+				Preview:     "Fix bug",
+				AuthorName:  "Alice",
+				AuthorEmail: "alice@example.com",
+				AuthorDate:  "2018-01-02T03:04:05Z",
+				Subject:     "Fix bug",
+			}},
+		},
+		"repository": {
+			result: &searchResultResolver{repo: &repositoryResolver{repo: repo}},
+			want:   []*SearchExportRow{{Repo: "github.com/foo/bar"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _ := searchExportRows(test.result)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -45,6 +45,14 @@ func searchRepositories(ctx context.Context, args *search.Args, limit int32) (re
 		return nil, nil, err
 	}
 
+	var exprMatcher *search.PatternMatcher
+	if args.Pattern.Expr != nil {
+		exprMatcher, err = args.Pattern.Expr.Compile(args.Pattern.IsCaseSensitive)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	common = &searchResultsCommon{}
 	var results []*searchResultResolver
 	for _, repo := range args.Repos {
@@ -52,6 +60,9 @@ func searchRepositories(ctx context.Context, args *search.Args, limit int32) (re
 			common.limitHit = true
 			break
 		}
+		if exprMatcher != nil && !exprMatcher.Match([]string{string(repo.Repo.Name)}) {
+			continue
+		}
 		if pattern.MatchString(string(repo.Repo.Name)) {
 			results = append(results, &searchResultResolver{repo: &repositoryResolver{repo: repo.Repo, icon: repoIcon}})
 		}
@@ -25,6 +25,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory/filelang"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/syntax"
+	searchquerytypes "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/types"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
@@ -633,9 +635,58 @@ func (r *searchResolver) getPatternInfo(opts *getPatternInfoOptions) (*search.Pa
 	if len(excludePatterns) > 0 {
 		patternInfo.ExcludePattern = unionRegExps(excludePatterns)
 	}
+	if r.query.Tree != nil && (opts == nil || !opts.forceFileSearch) {
+		patternInfo.Expr = patternExpr(r.query.Tree)
+		patternInfo.Pattern = patternInfo.Expr.Union()
+	}
+	patternInfo.CountOnly = r.countOnly && patternInfo.Expr == nil
 	return patternInfo, nil
 }
 
+// patternExpr converts the boolean expression tree of a query's patterns to
+// the search.PatternExpr evaluated by the search backends.
+func patternExpr(n *searchquerytypes.Node) *search.PatternExpr {
+	if n.Value != nil {
+		// Treat quoted strings as literal strings to match, not regexps.
+		var pattern string
+		switch {
+		case n.Value.String != nil:
+			pattern = regexp.QuoteMeta(*n.Value.String)
+		case n.Value.Regexp != nil:
+			pattern = n.Value.Regexp.String()
+		}
+		leaf := &search.PatternExpr{Op: search.PatternLeaf, Pattern: pattern}
+		if n.Value.Not() {
+			return &search.PatternExpr{Op: search.PatternNot, Operands: []*search.PatternExpr{leaf}}
+		}
+		return leaf
+	}
+
+	operands := make([]*search.PatternExpr, len(n.Operands))
+	for i, o := range n.Operands {
+		operands[i] = patternExpr(o)
+	}
+	switch n.Op {
+	case syntax.OpOr:
+		return &search.PatternExpr{Op: search.PatternOr, Operands: operands}
+	case syntax.OpNot:
+		return &search.PatternExpr{Op: search.PatternNot, Operands: operands}
+	case syntax.OpConcat:
+		// Juxtaposed patterns match in order on the same line, as in
+		// queries without boolean operators.
+		patterns := make([]string, 0, len(operands))
+		for _, o := range operands {
+			if o.Op != search.PatternLeaf {
+				return &search.PatternExpr{Op: search.PatternAnd, Operands: operands}
+			}
+			patterns = append(patterns, o.Pattern)
+		}
+		return &search.PatternExpr{Op: search.PatternLeaf, Pattern: regexpPatternMatchingExprsInOrder(patterns)}
+	default:
+		return &search.PatternExpr{Op: search.PatternAnd, Operands: operands}
+	}
+}
+
 var (
 	// The default timeout to use for queries.
 	defaultTimeout = 10 * time.Second
@@ -645,7 +696,7 @@ var (
 
 func (r *searchResolver) searchTimeoutFieldSet() bool {
 	timeout, _ := r.query.StringValue(query.FieldTimeout)
-	return timeout != "" || r.countIsSet()
+	return timeout != "" || r.countIsSet() || r.exhaustive
 }
 
 func (r *searchResolver) withTimeout(ctx context.Context) (context.Context, context.CancelFunc, error) {
@@ -657,8 +708,8 @@ func (r *searchResolver) withTimeout(ctx context.Context) (context.Context, cont
 		if err != nil {
 			return nil, nil, errors.WithMessage(err, `invalid "timeout:" value (examples: "timeout:2s", "timeout:200ms")`)
 		}
-	} else if r.countIsSet() {
-		// If `count:` is set but `timeout:` is not explicitely set, use the max timeout
+	} else if r.countIsSet() || r.exhaustive {
+		// If `count:` is set (or the search is exhaustive) but `timeout:` is not explicitely set, use the max timeout
 		d = maxTimeout
 	}
 	// don't run queries longer than 1 minute.
@@ -728,6 +779,15 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 			resultTypes = []string{"file", "path", "repo", "ref"}
 		}
 	}
+	if len(r.query.Values(query.FieldKind)) > 0 || len(r.query.Values(query.FieldParent)) > 0 {
+		// These filters only apply to symbols, so don't silently ignore them
+		// for other result types.
+		for _, resultType := range resultTypes {
+			if resultType != "symbol" {
+				return nil, &badRequestError{errors.New("the kind: and parent: filters require type:symbol")}
+			}
+		}
+	}
 	seenResultTypes := make(map[string]struct{}, len(resultTypes))
 	for _, resultType := range resultTypes {
 		if resultType == "file" {
@@ -840,7 +900,25 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 			goroutine.Go(func() {
 				defer wg.Done()
 
-				fileResults, fileCommon, err := searchFilesInRepos(ctx, &args)
+				var onMatch func(*fileMatchResolver)
+				if r.onFileMatch != nil {
+					onMatch = func(fm *fileMatchResolver) {
+						fileMatchesMu.Lock()
+						m, ok := fileMatches[fm.uri]
+						if ok {
+							// The file is already a symbol result, so merge into it.
+							m.JLimitHit = m.JLimitHit || fm.JLimitHit
+							m.JLineMatches = fm.JLineMatches
+						} else {
+							fileMatches[fm.uri] = fm
+						}
+						fileMatchesMu.Unlock()
+						if !ok {
+							r.onFileMatch(fm)
+						}
+					}
+				}
+				fileResults, fileCommon, err := searchFilesInRepos(ctx, &args, onMatch)
 				// Timeouts are reported through searchResultsCommon so don't report an error for them
 				if err != nil && !(err == context.DeadlineExceeded || err == context.Canceled) {
 					multiErrMu.Lock()
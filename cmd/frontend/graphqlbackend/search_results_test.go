@@ -124,6 +124,24 @@ func TestSearchResults(t *testing.T) {
 			t.Error("calledSearchSymbols")
 		}
 	})
+
+	t.Run("symbol filters without type:symbol", func(t *testing.T) {
+		db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+			return []*types.Repo{{Name: "repo"}}, nil
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		db.Mocks.Repos.MockGetByName(t, "repo", 1)
+
+		for _, q := range []string{"kind:function foo", "parent:Bar foo", "type:symbol type:file kind:function foo"} {
+			r, err := (&schemaResolver{}).Search(&struct{ Query string }{Query: q})
+			if err != nil {
+				t.Fatal("Search:", err)
+			}
+			if _, err := r.Results(context.Background()); err == nil {
+				t.Errorf("%q: got nil error, want error", q)
+			}
+		}
+	})
 }
 
 func TestRegexpPatternMatchingExprsInOrder(t *testing.T) {
@@ -220,6 +238,36 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 			PathPatternsAreRegExps: true,
 			ExcludePattern:         `f|(\.graphql$|\.gql$)`,
 		},
+		"p1 p2 OR q": {
+			Pattern:                "((p1).*?(p2))|(q)",
+			IsRegExp:               true,
+			PathPatternsAreRegExps: true,
+			Expr: &search.PatternExpr{Op: search.PatternOr, Operands: []*search.PatternExpr{
+				{Op: search.PatternLeaf, Pattern: "(p1).*?(p2)"},
+				{Op: search.PatternLeaf, Pattern: "q"},
+			}},
+		},
+		`p AND NOT "q.r"`: {
+			Pattern:                `(p)|(q\.r)`,
+			IsRegExp:               true,
+			PathPatternsAreRegExps: true,
+			Expr: &search.PatternExpr{Op: search.PatternAnd, Operands: []*search.PatternExpr{
+				{Op: search.PatternLeaf, Pattern: "p"},
+				{Op: search.PatternNot, Operands: []*search.PatternExpr{{Op: search.PatternLeaf, Pattern: `q\.r`}}},
+			}},
+		},
+		"(p OR q) -r": {
+			Pattern:                "(p)|(q)|(r)",
+			IsRegExp:               true,
+			PathPatternsAreRegExps: true,
+			Expr: &search.PatternExpr{Op: search.PatternAnd, Operands: []*search.PatternExpr{
+				{Op: search.PatternOr, Operands: []*search.PatternExpr{
+					{Op: search.PatternLeaf, Pattern: "p"},
+					{Op: search.PatternLeaf, Pattern: "q"},
+				}},
+				{Op: search.PatternNot, Operands: []*search.PatternExpr{{Op: search.PatternLeaf, Pattern: "r"}}},
+			}},
+		},
 	}
 	for queryStr, want := range tests {
 		t.Run(queryStr, func(t *testing.T) {
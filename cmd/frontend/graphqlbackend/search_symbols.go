@@ -111,7 +111,7 @@ func searchSymbols(ctx context.Context, args *search.Args, limit int) (res []*fi
 	return res, common, err
 }
 
-func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions, patternInfo *search.PatternInfo, query *query.Query, limit int) (res []*fileMatchResolver, err error) {
+func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions, patternInfo *search.PatternInfo, q *query.Query, limit int) (res []*fileMatchResolver, err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Search symbols in repo")
 	defer func() {
 		if err != nil {
@@ -138,6 +138,21 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 		return nil, err
 	}
 
+	kinds, excludeKinds := q.StringValues(query.FieldKind)
+	// The symbols service stores the language names of ctags, which are the
+	// canonical names of lang: (eg "JavaScript" for lang:js). Negated lang:
+	// values are already excluded by patternInfo.ExcludePattern.
+	langs, _ := q.StringValues(query.FieldLang)
+	var languages []string
+	for _, value := range langs {
+		if lang := lookupLanguage(value); lang != nil {
+			languages = append(languages, lang.Name)
+		}
+	}
+	var parentQuery string
+	if parents, _ := q.RegexpPatterns(query.FieldParent); len(parents) > 0 {
+		parentQuery = parents[0]
+	}
 	symbols, err := backend.Symbols.ListTags(ctx, protocol.SearchArgs{
 		Repo:            repoRevs.Repo.Name,
 		CommitID:        commitID,
@@ -146,11 +161,26 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 		IsRegExp:        patternInfo.IsRegExp,
 		IncludePatterns: patternInfo.IncludePatterns,
 		ExcludePattern:  patternInfo.ExcludePattern,
+		Kinds:           kinds,
+		ExcludeKinds:    excludeKinds,
+		Languages:       languages,
+		ParentQuery:     parentQuery,
 		First:           limit,
 	})
+	var exprMatcher *search.PatternMatcher
+	if patternInfo.Expr != nil {
+		// The symbols service only matched the union of the patterns.
+		var compileErr error
+		if exprMatcher, compileErr = patternInfo.Expr.Compile(patternInfo.IsCaseSensitive); compileErr != nil {
+			return nil, compileErr
+		}
+	}
 	fileMatchesByURI := make(map[string]*fileMatchResolver)
 	fileMatches := make([]*fileMatchResolver, 0)
 	for _, symbol := range symbols {
+		if exprMatcher != nil && !exprMatcher.Match([]string{symbol.Name}) {
+			continue
+		}
 		commit := &gitCommitResolver{
 			repo:     &repositoryResolver{repo: repoRevs.Repo},
 			oid:      gitObjectID(commitID),
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 )
@@ -69,7 +70,7 @@ func (r *settingsCascade) Subjects(ctx context.Context) ([]*settingsSubject, err
 
 // viewerFinalSettings returns the final (merged) settings for the viewer.
 func viewerFinalSettings(ctx context.Context) (*configurationResolver, error) {
-	cascade, err := (&schemaResolver{}).ViewerSettings(ctx)
+	cascade, err := viewerSettings(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +171,16 @@ func mergeSettingsValues(dst map[string]interface{}, field string, value interfa
 }
 
 func (schemaResolver) ViewerSettings(ctx context.Context) (*settingsCascade, error) {
+	// 🚨 SECURITY: Access tokens need the "settings:write" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeSettingsWrite); err != nil {
+		return nil, err
+	}
+	return viewerSettings(ctx)
+}
+
+// viewerSettings returns the settings cascade for the viewer. Unlike ViewerSettings, it does not
+// check the access token's scopes.
+func viewerSettings(ctx context.Context) (*settingsCascade, error) {
 	user, err := CurrentUser(ctx)
 	if err != nil {
 		return nil, err
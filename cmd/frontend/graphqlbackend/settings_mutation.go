@@ -9,6 +9,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
@@ -43,6 +44,11 @@ type settingsMutation struct {
 func (r *schemaResolver) SettingsMutation(ctx context.Context, args *struct {
 	Input *settingsMutationGroupInput
 }) (*settingsMutation, error) {
+	// 🚨 SECURITY: Access tokens need the "settings:write" scope for this field.
+	if err := checkAccessTokenScope(ctx, authz.ScopeSettingsWrite); err != nil {
+		return nil, err
+	}
+
 	subject, err := settingsSubjectByID(ctx, args.Input.Subject)
 	if err != nil {
 		return nil, err
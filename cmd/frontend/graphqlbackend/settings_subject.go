@@ -6,12 +6,18 @@ import (
 	"fmt"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 )
 
 func (schemaResolver) SettingsSubject(ctx context.Context, args *struct{ ID graphql.ID }) (*settingsSubject, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to look up arbitrary settings subjects
+	// (which include users).
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
 	return settingsSubjectByID(ctx, args.ID)
 }
 
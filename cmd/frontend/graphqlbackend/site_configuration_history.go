@@ -0,0 +1,187 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/confdb"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func (r *siteConfigurationResolver) History(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Offset *int32
+}) (*siteConfigurationVersionConnectionResolver, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may view it (or its past versions).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var opt confdb.ListOptions
+	if args.First != nil {
+		opt.Limit = int(*args.First)
+	}
+	if args.Offset != nil {
+		if *args.Offset < 0 {
+			return nil, errors.New("offset must not be negative")
+		}
+		opt.Offset = int(*args.Offset)
+	}
+	return &siteConfigurationVersionConnectionResolver{opt: opt}, nil
+}
+
+// siteConfigurationVersionConnectionResolver resolves a list of past versions
+// of the site configuration, newest first.
+type siteConfigurationVersionConnectionResolver struct {
+	opt confdb.ListOptions
+}
+
+func (r *siteConfigurationVersionConnectionResolver) Nodes(ctx context.Context) ([]*siteConfigurationVersionResolver, error) {
+	versions, err := confdb.SiteListVersions(ctx, r.opt)
+	if err != nil {
+		return nil, err
+	}
+	l := make([]*siteConfigurationVersionResolver, len(versions))
+	for i, v := range versions {
+		l[i] = &siteConfigurationVersionResolver{version: v}
+	}
+	return l, nil
+}
+
+func (r *siteConfigurationVersionConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := confdb.SiteCountVersions(ctx)
+	return int32(count), err
+}
+
+func (r *siteConfigurationVersionConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	if r.opt.Limit == 0 {
+		return graphqlutil.HasNextPage(false), nil
+	}
+	count, err := confdb.SiteCountVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(count > r.opt.Offset+r.opt.Limit), nil
+}
+
+// siteConfigurationVersionResolver resolves a past version of the site
+// configuration.
+type siteConfigurationVersionResolver struct {
+	version *confdb.SiteConfig
+}
+
+func (r *siteConfigurationVersionResolver) ID() int32 { return r.version.ID }
+
+func (r *siteConfigurationVersionResolver) Contents() string { return r.version.Contents }
+
+func (r *siteConfigurationVersionResolver) Author(ctx context.Context) (*UserResolver, error) {
+	if r.version.AuthorUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *r.version.AuthorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *siteConfigurationVersionResolver) CreatedAt() string {
+	return r.version.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *siteConfigurationResolver) Diff(ctx context.Context, args *struct {
+	From int32
+	To   int32
+}) ([]*siteConfigurationFieldDiffResolver, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may view it (or its past versions).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	from, err := siteConfigurationVersionByID(ctx, args.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := siteConfigurationVersionByID(ctx, args.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the site configuration versions differ, so use the current critical
+	// configuration for both.
+	raw := globals.ConfigurationServerFrontendOnly.Raw()
+	before, after := raw, raw
+	before.Site = from.Contents
+	after.Site = to.Contents
+	diffs, err := conf.DiffRaw(before, after)
+	if err != nil {
+		return nil, err
+	}
+	l := make([]*siteConfigurationFieldDiffResolver, len(diffs))
+	for i, d := range diffs {
+		l[i] = &siteConfigurationFieldDiffResolver{diff: d}
+	}
+	return l, nil
+}
+
+// siteConfigurationFieldDiffResolver resolves a site configuration field
+// whose value differs between two versions.
+type siteConfigurationFieldDiffResolver struct {
+	diff conf.FieldDiff
+}
+
+func (r *siteConfigurationFieldDiffResolver) Field() string { return r.diff.Field }
+
+func (r *siteConfigurationFieldDiffResolver) Before() *jsonValue { return toJSONValue(r.diff.Before) }
+
+func (r *siteConfigurationFieldDiffResolver) After() *jsonValue { return toJSONValue(r.diff.After) }
+
+func toJSONValue(v interface{}) *jsonValue {
+	if v == nil {
+		return nil
+	}
+	return &jsonValue{value: v}
+}
+
+func (r *schemaResolver) RestoreSiteConfiguration(ctx context.Context, args *struct {
+	ID int32
+}) (bool, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may restore a past version of it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return false, err
+	}
+	version, err := siteConfigurationVersionByID(ctx, args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	next := globals.ConfigurationServerFrontendOnly.Raw()
+	next.Site = version.Contents
+	problems, err := conf.Validate(next)
+	if err != nil {
+		return false, err
+	}
+	if len(problems) > 0 {
+		return false, fmt.Errorf("unable to restore site configuration version %d because it is invalid: %s", args.ID, strings.Join(problems, "; "))
+	}
+	if err := globals.ConfigurationServerFrontendOnly.Write(ctx, next); err != nil {
+		return false, err
+	}
+	return globals.ConfigurationServerFrontendOnly.NeedServerRestart(), nil
+}
+
+func siteConfigurationVersionByID(ctx context.Context, id int32) (*confdb.SiteConfig, error) {
+	version, err := confdb.SiteGetByID(ctx, id)
+	if err == confdb.ErrVersionNotFound {
+		return nil, fmt.Errorf("site configuration version %d not found", id)
+	}
+	return version, err
+}
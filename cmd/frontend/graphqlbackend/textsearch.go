@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -57,6 +58,7 @@ type fileMatchResolver struct {
 	JPath        string       `json:"Path"`
 	JLineMatches []*lineMatch `json:"LineMatches"`
 	JLimitHit    bool         `json:"LimitHit"`
+	JMatchCount  int32        `json:"MatchCount"` // set instead of JLineMatches if the search only counted matches (see search.PatternInfo.CountOnly)
 	symbols      []*searchSymbolResult
 	uri          string
 	repo         *types.Repo
@@ -141,6 +143,20 @@ func (lm *lineMatch) LimitHit() bool {
 // textSearch searches repo@commit with p.
 // Note: the returned matches do not set fileMatch.uri
 func textSearch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error) {
+	limitHit, err = textSearchStream(ctx, repo, commit, p, fetchTimeout, func(fm *fileMatchResolver) {
+		matches = append(matches, fm)
+	})
+	if err != nil && !errcode.IsTimeout(err) {
+		return nil, false, err
+	}
+	return matches, limitHit, err
+}
+
+// textSearchStream searches repo@commit with p, calling onMatch with each
+// file match as soon as searcher sends it. onMatch is not called
+// concurrently.
+// Note: the matches passed to onMatch do not set fileMatch.uri
+func textSearchStream(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.PatternInfo, fetchTimeout time.Duration, onMatch func(*fileMatchResolver)) (limitHit bool, err error) {
 	tr, ctx := trace.New(ctx, "searcher.client", fmt.Sprintf("%s@%s", repo.Name, commit))
 	defer func() {
 		tr.SetError(err)
@@ -168,11 +184,12 @@ func textSearch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p
 		"IncludePatterns": includePatterns,
 		"IncludePattern":  []string{p.IncludePattern},
 		"FetchTimeout":    []string{fetchTimeout.String()},
+		"Stream":          []string{"true"},
 	}
 	if deadline, ok := ctx.Deadline(); ok {
 		t, err := deadline.MarshalText()
 		if err != nil {
-			return nil, false, err
+			return false, err
 		}
 		q.Set("Deadline", string(t))
 	}
@@ -192,6 +209,9 @@ func textSearch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p
 	if p.PathPatternsAreCaseSensitive {
 		q.Set("PathPatternsAreCaseSensitive", "true")
 	}
+	if p.CountOnly {
+		q.Set("CountOnly", "true")
+	}
 	// TEMP BACKCOMPAT: always set even if false so that searcher can distinguish new frontends that send
 	// these fields from old frontends that do not (and provide a default in the latter case).
 	q.Set("PatternMatchesContent", strconv.FormatBool(p.PatternMatchesContent))
@@ -209,13 +229,22 @@ func textSearch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p
 		excludedSearchURLs = map[string]bool{}
 		attempt            = 0
 		maxAttempts        = 2
+
+		// sent is the number of matches passed to onMatch so far. Once we
+		// have sent a match we can not retry, since that would send
+		// duplicates.
+		sent = 0
 	)
+	countingOnMatch := func(fm *fileMatchResolver) {
+		sent++
+		onMatch(fm)
+	}
 	for {
 		attempt++
 
 		searcherURL, err := Search().SearcherURLs.Get(consistentHashKey, excludedSearchURLs)
 		if err != nil {
-			return nil, false, err
+			return false, err
 		}
 
 		// Fallback to a bad host if nothing is left
@@ -223,28 +252,29 @@ func textSearch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p
 			tr.LazyPrintf("failed to find endpoint, trying again without excludes")
 			searcherURL, err = Search().SearcherURLs.Get(consistentHashKey, nil)
 			if err != nil {
-				return nil, false, err
+				return false, err
 			}
 		}
 
 		url := searcherURL + "?" + rawQuery
 		tr.LazyPrintf("attempt %d: %s", attempt, url)
-		matches, limitHit, err = textSearchURL(ctx, url)
+		limitHit, err = textSearchURL(ctx, url, countingOnMatch)
 		// Useful trace for debugging:
 		//
-		// tr.LazyPrintf("%d matches, limitHit=%v, err=%v, ctx.Err()=%v", len(matches), limitHit, err, ctx.Err())
+		// tr.LazyPrintf("%d matches, limitHit=%v, err=%v, ctx.Err()=%v", sent, limitHit, err, ctx.Err())
 		if err == nil || errcode.IsTimeout(err) {
-			return matches, limitHit, err
+			return limitHit, err
 		}
 
 		// If we are canceled, return that error.
 		if err := ctx.Err(); err != nil {
-			return nil, false, err
+			return false, err
 		}
 
-		// If not temporary or our last attempt then don't try again.
-		if !errcode.IsTemporary(err) || attempt == maxAttempts {
-			return nil, false, err
+		// If not temporary, our last attempt or we already sent matches
+		// then don't try again.
+		if !errcode.IsTemporary(err) || attempt == maxAttempts || sent > 0 {
+			return false, err
 		}
 
 		tr.LazyPrintf("transient error %s", err.Error())
@@ -253,10 +283,13 @@ func textSearch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p
 	}
 }
 
-func textSearchURL(ctx context.Context, url string) ([]*fileMatchResolver, bool, error) {
+// textSearchURL runs the searcher request url, calling onMatch for each
+// file match in the response. It understands both the streaming response
+// format (which we request) and the buffered format of older searchers.
+func textSearchURL(ctx context.Context, url string, onMatch func(*fileMatchResolver)) (bool, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, false, err
+		return false, err
 	}
 	req = req.WithContext(ctx)
 
@@ -270,7 +303,7 @@ func textSearchURL(ctx context.Context, url string) ([]*fileMatchResolver, bool,
 
 	// Limit number of outstanding searcher requests
 	if err := textSearchLimiter.Acquire(ctx); err != nil {
-		return nil, false, err
+		return false, err
 	}
 	defer textSearchLimiter.Release()
 
@@ -281,17 +314,23 @@ func textSearchURL(ctx context.Context, url string) ([]*fileMatchResolver, bool,
 		if ctx.Err() != nil {
 			err = ctx.Err()
 		}
-		return nil, false, errors.Wrap(err, "searcher request failed")
+		return false, errors.Wrap(err, "searcher request failed")
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return nil, false, err
+			return false, err
 		}
-		return nil, false, errors.WithStack(&searcherError{StatusCode: resp.StatusCode, Message: string(body)})
+		return false, errors.WithStack(&searcherError{StatusCode: resp.StatusCode, Message: string(body)})
 	}
 
+	if resp.Header.Get("Content-Type") == "application/x-ndjson" {
+		return decodeSearcherStream(ctx, resp.Body, onMatch)
+	}
+
+	// BACKCOMPAT: Older searchers ignore the Stream parameter and respond
+	// with all matches at once.
 	r := struct {
 		Matches     []*fileMatchResolver
 		LimitHit    bool
@@ -299,12 +338,54 @@ func textSearchURL(ctx context.Context, url string) ([]*fileMatchResolver, bool,
 	}{}
 	err = json.NewDecoder(resp.Body).Decode(&r)
 	if err != nil {
-		return nil, false, errors.Wrap(err, "searcher response invalid")
+		return false, errors.Wrap(err, "searcher response invalid")
+	}
+	for _, fm := range r.Matches {
+		onMatch(fm)
 	}
 	if r.DeadlineHit {
 		err = context.DeadlineExceeded
 	}
-	return r.Matches, r.LimitHit, err
+	return r.LimitHit, err
+}
+
+// decodeSearcherStream reads the newline delimited JSON events of a
+// streaming searcher response from r, calling onMatch for each match.
+func decodeSearcherStream(ctx context.Context, r io.Reader, onMatch func(*fileMatchResolver)) (limitHit bool, err error) {
+	dec := json.NewDecoder(r)
+	for {
+		var ev struct {
+			Match       *fileMatchResolver
+			Done        bool
+			LimitHit    bool
+			DeadlineHit bool
+			Error       string
+		}
+		if err := dec.Decode(&ev); err != nil {
+			// A partial stream due to cancellation or timeout should be
+			// reported as such.
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return false, errors.Wrap(err, "searcher response invalid")
+		}
+		if ev.Match != nil {
+			onMatch(ev.Match)
+		}
+		if !ev.Done {
+			continue
+		}
+		if ev.Error != "" {
+			return false, errors.WithStack(&searcherError{StatusCode: http.StatusInternalServerError, Message: ev.Error})
+		}
+		if ev.DeadlineHit {
+			return ev.LimitHit, context.DeadlineExceeded
+		}
+		return ev.LimitHit, nil
+	}
 }
 
 type searcherError struct {
@@ -326,9 +407,19 @@ func (e *searcherError) Error() string {
 
 var mockSearchFilesInRepo func(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error)
 
-func searchFilesInRepo(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo, fetchTimeout time.Duration) (matches []*fileMatchResolver, limitHit bool, err error) {
+// searchFilesInRepo searches repo@rev with info. If onMatch is non-nil, it is
+// called with each file match as soon as searcher sends it (instead of the
+// matches being returned), and it is not called concurrently.
+func searchFilesInRepo(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, rev string, info *search.PatternInfo, fetchTimeout time.Duration, onMatch func(*fileMatchResolver)) (matches []*fileMatchResolver, limitHit bool, err error) {
 	if mockSearchFilesInRepo != nil {
-		return mockSearchFilesInRepo(ctx, repo, gitserverRepo, rev, info, fetchTimeout)
+		matches, limitHit, err = mockSearchFilesInRepo(ctx, repo, gitserverRepo, rev, info, fetchTimeout)
+		if onMatch != nil {
+			for _, fm := range matches {
+				onMatch(fm)
+			}
+			matches = nil
+		}
+		return matches, limitHit, err
 	}
 
 	// Do not trigger a repo-updater lookup (e.g.,
@@ -340,19 +431,89 @@ func searchFilesInRepo(ctx context.Context, repo *types.Repo, gitserverRepo gits
 		return nil, false, err
 	}
 
-	matches, limitHit, err = textSearch(ctx, gitserverRepo, commit, info, fetchTimeout)
-
 	workspace := fileMatchURI(repo.Name, rev, "")
-	for _, fm := range matches {
+	setRepo := func(fm *fileMatchResolver) {
 		fm.uri = workspace + fm.JPath
 		fm.repo = repo
 		fm.commitID = commit
 		fm.inputRev = &rev
 	}
 
+	if onMatch != nil {
+		var m *search.PatternMatcher
+		if info.Expr != nil {
+			if m, err = info.Expr.Compile(info.IsCaseSensitive); err != nil {
+				return nil, false, err
+			}
+		}
+		limitHit, err = textSearchStream(ctx, gitserverRepo, commit, info, fetchTimeout, func(fm *fileMatchResolver) {
+			if m != nil && !filterFileMatchByExpr(m, fm, info) {
+				return
+			}
+			setRepo(fm)
+			onMatch(fm)
+		})
+		return nil, limitHit, err
+	}
+
+	matches, limitHit, err = textSearch(ctx, gitserverRepo, commit, info, fetchTimeout)
+	if info.Expr != nil {
+		matches, err = filterFileMatchesByExpr(matches, info, err)
+	}
+	for _, fm := range matches {
+		setRepo(fm)
+	}
+
 	return matches, limitHit, err
 }
 
+// filterFileMatchesByExpr evaluates the boolean expression of patterns
+// info.Expr on the file matches returned by searcher, which only knows about
+// the union of its patterns. A file is considered to contain the lines that
+// searcher returned for it (and its path, if patterns match paths), so
+// negations aren't evaluated on files with more matching lines than searcher
+// returns.
+func filterFileMatchesByExpr(matches []*fileMatchResolver, info *search.PatternInfo, err error) ([]*fileMatchResolver, error) {
+	m, compileErr := info.Expr.Compile(info.IsCaseSensitive)
+	if compileErr != nil {
+		return nil, compileErr
+	}
+
+	filtered := matches[:0]
+	for _, fm := range matches {
+		if filterFileMatchByExpr(m, fm, info) {
+			filtered = append(filtered, fm)
+		}
+	}
+	return filtered, err
+}
+
+// filterFileMatchByExpr reports whether the file match fm satisfies the
+// compiled expression m (see filterFileMatchesByExpr). If so, it removes the
+// line matches of fm that only match negated patterns.
+func filterFileMatchByExpr(m *search.PatternMatcher, fm *fileMatchResolver, info *search.PatternInfo) bool {
+	units := make([]string, 0, len(fm.JLineMatches)+1)
+	if info.PatternMatchesPath {
+		units = append(units, fm.JPath)
+	}
+	for _, lm := range fm.JLineMatches {
+		units = append(units, lm.JPreview)
+	}
+	if !m.Match(units) {
+		return false
+	}
+
+	// Only report lines that match a pattern that isn't negated.
+	lineMatches := fm.JLineMatches[:0]
+	for _, lm := range fm.JLineMatches {
+		if m.MatchesLine(lm.JPreview) {
+			lineMatches = append(lineMatches, lm)
+		}
+	}
+	fm.JLineMatches = lineMatches
+	return true
+}
+
 func fileMatchURI(name api.RepoName, ref, path string) string {
 	var b strings.Builder
 	ref = url.QueryEscape(ref)
@@ -526,6 +687,22 @@ func zoektSearchHEAD(ctx context.Context, query *search.PatternInfo, repos []*se
 	}
 	matches := make([]*fileMatchResolver, len(resp.Files))
 	for i, file := range resp.Files {
+		repoRev := repoMap[api.RepoName(strings.ToLower(string(file.Repository)))]
+		matches[i] = &fileMatchResolver{
+			JPath:    file.FileName,
+			uri:      fileMatchURI(repoRev.Repo.Name, "", file.FileName),
+			repo:     repoRev.Repo,
+			commitID: api.CommitID(indexedRevisions[repoRev]),
+		}
+		if query.CountOnly {
+			for _, l := range file.LineMatches {
+				if !l.FileName {
+					matches[i].JMatchCount += int32(len(l.LineFragments))
+				}
+			}
+			continue
+		}
+
 		fileLimitHit := false
 		if len(file.LineMatches) > maxLineMatches {
 			file.LineMatches = file.LineMatches[:maxLineMatches]
@@ -551,15 +728,8 @@ func zoektSearchHEAD(ctx context.Context, query *search.PatternInfo, repos []*se
 				})
 			}
 		}
-		repoRev := repoMap[api.RepoName(strings.ToLower(string(file.Repository)))]
-		matches[i] = &fileMatchResolver{
-			JPath:        file.FileName,
-			JLineMatches: lines,
-			JLimitHit:    fileLimitHit,
-			uri:          fileMatchURI(repoRev.Repo.Name, "", file.FileName),
-			repo:         repoRev.Repo,
-			commitID:     api.CommitID(indexedRevisions[repoRev]),
-		}
+		matches[i].JLineMatches = lines
+		matches[i].JLimitHit = fileLimitHit
 	}
 
 	return matches, limitHit, reposLimitHit, nil
@@ -605,7 +775,41 @@ func queryToZoektQuery(query *search.PatternInfo) (zoektquery.Q, error) {
 		return parseRe(pattern, true)
 	}
 
-	if query.IsRegExp {
+	// exprQuery converts a boolean expression of patterns, which zoekt can
+	// evaluate natively.
+	var exprQuery func(e *search.PatternExpr) (zoektquery.Q, error)
+	exprQuery = func(e *search.PatternExpr) (zoektquery.Q, error) {
+		if e.Op == search.PatternLeaf {
+			return parseRe(e.Pattern, false)
+		}
+		children := make([]zoektquery.Q, len(e.Operands))
+		for i, o := range e.Operands {
+			var err error
+			if children[i], err = exprQuery(o); err != nil {
+				return nil, err
+			}
+		}
+		switch e.Op {
+		case search.PatternOr:
+			return zoektquery.NewOr(children...), nil
+		case search.PatternNot:
+			return &zoektquery.Not{Child: children[0]}, nil
+		default:
+			return zoektquery.NewAnd(children...), nil
+		}
+	}
+
+	if query.Expr != nil {
+		q, err := exprQuery(query.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if q, ok := q.(*zoektquery.And); ok {
+			and = append(and, q.Children...)
+		} else {
+			and = append(and, q)
+		}
+	} else if query.IsRegExp {
 		q, err := parseRe(query.Pattern, false)
 		if err != nil {
 			return nil, err
@@ -711,9 +915,20 @@ func zoektIndexedRepos(ctx context.Context, repos []*search.RepositoryRevisions)
 var mockSearchFilesInRepos func(args *search.Args) ([]*fileMatchResolver, *searchResultsCommon, error)
 
 // searchFilesInRepos searches a set of repos for a pattern.
-func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatchResolver, common *searchResultsCommon, err error) {
+//
+// If onMatch is non-nil, it is called with each file match as soon as it is
+// found (instead of the matches being returned), up to the file match limit
+// of the pattern. It is not called concurrently.
+func searchFilesInRepos(ctx context.Context, args *search.Args, onMatch func(*fileMatchResolver)) (res []*fileMatchResolver, common *searchResultsCommon, err error) {
 	if mockSearchFilesInRepos != nil {
-		return mockSearchFilesInRepos(args)
+		res, common, err = mockSearchFilesInRepos(args)
+		if onMatch != nil {
+			for _, fm := range res {
+				onMatch(fm)
+			}
+			res = nil
+		}
+		return res, common, err
 	}
 
 	tr, ctx := trace.New(ctx, "searchFilesInRepos", fmt.Sprintf("query: %+v, numRepoRevs: %d", args.Pattern, len(args.Repos)))
@@ -781,6 +996,7 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 		mu                sync.Mutex
 		unflattened       [][]*fileMatchResolver
 		flattenedSize     int
+		sent              int  // number of matches passed to onMatch
 		overLimitCanceled bool // canceled because we were over the limit
 	)
 
@@ -788,11 +1004,20 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 	addMatches := func(matches []*fileMatchResolver) {
 		if len(matches) > 0 {
 			common.resultCount += int32(len(matches))
-			sort.Slice(matches, func(i, j int) bool {
-				a, b := matches[i].uri, matches[j].uri
-				return a > b
-			})
-			unflattened = append(unflattened, matches)
+			if onMatch != nil {
+				for _, fm := range matches {
+					if sent < int(args.Pattern.FileMatchLimit) {
+						onMatch(fm)
+						sent++
+					}
+				}
+			} else {
+				sort.Slice(matches, func(i, j int) bool {
+					a, b := matches[i].uri, matches[j].uri
+					return a > b
+				})
+				unflattened = append(unflattened, matches)
+			}
 			flattenedSize += len(matches)
 
 			// Stop searching once we have found enough matches. This does
@@ -835,7 +1060,15 @@ func searchFilesInRepos(ctx context.Context, args *search.Args) (res []*fileMatc
 		go func(repoRev search.RepositoryRevisions) {
 			defer wg.Done()
 			rev := repoRev.RevSpecs()[0] // TODO(sqs): search multiple revs
-			matches, repoLimitHit, searchErr := searchFilesInRepo(ctx, repoRev.Repo, repoRev.GitserverRepo(), rev, args.Pattern, fetchTimeout)
+			var repoOnMatch func(*fileMatchResolver)
+			if onMatch != nil {
+				repoOnMatch = func(fm *fileMatchResolver) {
+					mu.Lock()
+					defer mu.Unlock()
+					addMatches([]*fileMatchResolver{fm})
+				}
+			}
+			matches, repoLimitHit, searchErr := searchFilesInRepo(ctx, repoRev.Repo, repoRev.GitserverRepo(), rev, args.Pattern, fetchTimeout, repoOnMatch)
 			if searchErr != nil {
 				tr.LogFields(otlog.String("repo", string(repoRev.Repo.Name)), otlog.String("searchErr", searchErr.Error()), otlog.Bool("timeout", errcode.IsTimeout(searchErr)), otlog.Bool("temporary", errcode.IsTemporary(searchErr)))
 				log15.Warn("searchFilesInRepo failed", "error", searchErr, "repo", repoRev.Repo.Name)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -91,6 +92,23 @@ func TestQueryToZoektQuery(t *testing.T) {
 			},
 			Query: `foo case:yes f:\.go$ f:\.yaml$ -f:\bvendor\b`,
 		},
+		{
+			Name: "expr",
+			Pattern: &search.PatternInfo{
+				IsRegExp:                     true,
+				IsCaseSensitive:              false,
+				Pattern:                      "(foo)|(bar)",
+				IncludePatterns:              []string{`\.go$`},
+				ExcludePattern:               "",
+				PathPatternsAreRegExps:       true,
+				PathPatternsAreCaseSensitive: false,
+				Expr: &search.PatternExpr{Op: search.PatternAnd, Operands: []*search.PatternExpr{
+					{Op: search.PatternLeaf, Pattern: "foo"},
+					{Op: search.PatternNot, Operands: []*search.PatternExpr{{Op: search.PatternLeaf, Pattern: "bar"}}},
+				}},
+			},
+			Query: `foo -bar case:no f:\.go$`,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
@@ -172,7 +190,7 @@ func TestSearchFilesInRepos(t *testing.T) {
 		Repos: makeRepositoryRevisions("foo/one", "foo/two", "foo/empty", "foo/cloning", "foo/missing", "foo/missing-db", "foo/timedout", "foo/no-rev"),
 		Query: q,
 	}
-	results, common, err := searchFilesInRepos(context.Background(), args)
+	results, common, err := searchFilesInRepos(context.Background(), args, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -190,6 +208,26 @@ func TestSearchFilesInRepos(t *testing.T) {
 		t.Errorf("unexpected timedout: %v", v)
 	}
 
+	// Matches are passed to onMatch (up to the file match limit) instead of
+	// being returned.
+	args.Pattern.FileMatchLimit = 1
+	var streamed []*fileMatchResolver
+	results, common, err = searchFilesInRepos(context.Background(), args, func(fm *fileMatchResolver) {
+		streamed = append(streamed, fm)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no returned results when streaming, got %d", len(results))
+	}
+	if len(streamed) != 1 {
+		t.Errorf("expected one streamed result, got %d", len(streamed))
+	}
+	if !common.limitHit {
+		t.Error("expected limitHit when more matches than the limit were found")
+	}
+
 	// If we specify a rev and it isn't found, we fail the whole search since
 	// that should be checked earlier.
 	args = &search.Args{
@@ -200,12 +238,56 @@ func TestSearchFilesInRepos(t *testing.T) {
 		Repos: makeRepositoryRevisions("foo/no-rev@dev"),
 		Query: q,
 	}
-	_, _, err = searchFilesInRepos(context.Background(), args)
+	_, _, err = searchFilesInRepos(context.Background(), args, nil)
 	if !git.IsRevisionNotFound(errors.Cause(err)) {
 		t.Fatalf("searching non-existent rev expected to fail with RevisionNotFoundError got: %v", err)
 	}
 }
 
+func TestFilterFileMatchesByExpr(t *testing.T) {
+	fileMatch := func(path string, lines ...string) *fileMatchResolver {
+		fm := &fileMatchResolver{JPath: path}
+		for _, l := range lines {
+			fm.JLineMatches = append(fm.JLineMatches, &lineMatch{JPreview: l})
+		}
+		return fm
+	}
+	info := &search.PatternInfo{
+		Pattern: "(foo)|(bar)",
+		Expr: &search.PatternExpr{Op: search.PatternAnd, Operands: []*search.PatternExpr{
+			{Op: search.PatternLeaf, Pattern: "foo"},
+			{Op: search.PatternNot, Operands: []*search.PatternExpr{{Op: search.PatternLeaf, Pattern: "bar"}}},
+		}},
+	}
+	matches := []*fileMatchResolver{
+		fileMatch("a.go", "foo", "x := foo()"),
+		fileMatch("b.go", "foo", "bar"),
+		fileMatch("foo.go", "bar"),
+	}
+	got, err := filterFileMatchesByExpr(matches, info, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].JPath != "a.go" || len(got[0].JLineMatches) != 2 {
+		t.Errorf("got %d matches, want only a.go", len(got))
+	}
+
+	// Paths are only considered if patterns match paths.
+	info.Expr = &search.PatternExpr{Op: search.PatternAnd, Operands: []*search.PatternExpr{
+		{Op: search.PatternLeaf, Pattern: "foo"},
+		{Op: search.PatternLeaf, Pattern: "bar"},
+	}}
+	matches = []*fileMatchResolver{fileMatch("foo.go", "bar")}
+	if got, _ := filterFileMatchesByExpr(matches, info, nil); len(got) != 0 {
+		t.Errorf("got %d matches, want none", len(got))
+	}
+	info.PatternMatchesPath = true
+	matches = []*fileMatchResolver{fileMatch("foo.go", "bar")}
+	if got, _ := filterFileMatchesByExpr(matches, info, nil); len(got) != 1 {
+		t.Errorf("got %d matches, want 1", len(got))
+	}
+}
+
 func makeRepositoryRevisions(repos ...string) []*search.RepositoryRevisions {
 	r := make([]*search.RepositoryRevisions, len(repos))
 	for i, repospec := range repos {
@@ -359,3 +441,49 @@ func Test_zoektSearchHEAD(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeSearcherStream(t *testing.T) {
+	ctx := context.Background()
+	collect := func(body string) ([]string, bool, error) {
+		var paths []string
+		limitHit, err := decodeSearcherStream(ctx, strings.NewReader(body), func(fm *fileMatchResolver) {
+			paths = append(paths, fm.JPath)
+		})
+		return paths, limitHit, err
+	}
+
+	paths, limitHit, err := collect(`{"Match":{"Path":"a.go"}}
+{"Match":{"Path":"b.go"}}
+{"Done":true,"LimitHit":true}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.go", "b.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+	if !limitHit {
+		t.Error("expected limitHit")
+	}
+
+	paths, _, err = collect(`{"Match":{"Path":"a.go"}}
+{"Done":true,"DeadlineHit":true}
+`)
+	if !errcode.IsTimeout(err) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("expected partial results to be sent, got %v", paths)
+	}
+
+	if _, _, err := collect(`{"Match":{"Path":"a.go"}}
+{"Done":true,"Error":"boom"}
+`); err == nil || err.Error() != "boom" {
+		t.Errorf("expected error boom, got %v", err)
+	}
+
+	if _, _, err := collect(`{"Match":{"Path":"a.go"}}
+`); err == nil {
+		t.Error("expected error for stream without done event")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
@@ -89,6 +90,11 @@ func (r *UserResolver) DatabaseID() int32 { return r.user.ID }
 // Email returns the user's oldest email, if one exists.
 // Deprecated: use Emails instead.
 func (r *UserResolver) Email(ctx context.Context) (string, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's email address,
+	// because users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return "", err
+	}
 	// 🚨 SECURITY: Only the user and admins are allowed to access the email address.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
 		return "", err
@@ -233,6 +239,11 @@ func (r *UserResolver) Organizations(ctx context.Context) (*orgConnectionStaticR
 }
 
 func (r *UserResolver) Tags(ctx context.Context) ([]string, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's tags, because
+	// users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
 	// 🚨 SECURITY: Only the user and admins are allowed to access the user's tags.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
 		return nil, err
@@ -241,6 +252,11 @@ func (r *UserResolver) Tags(ctx context.Context) ([]string, error) {
 }
 
 func (r *UserResolver) SurveyResponses(ctx context.Context) ([]*surveyResponseResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's survey responses,
+	// because users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
 	// 🚨 SECURITY: Only the user and admins are allowed to access the user's survey responses.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
 		return nil, err
@@ -4,12 +4,24 @@ import (
 	"context"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 )
 
 func (r *UserResolver) Emails(ctx context.Context) ([]*userEmailResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's emails, because
+	// users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
+
+	return r.emails(ctx)
+}
+
+// emails returns the user's emails. Unlike Emails, it does not check the access token's scopes.
+func (r *UserResolver) emails(ctx context.Context) ([]*userEmailResolver, error) {
 	// 🚨 SECURITY: Only the self user and site admins can fetch a user's emails.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
 		return nil, err
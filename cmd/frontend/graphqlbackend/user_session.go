@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 )
 
 func (r *UserResolver) Session(ctx context.Context) (*sessionResolver, error) {
+	// 🚨 SECURITY: Access tokens need the "user:all" scope to read a user's session, because
+	// users are reachable from fields that fine-grained scopes permit.
+	if err := checkAccessTokenScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
 	// 🚨 SECURITY: Only the user can view their session information, because it is retrieved from
 	// the context of this request (and not persisted in a way that is queryable).
 	actor := actor.FromContext(ctx)
@@ -0,0 +1,55 @@
+package bg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// permissionsSyncCheckInterval is how often to check for users whose repository permissions need
+	// to be synced.
+	permissionsSyncCheckInterval = time.Minute
+
+	// permissionsSyncBatchSize is the maximum number of users whose repository permissions are
+	// synced per check.
+	permissionsSyncBatchSize = 100
+)
+
+// SyncUserPermissionsPeriodically periodically syncs users' repository permissions from the authz
+// providers into the database, if background permissions syncing is enabled in site
+// configuration. It never returns.
+//
+// Each user's permissions are claimed while they are synced, so multiple frontend processes can run
+// this concurrently without syncing the same users.
+func SyncUserPermissionsPeriodically(ctx context.Context) {
+	for {
+		if enabled, interval := db.BackgroundPermissionsSync(); enabled {
+			if err := syncStaleUserPermissions(ctx, interval); err != nil {
+				log15.Error("Unable to sync user repository permissions.", "error", err)
+			}
+		}
+		time.Sleep(permissionsSyncCheckInterval)
+	}
+}
+
+// syncStaleUserPermissions syncs the repository permissions of (at most permissionsSyncBatchSize)
+// users whose permissions have not been synced within the given interval.
+func syncStaleUserPermissions(ctx context.Context, interval time.Duration) error {
+	syncedBefore := time.Now().Add(-interval)
+	var failedUserIDs []int32 // don't retry users whose sync failed until the next check
+	for i := 0; i < permissionsSyncBatchSize; i++ {
+		userID, err := db.SyncStaleUserPermissions(ctx, syncedBefore, failedUserIDs)
+		if userID == 0 {
+			return err // no more users with stale permissions, or an error claiming one
+		}
+		if err != nil {
+			// Continue syncing other users' permissions.
+			log15.Warn("Unable to sync user repository permissions.", "userID", userID, "error", err)
+			failedUserIDs = append(failedUserIDs, userID)
+		}
+	}
+	return nil
+}
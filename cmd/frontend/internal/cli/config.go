@@ -160,13 +160,19 @@ func (c configurationSource) Write(ctx context.Context, input conftypes.RawUnifi
 		return errors.Wrap(err, "confdb.SiteGetLatest")
 	}
 
-	_, err = confdb.CriticalCreateIfUpToDate(ctx, &critical.ID, input.Critical)
-	if err != nil {
-		return errors.Wrap(err, "confdb.CriticalCreateIfUpToDate")
+	// Only create new versions of the configs that changed, so that the
+	// configuration history is not cluttered with identical versions.
+	if critical.Contents != input.Critical {
+		_, err = confdb.CriticalCreateIfUpToDate(ctx, &critical.ID, input.Critical)
+		if err != nil {
+			return errors.Wrap(err, "confdb.CriticalCreateIfUpToDate")
+		}
 	}
-	_, err = confdb.SiteCreateIfUpToDate(ctx, &site.ID, input.Site)
-	if err != nil {
-		return errors.Wrap(err, "confdb.SiteCreateIfUpToDate")
+	if site.Contents != input.Site {
+		_, err = confdb.SiteCreateIfUpToDate(ctx, &site.ID, input.Site)
+		if err != nil {
+			return errors.Wrap(err, "confdb.SiteCreateIfUpToDate")
+		}
 	}
 	return nil
 }
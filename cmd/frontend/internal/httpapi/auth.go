@@ -12,6 +12,9 @@ import (
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
+// graphQLPath is the URL path of the GraphQL API endpoint.
+const graphQLPath = "/.api/graphql"
+
 // AccessTokenAuthMiddleware authenticates the user based on the
 // token query parameter or the "Authorization" header.
 func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
@@ -60,19 +63,29 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 			// Validate access token.
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
-			// is allowed to do.
-			var requiredScope string
+			// is allowed to do. Tokens without the "user:all" scope may only use the parts of the
+			// GraphQL API permitted by their fine-grained scopes (which the GraphQL API enforces).
+			var acceptableScopes []string
 			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
+				acceptableScopes = append([]string{authz.ScopeUserAll}, authz.APIScopes...)
 			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+				acceptableScopes = []string{authz.ScopeSiteAdminSudo}
 			}
-			subjectUserID, err := db.AccessTokens.Lookup(r.Context(), token, requiredScope)
+			accessToken, err := db.AccessTokens.Lookup(r.Context(), token, acceptableScopes...)
 			if err != nil {
 				log15.Error("Invalid access token.", "token", token, "err", err)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
 				return
 			}
+			subjectUserID := accessToken.SubjectUserID
+			restrictions := &authz.AccessTokenRestrictions{
+				Scopes:        accessToken.Scopes,
+				RepoAllowlist: accessToken.RepoAllowlist,
+			}
+			if !restrictions.HasScope(authz.ScopeUserAll) && r.URL.Path != graphQLPath {
+				http.Error(w, "Access tokens without the "+authz.ScopeUserAll+" scope may only be used with the GraphQL API.", http.StatusForbidden)
+				return
+			}
 
 			// Determine the actor's user ID.
 			var actorUserID int32
@@ -105,7 +118,8 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			ctx := actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID})
+			r = r.WithContext(authz.WithAccessTokenRestrictions(ctx, restrictions))
 		}
 
 		next.ServeHTTP(w, r)
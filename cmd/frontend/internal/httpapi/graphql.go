@@ -1,11 +1,16 @@
 package httpapi
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 )
 
@@ -18,6 +23,29 @@ func serveGraphQL(w http.ResponseWriter, r *http.Request) (err error) {
 		return errors.New("method must be POST")
 	}
 
+	// 🚨 SECURITY: Access tokens without the "user:all" scope may only use the parts of the API
+	// that their scopes permit.
+	if restrictions := authz.AccessTokenRestrictionsFromContext(r.Context()); restrictions != nil && !restrictions.HasScope(authz.ScopeUserAll) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var params struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(body, &params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
+		if errs := graphqlbackend.CheckAccessTokenScopes(r.Context(), params.Query); len(errs) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			return json.NewEncoder(w).Encode(&graphql.Response{Errors: errs})
+		}
+	}
+
 	relayHandler.ServeHTTP(w, r)
 	return nil
 }
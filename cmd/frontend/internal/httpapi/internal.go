@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 
 	log15 "gopkg.in/inconshreveable/log15.v2"
 
@@ -283,10 +284,11 @@ func serveSavedQueriesSetInfo(w http.ResponseWriter, r *http.Request) error {
 		return errors.Wrap(err, "Decode")
 	}
 	err = db.QueryRunnerState.Set(r.Context(), &db.SavedQueryInfo{
-		Query:        info.Query,
-		LastExecuted: info.LastExecuted,
-		LatestResult: info.LatestResult,
-		ExecDuration: info.ExecDuration,
+		Query:              info.Query,
+		LastExecuted:       info.LastExecuted,
+		LatestResult:       info.LatestResult,
+		ExecDuration:       info.ExecDuration,
+		ResultFingerprints: info.ResultFingerprints,
 	})
 	if err != nil {
 		return errors.Wrap(err, "SavedQueries.Set")
@@ -311,6 +313,38 @@ func serveSavedQueriesDeleteInfo(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveSavedQueriesRecordWebhookDelivery(w http.ResponseWriter, r *http.Request) error {
+	var delivery *api.SavedQueryWebhookDelivery
+	err := json.NewDecoder(r.Body).Decode(&delivery)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	savedSearchID, err := strconv.ParseInt(delivery.Key, 10, 32)
+	if err != nil {
+		return errors.Wrap(err, "invalid saved query key")
+	}
+	d := &types.SavedSearchWebhookDelivery{
+		SavedSearchID: int32(savedSearchID),
+		URL:           delivery.URL,
+		Test:          delivery.Test,
+		ResultCount:   int32(delivery.ResultCount),
+		Attempts:      int32(delivery.Attempts),
+	}
+	if delivery.StatusCode != 0 {
+		statusCode := int32(delivery.StatusCode)
+		d.StatusCode = &statusCode
+	}
+	if delivery.Error != "" {
+		d.Error = &delivery.Error
+	}
+	if err := db.SavedSearches.CreateWebhookDelivery(r.Context(), d); err != nil {
+		return errors.Wrap(err, "SavedSearches.CreateWebhookDelivery")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
 func serveSettingsGetForSubject(w http.ResponseWriter, r *http.Request) error {
 	var subject api.SettingsSubject
 	if err := json.NewDecoder(r.Body).Decode(&subject); err != nil {
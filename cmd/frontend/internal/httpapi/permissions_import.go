@@ -0,0 +1,140 @@
+package httpapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// permissionsImportLine is a line of the JSON Lines request body of the permissions import
+// endpoint. It grants a permission to a user or to all members of an organization (identified by
+// name) on a repository (identified by name) or on the repositories whose names match a pattern.
+type permissionsImportLine struct {
+	User              string `json:"user"`
+	Organization      string `json:"organization"`
+	Repository        string `json:"repository"`
+	RepositoryPattern string `json:"repositoryPattern"`
+	Permission        string `json:"permission"` // defaults to "read"
+}
+
+// permissionsImportMaxLineSize is the maximum size of a line of the request body.
+const permissionsImportMaxLineSize = 64 * 1024
+
+// servePermissionsImport grants the explicit repository permissions listed in the JSON Lines
+// request body (see permissionsImportLine) in a single transaction, so that either all or none of
+// them are granted. It responds with the number of newly granted permissions.
+func servePermissionsImport(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Only site admins may grant repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil
+	}
+
+	// Cache the IDs of names that occur on multiple lines.
+	var (
+		userIDs = map[string]int32{}
+		orgIDs  = map[string]int32{}
+		repoIDs = map[string]api.RepoID{}
+	)
+
+	var perms []*db.ExplicitRepoPermission
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(nil, permissionsImportMaxLineSize)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		badRequest := func(format string, args ...interface{}) error {
+			http.Error(w, fmt.Sprintf("line %d: ", lineNum)+fmt.Sprintf(format, args...), http.StatusBadRequest)
+			return nil
+		}
+
+		var l permissionsImportLine
+		if err := json.Unmarshal(line, &l); err != nil {
+			return badRequest("invalid JSON: %s", err)
+		}
+		if (l.User == "") == (l.Organization == "") {
+			return badRequest(`exactly one of "user" and "organization" must be set`)
+		}
+		if (l.Repository == "") == (l.RepositoryPattern == "") {
+			return badRequest(`exactly one of "repository" and "repositoryPattern" must be set`)
+		}
+		p := &db.ExplicitRepoPermission{RepoPattern: l.RepositoryPattern, Perm: authz.Perm(l.Permission)}
+		if p.Perm == "" {
+			p.Perm = authz.Read
+		}
+		if p.Perm != authz.Read {
+			return badRequest("unsupported permission %q (only %q is supported)", p.Perm, authz.Read)
+		}
+
+		if l.User != "" {
+			id, ok := userIDs[l.User]
+			if !ok {
+				user, err := db.Users.GetByUsername(ctx, l.User)
+				if errcode.IsNotFound(err) {
+					return badRequest("user %q not found", l.User)
+				} else if err != nil {
+					return err
+				}
+				id = user.ID
+				userIDs[l.User] = id
+			}
+			p.UserID = id
+		}
+		if l.Organization != "" {
+			id, ok := orgIDs[l.Organization]
+			if !ok {
+				org, err := db.Orgs.GetByName(ctx, l.Organization)
+				if _, ok := err.(*db.OrgNotFoundError); ok {
+					return badRequest("organization %q not found", l.Organization)
+				} else if err != nil {
+					return err
+				}
+				id = org.ID
+				orgIDs[l.Organization] = id
+			}
+			p.OrgID = id
+		}
+		if l.Repository != "" {
+			id, ok := repoIDs[l.Repository]
+			if !ok {
+				repo, err := db.Repos.GetByName(ctx, api.RepoName(l.Repository))
+				if errcode.IsNotFound(err) {
+					return badRequest("repository %q not found", l.Repository)
+				} else if err != nil {
+					return err
+				}
+				id = repo.ID
+				repoIDs[l.Repository] = id
+			}
+			p.RepoID = id
+		}
+
+		perms = append(perms, p)
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, "reading request body: "+err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	created, err := db.ExplicitRepoPermissions.Import(ctx, perms)
+	if err != nil {
+		return err
+	}
+	db.RefreshExplicitRepoPermissionGrantees(ctx, perms)
+
+	return writeJSON(w, struct {
+		Created int `json:"created"`
+	}{Created: created})
+}
@@ -0,0 +1,126 @@
+package httpapi
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestServePermissionsImport(t *testing.T) {
+	c := newTest()
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID, SiteAdmin: actor.FromContext(ctx).UID == 1}, nil
+	}
+	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		if username != "alice" {
+			return nil, &notFoundError{}
+		}
+		return &types.User{ID: 2, Username: username}, nil
+	}
+	db.Mocks.Orgs.GetByName = func(ctx context.Context, name string) (*types.Org, error) {
+		if name != "acme" {
+			return nil, &db.OrgNotFoundError{Message: name}
+		}
+		return &types.Org{ID: 3, Name: name}, nil
+	}
+	db.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		return &types.Repo{ID: 4, Name: name}, nil
+	}
+	var imported []*db.ExplicitRepoPermission
+	db.Mocks.ExplicitRepoPermissions.Import = func(ctx context.Context, perms []*db.ExplicitRepoPermission) (int, error) {
+		imported = perms
+		return len(perms), nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	tests := map[string]struct {
+		uid            int32
+		body           string
+		wantStatusCode int
+		wantBody       string
+		wantImported   []*db.ExplicitRepoPermission
+	}{
+		"unauthenticated": {
+			body:           `{"user":"alice","repository":"r"}`,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		"non-admin": {
+			uid:            2,
+			body:           `{"user":"alice","repository":"r"}`,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		"missing grantee": {
+			uid:            1,
+			body:           `{"user":"alice","repository":"r"}` + "\n" + `{"repository":"r"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		"unknown user": {
+			uid:            1,
+			body:           `{"user":"bob","repository":"r"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		"unknown organization": {
+			uid:            1,
+			body:           `{"organization":"other","repositoryPattern":"*"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		"unsupported permission": {
+			uid:            1,
+			body:           `{"user":"alice","repository":"r","permission":"write"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		"valid": {
+			uid:            1,
+			body:           `{"user":"alice","repository":"r"}` + "\n\n" + `{"organization":"acme","repositoryPattern":"gitolite.example.com/*","permission":"read"}` + "\n",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"created":2}`,
+			wantImported: []*db.ExplicitRepoPermission{
+				{UserID: 2, RepoID: 4, Perm: authz.Read},
+				{OrgID: 3, RepoPattern: "gitolite.example.com/*", Perm: authz.Read},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			imported = nil
+			req, _ := http.NewRequest("POST", "/permissions/import", strings.NewReader(test.body))
+			if test.uid != 0 {
+				req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: test.uid}))
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.wantStatusCode {
+				t.Errorf("got status %d, want %d", resp.StatusCode, test.wantStatusCode)
+			}
+			if test.wantBody != "" {
+				body, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := strings.TrimSpace(string(body)); got != test.wantBody {
+					t.Errorf("got body %q, want %q", got, test.wantBody)
+				}
+			}
+			if !reflect.DeepEqual(imported, test.wantImported) {
+				t.Errorf("got imported permissions %+v, want %+v", imported, test.wantImported)
+			}
+		})
+	}
+}
+
+type notFoundError struct{}
+
+func (*notFoundError) Error() string  { return "not found" }
+func (*notFoundError) NotFound() bool { return true }
@@ -14,35 +14,41 @@ const (
 	RepoShield  = "repo.shield"
 	RepoRefresh = "repo.refresh"
 	Telemetry   = "telemetry"
-
-	SavedQueriesListAll    = "internal.saved-queries.list-all"
-	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
-	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
-	SavedQueriesDeleteInfo = "internal.saved-queries.delete-info"
-	SettingsGetForSubject  = "internal.settings.get-for-subject"
-	OrgsListUsers          = "internal.orgs.list-users"
-	OrgsGetByName          = "internal.orgs.get-by-name"
-	UsersGetByUsername     = "internal.users.get-by-username"
-	UserEmailsGetEmail     = "internal.user-emails.get-email"
-	ExternalURL            = "internal.app-url"
-	GitServerAddrs         = "internal.git-server-addrs"
-	CanSendEmail           = "internal.can-send-email"
-	SendEmail              = "internal.send-email"
-	Extension              = "internal.extension"
-	GitResolveRevision     = "internal.git.resolve-revision"
-	GitTar                 = "internal.git.tar"
-	PhabricatorRepoCreate  = "internal.phabricator.repo.create"
-	ReposCreateIfNotExists = "internal.repos.create-if-not-exists"
-	ReposGetByName         = "internal.repos.get-by-name"
-	ReposInventoryUncached = "internal.repos.inventory-uncached"
-	ReposInventory         = "internal.repos.inventory"
-	ReposList              = "internal.repos.list"
-	ReposListEnabled       = "internal.repos.list-enabled"
-	ReposUpdateMetadata    = "internal.repos.update-metadata"
-	Configuration          = "internal.configuration"
-	SearchConfiguration    = "internal.search-configuration"
-	ExternalServiceConfigs = "internal.external-services.configs"
-	ExternalServicesList   = "internal.external-services.list"
+	Webhook     = "webhook"
+
+	SearchExport = "search.export"
+
+	PermissionsImport = "permissions.import"
+
+	SavedQueriesListAll               = "internal.saved-queries.list-all"
+	SavedQueriesGetInfo               = "internal.saved-queries.get-info"
+	SavedQueriesSetInfo               = "internal.saved-queries.set-info"
+	SavedQueriesDeleteInfo            = "internal.saved-queries.delete-info"
+	SavedQueriesRecordWebhookDelivery = "internal.saved-queries.record-webhook-delivery"
+	SettingsGetForSubject             = "internal.settings.get-for-subject"
+	OrgsListUsers                     = "internal.orgs.list-users"
+	OrgsGetByName                     = "internal.orgs.get-by-name"
+	UsersGetByUsername                = "internal.users.get-by-username"
+	UserEmailsGetEmail                = "internal.user-emails.get-email"
+	ExternalURL                       = "internal.app-url"
+	GitServerAddrs                    = "internal.git-server-addrs"
+	CanSendEmail                      = "internal.can-send-email"
+	SendEmail                         = "internal.send-email"
+	Extension                         = "internal.extension"
+	GitResolveRevision                = "internal.git.resolve-revision"
+	GitTar                            = "internal.git.tar"
+	PhabricatorRepoCreate             = "internal.phabricator.repo.create"
+	ReposCreateIfNotExists            = "internal.repos.create-if-not-exists"
+	ReposGetByName                    = "internal.repos.get-by-name"
+	ReposInventoryUncached            = "internal.repos.inventory-uncached"
+	ReposInventory                    = "internal.repos.inventory"
+	ReposList                         = "internal.repos.list"
+	ReposListEnabled                  = "internal.repos.list-enabled"
+	ReposUpdateMetadata               = "internal.repos.update-metadata"
+	Configuration                     = "internal.configuration"
+	SearchConfiguration               = "internal.search-configuration"
+	ExternalServiceConfigs            = "internal.external-services.configs"
+	ExternalServicesList              = "internal.external-services.list"
 )
 
 // New creates a new API router with route URL pattern definitions but
@@ -58,6 +64,12 @@ func New(base *mux.Router) *mux.Router {
 	addGraphQLRoute(base)
 	addTelemetryRoute(base)
 
+	base.Path("/webhooks/{ExternalServiceID:[0-9]+}").Methods("POST").Name(Webhook)
+
+	base.Path("/search/export").Methods("GET").Name(SearchExport)
+
+	base.Path("/permissions/import").Methods("POST").Name(PermissionsImport)
+
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
 
@@ -82,6 +94,7 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/saved-queries/get-info").Methods("POST").Name(SavedQueriesGetInfo)
 	base.Path("/saved-queries/set-info").Methods("POST").Name(SavedQueriesSetInfo)
 	base.Path("/saved-queries/delete-info").Methods("POST").Name(SavedQueriesDeleteInfo)
+	base.Path("/saved-queries/record-webhook-delivery").Methods("POST").Name(SavedQueriesRecordWebhookDelivery)
 	base.Path("/settings/get-for-subject").Methods("POST").Name(SettingsGetForSubject)
 	base.Path("/orgs/list-users").Methods("POST").Name(OrgsListUsers)
 	base.Path("/orgs/get-by-name").Methods("POST").Name(OrgsGetByName)
@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Trailers set on search export responses. They are trailers (not headers)
+// because they are only known after all results have been streamed.
+const (
+	searchExportLimitHitTrailer = "X-Sourcegraph-Search-Limit-Hit"
+	searchExportErrorTrailer    = "X-Sourcegraph-Search-Error"
+)
+
+// serveSearchExport streams every match of the search query in the "q" URL
+// query parameter, as CSV (format=csv, the default) or JSON Lines
+// (format=jsonl).
+func serveSearchExport(w http.ResponseWriter, r *http.Request) error {
+	// 🚨 SECURITY: Only authenticated users may export search results. Repository
+	// permissions are enforced by the search itself.
+	if !actor.FromContext(r.Context()).IsAuthenticated() {
+		http.Error(w, "Search export requires authentication.", http.StatusUnauthorized)
+		return nil
+	}
+
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		http.Error(w, `missing "q" query parameter`, http.StatusBadRequest)
+		return nil
+	}
+
+	var (
+		write func(*graphqlbackend.SearchExportRow) error
+		flush func() error
+	)
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(w)
+		header := true
+		write = func(row *graphqlbackend.SearchExportRow) error {
+			if header {
+				if err := cw.Write(graphqlbackend.SearchExportRowFields); err != nil {
+					return err
+				}
+				header = false
+			}
+			return cw.Write(row.Values())
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case "jsonl":
+		w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+		enc := json.NewEncoder(w)
+		write = func(row *graphqlbackend.SearchExportRow) error { return enc.Encode(row) }
+		flush = func() error { return nil }
+	default:
+		http.Error(w, "unsupported format "+strconv.Quote(format)+` (supported formats are "csv" and "jsonl")`, http.StatusBadRequest)
+		return nil
+	}
+	w.Header().Set("Trailer", searchExportLimitHitTrailer+", "+searchExportErrorTrailer)
+
+	// Once the first row has been written, the status code can't be changed,
+	// so errors are reported in a trailer instead.
+	started := false
+	flusher, _ := w.(http.Flusher)
+	limitHit, err := graphqlbackend.ExportSearchResults(r.Context(), q, func(row *graphqlbackend.SearchExportRow) error {
+		started = true
+		if err := write(row); err != nil {
+			return err
+		}
+		// Send rows to the client as they are found.
+		if flusher != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil && !started {
+		return err
+	}
+	if err != nil {
+		log15.Error("search export failed after streaming results", "query", q, "error", err)
+		w.Header().Set(searchExportErrorTrailer, err.Error())
+	}
+	w.Header().Set(searchExportLimitHitTrailer, strconv.FormatBool(limitHit))
+	return nil
+}
@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestServeSearchExport_BadRequests(t *testing.T) {
+	c := newTest()
+
+	tests := map[string]struct {
+		url            string
+		authenticated  bool
+		wantStatusCode int
+	}{
+		"unauthenticated": {url: "/search/export?q=foo", wantStatusCode: http.StatusUnauthorized},
+		"missing query":   {url: "/search/export", authenticated: true, wantStatusCode: http.StatusBadRequest},
+		"unknown format":  {url: "/search/export?q=foo&format=xml", authenticated: true, wantStatusCode: http.StatusBadRequest},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", test.url, nil)
+			if test.authenticated {
+				req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 1}))
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.wantStatusCode {
+				t.Errorf("got status %d, want %d", resp.StatusCode, test.wantStatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package httpapi
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+)
+
+// serveWebhook forwards webhooks sent by code hosts to repo-updater, which
+// verifies their signature against the webhook secret of the external
+// service.
+func serveWebhook(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(mux.Vars(r)["ExternalServiceID"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid external service ID", http.StatusBadRequest)
+		return nil
+	}
+
+	resp, err := repoupdater.DefaultClient.Webhook(r.Context(), id, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
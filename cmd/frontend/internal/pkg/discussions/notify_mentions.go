@@ -48,10 +48,50 @@ func NotifyNewComment(updatedThread *types.DiscussionThread, newComment *types.D
 	})
 }
 
+// NotifyThreadResolved should be invoked after a discussion thread has been
+// marked as resolved, in order to send relevant notifications.
+//
+// It returns immediately and does not block.
+func NotifyThreadResolved(updatedThread *types.DiscussionThread, resolvedBy *types.User) {
+	notifyMentions(&notifier{
+		typ:               threadResolvedNotification,
+		eventAuthorUserID: resolvedBy.ID,
+		thread:            updatedThread,
+		event:             "resolved this thread",
+		template:          threadEventEmailTemplate,
+	})
+}
+
+// NotifyThreadAssigned should be invoked after users have been assigned to a
+// discussion thread, in order to notify the newly assigned users.
+//
+// It returns immediately and does not block.
+func NotifyThreadAssigned(updatedThread *types.DiscussionThread, assignedBy *types.User, newAssigneeUserIDs []int32) {
+	if len(newAssigneeUserIDs) == 0 {
+		return
+	}
+	notifyMentions(&notifier{
+		typ:               threadAssignedNotification,
+		eventAuthorUserID: assignedBy.ID,
+		thread:            updatedThread,
+		event:             "assigned this thread to you",
+		recipients:        newAssigneeUserIDs,
+		template:          threadEventEmailTemplate,
+	})
+}
+
 func notifyMentions(n *notifier) {
 	goroutine.Go(func() {
 		ctx := context.Background()
-		subscribers, err := n.subscribers(ctx)
+		var (
+			subscribers []string
+			err         error
+		)
+		if n.recipients != nil {
+			subscribers, err = usernames(ctx, n.recipients)
+		} else {
+			subscribers, err = n.subscribers(ctx)
+		}
 		if err != nil {
 			log15.Error("discussions: determining subscribers", "error", err)
 		}
@@ -66,16 +106,29 @@ func notifyMentions(n *notifier) {
 type notificationType int
 
 const (
-	newThreadNotification  notificationType = iota
-	newCommentNotification notificationType = iota
+	newThreadNotification      notificationType = iota
+	newCommentNotification     notificationType = iota
+	threadResolvedNotification notificationType = iota
+	threadAssignedNotification notificationType = iota
 )
 
 type notifier struct {
 	typ               notificationType
 	eventAuthorUserID int32
 	thread            *types.DiscussionThread
-	comment           *types.DiscussionComment
 	template          txtypes.Templates
+
+	// comment is the new comment, or nil if the notification is about an event
+	// on the thread itself (such as it being resolved).
+	comment *types.DiscussionComment
+
+	// event describes the event for notifications that are not about a new
+	// comment, e.g. "resolved this thread".
+	event string
+
+	// recipients, if non-nil, are the users to notify instead of the thread's
+	// subscribers.
+	recipients []int32
 }
 
 // subscribers returns a list of all usernames who are subscribed to receive
@@ -85,6 +138,7 @@ type notifier struct {
 //
 // 	1. If you were previously mentioned in the thread, you are subscribed.
 // 	2. If you previously authored a comment, you are subscribed.
+// 	3. If you are assigned to the thread, you are subscribed.
 //
 func (n *notifier) subscribers(ctx context.Context) ([]string, error) {
 	comments, err := db.DiscussionComments.List(ctx, &db.DiscussionCommentsListOptions{
@@ -122,9 +176,32 @@ func (n *notifier) subscribers(ctx context.Context) ([]string, error) {
 			}
 		}
 	}
+	assignees, err := usernames(ctx, n.thread.AssigneeUserIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "assignees")
+	}
+	for _, assignee := range assignees {
+		if _, ok := set[assignee]; !ok {
+			set[assignee] = struct{}{}
+			subscribers = append(subscribers, assignee)
+		}
+	}
 	return subscribers, nil
 }
 
+// usernames returns the usernames of the given users.
+func usernames(ctx context.Context, userIDs []int32) ([]string, error) {
+	usernames := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := db.Users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetByID")
+		}
+		usernames = append(usernames, user.Username)
+	}
+	return usernames, nil
+}
+
 func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 	if !conf.CanSendEmail() {
 		// Can't send email, so we have nothing to do.
@@ -168,8 +245,10 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		msgID := func(commentID int64) string {
 			return fmt.Sprintf("%s+%d.%d@%s", emailParts[0], n.thread.ID, commentID, emailParts[1])
 		}
-		id := msgID(n.comment.ID)
-		messageID = &id
+		if n.comment != nil {
+			id := msgID(n.comment.ID)
+			messageID = &id
+		}
 
 		// Get a list of prior comments in the thread and generate the
 		// references list. This makes e.g. Gmail understand that this email is
@@ -184,16 +263,16 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 			return errors.Wrap(err, "DiscussionComments.List")
 		}
 		for _, comment := range comments {
-			if comment.ID == n.comment.ID {
+			if n.comment != nil && comment.ID == n.comment.ID {
 				continue
 			}
 			references = append(references, msgID(comment.ID))
 		}
 	}
 
-	url, err := URLToInlineComment(ctx, n.thread, n.comment)
+	url, err := urlToInline(ctx, n.thread, n.comment)
 	if err != nil {
-		return errors.Wrap(err, "URLToInlineComment")
+		return errors.Wrap(err, "urlToInline")
 	}
 	if url == nil {
 		return nil // can't generate a link to this thread target type
@@ -238,13 +317,22 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		}
 	}
 
-	commentAuthor, err := db.Users.GetByID(ctx, n.comment.AuthorUserID)
+	eventAuthor, err := db.Users.GetByID(ctx, n.eventAuthorUserID)
 	if err != nil {
-		return errors.Wrap(err, "CommentAuthor: GetByID")
+		return errors.Wrap(err, "EventAuthor: GetByID")
 	}
-	fromName := commentAuthor.DisplayName
+	fromName := eventAuthor.DisplayName
 	if fromName == "" {
-		fromName = commentAuthor.Username
+		fromName = eventAuthor.Username
+	}
+
+	var (
+		commentContents string
+		uniqueValue     = fmt.Sprintf("%d.%d", n.thread.ID, n.typ)
+	)
+	if n.comment != nil {
+		commentContents = n.comment.Contents
+		uniqueValue = fmt.Sprint(n.comment.ID)
 	}
 
 	return txemail.Send(ctx, txemail.Message{
@@ -257,6 +345,7 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		Data: struct {
 			ThreadTitle           string
 			CommentAuthorUsername string
+			Event                 string
 			CommentContents       string
 			CommentContentsHTML   template.HTML
 			URL                   string
@@ -270,11 +359,12 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 			CodeContextHTML template.HTML
 		}{
 			ThreadTitle:           n.thread.Title,
-			CommentAuthorUsername: commentAuthor.Username,
-			CommentContents:       n.comment.Contents,
-			CommentContentsHTML:   template.HTML(markdown.Render(n.comment.Contents, nil)),
+			CommentAuthorUsername: eventAuthor.Username,
+			Event:                 n.event,
+			CommentContents:       commentContents,
+			CommentContentsHTML:   template.HTML(markdown.Render(commentContents, nil)),
 			URL:                   url.String(),
-			UniqueValue:           fmt.Sprint(n.comment.ID),
+			UniqueValue:           uniqueValue,
 			CanReply:              conf.CanReadEmail(),
 
 			RepoName:        repoShortName,
@@ -345,6 +435,36 @@ var (
 </body>
 </html>
 `
+	threadEventTextTemplate = `
+{{- "@" -}}{{- .CommentAuthorUsername -}}{{- " " -}}{{- .Event -}}
+	{{- with .FileName -}}{{- " on " -}}{{- . -}}{{- end -}}
+	{{- ".\n" -}}
+{{- with .CodeContextText -}}
+	{{- "--------------------------------------------------------------------------------\n" -}}
+	{{- . -}}
+	{{- "\n" -}}
+{{- end -}}
+{{- "—\n" -}}
+{{- "View it on Sourcegraph:\n" -}}
+{{- "\n" -}}
+{{- "  " -}}{{- .URL -}}
+{{- "\n" -}}
+`
+
+	threadEventHTMLTemplate = `
+<html>
+<body>
+<p><strong>@{{.CommentAuthorUsername}}</strong> {{.Event}}{{with .FileName}} on <strong>{{.}}</strong>{{end}}.</p>
+{{with .CodeContextHTML}}
+	{{.}}
+{{end}}
+<p style="font-size: small; color: #666;">—<br/><a href="{{.URL}}">View it on Sourcegraph</a></p>
+<!-- this ensures Gmail doesn't trim the email -->
+<span style="opacity: 0">{{.UniqueValue}}</span>
+</body>
+</html>
+`
+
 	newThreadEmailTemplate = txemail.MustValidate(txtypes.Templates{
 		Subject: sharedCommentSubjectTemplate,
 		Text:    sharedCommentTextTemplate,
@@ -356,4 +476,10 @@ var (
 		Text:    sharedCommentTextTemplate,
 		HTML:    sharedCommentHTMLTemplate,
 	})
+
+	threadEventEmailTemplate = txemail.MustValidate(txtypes.Templates{
+		Subject: sharedCommentSubjectTemplate,
+		Text:    threadEventTextTemplate,
+		HTML:    threadEventHTMLTemplate,
+	})
 )
@@ -0,0 +1,109 @@
+package discussions
+
+import (
+	"bytes"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// RelocateSelection returns where the selection in the original file of a diff
+// is in the new file, given the diff's hunks (which must be in order).
+//
+// modified reports whether any of the selected lines were changed or removed,
+// or lines were inserted between them. Changes to lines surrounding the
+// selection only move it.
+//
+// If the last selected lines were replaced, the returned range includes the
+// lines that replaced them. If they were removed, the returned range ends
+// where they used to be.
+func RelocateSelection(selection LineRange, hunks []*diff.Hunk) (relocated LineRange, modified bool) {
+	// The last selected line, or the start line for an empty selection.
+	last := selection.EndLine - 1
+	if last < selection.StartLine {
+		last = selection.StartLine
+	}
+
+	var (
+		haveStart, haveEnd bool
+		lastDeleted        bool // whether the last selected line was removed
+		delta              int  // new line number minus old line number, for lines between hunks
+	)
+	// visit records the new position of an old line.
+	visit := func(oldLine, newLine int, deleted bool) {
+		if oldLine >= selection.StartLine && oldLine <= last && deleted {
+			modified = true
+		}
+		if oldLine == selection.StartLine {
+			relocated.StartLine, haveStart = newLine, true
+		}
+		if oldLine == last {
+			relocated.EndLine, haveEnd, lastDeleted = newLine+1, true, deleted
+			if deleted {
+				relocated.EndLine = newLine
+			}
+		}
+	}
+	for _, hunk := range hunks {
+		// Hunk line numbers are 1-based. Hunks that don't contain any
+		// original (or new) lines are numbered after the line that precedes
+		// them.
+		oldLine, newLine := int(hunk.OrigStartLine)-1, int(hunk.NewStartLine)-1
+		if hunk.OrigLines == 0 {
+			oldLine++
+		}
+		if hunk.NewLines == 0 {
+			newLine++
+		}
+
+		// Selected lines before the hunk are unchanged.
+		if !haveStart && selection.StartLine < oldLine {
+			visit(selection.StartLine, selection.StartLine+delta, false)
+		}
+		if !haveEnd && last < oldLine {
+			visit(last, last+delta, false)
+		}
+		if haveStart && haveEnd && !lastDeleted {
+			break
+		}
+
+		for _, line := range bytes.Split(bytes.TrimSuffix(hunk.Body, []byte("\n")), []byte("\n")) {
+			if len(line) == 0 {
+				// Some diffs omit the space prefix of empty context lines.
+				line = []byte{' '}
+			}
+			switch line[0] {
+			case ' ':
+				visit(oldLine, newLine, false)
+				oldLine++
+				newLine++
+				lastDeleted = false
+			case '-':
+				visit(oldLine, newLine, true)
+				oldLine++
+			case '+':
+				if oldLine > selection.StartLine && oldLine <= last {
+					// Inserted between selected lines.
+					modified = true
+				}
+				newLine++
+				if lastDeleted {
+					// Replaces the last selected line.
+					relocated.EndLine = newLine
+				}
+			}
+		}
+		lastDeleted = false
+		delta = newLine - oldLine
+	}
+	if !haveStart {
+		visit(selection.StartLine, selection.StartLine+delta, false)
+	}
+	if !haveEnd {
+		visit(last, last+delta, false)
+	}
+
+	if selection.EndLine <= selection.StartLine || relocated.EndLine < relocated.StartLine {
+		relocated.EndLine = relocated.StartLine
+	}
+	return relocated, modified
+}
@@ -0,0 +1,124 @@
+package discussions
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+func TestRelocateSelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		selection    LineRange
+		hunks        string
+		want         LineRange
+		wantModified bool
+	}{
+		{
+			name:      "no_hunks",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			want:      LineRange{StartLine: 3, EndLine: 5},
+		},
+		{
+			name:      "added_lines_before",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			hunks: `@@ -1,0 +2,2 @@
++a
++b
+`,
+			want: LineRange{StartLine: 5, EndLine: 7},
+		},
+		{
+			name:      "removed_lines_before_in_hunk_context",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			hunks: `@@ -1,6 +1,5 @@
+ 0
+-1
+ 2
+ 3
+ 4
+ 5
+`,
+			want: LineRange{StartLine: 2, EndLine: 4},
+		},
+		{
+			name:      "added_lines_after",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			hunks: `@@ -5,0 +6,1 @@
++a
+`,
+			want: LineRange{StartLine: 3, EndLine: 5},
+		},
+		{
+			name:      "multiple_hunks_before",
+			selection: LineRange{StartLine: 20, EndLine: 21},
+			hunks: `@@ -2,0 +3,3 @@
++a
++b
++c
+@@ -10,2 +12,0 @@
+-9
+-10
+`,
+			want: LineRange{StartLine: 21, EndLine: 22},
+		},
+		{
+			name:      "modified_selected_line",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			hunks: `@@ -3,4 +3,4 @@
+ 2
+ 3
+-4
++four
+ 5
+`,
+			want:         LineRange{StartLine: 3, EndLine: 5},
+			wantModified: true,
+		},
+		{
+			name:      "inserted_between_selected_lines",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			hunks: `@@ -4,1 +4,2 @@
+ 3
++a
+`,
+			want:         LineRange{StartLine: 3, EndLine: 6},
+			wantModified: true,
+		},
+		{
+			name:      "removed_selected_lines",
+			selection: LineRange{StartLine: 3, EndLine: 5},
+			hunks: `@@ -3,4 +3,2 @@
+ 2
+-3
+-4
+ 5
+`,
+			want:         LineRange{StartLine: 3, EndLine: 3},
+			wantModified: true,
+		},
+		{
+			name:      "empty_selection",
+			selection: LineRange{StartLine: 3, EndLine: 3},
+			hunks: `@@ -1,0 +2,1 @@
++a
+`,
+			want: LineRange{StartLine: 4, EndLine: 4},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hunks, err := diff.ParseHunks([]byte(test.hunks))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, modified := RelocateSelection(test.selection, hunks)
+			if got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+			if modified != test.wantModified {
+				t.Errorf("got modified %v, want %v", modified, test.wantModified)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PatternOp is the operator of a PatternExpr.
+type PatternOp int
+
+// All PatternOp values.
+const (
+	PatternLeaf PatternOp = iota // a single regexp pattern
+	PatternAnd                   // all operands must match
+	PatternOr                    // at least one operand must match
+	PatternNot                   // the single operand must not match
+)
+
+// PatternExpr is a boolean expression of regexp patterns, as written with the
+// AND, OR and NOT operators in a search query.
+//
+// Search backends generally only support a single pattern. They are given
+// Union and their results are post-filtered with a PatternMatcher.
+type PatternExpr struct {
+	Op       PatternOp
+	Pattern  string         // the regexp pattern, if Op == PatternLeaf
+	Operands []*PatternExpr // the operands, if Op != PatternLeaf
+}
+
+// leaves returns the patterns of the leaves of e. If positive is true, it
+// omits the leaves that are (transitively) negated.
+func (e *PatternExpr) leaves(positive bool) []string {
+	switch {
+	case e.Op == PatternLeaf:
+		return []string{e.Pattern}
+	case e.Op == PatternNot && positive:
+		return nil
+	}
+	var patterns []string
+	for _, o := range e.Operands {
+		patterns = append(patterns, o.leaves(positive)...)
+	}
+	return patterns
+}
+
+func unionPattern(patterns []string) string {
+	if len(patterns) == 1 {
+		return patterns[0]
+	}
+	return "(" + strings.Join(patterns, ")|(") + ")"
+}
+
+// Union returns a regexp pattern that matches wherever any pattern of e
+// matches, including negated ones. Backends that only return the lines
+// matching their pattern thus return all lines needed to evaluate e.
+//
+// The pattern only uses syntax shared by Go regexps and POSIX extended
+// regexps, so it can also be passed to git.
+func (e *PatternExpr) Union() string {
+	return unionPattern(e.leaves(false))
+}
+
+// Compile compiles e into a PatternMatcher.
+func (e *PatternExpr) Compile(caseSensitive bool) (*PatternMatcher, error) {
+	compile := func(pattern string) (*regexp.Regexp, error) {
+		if !caseSensitive {
+			pattern = "(?i:" + pattern + ")"
+		}
+		return regexp.Compile(pattern)
+	}
+
+	m := &PatternMatcher{}
+	if patterns := e.leaves(true); len(patterns) > 0 {
+		var err error
+		if m.positive, err = compile(unionPattern(patterns)); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if m.expr, err = compileExpr(e, compile); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// compiledExpr is a PatternExpr whose leaf patterns have been compiled.
+type compiledExpr struct {
+	op       PatternOp
+	re       *regexp.Regexp
+	operands []*compiledExpr
+}
+
+func compileExpr(e *PatternExpr, compile func(string) (*regexp.Regexp, error)) (*compiledExpr, error) {
+	c := &compiledExpr{op: e.Op}
+	if e.Op == PatternLeaf {
+		re, err := compile(e.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		c.re = re
+		return c, nil
+	}
+	c.operands = make([]*compiledExpr, len(e.Operands))
+	for i, o := range e.Operands {
+		var err error
+		if c.operands[i], err = compileExpr(o, compile); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *compiledExpr) match(units []string) bool {
+	switch c.op {
+	case PatternLeaf:
+		for _, u := range units {
+			if c.re.MatchString(u) {
+				return true
+			}
+		}
+		return false
+	case PatternNot:
+		return !c.operands[0].match(units)
+	case PatternOr:
+		for _, o := range c.operands {
+			if o.match(units) {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, o := range c.operands {
+			if !o.match(units) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// A PatternMatcher evaluates a compiled PatternExpr against search results.
+type PatternMatcher struct {
+	expr     *compiledExpr
+	positive *regexp.Regexp // union of the patterns that are not negated, if any
+}
+
+// Match reports whether a document consisting of the given units (such as the
+// lines of a file, or its path) matches the expression. A leaf pattern
+// matches the document if it matches any of its units.
+func (m *PatternMatcher) Match(units []string) bool {
+	return m.expr.match(units)
+}
+
+// MatchesLine reports whether line contains a match of a pattern that is not
+// negated, i.e. whether it should be reported as a matching line of a
+// document that matches the expression.
+func (m *PatternMatcher) MatchesLine(line string) bool {
+	return m.positive != nil && m.positive.MatchString(line)
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPatternExpr(t *testing.T) {
+	leaf := func(p string) *PatternExpr { return &PatternExpr{Op: PatternLeaf, Pattern: p} }
+	and := func(o ...*PatternExpr) *PatternExpr { return &PatternExpr{Op: PatternAnd, Operands: o} }
+	or := func(o ...*PatternExpr) *PatternExpr { return &PatternExpr{Op: PatternOr, Operands: o} }
+	not := func(o *PatternExpr) *PatternExpr { return &PatternExpr{Op: PatternNot, Operands: []*PatternExpr{o}} }
+
+	tests := map[string]struct {
+		expr      *PatternExpr
+		wantUnion string
+		matches   []string // documents (with lines separated by "|") that match
+		noMatches []string // documents that don't match
+	}{
+		"leaf": {
+			expr:      leaf("a"),
+			wantUnion: "a",
+			matches:   []string{"a", "xAx", "b|a"},
+			noMatches: []string{"", "b"},
+		},
+		"or": {
+			expr:      or(leaf("a"), leaf("b")),
+			wantUnion: "(a)|(b)",
+			matches:   []string{"a", "b", "c|b"},
+			noMatches: []string{"c"},
+		},
+		"and": {
+			expr:      and(leaf("a"), leaf("b")),
+			wantUnion: "(a)|(b)",
+			matches:   []string{"ab", "a|b"},
+			noMatches: []string{"a", "b|c"},
+		},
+		"and not": {
+			expr:      and(leaf("a"), not(leaf("b"))),
+			wantUnion: "(a)|(b)",
+			matches:   []string{"a", "a|c"},
+			noMatches: []string{"ab", "a|b", "c"},
+		},
+		"nested": {
+			expr:      and(or(leaf("a"), leaf("b")), not(or(leaf("c"), leaf("d")))),
+			wantUnion: "(a)|(b)|(c)|(d)",
+			matches:   []string{"a", "b|x"},
+			noMatches: []string{"a|d", "bc", "x"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if union := test.expr.Union(); union != test.wantUnion {
+				t.Errorf("got union %q, want %q", union, test.wantUnion)
+			}
+			m, err := test.expr.Compile(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, doc := range test.matches {
+				if !m.Match(strings.Split(doc, "|")) {
+					t.Errorf("%q: got no match, want match", doc)
+				}
+			}
+			for _, doc := range test.noMatches {
+				if m.Match(strings.Split(doc, "|")) {
+					t.Errorf("%q: got match, want no match", doc)
+				}
+			}
+		})
+	}
+}
+
+func TestPatternMatcher_MatchesLine(t *testing.T) {
+	expr := &PatternExpr{Op: PatternAnd, Operands: []*PatternExpr{
+		{Op: PatternLeaf, Pattern: "a"},
+		{Op: PatternNot, Operands: []*PatternExpr{{Op: PatternLeaf, Pattern: "b"}}},
+	}}
+	m, err := expr.Compile(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for line, want := range map[string]bool{"a": true, "A": false, "b": false, "ab": true} {
+		if got := m.MatchesLine(line); got != want {
+			t.Errorf("%q: got %v, want %v", line, got, want)
+		}
+	}
+}
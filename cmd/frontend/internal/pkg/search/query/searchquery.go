@@ -19,6 +19,10 @@ const (
 	FieldLang      = "lang"
 	FieldType      = "type"
 
+	// For symbol search only:
+	FieldKind   = "kind"
+	FieldParent = "parent"
+
 	// For diff and commit search only:
 	FieldBefore    = "before"
 	FieldAfter     = "after"
@@ -49,6 +53,9 @@ var (
 			FieldLang:      {Literal: types.StringType, Quoted: types.StringType, Negatable: true},
 			FieldType:      stringFieldType,
 
+			FieldKind:   {Literal: types.StringType, Quoted: types.StringType, Negatable: true},
+			FieldParent: {Literal: types.RegexpType, Quoted: types.RegexpType, Singular: true},
+
 			FieldBefore:    stringFieldType,
 			FieldAfter:     stringFieldType,
 			FieldAuthor:    regexpNegatableFieldType,
@@ -15,6 +15,9 @@ func (e *ParseError) Error() string {
 type parser struct {
 	tokens []Token
 	pos    int
+
+	exprs   []*Expr // all expressions parsed so far, in order
+	boolean bool    // whether the query uses boolean operators or parentheses
 }
 
 // context holds settings active within a given scope during parsing.
@@ -27,20 +30,35 @@ type context struct {
 //
 // BNF-ish query syntax:
 //
-//   exprList  := {exprSign} | exprSign (sep exprSign)*
-//   exprSign  := {"-"} expr
+//   orExpr    := andExpr ("OR" andExpr)*
+//   andExpr   := seq ("AND" seq)*
+//   seq       := {term} | term (sep term)*
+//   term      := {"-" | "NOT"} (expr | "(" orExpr ")")
 //   expr      := fieldExpr | lit | quoted | pattern
 //   fieldExpr := lit ":" value
 //   value     := lit | quoted
+//
+// Field expressions apply to the whole query wherever they appear, so only
+// patterns are part of the boolean expression tree (Query.Tree). Queries
+// without boolean operators and parentheses have no tree: their patterns are
+// matched in order on the same line, as always.
 func Parse(input string) (*Query, error) {
 	tokens := Scan(input)
 	p := parser{tokens: tokens}
 	ctx := context{field: ""}
-	exprs, err := p.parseExprList(ctx)
+	tree, _, err := p.parseOr(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Query{Expr: exprs, Input: input}, nil
+	if tok := p.next(); tok.Type != TokenEOF {
+		return nil, &ParseError{Pos: tok.Pos, Msg: fmt.Sprintf("got %s, want expr", tok.Type)}
+	}
+
+	q := &Query{Expr: p.exprs, Input: input}
+	if p.boolean {
+		q.Tree = tree
+	}
+	return q, nil
 }
 
 // peek returns the next token without consuming it. Peeking beyond the end of
@@ -68,54 +86,201 @@ func (p *parser) next() Token {
 	return Token{Type: TokenEOF}
 }
 
-// exprList := {exprSign} | exprSign (sep exprSign)*
-func (p *parser) parseExprList(ctx context) (exprList []*Expr, err error) {
-	if p.peek().Type == TokenEOF {
-		return nil, nil
+// skipSep skips over separators in the token stream.
+func (p *parser) skipSep() {
+	for p.peek().Type == TokenSep {
+		p.next()
 	}
+}
 
+// endOfExpr consumes the token following an expression and reports whether
+// it ends the expression. A closing parenthesis ends the expression but is
+// left in the token stream for the enclosing group.
+func (p *parser) endOfExpr() (Token, bool) {
+	tok := p.next()
+	switch tok.Type {
+	case TokenSep, TokenEOF:
+		return tok, true
+	case TokenRParen:
+		p.backup()
+		return tok, true
+	}
+	return tok, false
+}
+
+// orExpr := andExpr ("OR" andExpr)*
+func (p *parser) parseOr(ctx context) (*Node, int, error) {
+	var (
+		operands []*Node
+		pos      []int // start position of each operand
+		terms    int
+	)
 	for {
+		p.skipSep()
 		tok := p.peek()
-		if tok.Type == TokenEOF {
+		n, nterms, err := p.parseAnd(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(operands) > 0 && nterms == 0 {
+			return nil, 0, &ParseError{Pos: tok.Pos, Msg: fmt.Sprintf("got %s, want expr", tok.Type)}
+		}
+		operands = append(operands, n)
+		pos = append(pos, tok.Pos)
+		terms += nterms
+
+		p.skipSep()
+		if p.peek().Type != TokenOr {
 			break
 		}
-		if tok.Type == TokenSep {
-			p.next()
-			continue
+		if nterms == 0 {
+			tok = p.peek()
+			return nil, 0, &ParseError{Pos: tok.Pos, Msg: fmt.Sprintf("got %s, want expr", tok.Type)}
 		}
+		p.next()
+		p.boolean = true
+	}
+
+	if len(operands) == 1 {
+		return operands[0], terms, nil
+	}
+	for i, n := range operands {
+		if n == nil {
+			return nil, 0, &ParseError{Pos: pos[i], Msg: "OR operands must contain a search pattern (fields apply to the whole query)"}
+		}
+	}
+	return &Node{Pos: operands[0].Pos, Op: OpOr, Operands: operands}, terms, nil
+}
 
-		expr, err := p.parseExprSign(ctx)
+// andExpr := seq ("AND" seq)*
+func (p *parser) parseAnd(ctx context) (*Node, int, error) {
+	var (
+		operands []*Node
+		terms    int
+	)
+	for {
+		p.skipSep()
+		tok := p.peek()
+		n, nterms, err := p.parseSeq(ctx)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+		if terms > 0 && nterms == 0 {
+			return nil, 0, &ParseError{Pos: tok.Pos, Msg: fmt.Sprintf("got %s, want expr", tok.Type)}
+		}
+		if n != nil {
+			// Operands without patterns only contain fields, which apply to
+			// the whole query anyway.
+			operands = append(operands, n)
+		}
+		terms += nterms
+
+		p.skipSep()
+		if p.peek().Type != TokenAnd {
+			break
 		}
-		exprList = append(exprList, expr)
+		if nterms == 0 {
+			tok = p.peek()
+			return nil, 0, &ParseError{Pos: tok.Pos, Msg: fmt.Sprintf("got %s, want expr", tok.Type)}
+		}
+		p.next()
+		p.boolean = true
+	}
+
+	switch len(operands) {
+	case 0:
+		return nil, terms, nil
+	case 1:
+		return operands[0], terms, nil
+	default:
+		return &Node{Pos: operands[0].Pos, Op: OpAnd, Operands: operands}, terms, nil
 	}
+}
 
-	return exprList, nil
+// seq := {term} | term (sep term)*
+func (p *parser) parseSeq(ctx context) (*Node, int, error) {
+	var (
+		operands []*Node
+		terms    int
+	)
+	for {
+		p.skipSep()
+		switch p.peek().Type {
+		case TokenEOF, TokenRParen, TokenAnd, TokenOr:
+			switch len(operands) {
+			case 0:
+				return nil, terms, nil
+			case 1:
+				return operands[0], terms, nil
+			default:
+				return &Node{Pos: operands[0].Pos, Op: OpConcat, Operands: operands}, terms, nil
+			}
+		}
+
+		n, err := p.parseTerm(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		if n != nil {
+			operands = append(operands, n)
+		}
+		terms++
+	}
 }
 
-// exprSign := {"-"} expr
-func (p *parser) parseExprSign(ctx context) (*Expr, error) {
+// term := {"-" | "NOT"} (expr | "(" orExpr ")")
+//
+// It returns a nil node for field expressions, which are only recorded in
+// (*parser).exprs.
+func (p *parser) parseTerm(ctx context) (*Node, error) {
 	tok := p.next()
 	switch tok.Type {
 	case TokenMinus:
 		// consume token
+	case TokenNot:
+		p.boolean = true
+		p.skipSep()
 	default:
 		tok = Token{Type: TokenEOF}
 		p.backup()
 	}
+	not := tok.Type != TokenEOF
+
+	if lparen := p.peek(); lparen.Type == TokenLParen {
+		p.next()
+		p.boolean = true
+		n, terms, err := p.parseOr(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.skipSep()
+		if rparen := p.next(); rparen.Type != TokenRParen {
+			return nil, &ParseError{Pos: rparen.Pos, Msg: fmt.Sprintf("got %s, want closing parenthesis", rparen.Type)}
+		}
+		if terms == 0 || n == nil {
+			return nil, &ParseError{Pos: lparen.Pos, Msg: "parentheses must contain a search pattern"}
+		}
+		if not {
+			if n.Expr != nil {
+				n.Expr.Not = !n.Expr.Not
+			} else {
+				n = &Node{Pos: tok.Pos, Op: OpNot, Operands: []*Node{n}}
+			}
+		}
+		return n, nil
+	}
 
 	expr, err := p.parseExpr(ctx)
 	if err != nil {
 		return nil, err
 	}
+	expr.Not = not
+	p.exprs = append(p.exprs, expr)
 
-	switch tok.Type {
-	case TokenMinus:
-		expr.Not = true
+	if expr.Field != "" {
+		return nil, nil
 	}
-
-	return expr, nil
+	return &Node{Pos: expr.Pos, Expr: expr}, nil
 }
 
 // expr := exprField | lit | quoted | pattern
@@ -129,28 +294,31 @@ func (p *parser) parseExpr(ctx context) (*Expr, error) {
 			valueTok := p.next()
 			switch valueTok.Type {
 			case TokenLiteral, TokenQuoted:
-				if tok3 := p.next(); tok3.Type != TokenSep && tok3.Type != TokenEOF {
+				if tok3, ok := p.endOfExpr(); !ok {
 					return nil, &ParseError{Pos: tok3.Pos, Msg: fmt.Sprintf("got %s, want separator or EOF", tok3.Type)}
 				}
 				return &Expr{Pos: tok.Pos, Field: tok.Value, Value: valueTok.Value, ValueType: valueTok.Type}, nil
-			case TokenSep, TokenEOF:
+			case TokenSep, TokenEOF, TokenRParen:
+				if valueTok.Type == TokenRParen {
+					p.backup()
+				}
 				return &Expr{Pos: tok.Pos, Field: tok.Value, Value: "", ValueType: TokenLiteral}, nil
 			default:
 				return nil, &ParseError{Pos: valueTok.Pos, Msg: fmt.Sprintf("got %s, want value", valueTok.Type)}
 			}
 		case TokenSep, TokenEOF:
 			return &Expr{Pos: tok.Pos, Value: tok.Value, ValueType: tok.Type}, nil
+		case TokenRParen:
+			p.backup()
+			return &Expr{Pos: tok.Pos, Value: tok.Value, ValueType: tok.Type}, nil
 		default:
 			panic("unreachable")
 		}
 	case TokenQuoted, TokenPattern:
-		tok2 := p.next()
-		switch tok2.Type {
-		case TokenSep, TokenEOF:
-			return &Expr{Pos: tok.Pos, Value: tok.Value, ValueType: tok.Type}, nil
-		default:
+		if tok2, ok := p.endOfExpr(); !ok {
 			return nil, &ParseError{Pos: tok2.Pos, Msg: fmt.Sprintf("got %s, want separator or EOF", tok2.Type)}
 		}
+		return &Expr{Pos: tok.Pos, Value: tok.Value, ValueType: tok.Type}, nil
 	}
 
 	return nil, &ParseError{Pos: tok.Pos, Msg: fmt.Sprintf("got %s, want expr", tok.Type)}
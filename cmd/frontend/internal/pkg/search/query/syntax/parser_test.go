@@ -8,6 +8,7 @@ import (
 func TestParser(t *testing.T) {
 	tests := map[string]struct {
 		wantExpr   []*Expr
+		wantTree   string // empty if the query has no boolean expression tree
 		wantString string
 		wantErr    *ParseError
 	}{
@@ -67,6 +68,97 @@ func TestParser(t *testing.T) {
 				{Field: "b", Value: "", ValueType: TokenLiteral},
 			},
 		},
+		"a OR b": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+			},
+			wantTree: "a OR b",
+		},
+		"a b OR c": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+				{Value: "c", ValueType: TokenLiteral},
+			},
+			wantTree: "a b OR c",
+		},
+		"(a OR b) c": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+				{Value: "c", ValueType: TokenLiteral},
+			},
+			wantTree: "(a OR b) c",
+		},
+		"a AND b OR c AND d": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+				{Value: "c", ValueType: TokenLiteral},
+				{Value: "d", ValueType: TokenLiteral},
+			},
+			wantTree: "a AND b OR c AND d",
+		},
+		"a AND NOT b": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Not: true, Value: "b", ValueType: TokenLiteral},
+			},
+			wantTree:   "a AND -b",
+			wantString: "a AND -b",
+		},
+		"a -(b OR /c/)": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+				{Value: "c", ValueType: TokenPattern},
+			},
+			wantTree: "a -(b OR /c/)",
+		},
+		"repo:x (a OR b)": {
+			wantExpr: []*Expr{
+				{Field: "repo", Value: "x", ValueType: TokenLiteral},
+				{Value: "a", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+			},
+			wantTree:   "a OR b",
+			wantString: "repo:x a OR b",
+		},
+		"(a repo:x) OR b": {
+			wantExpr: []*Expr{
+				{Value: "a", ValueType: TokenLiteral},
+				{Field: "repo", Value: "x", ValueType: TokenLiteral},
+				{Value: "b", ValueType: TokenLiteral},
+			},
+			wantTree:   "a OR b",
+			wantString: "repo:x a OR b",
+		},
+		"(repo:x b:y)": {
+			wantErr: &ParseError{Pos: 0, Msg: "parentheses must contain a search pattern"},
+		},
+		"a OR repo:x": {
+			wantErr: &ParseError{Pos: 5, Msg: "OR operands must contain a search pattern (fields apply to the whole query)"},
+		},
+		"a OR": {
+			wantErr: &ParseError{Pos: 4, Msg: "got TokenEOF, want expr"},
+		},
+		"AND a": {
+			wantErr: &ParseError{Pos: 0, Msg: "got TokenAnd, want expr"},
+		},
+		"a AND OR b": {
+			wantErr: &ParseError{Pos: 6, Msg: "got TokenOr, want expr"},
+		},
+		"foo(": {
+			wantExpr: []*Expr{{Value: "foo(", ValueType: TokenLiteral}},
+		},
+		"(a|b)c or d": {
+			wantExpr: []*Expr{
+				{Value: "(a|b)c", ValueType: TokenLiteral},
+				{Value: "or", ValueType: TokenLiteral},
+				{Value: "d", ValueType: TokenLiteral},
+			},
+		},
 		"--": {
 			wantErr: &ParseError{Pos: 1, Msg: "got TokenMinus, want expr"},
 		},
@@ -105,7 +197,14 @@ func TestParser(t *testing.T) {
 			if test.wantString == "" && len(query.Expr) > 0 {
 				test.wantString = input
 			}
-			if exprString := ExprString(query.Expr); exprString != test.wantString {
+			var tree string
+			if query.Tree != nil {
+				tree = query.Tree.String()
+			}
+			if tree != test.wantTree {
+				t.Errorf("tree: %s\ngot  %s\nwant %s", input, tree, test.wantTree)
+			}
+			if exprString := QueryString(query.Expr, query.Tree); exprString != test.wantString {
 				t.Errorf("expr string: %s\ngot  %s\nwant %s", input, exprString, test.wantString)
 			}
 		})
@@ -9,6 +9,7 @@ import (
 type Query struct {
 	Input string  // the original input query string
 	Expr  []*Expr // expressions in this query
+	Tree  *Node   // boolean expression tree of the query's patterns, or nil if the query has no boolean operators
 }
 
 // An Expr describes an expression in a query.
@@ -47,3 +48,84 @@ func ExprString(expr []*Expr) string {
 	}
 	return strings.Join(s, " ")
 }
+
+// An Operator is a boolean operator combining the operands of a Node.
+type Operator int
+
+// All Operator values.
+const (
+	OpConcat Operator = iota // the operands are juxtaposed (e.g., "a b")
+	OpAnd                    // all operands must match (e.g., "a AND b")
+	OpOr                     // at least one operand must match (e.g., "a OR b")
+	OpNot                    // the single operand must not match (e.g., "-(a OR b)")
+)
+
+// precedence returns how tightly op binds its operands.
+func (op Operator) precedence() int {
+	switch op {
+	case OpOr:
+		return 1
+	case OpAnd:
+		return 2
+	case OpConcat:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// A Node is a node in the boolean expression tree of a query's patterns. It is
+// either a leaf holding a pattern expression (which may be negated), or an
+// operator applied to its operands. Field expressions (such as repo:foo) are
+// never part of the tree; they apply to the query as a whole.
+type Node struct {
+	Pos      int      // the starting character position of the node
+	Expr     *Expr    // the pattern expression, if this is a leaf
+	Op       Operator // the operator, if this is not a leaf
+	Operands []*Node  // the operands of Op
+}
+
+func (n *Node) String() string {
+	if n.Expr != nil {
+		return n.Expr.String()
+	}
+
+	s := make([]string, len(n.Operands))
+	for i, o := range n.Operands {
+		s[i] = o.String()
+		if o.Expr == nil && o.Op.precedence() <= n.Op.precedence() {
+			s[i] = "(" + s[i] + ")"
+		}
+	}
+
+	switch n.Op {
+	case OpAnd:
+		return strings.Join(s, " AND ")
+	case OpOr:
+		return strings.Join(s, " OR ")
+	case OpNot:
+		return "-" + s[0]
+	default:
+		return strings.Join(s, " ")
+	}
+}
+
+// QueryString returns the query string for the expressions expr of a query
+// whose patterns are combined by tree. If tree is nil, it is equivalent to
+// ExprString(expr).
+func QueryString(expr []*Expr, tree *Node) string {
+	if tree == nil {
+		return ExprString(expr)
+	}
+
+	fields := make([]*Expr, 0, len(expr))
+	for _, e := range expr {
+		if e.Field != "" {
+			fields = append(fields, e)
+		}
+	}
+	if len(fields) == 0 {
+		return tree.String()
+	}
+	return ExprString(fields) + " " + tree.String()
+}
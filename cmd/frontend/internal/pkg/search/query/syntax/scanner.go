@@ -22,8 +22,21 @@ const (
 	TokenColon
 	TokenMinus
 	TokenSep // separator (like a semicolon)
+	TokenLParen
+	TokenRParen
+	TokenAnd
+	TokenOr
+	TokenNot
 )
 
+// keywordTokens are the boolean operator keywords. They are only recognized
+// when written in uppercase and not used as a field value.
+var keywordTokens = map[string]TokenType{
+	"AND": TokenAnd,
+	"OR":  TokenOr,
+	"NOT": TokenNot,
+}
+
 var singleCharTokens = map[rune]TokenType{
 	':': TokenColon,
 	'-': TokenMinus,
@@ -54,6 +67,8 @@ type scanner struct {
 	pos     int
 	prevPos int
 	start   int
+
+	parenDepth int // number of currently open grouping parentheses
 }
 
 func (s *scanner) next() rune {
@@ -88,6 +103,18 @@ func (s *scanner) emit(typ TokenType) {
 	s.start = s.pos
 }
 
+// emitLiteral emits the current literal, or the keyword token it spells
+// unless it is a field value.
+func (s *scanner) emitLiteral() {
+	if typ, ok := keywordTokens[s.input[s.start:s.pos]]; ok {
+		if n := len(s.tokens); n == 0 || s.tokens[n-1].Type != TokenColon {
+			s.emit(typ)
+			return
+		}
+	}
+	s.emit(TokenLiteral)
+}
+
 func (s *scanner) emitError(msg string) {
 	s.tokens = append(s.tokens, Token{
 		Type:  TokenError,
@@ -118,12 +145,48 @@ func scanDefault(s *scanner) stateFn {
 		if r == '/' {
 			return scanPattern
 		}
+		if r == '(' && s.isGroupStart() {
+			s.next()
+			s.parenDepth++
+			s.emit(TokenLParen)
+			return scanDefault
+		}
+		if r == ')' && s.parenDepth > 0 {
+			s.next()
+			s.parenDepth--
+			s.emit(TokenRParen)
+			return scanDefault
+		}
 
 		return scanText
 	}
 	return scanSpace
 }
 
+// isGroupStart reports whether the '(' at the current position opens a
+// group. Parentheses that are balanced within a word (such as "(a|b)c") are
+// part of a literal, as are parentheses that are never closed (such as
+// "foo(" or "(foo") so that queries for code keep working.
+func (s *scanner) isGroupStart() bool {
+	depth := 0
+	for _, r := range s.input[s.pos:] {
+		if unicode.IsSpace(r) {
+			break
+		}
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			return false
+		}
+	}
+	rest := s.input[s.pos:]
+	return strings.Count(rest, ")") >= strings.Count(rest, "(")
+}
+
 func scanText(s *scanner) stateFn {
 	// Characters that may come before a ':' (TokenColon) in a TokenLiteral.
 	preColonChars := "abcdefghijklmnopqrstuvwxyz0123456789"
@@ -146,11 +209,12 @@ func scanText(s *scanner) stateFn {
 			return scanValue
 		}
 		if !strings.ContainsRune(preColonChars, r) {
+			s.backup()
 			return scanLiteral
 		}
 	}
 
-	s.emit(TokenLiteral)
+	s.emitLiteral()
 	return scanDefault
 }
 
@@ -169,6 +233,7 @@ func scanValue(s *scanner) stateFn {
 }
 
 func scanLiteral(s *scanner) stateFn {
+	depth := 0 // parentheses opened in this literal
 	for {
 		if s.eof() {
 			break
@@ -178,9 +243,23 @@ func scanLiteral(s *scanner) stateFn {
 			s.backup()
 			break
 		}
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ')' && s.parenDepth > 0:
+			// End of the enclosing group.
+			s.backup()
+			if s.pos == s.start {
+				return scanDefault
+			}
+			s.emitLiteral()
+			return scanDefault
+		}
 	}
 
-	s.emit(TokenLiteral)
+	s.emitLiteral()
 	return scanDefault
 }
 
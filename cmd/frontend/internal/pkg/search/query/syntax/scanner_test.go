@@ -67,6 +67,17 @@ func TestScanner(t *testing.T) {
 		"a /b/ c":  {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenPattern, TokenSep, TokenLiteral}, wantValues: []string{"a", " ", "b", " ", "c"}},
 		"a /b c":   {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenPattern}, wantValues: []string{"a", " ", "b c"}},
 		"a /b c/":  {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenPattern}, wantValues: []string{"a", " ", "b c"}},
+		"a OR b":   {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenOr, TokenSep, TokenLiteral}, wantValues: []string{"a", " ", "OR", " ", "b"}},
+		"a or b":   {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenLiteral, TokenSep, TokenLiteral}, wantValues: []string{"a", " ", "or", " ", "b"}},
+		"AND NOT":  {wantTypes: []TokenType{TokenAnd, TokenSep, TokenNot}, wantValues: []string{"AND", " ", "NOT"}},
+		"a:OR":     {wantTypes: []TokenType{TokenLiteral, TokenColon, TokenLiteral}, wantValues: []string{"a", ":", "OR"}},
+		"(a)":      {wantTypes: []TokenType{TokenLiteral}, wantValues: []string{"(a)"}},
+		"(a b)":    {wantTypes: []TokenType{TokenLParen, TokenLiteral, TokenSep, TokenLiteral, TokenRParen}, wantValues: []string{"(", "a", " ", "b", ")"}},
+		"-(a b)":   {wantTypes: []TokenType{TokenMinus, TokenLParen, TokenLiteral, TokenSep, TokenLiteral, TokenRParen}, wantValues: []string{"-", "(", "a", " ", "b", ")"}},
+		"(f(x) y)": {wantTypes: []TokenType{TokenLParen, TokenLiteral, TokenSep, TokenLiteral, TokenRParen}, wantValues: []string{"(", "f(x)", " ", "y", ")"}},
+		"(a:b c)":  {wantTypes: []TokenType{TokenLParen, TokenLiteral, TokenColon, TokenLiteral, TokenSep, TokenLiteral, TokenRParen}, wantValues: []string{"(", "a", ":", "b", " ", "c", ")"}},
+		"(a b":     {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenLiteral}, wantValues: []string{"(a", " ", "b"}},
+		"a b)":     {wantTypes: []TokenType{TokenLiteral, TokenSep, TokenLiteral}, wantValues: []string{"a", " ", "b)"}},
 	}
 	for input, test := range tests {
 		t.Run(input, func(t *testing.T) {
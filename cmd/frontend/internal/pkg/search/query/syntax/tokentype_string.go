@@ -16,11 +16,16 @@ func _() {
 	_ = x[TokenColon-5]
 	_ = x[TokenMinus-6]
 	_ = x[TokenSep-7]
+	_ = x[TokenLParen-8]
+	_ = x[TokenRParen-9]
+	_ = x[TokenAnd-10]
+	_ = x[TokenOr-11]
+	_ = x[TokenNot-12]
 }
 
-const _TokenType_name = "TokenEOFTokenErrorTokenLiteralTokenQuotedTokenPatternTokenColonTokenMinusTokenSep"
+const _TokenType_name = "TokenEOFTokenErrorTokenLiteralTokenQuotedTokenPatternTokenColonTokenMinusTokenSepTokenLParenTokenRParenTokenAndTokenOrTokenNot"
 
-var _TokenType_index = [...]uint8{0, 8, 18, 30, 41, 53, 63, 73, 81}
+var _TokenType_index = [...]uint8{0, 8, 18, 30, 41, 53, 63, 73, 81, 92, 103, 111, 118, 126}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenType_index)-1) {
@@ -44,8 +44,12 @@ func (c *Config) Check(query *syntax.Query) (*Query, error) {
 		Syntax: query,
 		Fields: map[string][]*Value{},
 	}
+	values := make(map[*syntax.Expr]*Value, len(query.Expr))
 	for _, expr := range query.Expr {
-		field, fieldType, value, err := c.checkExpr(expr)
+		// Patterns in a boolean expression tree may be negated, as long as
+		// the tree as a whole still matches something (see checkTree).
+		negatable := query.Tree != nil && expr.Field == ""
+		field, fieldType, value, err := c.checkExpr(expr, negatable)
 		if err != nil {
 			return nil, err
 		}
@@ -53,11 +57,60 @@ func (c *Config) Check(query *syntax.Query) (*Query, error) {
 			return nil, &TypeError{Pos: expr.Pos, Err: fmt.Errorf("field %q may not be used more than once", field)}
 		}
 		checkedQuery.Fields[field] = append(checkedQuery.Fields[field], value)
+		values[expr] = value
+	}
+
+	if query.Tree != nil {
+		if !positive(query.Tree) {
+			return nil, &TypeError{Pos: query.Tree.Pos, Err: errors.New("the query must contain a pattern that is not negated")}
+		}
+		checkedQuery.Tree = checkTree(query.Tree, values)
 	}
 	return &checkedQuery, nil
 }
 
-func (c *Config) resolveField(field string, not bool) (resolvedField string, typ FieldType, err error) {
+// checkTree returns the typechecked boolean expression tree of n, whose leaf
+// expressions have already been typechecked to values.
+func checkTree(n *syntax.Node, values map[*syntax.Expr]*Value) *Node {
+	if n.Expr != nil {
+		return &Node{Value: values[n.Expr]}
+	}
+	operands := make([]*Node, len(n.Operands))
+	for i, o := range n.Operands {
+		operands[i] = checkTree(o, values)
+	}
+	return &Node{Op: n.Op, Operands: operands}
+}
+
+// positive reports whether every match of n must contain a non-negated
+// pattern. Queries consisting only of negations (such as "-a" or
+// "-a OR b") would match nearly everything, which we can't search for
+// efficiently.
+func positive(n *syntax.Node) bool {
+	if n.Expr != nil {
+		return !n.Expr.Not
+	}
+	switch n.Op {
+	case syntax.OpNot:
+		return false
+	case syntax.OpOr:
+		for _, o := range n.Operands {
+			if !positive(o) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, o := range n.Operands {
+			if positive(o) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c *Config) resolveField(field string, not, negatable bool) (resolvedField string, typ FieldType, err error) {
 	// Resolve field alias, if any.
 	if resolvedField, ok := c.FieldAliases[field]; ok {
 		field = resolvedField
@@ -74,7 +127,7 @@ func (c *Config) resolveField(field string, not bool) (resolvedField string, typ
 		err = fmt.Errorf("unrecognized field %q; the feature flag for this field is not enabled", field)
 		return
 	}
-	if not && !typ.Negatable {
+	if not && !typ.Negatable && !negatable {
 		if field == "" {
 			err = errors.New("negated terms (-term) are not yet supported")
 		} else {
@@ -85,9 +138,9 @@ func (c *Config) resolveField(field string, not bool) (resolvedField string, typ
 	return field, typ, nil
 }
 
-func (c *Config) checkExpr(expr *syntax.Expr) (field string, fieldType FieldType, value *Value, err error) {
+func (c *Config) checkExpr(expr *syntax.Expr, negatable bool) (field string, fieldType FieldType, value *Value, err error) {
 	// Resolve field name.
-	resolvedField, fieldType, err := c.resolveField(expr.Field, expr.Not)
+	resolvedField, fieldType, err := c.resolveField(expr.Field, expr.Not, negatable)
 	if err != nil {
 		return "", FieldType{}, nil, &TypeError{Pos: expr.Pos, Err: err}
 	}
@@ -101,6 +101,18 @@ func TestCheck(t *testing.T) {
 		"b:z":        {wantErr: &TypeError{Pos: 0, Err: errors.New(`invalid boolean "z"`)}},
 		`b:"z"`:      {wantErr: &TypeError{Pos: 0, Err: errors.New(`invalid boolean "z"`)}},
 		"z:a":        {wantErr: &TypeError{Pos: 0, Err: errors.New(`unrecognized field "z"`)}},
+
+		"a AND NOT b": {want: map[string][]value{"": {
+			{Value: regexp.MustCompile("a")},
+			{Not: true, Value: regexp.MustCompile("b")},
+		}}},
+		"(a OR b) -r:c": {want: map[string][]value{
+			"":  {{Value: regexp.MustCompile("a")}, {Value: regexp.MustCompile("b")}},
+			"r": {{Not: true, Value: regexp.MustCompile("c")}},
+		}},
+		"-a OR b":         {wantErr: &TypeError{Pos: 1, Err: errors.New(`the query must contain a pattern that is not negated`)}},
+		"NOT (a OR b)":    {wantErr: &TypeError{Pos: 0, Err: errors.New(`the query must contain a pattern that is not negated`)}},
+		"(a OR b) -b:yes": {wantErr: &TypeError{Pos: 10, Err: errors.New(`field "b" does not support negation`)}},
 	}
 	for input, test := range tests {
 		t.Run(input, func(t *testing.T) {
@@ -119,6 +131,9 @@ func TestCheck(t *testing.T) {
 			if err != nil {
 				return
 			}
+			if (query.Tree == nil) != (syntaxQuery.Tree == nil) {
+				t.Errorf("got tree %v, want tree for syntax tree %v", query.Tree, syntaxQuery.Tree)
+			}
 			if got := toTestValueMap(query.Fields); !reflect.DeepEqual(got, test.want) {
 				t.Errorf("fields\ngot  %+v\nwant %+v", got, test.want)
 			}
@@ -10,6 +10,15 @@ import (
 type Query struct {
 	Syntax *syntax.Query       // the query syntax
 	Fields map[string][]*Value // map of field name -> values
+	Tree   *Node               // boolean expression tree of the patterns, or nil (see syntax.Query.Tree)
+}
+
+// A Node is a node in the typechecked boolean expression tree of a query's
+// patterns. It mirrors the syntax.Node it was checked from.
+type Node struct {
+	Value    *Value          // the pattern value, if this is a leaf
+	Op       syntax.Operator // the operator, if this is not a leaf
+	Operands []*Node         // the operands of Op
 }
 
 // ValueType is the set of types of values in queries.
@@ -27,6 +27,18 @@ type PatternInfo struct {
 
 	PatternMatchesContent bool
 	PatternMatchesPath    bool
+
+	// Expr is the boolean expression of patterns if the query uses AND, OR
+	// or NOT, in which case Pattern is Expr.Union(). It is not sent to
+	// searcher; results are post-filtered with it instead.
+	Expr *PatternExpr
+
+	// CountOnly is whether only the number of matches in each file is needed
+	// (for aggregations). Backends then count all of a file's matches instead
+	// of returning a limited number of line matches, and omit the contents of
+	// the matches. It can't be combined with Expr, which is evaluated on the
+	// contents of the matches.
+	CountOnly bool
 }
 
 func (p *PatternInfo) IsEmpty() bool {
@@ -177,7 +177,15 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 		}
 		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now()}
 	}
-	return SetData(w, r, "actor", value)
+	if err := SetData(w, r, "actor", value); err != nil {
+		return err
+	}
+	if actor.IsAuthenticated() {
+		// Refresh the user's repository permissions upon sign-in, so that any changes to the user's
+		// permissions on the code host take effect immediately.
+		db.RefreshUserPermissions(actor.UID)
+	}
+	return nil
 }
 
 func hasSessionCookie(r *http.Request) bool {
@@ -86,3 +86,7 @@ func (r *registryExtensionRemoteResolver) IsWorkInProgress() bool {
 func (r *registryExtensionRemoteResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
 	return false, nil // can't administer remote extensions
 }
+
+func (r *registryExtensionRemoteResolver) Signature(context.Context) (graphqlbackend.ExtensionReleaseSignature, error) {
+	return VerifyRemoteExtensionSignature(r.v)
+}
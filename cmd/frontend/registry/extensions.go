@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
@@ -20,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/httputil"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 func init() {
@@ -165,6 +167,46 @@ var IsRemoteExtensionAllowed = func(extensionID string) bool {
 	return true
 }
 
+// VerifyRemoteExtensionSignature verifies the signature of the remote extension's release. It
+// returns nil if the release is unsigned, and an error if the signature is invalid or the release
+// is not allowed by the site configuration's signing requirements.
+//
+// It can be overridden to use custom logic.
+var VerifyRemoteExtensionSignature = func(x *registry.Extension) (graphqlbackend.ExtensionReleaseSignature, error) {
+	// By default, signatures are not verified.
+	return nil, nil
+}
+
+// VerifyRemoteExtensionBundle verifies that the bundle that the remote extension's manifest refers
+// to (and that clients load) matches the bundle hash in the extension's release information, if the
+// site configuration requires this. Unlike VerifyRemoteExtensionSignature, which only checks the
+// information reported by the remote registry, it fetches the bundle.
+//
+// It can be overridden to use custom logic.
+var VerifyRemoteExtensionBundle = func(ctx context.Context, x *registry.Extension) error {
+	// By default, bundles are not verified.
+	return nil
+}
+
+// remoteReleaseQuery returns the release query for the remote extension with the given extension ID,
+// according to the "extensions.pinnedVersions" and "extensions.betaExtensions" site configuration
+// properties.
+func remoteReleaseQuery(extensionID string) registry.ReleaseQuery {
+	var q registry.ReleaseQuery
+	x := conf.Get().Extensions
+	if x == nil {
+		return q
+	}
+	q.Version = x.PinnedVersions[extensionID]
+	for _, id := range x.BetaExtensions {
+		if id == extensionID {
+			q.Channel = registry.ChannelBeta
+			break
+		}
+	}
+	return q
+}
+
 var mockGetRemoteRegistryExtension func(field, value string) (*registry.Extension, error)
 
 // getRemoteRegistryExtension gets the remote registry extension and rewrites its fields to be from
@@ -182,9 +224,16 @@ func getRemoteRegistryExtension(ctx context.Context, field, value string) (*regi
 	var x *registry.Extension
 	switch field {
 	case "uuid":
-		x, err = registry.GetByUUID(ctx, registryURL, value)
+		x, err = registry.GetByUUID(ctx, registryURL, value, registry.ReleaseQuery{})
+		if x != nil && err == nil {
+			// The extension ID is not known until the extension is fetched, so fetch it again if
+			// the site pins it to a different release.
+			if release := remoteReleaseQuery(x.ExtensionID); !release.IsZero() {
+				x, err = registry.GetByUUID(ctx, registryURL, value, release)
+			}
+		}
 	case "extensionID":
-		x, err = registry.GetByExtensionID(ctx, registryURL, value)
+		x, err = registry.GetByExtensionID(ctx, registryURL, value, remoteReleaseQuery(value))
 	default:
 		panic("unexpected field: " + field)
 	}
@@ -195,6 +244,14 @@ func getRemoteRegistryExtension(ctx context.Context, field, value string) (*regi
 	if x != nil && !IsRemoteExtensionAllowed(x.ExtensionID) {
 		return nil, fmt.Errorf("extension is not allowed in site configuration: %q", x.ExtensionID)
 	}
+	if x != nil {
+		if _, err := VerifyRemoteExtensionSignature(x); err != nil {
+			return nil, err
+		}
+		if err := VerifyRemoteExtensionBundle(ctx, x); err != nil {
+			return nil, err
+		}
+	}
 
 	return x, err
 }
@@ -221,10 +278,32 @@ func listRemoteRegistryExtensions(ctx context.Context, query string) ([]*registr
 		return nil, err
 	}
 	xs = FilterRemoteExtensions(xs)
-	for _, x := range xs {
+
+	// Verify the bundles concurrently (up to a limit), because each verification may need to fetch
+	// a bundle.
+	errs := make([]error, len(xs))
+	sem := make(chan struct{}, 10)
+	var wg sync.WaitGroup
+	for i, x := range xs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, x *registry.Extension) {
+			defer func() { <-sem; wg.Done() }()
+			errs[i] = VerifyRemoteExtensionBundle(ctx, x)
+		}(i, x)
+	}
+	wg.Wait()
+
+	keep := xs[:0]
+	for i, x := range xs {
+		if errs[i] != nil {
+			log15.Warn("Omitting remote extension whose bundle failed verification.", "extension", x.ExtensionID, "error", errs[i])
+			continue
+		}
 		x.RegistryURL = registryURL.String()
+		keep = append(keep, x)
 	}
-	return xs, nil
+	return keep, nil
 }
 
 // sleepIfUncachedTransport is used to simulate latency in local dev mode.
@@ -17,6 +17,10 @@ type DiscussionThread struct {
 	ArchivedAt   *time.Time
 	UpdatedAt    time.Time
 	DeletedAt    *time.Time
+
+	ResolvedAt       *time.Time
+	ResolvedByUserID *int32
+	AssigneeUserIDs  []int32
 }
 
 // DiscussionThreadTargetRepo mirrors the underlying discussion_threads_target_repo field types exactly.
@@ -56,3 +60,12 @@ type DiscussionComment struct {
 	DeletedAt    *time.Time
 	Reports      []string
 }
+
+// DiscussionCommentReaction mirrors the underlying discussion_comment_reactions field types exactly.
+// It intentionally does not try to e.g. alleviate null fields.
+type DiscussionCommentReaction struct {
+	CommentID int64
+	UserID    int32
+	Emoji     string
+	CreatedAt time.Time
+}
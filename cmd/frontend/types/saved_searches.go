@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // SavedSearch represents a saved search
 type SavedSearch struct {
 	ID              int32 // the globally unique DB ID
@@ -10,4 +12,21 @@ type SavedSearch struct {
 	UserID          *int32  // if non-nil, the owner is this user. UserID/OrgID are mutually exclusive.
 	OrgID           *int32  // if non-nil, the owner is this organization. UserID/OrgID are mutually exclusive.
 	SlackWebhookURL *string // if non-nil && NotifySlack == true, indicates that this Slack webhook URL should be used instead of the owners default Slack webhook.
+	NotifyWebhook   bool    // whether or not to notify the generic webhook at WebhookURL
+	WebhookURL      *string // the URL that webhook notifications are POSTed to
+	WebhookSecret   *string // if non-nil, webhook payloads are signed with this secret
+}
+
+// SavedSearchWebhookDelivery is an entry in the delivery history of a saved
+// search's webhook notifications.
+type SavedSearchWebhookDelivery struct {
+	ID            int32
+	SavedSearchID int32
+	URL           string
+	Test          bool   // whether this was a test notification
+	ResultCount   int32  // the number of new results in the notification
+	Attempts      int32  // the number of delivery attempts
+	StatusCode    *int32 // the HTTP status code of the last attempt, if any
+	Error         *string
+	CreatedAt     time.Time
 }
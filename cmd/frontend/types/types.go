@@ -50,6 +50,17 @@ type ExternalService struct {
 	DeletedAt   *time.Time
 }
 
+// RepoGroup is a named group of repositories that can be searched with the
+// repogroup: filter.
+type RepoGroup struct {
+	ID          int32
+	Name        string
+	Description string
+	Rule        *api.RepoGroupRule // the rule for dynamic groups, or nil if the members are set explicitly
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
 type GlobalState struct {
 	SiteID      string
 	Initialized bool // whether the initial site admin account has been created
@@ -26,6 +26,9 @@ var (
 	runRepoCleanup, _ = strconv.ParseBool(env.Get("SRC_RUN_REPO_CLEANUP", "", "Periodically remove inactive repositories."))
 	wantFreeG         = env.Get("SRC_REPOS_DESIRED_FREE_GB", "10", "How many gigabytes of space to keep free on the disk with the repos")
 	janitorInterval   = env.Get("SRC_REPOS_JANITOR_INTERVAL", "1m", "Interval between cleanup runs")
+	gitServiceAllow   = env.Get("SRC_GIT_SERVICE_ALLOWLIST", "", "Comma separated list of CIDRs of clients which may clone repositories from gitserver over HTTP")
+	evictionPolicy    = env.Get("SRC_REPOS_EVICTION_POLICY", "lru", "Which repos to remove first when freeing up disk space: lru (least recently used) or weighted (by size and access frequency)")
+	repoSizeCaps      = env.Get("SRC_REPOS_SIZE_CAPS", "", "Space separated list of PATTERN=SIZE. Repos whose name matches the regexp PATTERN and which are larger than SIZE (eg 500M or 2G) are not cloned. The first match applies.")
 )
 
 func main() {
@@ -44,10 +47,25 @@ func main() {
 	if err != nil {
 		log.Fatalf("parsing $SRC_REPOS_DESIRED_FREE_GB: %v", err)
 	}
+	gitServiceAllowlist, err := server.ParseGitServiceAllowlist(gitServiceAllow)
+	if err != nil {
+		log.Fatalf("parsing $SRC_GIT_SERVICE_ALLOWLIST: %v", err)
+	}
+	evictionPolicy2, err := server.EvictionPolicyByName(evictionPolicy)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_EVICTION_POLICY: %v", err)
+	}
+	repoSizeCaps2, err := server.ParseRepoSizeCaps(repoSizeCaps)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_SIZE_CAPS: %v", err)
+	}
 	gitserver := server.Server{
 		ReposDir:                reposDir,
 		DeleteStaleRepositories: runRepoCleanup,
 		DesiredFreeDiskSpace:    uint64(wantFreeG2 * 1024 * 1024 * 1024),
+		GitServiceAllowlist:     gitServiceAllowlist,
+		EvictionPolicy:          evictionPolicy2,
+		RepoSizeCaps:            repoSizeCaps2,
 	}
 	gitserver.RegisterMetrics()
 
@@ -8,7 +8,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -16,8 +15,6 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
-	"github.com/sourcegraph/sourcegraph/pkg/api"
-	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -27,6 +24,7 @@ import (
 func init() {
 	prometheus.MustRegister(reposRemoved)
 	prometheus.MustRegister(reposRecloned)
+	prometheus.MustRegister(reposTooLarge)
 }
 
 const repoTTL = time.Hour * 24 * 45
@@ -43,13 +41,20 @@ var reposRecloned = prometheus.NewCounter(prometheus.CounterOpts{
 	Name:      "repos_recloned",
 	Help:      "number of repos removed and recloned due to age",
 })
+var reposTooLarge = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "gitserver",
+	Name:      "repos_too_large",
+	Help:      "number of repos not cloned or removed because they are larger than their size cap",
+})
 
 // cleanupRepos walks the repos directory and performs maintenance tasks:
 //
 // 1. Remove corrupt repos.
 // 2. Remove stale lock files.
 // 3. Remove inactive repos on sourcegraph.com
-// 4. Reclone repos after a while. (simulate git gc)
+// 4. Track repo sizes and remove repos larger than their size cap.
+// 5. Reclone repos after a while. (simulate git gc)
 func (s *Server) cleanupRepos() {
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
@@ -89,8 +94,7 @@ func (s *Server) cleanupRepos() {
 		ctx, cancel := context.WithTimeout(bCtx, longGitCommandTimeout)
 		defer cancel()
 
-		// name is the relative path to ReposDir, but without the .git suffix.
-		repo := protocol.NormalizeRepo(api.RepoName(strings.TrimPrefix(filepath.Dir(gitDir), s.ReposDir+"/")))
+		repo := s.repoNameFromGitDir(gitDir)
 		log15.Info("recloning expired repo", "repo", repo)
 
 		remoteURL, err := repoRemoteURL(ctx, gitDir)
@@ -105,6 +109,35 @@ func (s *Server) cleanupRepos() {
 		return true, nil
 	}
 
+	updateSize := func(gitDir string) (done bool, err error) {
+		repo := s.repoNameFromGitDir(gitDir)
+		// Sizes are recorded after every clone, so we only need to walk the
+		// repositories whose size we don't know yet or that were fetched
+		// since their size was computed.
+		size, ok := s.repoSizes().get(repo)
+		if !ok || size.TooLarge || size.Stale {
+			n, err := dirSize(gitDir)
+			if err != nil {
+				return false, err
+			}
+			size = repoSize{Size: n}
+			s.repoSizes().set(repo, size)
+		}
+
+		// The repository may have grown past its cap since it was cloned,
+		// or the cap may have been lowered.
+		if maxBytes := s.repoSizeCap(repo); maxBytes > 0 && size.Size > maxBytes {
+			log15.Info("removing repo larger than its size cap", "repo", repo, "size", size.Size, "cap", maxBytes)
+			if err := s.removeRepoDirectory(gitDir); err != nil {
+				return true, err
+			}
+			s.markRepoTooLarge(repo, size.Size)
+			reposRemoved.Inc()
+			return true, nil
+		}
+		return false, nil
+	}
+
 	removeStaleLocks := func(gitDir string) (done bool, err error) {
 		// if removing a lock fails, we still want to try the other locks.
 		var multi error
@@ -154,6 +187,9 @@ func (s *Server) cleanupRepos() {
 		// We always want to have the same git attributes file at
 		// info/attributes.
 		{"ensure git attributes", ensureGitAttributes},
+		// Track the size of repositories for the eviction policy and
+		// enforce size caps.
+		{"update size", updateSize},
 	}
 	// Old git clones accumulate loose git objects that waste space and
 	// slow down git operations. Periodically do a fresh clone to avoid
@@ -192,6 +228,9 @@ func (s *Server) cleanupRepos() {
 	if err != nil {
 		log15.Error("cleanup: error iterating over repositories", "error", err)
 	}
+	if err := s.repoSizes().flush(); err != nil {
+		log15.Error("cleanup: failed to persist repository sizes", "error", err)
+	}
 
 	// Check how much disk space is available.
 	actualFreeBytes, err := s.bytesFreeOnDisk()
@@ -283,43 +322,39 @@ func device(f string) (int64, error) {
 	return int64(stat.Dev), nil
 }
 
-// freeUpSpace removes git directories under ReposDir, in order from least
-// recently to most recently used, until it has freed howManyBytesToFree.
+// freeUpSpace removes git directories under ReposDir, in the order decided
+// by the eviction policy, until it has freed howManyBytesToFree.
 func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	if howManyBytesToFree <= 0 {
 		return nil
 	}
 
-	// Get the git directories and their mod times.
+	// Get the git directories and their usage.
 	gitDirs, err := s.findGitDirs(s.ReposDir)
 	if err != nil {
 		return errors.Wrap(err, "finding git dirs")
 	}
-	dirModTimes := make(map[string]time.Time, len(gitDirs))
+	repos := make([]RepoUsage, 0, len(gitDirs))
 	for _, d := range gitDirs {
-		mt, err := gitDirModTime(d)
+		usage, err := s.repoUsage(d)
 		if err != nil {
-			return errors.Wrap(err, "computing mod time of git dir")
+			return err
 		}
-		dirModTimes[d] = mt
+		repos = append(repos, usage)
 	}
 
-	// Sort the repos from least to most recently used.
-	sort.Slice(gitDirs, func(i, j int) bool {
-		return dirModTimes[gitDirs[i]].Before(dirModTimes[gitDirs[j]])
-	})
+	policy := s.evictionPolicy()
+	policy.Sort(repos, time.Now())
 
 	// Remove repos until howManyBytesToFree is met or exceeded.
 	var spaceFreed int64
-	for _, d := range gitDirs {
-		delta, err := dirSize(d)
-		if err != nil {
-			return errors.Wrapf(err, "computing size of directory %s", d)
-		}
-		if err := s.removeRepoDirectory(d); err != nil {
+	for _, r := range repos {
+		if err := s.removeRepoDirectory(r.GitDir); err != nil {
 			return errors.Wrap(err, "removing repo directory")
 		}
-		spaceFreed += delta
+		spaceFreed += r.Size
+		s.repoSizes().remove(s.repoNameFromGitDir(r.GitDir))
+		s.forgetAccess(filepath.Dir(r.GitDir))
 
 		// Report the new disk usage situation after removing this repo.
 		actualFreeBytes, err := s.bytesFreeOnDisk()
@@ -327,18 +362,24 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 			return errors.Wrap(err, "finding the amount of space free on disk")
 		}
 		G := float64(1024 * 1024 * 1024)
-		log15.Info("cleanup: removed least recently used repo",
-			"repo", d,
-			"how old", time.Since(dirModTimes[d]),
+		log15.Info("cleanup: removed repo to free up space",
+			"repo", r.GitDir,
+			"policy", policy,
+			"how old", time.Since(r.LastAccess),
+			"accesses", r.Accesses,
+			"size in GiB", float64(r.Size)/G,
 			"free space in GiB", float64(actualFreeBytes)/G,
 			"desired free space in GiB", float64(s.DesiredFreeDiskSpace)/G,
 			"space freed in GiB", float64(spaceFreed)/G,
 			"how much space to free in GiB", float64(howManyBytesToFree)/G)
 
 		if spaceFreed >= howManyBytesToFree {
-			return nil
+			break
 		}
 	}
+	if err := s.repoSizes().flush(); err != nil {
+		log15.Error("cleanup: failed to persist repository sizes", "error", err)
+	}
 
 	// Check.
 	if spaceFreed < howManyBytesToFree {
@@ -347,6 +388,33 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	return nil
 }
 
+// repoUsage returns the usage of the repository with the git directory
+// gitDir. The size is taken from the repository size store if it is known.
+func (s *Server) repoUsage(gitDir string) (RepoUsage, error) {
+	mt, err := gitDirModTime(gitDir)
+	if err != nil {
+		return RepoUsage{}, errors.Wrap(err, "computing mod time of git dir")
+	}
+	usage := RepoUsage{GitDir: gitDir, LastAccess: mt}
+
+	if a := s.access(filepath.Dir(gitDir)); a.count > 0 {
+		usage.Accesses = a.count
+		if a.last.After(usage.LastAccess) {
+			usage.LastAccess = a.last
+		}
+	}
+
+	if size, ok := s.repoSizes().get(s.repoNameFromGitDir(gitDir)); ok && !size.TooLarge {
+		usage.Size = size.Size
+	} else {
+		usage.Size, err = dirSize(gitDir)
+		if err != nil {
+			return RepoUsage{}, errors.Wrapf(err, "computing size of directory %s", gitDir)
+		}
+	}
+	return usage, nil
+}
+
 func gitDirModTime(d string) (time.Time, error) {
 	head, err := os.Stat(filepath.Join(d, "HEAD"))
 	if err != nil {
@@ -181,6 +181,8 @@ func TestCleanupOldLocks(t *testing.T) {
 		"github.com/foo/refslock/.git/refs/heads/fresh.lock",
 		"github.com/foo/refslock/.git/refs/heads/stale",
 		"github.com/foo/refslock/.git/info/attributes",
+
+		repoSizesFileName,
 	)
 }
 
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RepoUsage describes how a cloned repository uses gitserver, for deciding
+// which repositories to evict when disk space runs low.
+type RepoUsage struct {
+	// GitDir is the repository's git directory.
+	GitDir string
+
+	// Size is the size of GitDir in bytes.
+	Size int64
+
+	// LastAccess is the most recent time the repository was fetched or
+	// used to serve a request.
+	LastAccess time.Time
+
+	// Accesses is the number of requests the repository served since
+	// gitserver started.
+	Accesses int64
+}
+
+// EvictionPolicy decides in which order repositories are removed when
+// gitserver needs to free up disk space.
+type EvictionPolicy interface {
+	// Sort sorts repos so that the repositories to remove first are at the
+	// start. now is the current time.
+	Sort(repos []RepoUsage, now time.Time)
+}
+
+// LRUEvictionPolicy removes the least recently used repositories first.
+type LRUEvictionPolicy struct{}
+
+func (LRUEvictionPolicy) Sort(repos []RepoUsage, now time.Time) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].LastAccess.Before(repos[j].LastAccess)
+	})
+}
+
+func (LRUEvictionPolicy) String() string { return "lru" }
+
+// WeightedEvictionPolicy removes repositories which take up a lot of space
+// but are rarely used first. A repository's score is its size multiplied
+// by the time since it was last accessed, divided by how often it was
+// accessed. Repositories with the highest score are removed first.
+type WeightedEvictionPolicy struct{}
+
+func (WeightedEvictionPolicy) Sort(repos []RepoUsage, now time.Time) {
+	score := func(r RepoUsage) float64 {
+		idle := now.Sub(r.LastAccess).Hours()
+		if idle < 1 {
+			// Don't let repositories accessed in the last hour all have a
+			// score of 0 regardless of their size.
+			idle = 1
+		}
+		return float64(r.Size) * idle / float64(r.Accesses+1)
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		return score(repos[i]) > score(repos[j])
+	})
+}
+
+func (WeightedEvictionPolicy) String() string { return "weighted" }
+
+// EvictionPolicyByName returns the eviction policy called name ("lru" or
+// "weighted").
+func EvictionPolicyByName(name string) (EvictionPolicy, error) {
+	switch name {
+	case "", "lru":
+		return LRUEvictionPolicy{}, nil
+	case "weighted":
+		return WeightedEvictionPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown eviction policy %q", name)
+	}
+}
+
+// evictionPolicy returns s.EvictionPolicy, defaulting to LRU.
+func (s *Server) evictionPolicy() EvictionPolicy {
+	if s.EvictionPolicy != nil {
+		return s.EvictionPolicy
+	}
+	return LRUEvictionPolicy{}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEvictionPolicy(t *testing.T) {
+	now := time.Now()
+	repos := []RepoUsage{
+		// Large, but used a lot.
+		{GitDir: "large-busy", Size: 100, LastAccess: now.Add(-time.Hour), Accesses: 1000},
+		// Small and idle for a long time.
+		{GitDir: "small-idle", Size: 1, LastAccess: now.Add(-48 * time.Hour)},
+		// Large and idle.
+		{GitDir: "large-idle", Size: 100, LastAccess: now.Add(-24 * time.Hour), Accesses: 1},
+	}
+
+	tests := []struct {
+		policy EvictionPolicy
+		want   []string
+	}{
+		{LRUEvictionPolicy{}, []string{"small-idle", "large-idle", "large-busy"}},
+		{WeightedEvictionPolicy{}, []string{"large-idle", "small-idle", "large-busy"}},
+	}
+	for _, test := range tests {
+		sorted := append([]RepoUsage(nil), repos...)
+		test.policy.Sort(sorted, now)
+		var got []string
+		for _, r := range sorted {
+			got = append(got, r.GitDir)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.policy, got, test.want)
+		}
+	}
+}
+
+func TestEvictionPolicyByName(t *testing.T) {
+	for name, want := range map[string]EvictionPolicy{
+		"":         LRUEvictionPolicy{},
+		"lru":      LRUEvictionPolicy{},
+		"weighted": WeightedEvictionPolicy{},
+	} {
+		got, err := EvictionPolicyByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("EvictionPolicyByName(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := EvictionPolicyByName("random"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/pkg/repotrackutil"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// gitServicePrefix is the path prefix of the git smart HTTP endpoint. A
+// repository can be cloned with
+//
+//	git clone http://gitserver:3178/git/github.com/foo/bar
+const gitServicePrefix = "/git/"
+
+// handleGitService serves the read-only part of the git smart HTTP protocol
+// (git-upload-pack) for cloned repositories. Only clients in
+// GitServiceAllowlist may use it. It never triggers a clone or fetch.
+func (s *Server) handleGitService(w http.ResponseWriter, r *http.Request) {
+	if !s.gitServiceAllowed(r) {
+		http.Error(w, "git service not allowed from "+r.RemoteAddr, http.StatusForbidden)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, gitServicePrefix)
+	var repoPath string
+	var advertiseRefs bool
+	switch {
+	case strings.HasSuffix(rest, "/info/refs"):
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if service := r.URL.Query().Get("service"); service != "git-upload-pack" {
+			// We don't support the dumb protocol or pushing.
+			http.Error(w, fmt.Sprintf("unsupported service %q", service), http.StatusForbidden)
+			return
+		}
+		repoPath = strings.TrimSuffix(rest, "/info/refs")
+		advertiseRefs = true
+
+	case strings.HasSuffix(rest, "/git-upload-pack"):
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		repoPath = strings.TrimSuffix(rest, "/git-upload-pack")
+
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	repo := protocol.NormalizeRepo(api.RepoName(repoPath))
+	if repo == "" || repo == "." || strings.HasPrefix(string(repo), "..") || strings.HasPrefix(string(repo), "/") {
+		http.Error(w, "invalid repository", http.StatusBadRequest)
+		return
+	}
+	dir := path.Join(s.ReposDir, string(repo))
+	if _, cloneInProgress := s.locker.Status(dir); cloneInProgress || !repoCloned(dir) {
+		http.Error(w, "repository not found", http.StatusNotFound)
+		return
+	}
+
+	s.recordAccess(dir)
+	gitServiceRequests.WithLabelValues(repotrackutil.GetTrackedRepo(repo), strings.TrimPrefix(path.Base(r.URL.Path), "git-")).Inc()
+
+	body := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, "malformed gzip body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	args := []string{"upload-pack", "--stateless-rpc"}
+	if advertiseRefs {
+		args = append(args, "--advertise-refs")
+	}
+	args = append(args, ".")
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(r.Context(), "git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = body
+	cmd.Stderr = &stderr
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if advertiseRefs {
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, packetLine("# service=git-upload-pack\n"))
+		_, _ = io.WriteString(w, "0000")
+	} else {
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+		w.WriteHeader(http.StatusOK)
+	}
+
+	// Stream the pack to the client as it is generated.
+	if fw := newFlushingResponseWriter(w); fw != nil {
+		cmd.Stdout = fw
+		defer fw.Close()
+	} else {
+		cmd.Stdout = w
+	}
+
+	if _, err := runCommand(r.Context(), cmd); err != nil {
+		// The response has already started, so all we can do is log.
+		gitServiceErrors.Inc()
+		log15.Error("git upload-pack failed", "repo", repo, "error", err, "stderr", stderr.String())
+	}
+}
+
+// gitServiceAllowed returns true if the client of r is in
+// GitServiceAllowlist.
+func (s *Server) gitServiceAllowed(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range s.GitServiceAllowlist {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGitServiceAllowlist parses a comma separated list of CIDRs (eg
+// "10.0.0.0/8,192.168.1.1/32") for Server.GitServiceAllowlist. Plain IP
+// addresses are treated as networks containing only that address.
+func ParseGitServiceAllowlist(s string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			ip := net.ParseIP(field)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", field)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(field)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// packetLine encodes s as a git pkt-line.
+func packetLine(s string) string {
+	return fmt.Sprintf("%04x%s", len(s)+4, s)
+}
+
+var (
+	gitServiceRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "git_service_requests",
+		Help:      "Number of git smart HTTP requests served.",
+	}, []string{"repo", "service"})
+	gitServiceErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "gitserver",
+		Name:      "git_service_errors",
+		Help:      "Number of git smart HTTP requests which failed.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(gitServiceRequests)
+	prometheus.MustRegister(gitServiceErrors)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGitService(t *testing.T) {
+	reposDir, cleanup1 := tmpDir(t)
+	defer cleanup1()
+	clones, cleanup2 := tmpDir(t)
+	defer cleanup2()
+
+	cmd := func(dir, name string, arg ...string) string {
+		t.Helper()
+		c := exec.Command(name, arg...)
+		c.Dir = dir
+		c.Env = append(os.Environ(),
+			"GIT_COMMITTER_NAME=a",
+			"GIT_COMMITTER_EMAIL=a@a.com",
+			"GIT_AUTHOR_NAME=a",
+			"GIT_AUTHOR_EMAIL=a@a.com",
+		)
+		b, err := c.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s %s failed: %s\n%s", name, strings.Join(arg, " "), err, b)
+		}
+		return string(b)
+	}
+
+	// Setup a repo in the same layout as a clone made by gitserver.
+	repoDir := filepath.Join(reposDir, "example.com/foo/bar")
+	if err := os.MkdirAll(repoDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	cmd(repoDir, "git", "init", ".")
+	cmd(repoDir, "sh", "-c", "echo hello world > hello.txt")
+	cmd(repoDir, "git", "add", "hello.txt")
+	cmd(repoDir, "git", "commit", "-m", "hello")
+	wantCommit := cmd(repoDir, "git", "rev-parse", "HEAD")
+
+	_, localhost, _ := net.ParseCIDR("127.0.0.0/8")
+	s := &Server{
+		ReposDir:            reposDir,
+		locker:              &RepositoryLocker{},
+		GitServiceAllowlist: []*net.IPNet{localhost},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(s.handleGitService))
+	defer srv.Close()
+
+	cmd(clones, "git", "clone", srv.URL+"/git/example.com/foo/bar", "bar")
+	if gotCommit := cmd(filepath.Join(clones, "bar"), "git", "rev-parse", "HEAD"); gotCommit != wantCommit {
+		t.Errorf("got commit %q, want %q", gotCommit, wantCommit)
+	}
+
+	get := func(path string) int {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got, want := get("/git/example.com/foo/missing/info/refs?service=git-upload-pack"), http.StatusNotFound; got != want {
+		t.Errorf("missing repo: got status %d, want %d", got, want)
+	}
+	if got, want := get("/git/example.com/foo/bar/info/refs?service=git-receive-pack"), http.StatusForbidden; got != want {
+		t.Errorf("receive-pack: got status %d, want %d", got, want)
+	}
+	if got, want := get("/git/../foo/info/refs?service=git-upload-pack"), http.StatusBadRequest; got != want {
+		t.Errorf("path traversal: got status %d, want %d", got, want)
+	}
+
+	s.GitServiceAllowlist = nil
+	if got, want := get("/git/example.com/foo/bar/info/refs?service=git-upload-pack"), http.StatusForbidden; got != want {
+		t.Errorf("not in allowlist: got status %d, want %d", got, want)
+	}
+}
+
+func TestParseGitServiceAllowlist(t *testing.T) {
+	got, err := ParseGitServiceAllowlist(" 10.0.0.0/8, 192.168.1.1,::1 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotStrings []string
+	for _, n := range got {
+		gotStrings = append(gotStrings, n.String())
+	}
+	if want := []string{"10.0.0.0/8", "192.168.1.1/32", "::1/128"}; !reflect.DeepEqual(gotStrings, want) {
+		t.Errorf("got %v, want %v", gotStrings, want)
+	}
+
+	if got, err := ParseGitServiceAllowlist(""); err != nil || len(got) != 0 {
+		t.Errorf("got %v, %v for empty allowlist", got, err)
+	}
+
+	if _, err := ParseGitServiceAllowlist("10.0.0.0/8,nope"); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
@@ -20,6 +20,7 @@ func (s *Server) repoInfo(ctx context.Context, repo api.RepoName) (*protocol.Rep
 	resp := protocol.RepoInfo{
 		Cloned: repoCloned(dir),
 	}
+	resp.TooLarge = !resp.Cloned && s.checkRepoTooLarge(repo) != nil
 	if resp.Cloned {
 		remoteURL, err := repoRemoteURL(ctx, dir)
 		if err != nil {
@@ -167,5 +168,14 @@ func (s *Server) deleteRepo(repo api.RepoName) error {
 		}
 	}
 
-	return s.removeRepoDirectory(dir)
+	if err := s.removeRepoDirectory(dir); err != nil {
+		return err
+	}
+
+	sizes := s.repoSizes()
+	sizes.remove(repo)
+	if err := sizes.flush(); err != nil {
+		log15.Error("failed to persist repository sizes", "error", err)
+	}
+	return nil
 }
@@ -0,0 +1,302 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// repoSizesFileName is the name of the file in ReposDir in which the sizes
+// of repositories are persisted. The name starts with tempDirName so that
+// repository listing operations ignore it, but it is not in the temporary
+// directory so it survives restarts.
+const repoSizesFileName = tempDirName + "-repo-sizes.json"
+
+// RepoSizeCap limits the size of the repositories whose names match
+// Pattern.
+type RepoSizeCap struct {
+	Pattern  *regexp.Regexp
+	MaxBytes int64
+}
+
+// ParseRepoSizeCaps parses a whitespace separated list of size caps of the
+// form PATTERN=SIZE, eg "^github.com/torvalds/linux$=5G .*=1G". PATTERN is a
+// regular expression matched against the repository name and SIZE is a
+// number of bytes with an optional K, M or G suffix.
+func ParseRepoSizeCaps(s string) ([]RepoSizeCap, error) {
+	var caps []RepoSizeCap
+	for _, field := range strings.Fields(s) {
+		i := strings.LastIndexByte(field, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid repository size cap %q: expected PATTERN=SIZE", field)
+		}
+		pattern, err := regexp.Compile(field[:i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid repository size cap %q", field)
+		}
+		maxBytes, err := parseByteSize(field[i+1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid repository size cap %q", field)
+		}
+		caps = append(caps, RepoSizeCap{Pattern: pattern, MaxBytes: maxBytes})
+	}
+	return caps, nil
+}
+
+func parseByteSize(s string) (int64, error) {
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1024
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(s, "G"):
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier != 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("size must be positive")
+	}
+	return n * multiplier, nil
+}
+
+// repoSizeCap returns the maximum size in bytes of repo, or 0 if it is
+// unlimited. The first matching cap in RepoSizeCaps applies.
+func (s *Server) repoSizeCap(repo api.RepoName) int64 {
+	for _, c := range s.RepoSizeCaps {
+		if c.Pattern.MatchString(string(repo)) {
+			return c.MaxBytes
+		}
+	}
+	return 0
+}
+
+// repoTooLargeError is returned when a repository is not cloned because it
+// is larger than its size cap.
+type repoTooLargeError struct {
+	repo     api.RepoName
+	size     int64
+	maxBytes int64
+}
+
+func (e *repoTooLargeError) Error() string {
+	return fmt.Sprintf("repository %s not cloned: too large (%d bytes, limit is %d bytes)", e.repo, e.size, e.maxBytes)
+}
+
+func isRepoTooLarge(err error) bool {
+	_, ok := errors.Cause(err).(*repoTooLargeError)
+	return ok
+}
+
+// checkRepoTooLarge returns a repoTooLargeError if repo previously
+// exceeded its current size cap.
+func (s *Server) checkRepoTooLarge(repo api.RepoName) error {
+	maxBytes := s.repoSizeCap(repo)
+	if maxBytes == 0 {
+		return nil
+	}
+	if size, ok := s.repoSizes().get(repo); ok && size.TooLarge && size.Size > maxBytes {
+		return &repoTooLargeError{repo: repo, size: size.Size, maxBytes: maxBytes}
+	}
+	return nil
+}
+
+// markRepoTooLarge records that repo was not cloned because it is size
+// bytes large.
+func (s *Server) markRepoTooLarge(repo api.RepoName, size int64) {
+	sizes := s.repoSizes()
+	sizes.set(repo, repoSize{Size: size, TooLarge: true})
+	if err := sizes.flush(); err != nil {
+		log15.Error("failed to persist repository sizes", "error", err)
+	}
+	reposTooLarge.Inc()
+}
+
+// watchCloneSize calls cancel if the size of dir exceeds maxBytes. It
+// returns when done is closed.
+func watchCloneSize(dir string, maxBytes int64, cancel func(size int64), done <-chan struct{}) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if size, err := dirSize(dir); err == nil && size > maxBytes {
+				cancel(size)
+				return
+			}
+		}
+	}
+}
+
+// repoSize is the persisted size information of a repository.
+type repoSize struct {
+	// Size is the size of the repository's git directory in bytes.
+	Size int64 `json:"size"`
+
+	// TooLarge is true if the repository is not cloned because Size is
+	// larger than its size cap.
+	TooLarge bool `json:"tooLarge,omitempty"`
+
+	// Stale is true if the repository was fetched since Size was
+	// computed. The janitor recomputes stale sizes.
+	Stale bool `json:"stale,omitempty"`
+}
+
+// repoSizeStore tracks the sizes of repositories. Changes are persisted to
+// path by flush.
+type repoSizeStore struct {
+	path string
+
+	mu    sync.Mutex
+	sizes map[api.RepoName]repoSize
+	dirty bool
+}
+
+// repoSizes returns the repository size store of s, loading it from disk
+// on first use.
+func (s *Server) repoSizes() *repoSizeStore {
+	s.repoSizesOnce.Do(func() {
+		s.repoSizeStore = &repoSizeStore{
+			path:  filepath.Join(s.ReposDir, repoSizesFileName),
+			sizes: map[api.RepoName]repoSize{},
+		}
+		if err := s.repoSizeStore.load(); err != nil {
+			log15.Warn("failed to load repository sizes, they will be recomputed", "error", err)
+		}
+	})
+	return s.repoSizeStore
+}
+
+func (r *repoSizeStore) load() error {
+	b, err := ioutil.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &r.sizes)
+}
+
+func (r *repoSizeStore) get(repo api.RepoName) (repoSize, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	size, ok := r.sizes[protocol.NormalizeRepo(repo)]
+	return size, ok
+}
+
+func (r *repoSizeStore) set(repo api.RepoName, size repoSize) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sizes[protocol.NormalizeRepo(repo)] = size
+	r.dirty = true
+}
+
+// markStale records that the size of repo changed since it was computed, if
+// it is known.
+func (r *repoSizeStore) markStale(repo api.RepoName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo = protocol.NormalizeRepo(repo)
+	if size, ok := r.sizes[repo]; ok && !size.TooLarge && !size.Stale {
+		size.Stale = true
+		r.sizes[repo] = size
+		r.dirty = true
+	}
+}
+
+func (r *repoSizeStore) remove(repo api.RepoName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo = protocol.NormalizeRepo(repo)
+	if _, ok := r.sizes[repo]; ok {
+		delete(r.sizes, repo)
+		r.dirty = true
+	}
+}
+
+// flush atomically writes the sizes to disk if they changed since the last
+// flush.
+func (r *repoSizeStore) flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.dirty {
+		return nil
+	}
+
+	b, err := json.Marshal(r.sizes)
+	if err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// repoAccess is how often and when a repository was last accessed.
+type repoAccess struct {
+	count int64
+	last  time.Time
+}
+
+// recordAccess records that the repository in dir was used to serve a
+// request.
+func (s *Server) recordAccess(dir string) {
+	s.accessMu.Lock()
+	defer s.accessMu.Unlock()
+	if s.accesses == nil {
+		s.accesses = map[string]repoAccess{}
+	}
+	a := s.accesses[dir]
+	a.count++
+	a.last = time.Now()
+	s.accesses[dir] = a
+}
+
+// access returns how often and when the repository in dir was accessed
+// since gitserver started.
+func (s *Server) access(dir string) repoAccess {
+	s.accessMu.Lock()
+	defer s.accessMu.Unlock()
+	return s.accesses[dir]
+}
+
+// forgetAccess removes the access statistics of the repository in dir.
+func (s *Server) forgetAccess(dir string) {
+	s.accessMu.Lock()
+	defer s.accessMu.Unlock()
+	delete(s.accesses, dir)
+}
+
+// repoNameFromGitDir returns the repository name of the git directory
+// gitDir in ReposDir.
+func (s *Server) repoNameFromGitDir(gitDir string) api.RepoName {
+	// name is the relative path to ReposDir, but without the .git suffix.
+	return protocol.NormalizeRepo(api.RepoName(strings.TrimPrefix(filepath.Dir(gitDir), s.ReposDir+"/")))
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestParseRepoSizeCaps(t *testing.T) {
+	caps, err := ParseRepoSizeCaps(" ^github.com/foo/big$=2G  github.com/foo/=500M\n.*=1024 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{RepoSizeCaps: caps}
+	for repo, want := range map[api.RepoName]int64{
+		"github.com/foo/big":   2 * 1024 * 1024 * 1024,
+		"github.com/foo/small": 500 * 1024 * 1024,
+		"gitlab.com/bar/baz":   1024,
+	} {
+		if got := s.repoSizeCap(repo); got != want {
+			t.Errorf("cap of %s is %d, want %d", repo, got, want)
+		}
+	}
+
+	if got := (&Server{}).repoSizeCap("github.com/foo/big"); got != 0 {
+		t.Errorf("got cap %d without caps, want 0", got)
+	}
+
+	for _, invalid := range []string{"nosize", "a=", "a=-1", "a=1T", "(=1G"} {
+		if _, err := ParseRepoSizeCaps(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestRepoSizeStore(t *testing.T) {
+	root, cleanup := tmpDir(t)
+	defer cleanup()
+
+	s := &Server{ReposDir: root}
+	s.repoSizes().set("github.com/foo/a", repoSize{Size: 10})
+	s.repoSizes().set("github.com/foo/b", repoSize{Size: 20, TooLarge: true})
+	s.repoSizes().remove("github.com/foo/a")
+	if err := s.repoSizes().flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new server should load the persisted sizes.
+	s2 := &Server{ReposDir: root}
+	if _, ok := s2.repoSizes().get("github.com/foo/a"); ok {
+		t.Error("expected removed repo to not be persisted")
+	}
+	if got, ok := s2.repoSizes().get("github.com/foo/b"); !ok || got != (repoSize{Size: 20, TooLarge: true}) {
+		t.Errorf("got %+v, want too large repo of size 20", got)
+	}
+
+	// The too large marker only applies while the repo exceeds its cap.
+	s2.RepoSizeCaps = []RepoSizeCap{{Pattern: regexp.MustCompile("foo/b"), MaxBytes: 10}}
+	if err := s2.checkRepoTooLarge("github.com/foo/b"); !isRepoTooLarge(err) {
+		t.Errorf("got %v, want too large error", err)
+	}
+	s2.RepoSizeCaps[0].MaxBytes = 100
+	if err := s2.checkRepoTooLarge("github.com/foo/b"); err != nil {
+		t.Errorf("got %v after raising the cap, want nil", err)
+	}
+}
+
+func TestCleanupTooLarge(t *testing.T) {
+	root, cleanup := tmpDir(t)
+	defer cleanup()
+
+	if err := makeFakeRepo(filepath.Join(root, "github.com/foo/big"), 5000); err != nil {
+		t.Fatal(err)
+	}
+	if err := makeFakeRepo(filepath.Join(root, "github.com/foo/small"), 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := makeFakeRepo(filepath.Join(root, "github.com/foo/recorded"), 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := makeFakeRepo(filepath.Join(root, "github.com/foo/fetched"), 5000); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ReposDir:     root,
+		RepoSizeCaps: []RepoSizeCap{{Pattern: regexp.MustCompile(".*"), MaxBytes: 1000}},
+	}
+	s.Handler() // Handler as a side-effect sets up Server
+	// The janitor uses recorded sizes instead of computing them.
+	s.repoSizes().set("github.com/foo/recorded", repoSize{Size: 5000})
+	// Sizes of repositories that were fetched since they were recorded are
+	// recomputed.
+	s.repoSizes().set("github.com/foo/fetched", repoSize{Size: 10})
+	s.repoSizes().markStale("github.com/foo/fetched")
+	s.cleanupRepos()
+
+	if _, err := os.Stat(filepath.Join(root, "github.com/foo/big/.git")); !os.IsNotExist(err) {
+		t.Error("expected repo larger than its cap to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "github.com/foo/small/.git")); err != nil {
+		t.Errorf("expected small repo to remain: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "github.com/foo/recorded/.git")); !os.IsNotExist(err) {
+		t.Error("expected repo with a recorded size larger than its cap to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "github.com/foo/fetched/.git")); !os.IsNotExist(err) {
+		t.Error("expected fetched repo larger than its cap to be removed")
+	}
+	if err := s.checkRepoTooLarge("github.com/foo/big"); !isRepoTooLarge(err) {
+		t.Errorf("got %v, want too large error", err)
+	}
+	if size, ok := s.repoSizes().get("github.com/foo/small"); !ok || size.Size < 10 {
+		t.Errorf("got size %+v for small repo, want it to be tracked", size)
+	}
+
+	info, err := s.repoInfo(context.Background(), "github.com/foo/big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.TooLarge {
+		t.Error("expected repo info to report the repo as too large")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -114,6 +115,20 @@ type Server struct {
 	// DesiredFreeDiskSpace is how much space we need to keep free in bytes.
 	DesiredFreeDiskSpace uint64
 
+	// GitServiceAllowlist are the networks whose clients may clone and
+	// fetch repositories using the git smart HTTP protocol under /git/. If
+	// it is empty, no client may.
+	GitServiceAllowlist []*net.IPNet
+
+	// EvictionPolicy decides which repositories are removed first when
+	// disk space runs low. It defaults to LRUEvictionPolicy.
+	EvictionPolicy EvictionPolicy
+
+	// RepoSizeCaps limit the size of repositories. The first cap whose
+	// pattern matches a repository's name applies. Repositories larger than
+	// their cap are not cloned.
+	RepoSizeCaps []RepoSizeCap
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
@@ -136,6 +151,14 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// repoSizeStore persists the sizes of repositories. Use s.repoSizes()
+	// instead of using it directly.
+	repoSizesOnce sync.Once
+	repoSizeStore *repoSizeStore
+
+	accessMu sync.Mutex            // protects accesses
+	accesses map[string]repoAccess // repo dir -> access statistics
 }
 
 type locks struct {
@@ -232,6 +255,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	mux.HandleFunc(gitServicePrefix, s.handleGitService)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -560,6 +584,12 @@ func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !repoCloned(dir) {
+		if s.checkRepoTooLarge(req.Repo) != nil {
+			status = "repo-too-large"
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{TooLarge: true})
+			return
+		}
 		if req.URL == "" {
 			status = "repo-not-found"
 			w.WriteHeader(http.StatusNotFound)
@@ -571,7 +601,7 @@ func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
 			log15.Debug("error cloning repo", "repo", req.Repo, "err", err)
 			status = "repo-not-found"
 			w.WriteHeader(http.StatusNotFound)
-			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false})
+			_ = json.NewEncoder(w).Encode(&protocol.NotFoundPayload{CloneInProgress: false, TooLarge: isRepoTooLarge(err)})
 			return
 		}
 		status = "clone-in-progress"
@@ -589,6 +619,7 @@ func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
 	} else {
 		ensureRevisionStatus = "noop"
 	}
+	s.recordAccess(dir)
 
 	w.Header().Set("Trailer", "X-Exec-Error")
 	w.Header().Add("Trailer", "X-Exec-Exit-Status")
@@ -680,6 +711,11 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		return progress, nil
 	}
 
+	// Don't repeatedly clone repositories we know are too large.
+	if err := s.checkRepoTooLarge(repo); err != nil {
+		return "", err
+	}
+
 	// isCloneable causes a network request, so we limit the number that can
 	// run at one time. We use a separate semaphore to cloning since these
 	// checks being blocked by a few slow clones will lead to poor feedback to
@@ -744,6 +780,21 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		defer os.RemoveAll(tmpPath)
 		tmpPath = filepath.Join(tmpPath, ".git")
 
+		// Abort the clone as soon as it exceeds the repository's size cap.
+		maxBytes := s.repoSizeCap(repo)
+		var tooLargeSize int64 // set by watchCloneSize, use atomic
+		if maxBytes > 0 {
+			var cancel3 context.CancelFunc
+			ctx, cancel3 = context.WithCancel(ctx)
+			defer cancel3()
+			done := make(chan struct{})
+			defer close(done)
+			go watchCloneSize(tmpPath, maxBytes, func(size int64) {
+				atomic.StoreInt64(&tooLargeSize, size)
+				cancel3()
+			}, done)
+		}
+
 		cmd := exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
 		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
 
@@ -752,9 +803,22 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		go readCloneProgress(repo, url, lock, pr)
 
 		if output, err := s.runWithRemoteOpts(ctx, cmd, pw); err != nil {
+			if size := atomic.LoadInt64(&tooLargeSize); size > 0 {
+				s.markRepoTooLarge(repo, size)
+				return &repoTooLargeError{repo: repo, size: size, maxBytes: maxBytes}
+			}
 			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
 		}
 
+		size, err := dirSize(tmpPath)
+		if err != nil {
+			return err
+		}
+		if maxBytes > 0 && size > maxBytes {
+			s.markRepoTooLarge(repo, size)
+			return &repoTooLargeError{repo: repo, size: size, maxBytes: maxBytes}
+		}
+
 		// Update the last-changed stamp.
 		if err := setLastChanged(tmpPath); err != nil {
 			return errors.Wrapf(err, "failed to update last changed time")
@@ -783,6 +847,9 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		log15.Info("repo cloned", "repo", repo)
 		repoClonedCounter.Inc()
 
+		// The sizes are persisted by the next janitor run.
+		s.repoSizes().set(repo, repoSize{Size: size})
+
 		return nil
 	}
 
@@ -1209,6 +1276,9 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
 	}
 
+	// The size of the repository changed. The janitor recomputes it.
+	s.repoSizes().markStale(repo)
+
 	headBranch := "master"
 
 	// try to fetch HEAD from origin
@@ -0,0 +1,190 @@
+package shared
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/pkg/db/confdb"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+type jsonConfigurationVersion struct {
+	ID           string
+	Contents     string
+	AuthorUserID *int32
+	CreatedAt    time.Time
+}
+
+// serveHistory lists the past versions of the critical configuration, newest
+// first. The "first" and "offset" query parameters page through the list.
+func serveHistory(w http.ResponseWriter, r *http.Request) {
+	logger := log15.New("route", "history")
+
+	var opt confdb.ListOptions
+	if first := r.URL.Query().Get("first"); first != "" {
+		limit, err := strconv.Atoi(first)
+		if err != nil {
+			httpError(w, errors.Wrap(err, "Unexpected error when decoding first argument").Error(), "bad_request")
+			return
+		}
+		opt.Limit = limit
+	}
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			httpError(w, "Unexpected error when decoding offset argument", "bad_request")
+			return
+		}
+		opt.Offset = n
+	}
+
+	versions, err := confdb.CriticalListVersions(r.Context(), opt)
+	if err != nil {
+		logger.Error("confdb.CriticalListVersions failed", "error", err)
+		httpError(w, "Error retrieving critical configuration history.", "internal_error")
+		return
+	}
+
+	history := make([]jsonConfigurationVersion, len(versions))
+	for i, v := range versions {
+		history[i] = jsonConfigurationVersion{
+			ID:           strconv.Itoa(int(v.ID)),
+			Contents:     v.Contents,
+			AuthorUserID: v.AuthorUserID,
+			CreatedAt:    v.CreatedAt,
+		}
+	}
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		logger.Error("json response encoding failed", "error", err)
+		httpError(w, errors.Wrap(err, "Error encoding JSON response").Error(), "internal_error")
+	}
+}
+
+// serveDiff returns the fields whose values differ between two versions of
+// the critical configuration.
+func serveDiff(w http.ResponseWriter, r *http.Request) {
+	logger := log15.New("route", "diff")
+
+	from, ok := getCriticalVersion(w, r, logger, r.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := getCriticalVersion(w, r, logger, r.URL.Query().Get("to"))
+	if !ok {
+		return
+	}
+
+	site, err := confdb.SiteGetLatest(r.Context())
+	if err != nil {
+		logger.Error("confdb.SiteGetLatest failed", "error", err)
+		httpError(w, "Error retrieving latest site configuration.", "internal_error")
+		return
+	}
+	diffs, err := conf.DiffRaw(
+		conftypes.RawUnified{Critical: from.Contents, Site: site.Contents},
+		conftypes.RawUnified{Critical: to.Contents, Site: site.Contents},
+	)
+	if err != nil {
+		httpError(w, errors.Wrap(err, "Error parsing critical configuration").Error(), "bad_request")
+		return
+	}
+	if diffs == nil {
+		diffs = []conf.FieldDiff{}
+	}
+	if err := json.NewEncoder(w).Encode(diffs); err != nil {
+		logger.Error("json response encoding failed", "error", err)
+		httpError(w, errors.Wrap(err, "Error encoding JSON response").Error(), "internal_error")
+	}
+}
+
+// serveRestore saves the contents of a past version of the critical
+// configuration as the new critical configuration, if they are valid.
+func serveRestore(w http.ResponseWriter, r *http.Request) {
+	logger := log15.New("route", "restore")
+
+	var args struct {
+		ID     string
+		LastID string
+	}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		logger.Error("json argument decoding failed", "error", err)
+		httpError(w, errors.Wrap(err, "Unexpected error when decoding arguments").Error(), "bad_request")
+		return
+	}
+
+	lastID, err := strconv.Atoi(args.LastID)
+	if err != nil {
+		logger.Error("argument LastID decoding failed", "error", err)
+		httpError(w, errors.Wrap(err, "Unexpected error when decoding LastID argument").Error(), "bad_request")
+		return
+	}
+	lastIDInt32 := int32(lastID)
+
+	version, ok := getCriticalVersion(w, r, logger, args.ID)
+	if !ok {
+		return
+	}
+
+	site, err := confdb.SiteGetLatest(r.Context())
+	if err != nil {
+		logger.Error("confdb.SiteGetLatest failed", "error", err)
+		httpError(w, "Error retrieving latest site configuration.", "internal_error")
+		return
+	}
+	problems, err := conf.Validate(conftypes.RawUnified{Critical: version.Contents, Site: site.Contents})
+	if err != nil {
+		httpError(w, errors.Wrap(err, "Error validating critical configuration").Error(), "bad_request")
+		return
+	}
+	if len(problems) > 0 {
+		httpError(w, "Unable to restore invalid critical configuration: "+strings.Join(problems, "; "), "invalid_configuration")
+		return
+	}
+
+	critical, err := confdb.CriticalCreateIfUpToDate(r.Context(), &lastIDInt32, version.Contents)
+	if err != nil {
+		if err == confdb.ErrNewerEdit {
+			httpError(w, confdb.ErrNewerEdit.Error(), "newer_edit")
+			return
+		}
+		logger.Error("confdb.CriticalCreateIfUpToDate failed", "error", err)
+		httpError(w, errors.Wrap(err, "Error updating latest critical configuration").Error(), "internal_error")
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(&jsonConfiguration{
+		ID:       strconv.Itoa(int(critical.ID)),
+		Contents: critical.Contents,
+	})
+	if err != nil {
+		logger.Error("json response encoding failed", "error", err)
+		httpError(w, errors.Wrap(err, "Error encoding JSON response").Error(), "internal_error")
+	}
+}
+
+// getCriticalVersion returns the version of the critical configuration with
+// the given ID. If it fails, it writes the error response and returns false.
+func getCriticalVersion(w http.ResponseWriter, r *http.Request, logger log15.Logger, idStr string) (*confdb.CriticalConfig, bool) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		httpError(w, errors.Wrap(err, "Unexpected error when decoding ID argument").Error(), "bad_request")
+		return nil, false
+	}
+	critical, err := confdb.CriticalGetByID(r.Context(), int32(id))
+	if err == confdb.ErrVersionNotFound {
+		httpError(w, err.Error(), "not_found")
+		return nil, false
+	} else if err != nil {
+		logger.Error("confdb.CriticalGetByID failed", "error", err)
+		httpError(w, "Error retrieving critical configuration version.", "internal_error")
+		return nil, false
+	}
+	return critical, true
+}
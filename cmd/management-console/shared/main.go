@@ -124,6 +124,9 @@ func Main() {
 	protectedRoutes := http.NewServeMux()
 	protectedRoutes.HandleFunc("/api/get", serveGet)
 	protectedRoutes.HandleFunc("/api/update", serveUpdate)
+	protectedRoutes.HandleFunc("/api/history", serveHistory)
+	protectedRoutes.HandleFunc("/api/diff", serveDiff)
+	protectedRoutes.HandleFunc("/api/restore", serveRestore)
 
 	// Static assets are excluded from the authentication middleware because
 	// they are the same for all Sourcegraph users AND because the
@@ -29,6 +29,14 @@ func (n *notifier) emailNotify(ctx context.Context) {
 		return
 	}
 
+	matches := make([]emailMatch, 0, maxNotificationMatches)
+	for i, match := range n.newMatches {
+		if i == maxNotificationMatches {
+			break
+		}
+		matches = append(matches, emailMatch{Summary: matchSummary(match), URL: matchURL(match, utmSourceEmail)})
+	}
+
 	// Send tx emails asynchronously.
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -44,23 +52,27 @@ func (n *notifier) emailNotify(ctx context.Context) {
 			}
 
 			plural := ""
-			if n.results.Data.Search.Results.ApproximateResultCount != "1" {
+			if len(n.newMatches) != 1 {
 				plural = "s"
 			}
 			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, struct {
-				URL                    string
-				Description            string
-				Query                  string
-				ApproximateResultCount string
-				Ownership              string
-				PluralResults          string
+				URL             string
+				Description     string
+				Query           string
+				ResultCount     int
+				Matches         []emailMatch
+				MoreResultCount int
+				Ownership       string
+				PluralResults   string
 			}{
-				URL:                    searchURL(n.newQuery, utmSourceEmail),
-				Description:            n.query.Description,
-				Query:                  n.query.Query,
-				ApproximateResultCount: n.results.Data.Search.Results.ApproximateResultCount,
-				Ownership:              ownership,
-				PluralResults:          plural,
+				URL:             searchURL(n.newQuery, utmSourceEmail),
+				Description:     n.query.Description,
+				Query:           n.query.Query,
+				ResultCount:     len(n.newMatches),
+				Matches:         matches,
+				MoreResultCount: len(n.newMatches) - len(matches),
+				Ownership:       ownership,
+				PluralResults:   plural,
 			}); err != nil {
 				log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
 			}
@@ -68,21 +80,37 @@ func (n *notifier) emailNotify(ctx context.Context) {
 	}()
 }
 
+// emailMatch is a new match listed in a new search results email.
+type emailMatch struct {
+	Summary string
+	URL     string
+}
+
 var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
-	Subject: `[{{.ApproximateResultCount}} new result{{.PluralResults}}] {{.Description}}`,
+	Subject: `[{{.ResultCount}} new result{{.PluralResults}}] {{.Description}}`,
 	Text: `
-{{.ApproximateResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+{{.ResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
   "{{.Description}}"
-
-View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
+{{range .Matches}}
+  - {{.Summary}}
+    {{.URL}}
+{{end}}{{if .MoreResultCount}}
+  ...and {{.MoreResultCount}} more
+{{end}}
+View the search on Sourcegraph: {{.URL}}
 `,
 	HTML: `
-<strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+<strong>{{.ResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
 
-<p><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
+<ul>
+{{range .Matches}}<li><a href="{{.URL}}"><code>{{.Summary}}</code></a></li>
+{{end}}{{if .MoreResultCount}}<li>...and {{.MoreResultCount}} more</li>
+{{end}}</ul>
+
+<p><a href="{{.URL}}">View the search on Sourcegraph</a></p>
 `,
 })
 
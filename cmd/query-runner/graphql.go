@@ -81,6 +81,7 @@ const gqlSearchQuery = `query Search(
 						}
 						oid
 						abbreviatedOID
+						url
 						author {
 							person {
 								displayName
@@ -91,6 +92,10 @@ const gqlSearchQuery = `query Search(
 						message
 					}
 				}
+				... on Repository {
+					name
+					url
+				}
 			}
 			alert {
 				title
@@ -113,9 +118,13 @@ type gqlSearchResponse struct {
 		Search struct {
 			Results struct {
 				ApproximateResultCount string
+				LimitHit               bool
 				Cloning                []*api.Repo
 				Timedout               []*api.Repo
 				Results                []interface{}
+				Alert                  *struct {
+					Title string
+				}
 			}
 		}
 	}
@@ -55,6 +55,8 @@ func main() {
 
 	http.HandleFunc(queryrunnerapi.PathTestNotification, serveTestNotification)
 
+	go runWebhookQueue(ctx)
+
 	go func() {
 		err := executor.run(ctx)
 		if err != nil {
@@ -140,16 +142,10 @@ func (e *executorT) run(ctx context.Context) error {
 // runQuery runs the given query if an appropriate amount of time has elapsed
 // since it last ran.
 func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery) error {
-	if !query.Notify && !query.NotifySlack {
+	if !query.Notify && !query.NotifySlack && !query.NotifyWebhook {
 		// No need to run this query because there will be nobody to notify.
 		return nil
 	}
-	if !strings.Contains(query.Query, "type:diff") && !strings.Contains(query.Query, "type:commit") {
-		// TODO(slimsag): we temporarily do not support non-commit search
-		// queries, since those do not support the after:"time" operator.
-		return nil
-	}
-
 	info, err := api.InternalClient.SavedQueriesGetInfo(ctx, query.Query)
 	if err != nil {
 		return errors.Wrap(err, "SavedQueriesGetInfo")
@@ -177,21 +173,36 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		}
 	}
 
-	// Construct a new query which finds search results introduced after the
-	// last time we queried.
-	var latestKnownResult time.Time
+	// Commit searches are restricted to commits introduced after the last
+	// time we queried. Other searches are run as-is, and their matches are
+	// compared to the previous run's matches to determine which are new.
+	newQuery := query.Query
+	commitSearch := isCommitSearch(query.Query)
+	if commitSearch {
+		var latestKnownResult time.Time
+		if info != nil {
+			latestKnownResult = info.LatestResult
+		} else {
+			// We've never executed this search query before, so use the current
+			// time. We'll most certainly find nothing, which is okay.
+			latestKnownResult = time.Now()
+		}
+		afterTime := latestKnownResult.UTC().Format(time.RFC3339)
+		newQuery = strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
+	} else if !strings.Contains(query.Query, "count:") {
+		// Raise the default result limit so that matches are not reported as
+		// added or removed just because the results were truncated
+		// differently.
+		newQuery = strings.Join([]string{query.Query, fmt.Sprintf("count:%d", maxSavedQueryResults)}, " ")
+	}
+	var prevFingerprints []string
 	if info != nil {
-		latestKnownResult = info.LatestResult
-	} else {
-		// We've never executed this search query before, so use the current
-		// time. We'll most certainly find nothing, which is okay.
-		latestKnownResult = time.Now()
+		prevFingerprints = info.ResultFingerprints
 	}
-	afterTime := latestKnownResult.UTC().Format(time.RFC3339)
-	newQuery := strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
 	if debugPretendSavedQueryResultsExist {
 		debugPretendSavedQueryResultsExist = false
 		newQuery = query.Query
+		prevFingerprints = []string{}
 	}
 
 	// Perform the search and mark the saved query as having been executed in
@@ -200,11 +211,24 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 	// constantly and potentially causing harm to the system. We'll retry at
 	// our normal interval, regardless of errors.
 	v, execDuration, searchErr := performSearch(ctx, newQuery)
+	var (
+		added        []*searchMatch
+		removed      int
+		fingerprints = prevFingerprints
+	)
+	if searchErr == nil {
+		added, removed, fingerprints = diffSearchResults(prevFingerprints, v, commitSearch)
+	}
+	latestResult := time.Now()
+	if commitSearch {
+		latestResult = latestResultTime(info, v, searchErr)
+	}
 	if err := api.InternalClient.SavedQueriesSetInfo(ctx, &api.SavedQueryInfo{
-		Query:        query.Query,
-		LastExecuted: time.Now(),
-		LatestResult: latestResultTime(info, v, searchErr),
-		ExecDuration: execDuration,
+		Query:              query.Query,
+		LastExecuted:       time.Now(),
+		LatestResult:       latestResult,
+		ExecDuration:       execDuration,
+		ResultFingerprints: fingerprints,
 	}); err != nil {
 		return errors.Wrap(err, "SavedQueriesSetInfo")
 	}
@@ -217,13 +241,23 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 	// that we don't block other search queries from running in sequence (which
 	// is done intentionally, to ensure no overloading of searcher/gitserver).
 	go func() {
-		if err := notify(context.Background(), spec, query, newQuery, v); err != nil {
+		if err := notify(context.Background(), spec, query, newQuery, added, removed); err != nil {
 			log15.Error("executor: failed to send notifications", "error", err)
 		}
 	}()
 	return nil
 }
 
+// maxSavedQueryResults is the result limit of saved queries that don't
+// specify one with "count:".
+const maxSavedQueryResults = 1000
+
+// isCommitSearch reports whether the query only searches diffs or commits,
+// which support the after:"time" operator.
+func isCommitSearch(query string) bool {
+	return strings.Contains(query, "type:diff") || strings.Contains(query, "type:commit")
+}
+
 func performSearch(ctx context.Context, query string) (v *gqlSearchResponse, execDuration time.Duration, err error) {
 	attempts := 0
 	for {
@@ -278,11 +312,11 @@ func latestResultTime(prevInfo *api.SavedQueryInfo, v *gqlSearchResponse, search
 var externalURL *url.URL
 
 // notify handles sending notifications for new search results.
-func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results *gqlSearchResponse) error {
-	if len(results.Data.Search.Results.Results) == 0 {
+func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, newMatches []*searchMatch, removedCount int) error {
+	if len(newMatches) == 0 {
 		return nil
 	}
-	log15.Info("sending notifications", "new_results", len(results.Data.Search.Results.Results), "description", query.Description)
+	log15.Info("sending notifications", "new_results", len(newMatches), "removed_results", removedCount, "description", query.Description)
 
 	// Determine which users to notify.
 	recipients, err := getNotificationRecipients(ctx, spec, query)
@@ -290,48 +324,64 @@ func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSave
 		return err
 	}
 
-	// Send slack notifications.
 	n := &notifier{
-		spec:       spec,
-		query:      query,
-		newQuery:   newQuery,
-		results:    results,
-		recipients: recipients,
+		spec:         spec,
+		query:        query,
+		newQuery:     newQuery,
+		newMatches:   newMatches,
+		removedCount: removedCount,
+		recipients:   recipients,
 	}
 
-	// Send Slack and email notifications.
+	// Send Slack, email and webhook notifications.
 	n.slackNotify(ctx)
 	n.emailNotify(ctx)
+	n.webhookNotify(ctx)
 	return nil
 }
 
 type notifier struct {
-	spec       api.SavedQueryIDSpec
-	query      api.ConfigSavedQuery
-	newQuery   string
-	results    *gqlSearchResponse
-	recipients recipients
+	spec         api.SavedQueryIDSpec
+	query        api.ConfigSavedQuery
+	newQuery     string
+	newMatches   []*searchMatch // the matches that are new since the previous run
+	removedCount int            // the number of matches of the previous run that no longer match
+	recipients   recipients
 }
 
+// maxNotificationMatches is the maximum number of new matches that are listed
+// in Slack and email notifications.
+const maxNotificationMatches = 10
+
 const (
 	utmSourceEmail = "saved-search-email"
 	utmSourceSlack = "saved-search-slack"
 )
 
-func searchURL(query, utmSource string) string {
+// getExternalURL returns the external URL of the Sourcegraph instance, or nil
+// if it can't be determined.
+func getExternalURL() *url.URL {
 	if externalURL == nil {
 		// Determine the external URL.
 		externalURLStr, err := api.InternalClient.ExternalURL(context.Background())
 		if err != nil {
 			log15.Error("failed to get ExternalURL", err)
-			return ""
+			return nil
 		}
 		externalURL, err = url.Parse(externalURLStr)
 		if err != nil {
 			log15.Error("failed to parse ExternalURL", err)
-			return ""
+			return nil
 		}
 	}
+	return externalURL
+}
+
+func searchURL(query, utmSource string) string {
+	externalURL := getExternalURL()
+	if externalURL == nil {
+		return ""
+	}
 
 	// Construct URL to the search query.
 	u := externalURL.ResolveReference(&url.URL{Path: "search"})
@@ -342,6 +392,23 @@ func searchURL(query, utmSource string) string {
 	return u.String()
 }
 
+// matchURL returns the absolute URL of a match.
+func matchURL(match *searchMatch, utmSource string) string {
+	externalURL := getExternalURL()
+	if externalURL == nil {
+		return ""
+	}
+	ref, err := url.Parse(match.URL)
+	if err != nil {
+		return ""
+	}
+	u := externalURL.ResolveReference(ref)
+	q := u.Query()
+	q.Set("utm_source", utmSource)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 func logEvent(userID int32, email, eventName, eventType string) {
 	eventlogger.LogEvent(userID, email, eventName, json.RawMessage(fmt.Sprintf(`{"saved_searches": {"event_type": "%s"}}`, eventType)))
 }
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+// searchMatch is a single match of a saved search: a line of a file, a file
+// (if it matched only by path), a commit or a repository.
+type searchMatch struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit,omitempty"`
+	Author     string `json:"author,omitempty"`
+	Date       string `json:"date,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Path       string `json:"path,omitempty"`
+	LineNumber int    `json:"lineNumber,omitempty"` // 1-based, or 0 if the match is not on a line
+	Preview    string `json:"preview,omitempty"`    // the matching line, or the matching part of the diff or commit message
+	URL        string `json:"url"`
+
+	// fingerprint identifies the match across runs of the saved search.
+	fingerprint string
+}
+
+// searchMatches converts the results of a search (decoded from the JSON
+// response of gqlSearchQuery) to matches. File results produce one match per
+// matching line. Results of unexpected shapes are skipped.
+func searchMatches(results []interface{}) []*searchMatch {
+	str := func(m map[string]interface{}, keys ...string) string {
+		for i, key := range keys {
+			if i == len(keys)-1 {
+				s, _ := m[key].(string)
+				return s
+			}
+			if m, _ = m[key].(map[string]interface{}); m == nil {
+				return ""
+			}
+		}
+		return ""
+	}
+
+	matches := make([]*searchMatch, 0, len(results))
+	for _, result := range results {
+		m, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch m["__typename"] {
+		case "FileMatch":
+			// The resource is of the form "git://repo?rev#path".
+			u, err := url.Parse(str(m, "resource"))
+			if err != nil || u.Host == "" {
+				continue
+			}
+			repo, rev, path := u.Host+u.Path, u.RawQuery, u.Fragment
+			repoRev := repo
+			if rev != "" {
+				repoRev += "@" + rev
+			}
+			fileURL := "/" + repoRev + "/-/blob/" + path
+
+			lineMatches, _ := m["lineMatches"].([]interface{})
+			if len(lineMatches) == 0 {
+				matches = append(matches, &searchMatch{
+					Repository:  repo,
+					Path:        path,
+					URL:         fileURL,
+					fingerprint: "path:" + repo + ":" + path,
+				})
+				continue
+			}
+			for _, lm := range lineMatches {
+				lm, ok := lm.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				lineNumber, _ := lm["lineNumber"].(float64) // 0-based
+				preview := str(lm, "preview")
+				matches = append(matches, &searchMatch{
+					Repository:  repo,
+					Path:        path,
+					LineNumber:  int(lineNumber) + 1,
+					Preview:     preview,
+					URL:         fmt.Sprintf("%s#L%d", fileURL, int(lineNumber)+1),
+					fingerprint: fmt.Sprintf("line:%s:%s:%d:%s", repo, path, int(lineNumber)+1, contentHash(preview)),
+				})
+			}
+
+		case "CommitSearchResult":
+			match := &searchMatch{
+				Repository: str(m, "commit", "repository", "name"),
+				Commit:     str(m, "commit", "oid"),
+				Author:     str(m, "commit", "author", "person", "displayName"),
+				Date:       str(m, "commit", "author", "date"),
+				Message:    str(m, "commit", "message"),
+				Preview:    str(m, "diffPreview", "value"),
+				URL:        str(m, "commit", "url"),
+			}
+			if match.Preview == "" {
+				match.Preview = str(m, "messagePreview", "value")
+			}
+			match.fingerprint = "commit:" + match.Repository + "@" + match.Commit
+			matches = append(matches, match)
+
+		case "Repository":
+			name := str(m, "name")
+			matches = append(matches, &searchMatch{
+				Repository:  name,
+				URL:         str(m, "url"),
+				fingerprint: "repo:" + name,
+			})
+		}
+	}
+	return matches
+}
+
+// contentHash returns a short hash of a matching line, so that fingerprints
+// change when the content of the line changes without storing the line
+// itself.
+func contentHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// diffSearchResults compares the results of the current run of a saved search
+// to the fingerprints of the previous run (nil if there was none). It returns
+// the matches that are new, the number of previous matches that no longer
+// match, and the fingerprints to store for the next run.
+//
+// Matches that are missing from incomplete results are not reported as
+// removed (and do not cause them to be reported as new again on the next
+// run): the previous fingerprints of repositories that timed out or were
+// cloning are carried forward, and if the results were truncated (or only
+// consist of an alert), nothing is reported and the previous fingerprints are
+// kept.
+func diffSearchResults(prev []string, v *gqlSearchResponse, commitSearch bool) (added []*searchMatch, removed int, fingerprints []string) {
+	results := v.Data.Search.Results
+	var incompleteRepos []string
+	for _, repos := range [][]*api.Repo{results.Cloning, results.Timedout} {
+		for _, repo := range repos {
+			incompleteRepos = append(incompleteRepos, string(repo.Name))
+		}
+	}
+	added, removed, fingerprints = diffMatches(prev, searchMatches(results.Results), incompleteRepos)
+
+	switch {
+	case commitSearch:
+		// Only commits after the previous run are returned, so commits
+		// missing from the results have not been removed. The previous
+		// fingerprints are only needed to skip commits at the boundary that
+		// were already reported.
+		removed = 0
+		if len(fingerprints) == 0 {
+			fingerprints = prev
+		}
+	case results.LimitHit || (results.Alert != nil && len(results.Results) == 0):
+		// Matches may be missing from (or present in) truncated results only
+		// because of where they were truncated.
+		added, removed, fingerprints = nil, 0, prev
+	case prev == nil:
+		// This is the first run (or the first since result diffing was
+		// introduced), so there is nothing to compare to. Record the matches
+		// without notifying about them.
+		added, removed = nil, 0
+	}
+	return added, removed, fingerprints
+}
+
+// diffMatches compares the matches of the current run of a saved search to the
+// fingerprints of the previous run. It returns the matches that are new, the
+// number of previous matches that no longer match, and the fingerprints of the
+// current run (which should be stored for the next run).
+//
+// The previous fingerprints of matches in incompleteRepos (repositories that
+// were not fully searched) are carried forward instead of being counted as
+// removed.
+func diffMatches(prev []string, matches []*searchMatch, incompleteRepos []string) (added []*searchMatch, removed int, fingerprints []string) {
+	prevSet := make(map[string]struct{}, len(prev))
+	for _, fp := range prev {
+		prevSet[fp] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(matches))
+	fingerprints = make([]string, 0, len(matches))
+	for _, match := range matches {
+		if _, ok := seen[match.fingerprint]; ok {
+			continue
+		}
+		seen[match.fingerprint] = struct{}{}
+		fingerprints = append(fingerprints, match.fingerprint)
+		if _, ok := prevSet[match.fingerprint]; !ok {
+			added = append(added, match)
+		}
+	}
+	for _, fp := range prev {
+		if _, ok := seen[fp]; ok {
+			continue
+		}
+		seen[fp] = struct{}{}
+		if fingerprintInRepos(fp, incompleteRepos) {
+			fingerprints = append(fingerprints, fp)
+		} else {
+			removed++
+		}
+	}
+	return added, removed, fingerprints
+}
+
+// fingerprintInRepos reports whether the fingerprint (see searchMatches) is of
+// a match in one of the repositories.
+func fingerprintInRepos(fp string, repos []string) bool {
+	for _, repo := range repos {
+		if strings.HasPrefix(fp, "line:"+repo+":") || strings.HasPrefix(fp, "path:"+repo+":") || strings.HasPrefix(fp, "commit:"+repo+"@") || fp == "repo:"+repo {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSummary returns a one-line description of a match, for use in
+// notifications.
+func matchSummary(match *searchMatch) string {
+	switch {
+	case match.Commit != "":
+		commit := match.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		subject := match.Message
+		if i := strings.IndexByte(subject, '\n'); i >= 0 {
+			subject = subject[:i]
+		}
+		return fmt.Sprintf("%s@%s: %s", match.Repository, commit, subject)
+	case match.LineNumber > 0:
+		return fmt.Sprintf("%s/%s:%d: %s", match.Repository, match.Path, match.LineNumber, strings.TrimSpace(match.Preview))
+	case match.Path != "":
+		return match.Repository + "/" + match.Path
+	}
+	return match.Repository
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestSearchMatches(t *testing.T) {
+	var results []interface{}
+	if err := json.Unmarshal([]byte(`[
+		{
+			"__typename": "CommitSearchResult",
+			"diffPreview": {"value": "+foo"},
+			"commit": {
+				"repository": {"name": "github.com/foo/bar"},
+				"oid": "c0ffee",
+				"url": "/github.com/foo/bar/-/commit/c0ffee",
+				"author": {"person": {"displayName": "Alice"}, "date": "2018-01-02T03:04:05Z"},
+				"message": "Add foo"
+			}
+		},
+		{
+			"__typename": "CommitSearchResult",
+			"messagePreview": {"value": "Fix bar"},
+			"commit": {"oid": "beef"}
+		},
+		{
+			"__typename": "FileMatch",
+			"resource": "git://github.com/foo/bar?master#cmd/main.go",
+			"lineMatches": [
+				{"preview": "func main() {", "lineNumber": 9},
+				{"preview": "\tfoo()", "lineNumber": 10}
+			]
+		},
+		{"__typename": "FileMatch", "resource": "git://github.com/foo/bar#README.md", "lineMatches": []},
+		{"__typename": "Repository", "name": "github.com/foo/baz", "url": "/github.com/foo/baz"},
+		{"__typename": "Unknown"}
+	]`), &results); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*searchMatch{
+		{
+			Repository:  "github.com/foo/bar",
+			Commit:      "c0ffee",
+			Author:      "Alice",
+			Date:        "2018-01-02T03:04:05Z",
+			Message:     "Add foo",
+			Preview:     "+foo",
+			URL:         "/github.com/foo/bar/-/commit/c0ffee",
+			fingerprint: "commit:github.com/foo/bar@c0ffee",
+		},
+		{Commit: "beef", Preview: "Fix bar", fingerprint: "commit:@beef"},
+		{
+			Repository:  "github.com/foo/bar",
+			Path:        "cmd/main.go",
+			LineNumber:  10,
+			Preview:     "func main() {",
+			URL:         "/github.com/foo/bar@master/-/blob/cmd/main.go#L10",
+			fingerprint: "line:github.com/foo/bar:cmd/main.go:10:" + contentHash("func main() {"),
+		},
+		{
+			Repository:  "github.com/foo/bar",
+			Path:        "cmd/main.go",
+			LineNumber:  11,
+			Preview:     "\tfoo()",
+			URL:         "/github.com/foo/bar@master/-/blob/cmd/main.go#L11",
+			fingerprint: "line:github.com/foo/bar:cmd/main.go:11:" + contentHash("\tfoo()"),
+		},
+		{
+			Repository:  "github.com/foo/bar",
+			Path:        "README.md",
+			URL:         "/github.com/foo/bar/-/blob/README.md",
+			fingerprint: "path:github.com/foo/bar:README.md",
+		},
+		{Repository: "github.com/foo/baz", URL: "/github.com/foo/baz", fingerprint: "repo:github.com/foo/baz"},
+	}
+	if got := searchMatches(results); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffMatches(t *testing.T) {
+	a := &searchMatch{fingerprint: "a"}
+	b := &searchMatch{fingerprint: "b"}
+	c := &searchMatch{fingerprint: "c"}
+
+	tests := map[string]struct {
+		prev             []string
+		matches          []*searchMatch
+		incompleteRepos  []string
+		wantAdded        []*searchMatch
+		wantRemoved      int
+		wantFingerprints []string
+	}{
+		"no previous matches": {
+			prev:             []string{},
+			matches:          []*searchMatch{a, b},
+			wantAdded:        []*searchMatch{a, b},
+			wantFingerprints: []string{"a", "b"},
+		},
+		"unchanged": {
+			prev:             []string{"a", "b"},
+			matches:          []*searchMatch{b, a},
+			wantFingerprints: []string{"b", "a"},
+		},
+		"added and removed": {
+			prev:             []string{"a", "b"},
+			matches:          []*searchMatch{b, c},
+			wantAdded:        []*searchMatch{c},
+			wantRemoved:      1,
+			wantFingerprints: []string{"b", "c"},
+		},
+		"duplicate matches": {
+			prev:             []string{},
+			matches:          []*searchMatch{a, a},
+			wantAdded:        []*searchMatch{a},
+			wantFingerprints: []string{"a"},
+		},
+		"all removed": {
+			prev:             []string{"a", "b"},
+			wantRemoved:      2,
+			wantFingerprints: []string{},
+		},
+		"incomplete repository": {
+			prev:             []string{"line:r1:f:1:x", "path:r1:g", "repo:r1", "commit:r1@c", "line:r2:f:1:x", "line:r10:f:1:x"},
+			matches:          []*searchMatch{{fingerprint: "line:r2:f:1:x"}},
+			incompleteRepos:  []string{"r1"},
+			wantRemoved:      1,
+			wantFingerprints: []string{"line:r2:f:1:x", "line:r1:f:1:x", "path:r1:g", "repo:r1", "commit:r1@c"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			added, removed, fingerprints := diffMatches(test.prev, test.matches, test.incompleteRepos)
+			if !reflect.DeepEqual(added, test.wantAdded) {
+				t.Errorf("got added %v, want %v", added, test.wantAdded)
+			}
+			if removed != test.wantRemoved {
+				t.Errorf("got removed %d, want %d", removed, test.wantRemoved)
+			}
+			if !reflect.DeepEqual(fingerprints, test.wantFingerprints) {
+				t.Errorf("got fingerprints %v, want %v", fingerprints, test.wantFingerprints)
+			}
+		})
+	}
+}
+
+func TestDiffSearchResults(t *testing.T) {
+	response := func(results string, modify func(v *gqlSearchResponse)) *gqlSearchResponse {
+		var v gqlSearchResponse
+		if err := json.Unmarshal([]byte(`{"data": {"search": {"results": {"results": `+results+`}}}}`), &v); err != nil {
+			t.Fatal(err)
+		}
+		if modify != nil {
+			modify(&v)
+		}
+		return &v
+	}
+	const (
+		a = `{"__typename": "Repository", "name": "a"}`
+		b = `{"__typename": "Repository", "name": "b"}`
+	)
+	prev := []string{"repo:a", "repo:b"}
+
+	tests := map[string]struct {
+		prev             []string
+		v                *gqlSearchResponse
+		commitSearch     bool
+		wantAdded        int
+		wantRemoved      int
+		wantFingerprints []string
+	}{
+		"complete": {
+			prev:             []string{"repo:a"},
+			v:                response(`[`+a+`, `+b+`]`, nil),
+			wantAdded:        1,
+			wantFingerprints: []string{"repo:a", "repo:b"},
+		},
+		"first run": {
+			v:                response(`[`+a+`]`, nil),
+			wantFingerprints: []string{"repo:a"},
+		},
+		"timed out repository": {
+			prev: prev,
+			v: response(`[`+a+`]`, func(v *gqlSearchResponse) {
+				v.Data.Search.Results.Timedout = []*api.Repo{{Name: "b"}}
+			}),
+			wantFingerprints: prev,
+		},
+		"cloning repository": {
+			prev: prev,
+			v: response(`[`+a+`]`, func(v *gqlSearchResponse) {
+				v.Data.Search.Results.Cloning = []*api.Repo{{Name: "b"}}
+			}),
+			wantFingerprints: prev,
+		},
+		"limit hit": {
+			prev: prev,
+			v: response(`[`+a+`]`, func(v *gqlSearchResponse) {
+				v.Data.Search.Results.LimitHit = true
+			}),
+			wantFingerprints: prev,
+		},
+		"alert without results": {
+			prev: prev,
+			v: response(`[]`, func(v *gqlSearchResponse) {
+				v.Data.Search.Results.Alert = &struct{ Title string }{Title: "Timed out"}
+			}),
+			wantFingerprints: prev,
+		},
+		"commit search": {
+			prev:             []string{"commit:r@c1"},
+			v:                response(`[]`, nil),
+			commitSearch:     true,
+			wantFingerprints: []string{"commit:r@c1"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			added, removed, fingerprints := diffSearchResults(test.prev, test.v, test.commitSearch)
+			if len(added) != test.wantAdded {
+				t.Errorf("got %d added, want %d", len(added), test.wantAdded)
+			}
+			if removed != test.wantRemoved {
+				t.Errorf("got removed %d, want %d", removed, test.wantRemoved)
+			}
+			if !reflect.DeepEqual(fingerprints, test.wantFingerprints) {
+				t.Errorf("got fingerprints %v, want %v", fingerprints, test.wantFingerprints)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	log15 "gopkg.in/inconshreveable/log15.v2"
 
@@ -12,19 +13,27 @@ import (
 
 func (n *notifier) slackNotify(ctx context.Context) {
 	plural := ""
-	if n.results.Data.Search.Results.ApproximateResultCount != "1" {
+	if len(n.newMatches) != 1 {
 		plural = "s"
 	}
 
-	text := fmt.Sprintf(`*%s* new result%s found for saved search <%s|"%s">`,
-		n.results.Data.Search.Results.ApproximateResultCount,
+	var text strings.Builder
+	fmt.Fprintf(&text, `*%d* new result%s found for saved search <%s|"%s">`,
+		len(n.newMatches),
 		plural,
 		searchURL(n.newQuery, utmSourceSlack),
 		n.query.Description,
 	)
+	for i, match := range n.newMatches {
+		if i == maxNotificationMatches {
+			fmt.Fprintf(&text, "\n…and %d more", len(n.newMatches)-i)
+			break
+		}
+		fmt.Fprintf(&text, "\n• <%s|%s>", matchURL(match, utmSourceSlack), slackEscape(matchSummary(match)))
+	}
 	for _, recipient := range n.recipients {
-		if err := slackNotify(ctx, recipient, text, n.query.SlackWebhookURL); err != nil {
-			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
+		if err := slackNotify(ctx, recipient, text.String(), n.query.SlackWebhookURL); err != nil {
+			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text.String(), "error", err)
 		}
 	}
 	// TODO(Dan): find all users in the recipient list and log events for all of them
@@ -76,3 +85,9 @@ func slackNotify(ctx context.Context, recipient *recipient, text string, slackWe
 	client := slack.New(*slackWebhookURL, true)
 	return slack.Post(payload, client.WebhookURL)
 }
+
+// slackEscape escapes the characters that have a special meaning in Slack
+// message text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
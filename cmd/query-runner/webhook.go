@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+const (
+	utmSourceWebhook = "saved-search-webhook"
+
+	// webhookSignatureHeader is the header that contains the signature of
+	// the payload, if the saved search has a webhook secret.
+	webhookSignatureHeader = "X-Sourcegraph-Signature"
+	// webhookEventHeader is the header that contains the event type of the
+	// payload ("results" or "test").
+	webhookEventHeader = "X-Sourcegraph-Event"
+
+	// webhookMaxAttempts is the number of times delivery of a webhook
+	// notification of new results is attempted before giving up.
+	webhookMaxAttempts = 5
+)
+
+var (
+	webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+	// webhookBackoff is the delay before the first retry of a failed webhook
+	// delivery. It doubles after each attempt.
+	webhookBackoff = 2 * time.Second
+)
+
+// webhookPayload is the JSON body that is POSTed to saved search webhooks.
+type webhookPayload struct {
+	Event              string             `json:"event"`
+	SavedSearch        webhookSavedSearch `json:"savedSearch"`
+	NewResultCount     int                `json:"newResultCount"`
+	NewResults         []*searchMatch     `json:"newResults"`
+	RemovedResultCount int                `json:"removedResultCount"` // number of previous results that no longer match
+	NewResultsURL      string             `json:"newResultsURL"`      // URL to a search for the new results
+	SearchURL          string             `json:"searchURL"`          // URL to the saved search's query
+}
+
+type webhookSavedSearch struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Query       string `json:"query"`
+}
+
+func (n *notifier) webhookNotify(ctx context.Context) {
+	if !n.query.NotifyWebhook {
+		return
+	}
+
+	payload := &webhookPayload{
+		Event: "results",
+		SavedSearch: webhookSavedSearch{
+			ID:          n.spec.Key,
+			Description: n.query.Description,
+			Query:       n.query.Query,
+		},
+		NewResultCount:     len(n.newMatches),
+		NewResults:         n.newMatches,
+		RemovedResultCount: n.removedCount,
+		NewResultsURL:      searchURL(n.newQuery, utmSourceWebhook),
+		SearchURL:          searchURL(n.query.Query, utmSourceWebhook),
+	}
+	// Deliveries (and their retries) happen in the background so that a slow
+	// or failing webhook doesn't hold up the other notifications and queries.
+	select {
+	case webhookQueue <- &webhookJob{spec: n.spec, query: n.query, payload: payload}:
+	default:
+		log15.Error("Dropping webhook notification because the delivery queue is full.", "savedSearch", n.spec.Key)
+	}
+}
+
+// webhookNotifyTest sends a test notification to the webhook of the saved
+// search. The delivery is attempted only once, because the caller is waiting
+// for the result.
+func webhookNotifyTest(ctx context.Context, query api.SavedQuerySpecAndConfig) error {
+	if !query.Config.NotifyWebhook {
+		return nil
+	}
+	payload := &webhookPayload{
+		Event: "test",
+		SavedSearch: webhookSavedSearch{
+			ID:          query.Spec.Key,
+			Description: query.Config.Description,
+			Query:       query.Config.Query,
+		},
+		NewResults: []*searchMatch{},
+		SearchURL:  searchURL(query.Config.Query, utmSourceWebhook),
+	}
+	return webhookNotify(ctx, query.Spec, query.Config, payload, 1)
+}
+
+// webhookJob is a webhook notification waiting to be delivered by
+// runWebhookQueue.
+type webhookJob struct {
+	spec    api.SavedQueryIDSpec
+	query   api.ConfigSavedQuery
+	payload *webhookPayload
+}
+
+// webhookQueue holds the webhook notifications of saved search results until
+// they are delivered by runWebhookQueue.
+var webhookQueue = make(chan *webhookJob, 100)
+
+// runWebhookQueue delivers the webhook notifications in webhookQueue, retrying
+// failed deliveries. It runs until ctx is done.
+func runWebhookQueue(ctx context.Context) {
+	for {
+		select {
+		case job := <-webhookQueue:
+			if err := webhookNotify(ctx, job.spec, job.query, job.payload, webhookMaxAttempts); err != nil {
+				log15.Error("Failed to deliver webhook notification.", "savedSearch", job.spec.Key, "error", err)
+				continue
+			}
+			logEvent(0, "", "SavedSearchWebhookNotificationSent", job.payload.Event)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// webhookNotify delivers the payload to the webhook of the saved search,
+// making at most maxAttempts attempts, and records the delivery in the saved
+// search's delivery history.
+func webhookNotify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, payload *webhookPayload, maxAttempts int) error {
+	if query.WebhookURL == nil || *query.WebhookURL == "" {
+		return fmt.Errorf("unable to send webhook notification because saved search %q has no webhook URL configured", query.Description)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "Marshal")
+	}
+
+	var secret string
+	if query.WebhookSecret != nil {
+		secret = *query.WebhookSecret
+	}
+	attempts, statusCode, deliveryErr := deliverWebhook(ctx, *query.WebhookURL, secret, payload.Event, body, maxAttempts)
+
+	delivery := &api.SavedQueryWebhookDelivery{
+		Key:         spec.Key,
+		URL:         *query.WebhookURL,
+		Test:        payload.Event == "test",
+		ResultCount: payload.NewResultCount,
+		Attempts:    attempts,
+		StatusCode:  statusCode,
+	}
+	if deliveryErr != nil {
+		delivery.Error = deliveryErr.Error()
+	}
+	if err := api.InternalClient.SavedQueriesRecordWebhookDelivery(ctx, delivery); err != nil {
+		log15.Error("Failed to record webhook delivery.", "savedSearch", spec.Key, "error", err)
+	}
+	return deliveryErr
+}
+
+// deliverWebhook POSTs body to url, making at most maxAttempts attempts and
+// retrying with exponential backoff if the request fails or the server
+// responds with a 5xx or 429 status code. It returns the number of attempts
+// and the status code of the last response (or 0 if there was none).
+func deliverWebhook(ctx context.Context, url, secret, event string, body []byte, maxAttempts int) (attempts, statusCode int, err error) {
+	backoff := webhookBackoff
+	for {
+		attempts++
+		var retry bool
+		statusCode, retry, err = postWebhook(ctx, url, secret, event, body)
+		if err == nil || !retry || attempts >= maxAttempts {
+			return attempts, statusCode, err
+		}
+
+		log15.Warn("Webhook delivery failed, retrying.", "url", url, "attempt", attempts, "backoff", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return attempts, statusCode, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// postWebhook makes a single webhook delivery attempt. It returns whether the
+// delivery should be retried if it failed.
+func postWebhook(ctx context.Context, url, secret, event string, body []byte) (statusCode int, retry bool, err error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sourcegraph-Webhook")
+	req.Header.Set(webhookEventHeader, event)
+	if secret != "" {
+		req.Header.Set(webhookSignatureHeader, webhookSignature(secret, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return resp.StatusCode, retry, fmt.Errorf("webhook responded with HTTP status %d", resp.StatusCode)
+}
+
+// webhookSignature returns the value of the signature header for the body:
+// "sha256=" followed by the hex-encoded HMAC-SHA256 of the body, keyed with the
+// secret.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
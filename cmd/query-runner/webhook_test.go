@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeliverWebhook(t *testing.T) {
+	defer func(d time.Duration) { webhookBackoff = d }(webhookBackoff)
+	webhookBackoff = 0
+
+	ctx := context.Background()
+	body := []byte(`{"event":"results"}`)
+
+	t.Run("signed", func(t *testing.T) {
+		var gotSignature, gotEvent string
+		var gotBody []byte
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotSignature = r.Header.Get(webhookSignatureHeader)
+			gotEvent = r.Header.Get(webhookEventHeader)
+			gotBody, _ = ioutil.ReadAll(r.Body)
+		}))
+		defer ts.Close()
+
+		attempts, statusCode, err := deliverWebhook(ctx, ts.URL, "s3cr3t", "results", body, webhookMaxAttempts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 1 || statusCode != http.StatusOK {
+			t.Errorf("got attempts %d and status code %d, want 1 and 200", attempts, statusCode)
+		}
+		if want := webhookSignature("s3cr3t", body); gotSignature != want {
+			t.Errorf("got signature %q, want %q", gotSignature, want)
+		}
+		if gotEvent != "results" {
+			t.Errorf("got event %q, want %q", gotEvent, "results")
+		}
+		if string(gotBody) != string(body) {
+			t.Errorf("got body %q, want %q", gotBody, body)
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.Header[webhookSignatureHeader]; ok {
+				t.Error("got signature header for webhook without secret")
+			}
+		}))
+		defer ts.Close()
+
+		if _, _, err := deliverWebhook(ctx, ts.URL, "", "results", body, webhookMaxAttempts); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer ts.Close()
+
+		attempts, statusCode, err := deliverWebhook(ctx, ts.URL, "", "results", body, webhookMaxAttempts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 3 || statusCode != http.StatusOK {
+			t.Errorf("got attempts %d and status code %d, want 3 and 200", attempts, statusCode)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		attempts, statusCode, err := deliverWebhook(ctx, ts.URL, "", "results", body, webhookMaxAttempts)
+		if err == nil {
+			t.Fatal("got nil error, want error")
+		}
+		if attempts != webhookMaxAttempts || statusCode != http.StatusServiceUnavailable {
+			t.Errorf("got attempts %d and status code %d, want %d and 503", attempts, statusCode, webhookMaxAttempts)
+		}
+	})
+
+	t.Run("single attempt", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		attempts, _, err := deliverWebhook(ctx, ts.URL, "", "test", body, 1)
+		if err == nil {
+			t.Fatal("got nil error, want error")
+		}
+		if attempts != 1 {
+			t.Errorf("got attempts %d, want 1", attempts)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		attempts, _, err := deliverWebhook(ctx, ts.URL, "", "results", body, webhookMaxAttempts)
+		if err == nil {
+			t.Fatal("got nil error, want error")
+		}
+		if attempts != 1 {
+			t.Errorf("got attempts %d, want 1", attempts)
+		}
+	})
+}
@@ -1,5 +1,5 @@
-// Command replacer is an interface to replace and rewrite code. It passes a zipped repo
-// to external tools and streams back JSON lines results.
+// Command replacer is an interface to replace and rewrite code. It rewrites a zipped
+// repo with external tools or a built-in engine and streams back JSON lines results.
 package main
 
 import (
@@ -23,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/debugserver"
 	"github.com/sourcegraph/sourcegraph/pkg/env"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/store"
 
 	"github.com/sourcegraph/sourcegraph/pkg/tracer"
@@ -61,6 +62,14 @@ func main() {
 	service := &replace.Service{
 		Store: &store,
 		Log:   log15.Root(),
+		CreateCommitFromPatch: func(ctx context.Context, req protocol.CreateCommitFromPatchRequest) (api.CommitID, error) {
+			if _, err := gitserver.DefaultClient.CreateCommitFromPatch(ctx, req); err != nil {
+				return "", err
+			}
+			return git.ResolveRevision(ctx, gitserver.Repo{Name: req.Repo}, nil, req.TargetRef, &git.ResolveRevisionOptions{
+				NoEnsureRevision: true,
+			})
+		},
 	}
 	handler := nethttp.Middleware(opentracing.GlobalTracer(), service)
 
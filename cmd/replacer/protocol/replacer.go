@@ -34,9 +34,27 @@ type Request struct {
 	// the fetch will still happen in the background so future requests don't have to wait.
 	FetchTimeout string
 
+	// Diff if true will respond with a unified diff per rewritten file
+	// instead of the full rewritten file contents.
+	Diff bool
+
 	RewriteSpecification
 }
 
+// Engines which can be specified in RewriteSpecification.Engine.
+const (
+	// EngineComby rewrites with the external comby tool. It is the default.
+	EngineComby = "comby"
+
+	// EngineRegexp rewrites matches of a Go regular expression. The rewrite
+	// template may refer to submatches with $1 or ${name}, see
+	// regexp.Regexp.Expand.
+	EngineRegexp = "regexp"
+
+	// EngineLiteral replaces every occurrence of a literal string.
+	EngineLiteral = "literal"
+)
+
 type RewriteSpecification struct {
 	// A template pattern that expresses what to match.
 	MatchTemplate string
@@ -46,7 +64,84 @@ type RewriteSpecification struct {
 
 	// A file extension suffix filtering which files to process (e.g., ".go")
 	FileExtension string
+
+	// Engine is the rewrite engine which interprets MatchTemplate and
+	// RewriteTemplate. If empty, EngineComby is used.
+	Engine string
+}
+
+// FileResult is a single JSON line of a replacer response. The JSON field
+// names match the JSON lines output of comby.
+type FileResult struct {
+	// Path is the path of the rewritten file in the repository.
+	Path string `json:"uri"`
+
+	// Content is the rewritten file content. It is not set if the request
+	// set Diff.
+	Content string `json:"rewritten_source,omitempty"`
+
+	// Diff is a unified diff from the original to the rewritten file
+	// content. It is only set if the request set Diff.
+	Diff string `json:"diff,omitempty"`
+}
+
+// StreamDone is the last JSON line of a successful replacer response. It
+// follows the FileResult lines and tells the client whether it received
+// all results.
+type StreamDone struct {
+	// Done is always true. It distinguishes this line from a FileResult.
+	Done bool `json:"done"`
+
+	// DeadlineHit is true if the request deadline was hit before all
+	// files were rewritten.
+	DeadlineHit bool `json:"deadline_hit,omitempty"`
+
+	// Error is set if rewriting failed after results were already sent.
+	// The results before this line are then incomplete.
+	Error string `json:"error,omitempty"`
 }
 
 // GitserverRepo returns the repository information necessary to perform gitserver requests.
 func (r Request) GitserverRepo() gitserver.Repo { return gitserver.Repo{Name: r.Repo, URL: r.URL} }
+
+// ApplyRequest is a request to rewrite a repository and commit the result
+// on gitserver. It is sent as JSON to the /apply endpoint.
+type ApplyRequest struct {
+	// Repo, URL, Commit, FetchTimeout and RewriteSpecification describe
+	// the rewrite, like for a normal replace request. Diff is ignored.
+	Request
+
+	// TargetRef is the ref which will point to the new commit. If empty,
+	// a unique ref under refs/sourcegraph/rewrites/ is used.
+	TargetRef string
+
+	// Message is the commit message. If empty, a message describing the
+	// rewrite is used.
+	Message string
+
+	// AuthorName and AuthorEmail are the author of the new commit. If
+	// empty, gitserver uses a default author.
+	AuthorName  string
+	AuthorEmail string
+}
+
+// ApplyResponse is the response to an ApplyRequest.
+type ApplyResponse struct {
+	// Commit is the ID of the new commit. It is empty if the rewrite did not
+	// change any files, in which case no commit is created.
+	Commit api.CommitID
+
+	// Ref is the ref pointing to Commit.
+	Ref string
+
+	// Files summarizes the changes to each rewritten file.
+	Files []FileSummary
+}
+
+// FileSummary describes the changes a rewrite made to a single file.
+type FileSummary struct {
+	Path string
+
+	// Added and Deleted are the number of lines added and deleted.
+	Added, Deleted int32
+}
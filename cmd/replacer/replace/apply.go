@@ -0,0 +1,111 @@
+package replace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/replacer/protocol"
+	gitprotocol "github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// serveApply handles requests to rewrite a repository and commit the result
+// to a new ref on gitserver.
+func (s *Service) serveApply(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	running.Inc()
+	defer running.Dec()
+
+	var req protocol.ApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateParams(&req.Request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.apply(ctx, &req)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatusCode(ctx, &req.Request, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// apply runs the rewrite described by req, builds a patch from the
+// rewritten files and creates a commit from it on gitserver.
+func (s *Service) apply(ctx context.Context, req *protocol.ApplyRequest) (*protocol.ApplyResponse, error) {
+	if s.CreateCommitFromPatch == nil {
+		return nil, errors.New("replacer is not configured to create commits")
+	}
+
+	p := req.Request
+	p.Diff = true
+
+	var (
+		patch strings.Builder
+		resp  protocol.ApplyResponse
+	)
+	deadlineHit, err := s.replace(ctx, &p, func(r *protocol.FileResult) error {
+		fd, err := diff.ParseFileDiff([]byte(r.Diff))
+		if err != nil {
+			return errors.Wrapf(err, "invalid diff for %s", r.Path)
+		}
+		stat := fd.Stat()
+		resp.Files = append(resp.Files, protocol.FileSummary{
+			Path:    r.Path,
+			Added:   stat.Added + stat.Changed,
+			Deleted: stat.Deleted + stat.Changed,
+		})
+		patch.WriteString(r.Diff)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deadlineHit {
+		// Committing a partial rewrite would be surprising.
+		return nil, context.DeadlineExceeded
+	}
+	if len(resp.Files) == 0 {
+		return &resp, nil
+	}
+
+	resp.Ref = req.TargetRef
+	if resp.Ref == "" {
+		resp.Ref = fmt.Sprintf("refs/sourcegraph/rewrites/%d", time.Now().UnixNano())
+	}
+	message := req.Message
+	if message == "" {
+		message = fmt.Sprintf("Rewrite %q to %q", req.MatchTemplate, req.RewriteTemplate)
+	}
+
+	resp.Commit, err = s.CreateCommitFromPatch(ctx, gitprotocol.CreateCommitFromPatchRequest{
+		Repo:       req.Repo,
+		BaseCommit: req.Commit,
+		Patch:      patch.String(),
+		TargetRef:  resp.Ref,
+		CommitInfo: gitprotocol.PatchCommitInfo{
+			Message:     message,
+			AuthorName:  req.AuthorName,
+			AuthorEmail: req.AuthorEmail,
+			Date:        time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating commit from patch")
+	}
+
+	log15.Info("replacer: created commit from rewrite", "repo", req.Repo, "base", req.Commit, "commit", resp.Commit, "ref", resp.Ref, "files", len(resp.Files))
+	return &resp, nil
+}
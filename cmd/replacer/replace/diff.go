@@ -0,0 +1,148 @@
+package replace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+// in a hunk. It is the same default as git diff.
+const diffContextLines = 3
+
+// unifiedDiff returns a unified diff from a to b for the file at path. The
+// diff is parsed again before it is returned, so callers can rely on it
+// being well formed.
+func unifiedDiff(path, a, b string) (string, error) {
+	d := formatUnifiedDiff(path, diffLines(a, b))
+	if _, err := diff.ParseFileDiff([]byte(d)); err != nil {
+		return "", errors.Wrapf(err, "generated invalid diff for %s", path)
+	}
+	return d, nil
+}
+
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string // includes the trailing newline, if any
+}
+
+// diffLines returns the lines of a and b annotated with whether they were
+// deleted, inserted or are unchanged.
+func diffLines(a, b string) []diffLine {
+	dmp := diffmatchpatch.New()
+	ac, bc, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(ac, bc, false), lines)
+
+	var out []diffLine
+	for _, d := range diffs {
+		text := d.Text
+		for text != "" {
+			i := strings.IndexByte(text, '\n') + 1
+			if i == 0 {
+				i = len(text)
+			}
+			out = append(out, diffLine{op: d.Type, text: text[:i]})
+			text = text[i:]
+		}
+	}
+	return out
+}
+
+func formatUnifiedDiff(path string, lines []diffLine) string {
+	// oldLine[i] and newLine[i] are the 1-based line numbers in a and b of
+	// the first line at or after lines[i].
+	oldLine := make([]int, len(lines)+1)
+	newLine := make([]int, len(lines)+1)
+	oldLine[0], newLine[0] = 1, 1
+	for i, l := range lines {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if l.op != diffmatchpatch.DiffInsert {
+			oldLine[i+1]++
+		}
+		if l.op != diffmatchpatch.DiffDelete {
+			newLine[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+
+	for i := 0; i < len(lines); {
+		// Skip to the next change.
+		for i < len(lines) && lines[i].op == diffmatchpatch.DiffEqual {
+			i++
+		}
+		if i == len(lines) {
+			break
+		}
+
+		// A hunk continues until there are more than 2*diffContextLines
+		// unchanged lines in a row.
+		end := i
+		for j := i; j < len(lines); {
+			if lines[j].op != diffmatchpatch.DiffEqual {
+				j++
+				end = j
+				continue
+			}
+			k := j
+			for k < len(lines) && lines[k].op == diffmatchpatch.DiffEqual {
+				k++
+			}
+			if k == len(lines) || k-j > 2*diffContextLines {
+				break
+			}
+			j = k
+		}
+
+		start := max(0, i-diffContextLines)
+		end = min(len(lines), end+diffContextLines)
+
+		oldStart, oldCount := oldLine[start], oldLine[end]-oldLine[start]
+		newStart, newCount := newLine[start], newLine[end]-newLine[start]
+		// An empty range starts at the line before it.
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+		for _, l := range lines[start:end] {
+			switch l.op {
+			case diffmatchpatch.DiffDelete:
+				b.WriteByte('-')
+			case diffmatchpatch.DiffInsert:
+				b.WriteByte('+')
+			default:
+				b.WriteByte(' ')
+			}
+			b.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return b.String()
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,159 @@
+package replace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/replacer/protocol"
+	"github.com/sourcegraph/sourcegraph/pkg/store"
+)
+
+// Engine rewrites the files in a repository archive.
+type Engine interface {
+	// Rewrite runs spec against the archive zf, which is stored on disk at
+	// zipPath. It calls onRewrite with the new content of every file
+	// which changed. Calls to onRewrite are not concurrent.
+	Rewrite(ctx context.Context, spec *protocol.RewriteSpecification, zipPath string, zf *store.ZipFile, onRewrite func(path string, content []byte) error) error
+}
+
+// newEngine returns the engine described by spec. It returns a bad request
+// error if spec is invalid for the engine.
+func newEngine(spec *protocol.RewriteSpecification) (Engine, error) {
+	switch spec.Engine {
+	case "", protocol.EngineComby:
+		return &ExternalTool{Name: "comby", BinaryPath: "comby"}, nil
+
+	case protocol.EngineRegexp:
+		re, err := regexp.Compile(spec.MatchTemplate)
+		if err != nil {
+			return nil, badRequestError{err.Error()}
+		}
+		return &regexpEngine{re: re}, nil
+
+	case protocol.EngineLiteral:
+		return &regexpEngine{re: regexp.MustCompile(regexp.QuoteMeta(spec.MatchTemplate)), literal: true}, nil
+
+	default:
+		return nil, badRequestError{"unknown rewrite engine " + spec.Engine}
+	}
+}
+
+// regexpEngine is an Engine which is implemented in Go. It replaces all
+// matches of re.
+type regexpEngine struct {
+	re *regexp.Regexp
+
+	// literal if true will use the rewrite template as is instead of
+	// expanding submatch references like $1.
+	literal bool
+}
+
+func (e *regexpEngine) Rewrite(ctx context.Context, spec *protocol.RewriteSpecification, zipPath string, zf *store.ZipFile, onRewrite func(path string, content []byte) error) error {
+	repl := []byte(spec.RewriteTemplate)
+	for i := range zf.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		f := &zf.Files[i]
+		if !strings.HasSuffix(f.Name, spec.FileExtension) {
+			continue
+		}
+		data := zf.DataFor(f)
+		if isBinary(data) || !e.re.Match(data) {
+			continue
+		}
+
+		var content []byte
+		if e.literal {
+			content = e.re.ReplaceAllLiteral(data, repl)
+		} else {
+			content = e.re.ReplaceAll(data, repl)
+		}
+		if bytes.Equal(content, data) {
+			continue
+		}
+		if err := onRewrite(f.Name, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isBinary is a heuristic which returns true if data looks like the content
+// of a binary file.
+func isBinary(data []byte) bool {
+	if len(data) > 512 {
+		data = data[:512]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// command configures the command line options and returns the command to
+// execute using an external tool.
+func (t *ExternalTool) command(ctx context.Context, spec *protocol.RewriteSpecification, zipPath string) (cmd *exec.Cmd, err error) {
+	switch t.Name {
+	case "comby":
+		return exec.CommandContext(ctx, t.BinaryPath, spec.MatchTemplate, spec.RewriteTemplate, spec.FileExtension, "-zip", zipPath, "-json-lines"), nil
+	default:
+		return nil, errors.Errorf("Unknown external replace tool %q", t.Name)
+	}
+}
+
+// Rewrite runs the external tool and parses the JSON lines it writes to
+// stdout.
+func (t *ExternalTool) Rewrite(ctx context.Context, spec *protocol.RewriteSpecification, zipPath string, zf *store.ZipFile, onRewrite func(path string, content []byte) error) error {
+	cmd, err := t.command(ctx, spec, zipPath)
+	if err != nil {
+		return err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "could not connect to command stdout")
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "error starting command")
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	// Each line contains a whole rewritten file.
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r protocol.FileResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return errors.Wrapf(err, "invalid %s output", t.Name)
+		}
+		if err := onRewrite(r.Path, []byte(r.Content)); err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return errors.Wrapf(err, "error reading %s output", t.Name)
+	}
+
+	return errors.Wrapf(cmd.Wait(), "error after executing %s", t.Name)
+}
+
+// maxFileSize is the largest JSON line we accept from an external tool.
+const maxFileSize = 100 << 20
+
+type badRequestError struct{ msg string }
+
+func (e badRequestError) Error() string    { return e.msg }
+func (e badRequestError) BadRequest() bool { return true }
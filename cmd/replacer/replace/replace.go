@@ -9,19 +9,22 @@
 // * On disk cache of fetched archives to reduce load on gitserver
 //
 // - Here is where replacer.go differs
-// * Pass the zip file path to external replacer tool(s) after validating
-// * Read tool stdout and write it out on the HTTP connection
-// * Input from stdout is expected to use JSON lines format, but the format isn't checked here: line-buffering is done on the frontend
+// * Rewrite the archive with an Engine: either an external tool like comby
+//   which is passed the zip file path, or a built-in Go regexp/literal engine
+// * Results are written out on the HTTP connection as JSON lines, one
+//   protocol.FileResult per rewritten file followed by a final
+//   protocol.StreamDone line which reports a deadline hit or an error
+// * In diff mode a unified diff is generated and validated per file instead
+//   of sending the whole rewritten file
 
 package replace
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"os/exec"
 	"time"
 
 	"golang.org/x/net/trace"
@@ -32,6 +35,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/sourcegraph/cmd/replacer/protocol"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	gitprotocol "github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/store"
 	"gopkg.in/inconshreveable/log15.v2"
 
@@ -41,23 +46,19 @@ import (
 type Service struct {
 	Store *store.Store
 	Log   log15.Logger
+
+	// CreateCommitFromPatch creates a commit on gitserver and returns its
+	// ID. It is used to serve apply requests. See
+	// (gitserver.Client).CreateCommitFromPatch.
+	CreateCommitFromPatch func(ctx context.Context, req gitprotocol.CreateCommitFromPatchRequest) (api.CommitID, error)
 }
 
+// ExternalTool is an Engine which runs an external replace tool.
 type ExternalTool struct {
 	Name       string
 	BinaryPath string
 }
 
-// Configure the command line options and return the command to execute using an external tool
-func (t *ExternalTool) command(spec *protocol.RewriteSpecification, zipPath string) (cmd *exec.Cmd, err error) {
-	switch t.Name {
-	case "comby":
-		return exec.Command(t.BinaryPath, spec.MatchTemplate, spec.RewriteTemplate, spec.FileExtension, "-zip", zipPath, "-json-lines"), nil
-	default:
-		return nil, errors.Errorf("Unknown external replace tool %q", t.Name)
-	}
-}
-
 var decoder = schema.NewDecoder()
 
 func init() {
@@ -66,6 +67,11 @@ func init() {
 
 // ServeHTTP handles HTTP based replace requests
 func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/apply" {
+		s.serveApply(w, r)
+		return
+	}
+
 	ctx := r.Context()
 	running.Inc()
 	defer running.Dec()
@@ -89,29 +95,62 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	deadlineHit, err := s.replace(ctx, &p, w, r)
-	if err != nil {
-		code := http.StatusInternalServerError
-		if isBadRequest(err) || ctx.Err() == context.Canceled {
-			code = http.StatusBadRequest
-		} else if isTemporary(err) {
-			code = http.StatusServiceUnavailable
-		} else {
-			log.Printf("internal error serving %#+v: %s", p, err)
+	var (
+		enc     = json.NewEncoder(w)
+		started = false
+	)
+	start := func() {
+		w.Header().Set("Transfer-Encoding", "chunked")
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+	deadlineHit, err := s.replace(ctx, &p, func(r *protocol.FileResult) error {
+		if !started {
+			start()
 		}
-		http.Error(w, err.Error(), code)
+		return enc.Encode(r)
+	})
+	if err != nil && !started {
+		http.Error(w, err.Error(), errorStatusCode(ctx, &p, err))
 		return
 	}
-
-	if deadlineHit {
+	if deadlineHit && !started {
 		log15.Info("Deadline hit")
 		http.Error(w, "Deadline hit", http.StatusRequestTimeout)
 		return
 	}
 
+	// We may have already written the header, so end the stream with a
+	// line telling the client whether it received all results.
+	if !started {
+		start()
+	}
+	done := protocol.StreamDone{Done: true, DeadlineHit: deadlineHit}
+	if err != nil {
+		log15.Error("Error rewriting files", "repo", p.Repo, "commit", p.Commit, "error", err)
+		done.Error = err.Error()
+	}
+	// The client may have gone away, in which case there is nobody left to
+	// tell about a failed write.
+	_ = enc.Encode(&done)
+}
+
+// errorStatusCode returns the HTTP status code to respond with when the
+// replace request p failed with err.
+func errorStatusCode(ctx context.Context, p *protocol.Request, err error) int {
+	if isBadRequest(err) || ctx.Err() == context.Canceled {
+		return http.StatusBadRequest
+	}
+	if isTemporary(err) {
+		return http.StatusServiceUnavailable
+	}
+	log.Printf("internal error serving %#+v: %s", *p, err)
+	return http.StatusInternalServerError
 }
 
-func (s *Service) replace(ctx context.Context, p *protocol.Request, w http.ResponseWriter, r *http.Request) (deadlineHit bool, err error) {
+// replace rewrites the files of p.Repo at p.Commit according to
+// p.RewriteSpecification and calls onResult for every rewritten file.
+func (s *Service) replace(ctx context.Context, p *protocol.Request, onResult func(*protocol.FileResult) error) (deadlineHit bool, err error) {
 	tr := trace.New("replace", fmt.Sprintf("%s@%s", p.Repo, p.Commit))
 	tr.LazyPrintf("%s", p.RewriteSpecification)
 
@@ -121,6 +160,7 @@ func (s *Service) replace(ctx context.Context, p *protocol.Request, w http.Respo
 	span.SetTag("url", p.URL)
 	span.SetTag("commit", p.Commit)
 	span.SetTag("rewriteSpecification", p.RewriteSpecification)
+	span.SetTag("diff", p.Diff)
 	defer func(start time.Time) {
 		code := "200"
 		// We often have canceled and timed out requests. We do not want to
@@ -191,41 +231,42 @@ func (s *Service) replace(ctx context.Context, p *protocol.Request, w http.Respo
 	archiveFiles.Observe(float64(nFiles))
 	archiveSize.Observe(float64(bytes))
 
-	w.Header().Set("Transfer-Encoding", "chunked")
-	w.WriteHeader(http.StatusOK)
-
-	t := &ExternalTool{
-		Name:       "comby",
-		BinaryPath: "comby",
-	}
-
-	cmd, err := t.command(&p.RewriteSpecification, zipPath)
-
-	if err != nil {
-		log15.Info("Invalid command: " + err.Error())
-		return
-	}
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		log15.Info("Could not connect to command stdout: " + err.Error())
-		return
-	}
-
-	if err := cmd.Start(); err != nil {
-		log15.Info("Error starting command: " + err.Error())
-	}
-
-	_, err = io.Copy(w, stdout)
+	engine, err := newEngine(&p.RewriteSpecification)
 	if err != nil {
-		log15.Info("Error copying external command output to HTTP writer: " + err.Error())
+		return false, err
 	}
 
-	if err := cmd.Wait(); err != nil {
-		log15.Info("Error after executing command: " + err.Error())
+	// In diff mode we need the original content of rewritten files.
+	var files map[string]*store.SrcFile
+	if p.Diff {
+		files = make(map[string]*store.SrcFile, len(zf.Files))
+		for i := range zf.Files {
+			files[zf.Files[i].Name] = &zf.Files[i]
+		}
 	}
 
-	return false, nil
+	rewrites := 0
+	err = engine.Rewrite(ctx, &p.RewriteSpecification, zipPath, zf, func(path string, content []byte) error {
+		rewrites++
+		r := protocol.FileResult{Path: path}
+		if p.Diff {
+			f, ok := files[path]
+			if !ok {
+				return errors.Errorf("rewritten file %q is not in the archive", path)
+			}
+			d, err := unifiedDiff(path, string(zf.DataFor(f)), string(content))
+			if err != nil {
+				return err
+			}
+			r.Diff = d
+		} else {
+			r.Content = string(content)
+		}
+		return onResult(&r)
+	})
+	tr.LazyPrintf("rewrites=%d", rewrites)
+	span.LogFields(otlog.Int("rewrites", rewrites))
+	return false, err
 }
 
 func validateParams(p *protocol.Request) error {
@@ -240,6 +281,9 @@ func validateParams(p *protocol.Request) error {
 	if p.RewriteSpecification.MatchTemplate == "" {
 		return errors.New("MatchTemplate must be non-empty")
 	}
+	if _, err := newEngine(&p.RewriteSpecification); err != nil {
+		return err
+	}
 	return nil
 }
 
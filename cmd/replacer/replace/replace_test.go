@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/replacer/replace"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+	gitprotocol "github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/store"
 )
 
@@ -91,6 +94,204 @@ func main() {
 
 }
 
+func TestReplace_engines(t *testing.T) {
+	files := map[string]string{
+		"README.md": `# Hello World
+
+Hello world example in go`,
+		"main.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello foo")
+	fmt.Println("Hello bar")
+}
+`,
+		"bin.go": "foo\x00",
+	}
+
+	cases := []struct {
+		name string
+		diff bool
+		arg  protocol.RewriteSpecification
+		want string
+	}{
+		{
+			name: "literal",
+			arg: protocol.RewriteSpecification{
+				MatchTemplate:   "Hello $1",
+				RewriteTemplate: "Bye $1",
+				Engine:          protocol.EngineLiteral,
+			},
+			want: `
+{"done":true}
+`,
+		},
+		{
+			name: "regexp",
+			arg: protocol.RewriteSpecification{
+				MatchTemplate:   `Hello (\w+)"`,
+				RewriteTemplate: `Bye ${1}s"`,
+				FileExtension:   ".go",
+				Engine:          protocol.EngineRegexp,
+			},
+			want: `
+{"uri":"main.go","rewritten_source":"package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Bye foos\")\n\tfmt.Println(\"Bye bars\")\n}\n"}
+{"done":true}
+`,
+		},
+		{
+			name: "diff",
+			diff: true,
+			arg: protocol.RewriteSpecification{
+				MatchTemplate:   "bar",
+				RewriteTemplate: "baz",
+				Engine:          protocol.EngineLiteral,
+			},
+			want: `
+{"uri":"main.go","diff":"--- a/main.go\n+++ b/main.go\n@@ -4,5 +4,5 @@\n \n func main() {\n \tfmt.Println(\"Hello foo\")\n-\tfmt.Println(\"Hello bar\")\n+\tfmt.Println(\"Hello baz\")\n }\n"}
+{"done":true}
+`,
+		},
+		{
+			name: "diff without trailing newline",
+			diff: true,
+			arg: protocol.RewriteSpecification{
+				MatchTemplate:   "go$",
+				RewriteTemplate: "Go",
+				FileExtension:   ".md",
+				Engine:          protocol.EngineRegexp,
+			},
+			want: `
+{"uri":"README.md","diff":"--- a/README.md\n+++ b/README.md\n@@ -1,3 +1,3 @@\n # Hello World\n \n-Hello world example in go\n\\ No newline at end of file\n+Hello world example in Go\n\\ No newline at end of file\n"}
+{"done":true}
+`,
+		},
+	}
+
+	store, cleanup, err := newStore(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	ts := httptest.NewServer(&replace.Service{Store: store})
+	defer ts.Close()
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			req := protocol.Request{
+				Repo:                 "foo",
+				URL:                  "u",
+				Commit:               "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+				RewriteSpecification: test.arg,
+				FetchTimeout:         "500ms",
+				Diff:                 test.diff,
+			}
+			got, err := doReplace(ts.URL, &req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// We have an extra newline to make expected readable
+			if len(test.want) > 0 {
+				test.want = test.want[1:]
+			}
+
+			if got != test.want {
+				d, err := diff(test.want, got)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Errorf("unexpected response:\n%s", d)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	files := map[string]string{
+		"a.go": "package a\n\nvar foo = 1\n",
+		"b.go": "package b\n\nvar bar = 1\n",
+	}
+
+	store, cleanup, err := newStore(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	var got gitprotocol.CreateCommitFromPatchRequest
+	ts := httptest.NewServer(&replace.Service{
+		Store: store,
+		CreateCommitFromPatch: func(ctx context.Context, req gitprotocol.CreateCommitFromPatchRequest) (api.CommitID, error) {
+			got = req
+			return "cafecafecafecafecafecafecafecafecafecafe", nil
+		},
+	})
+	defer ts.Close()
+
+	body, err := json.Marshal(&protocol.ApplyRequest{
+		Request: protocol.Request{
+			Repo:   "foo",
+			URL:    "u",
+			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			RewriteSpecification: protocol.RewriteSpecification{
+				MatchTemplate:   "foo",
+				RewriteTemplate: "baz",
+				Engine:          protocol.EngineLiteral,
+			},
+			FetchTimeout: "500ms",
+		},
+		TargetRef: "refs/sourcegraph/rewrites/test",
+		Message:   "Rename foo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(ts.URL+"/apply", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("non-200 response: code=%d body=%s", resp.StatusCode, string(b))
+	}
+	var ar protocol.ApplyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		t.Fatal(err)
+	}
+
+	want := protocol.ApplyResponse{
+		Commit: "cafecafecafecafecafecafecafecafecafecafe",
+		Ref:    "refs/sourcegraph/rewrites/test",
+		Files:  []protocol.FileSummary{{Path: "a.go", Added: 1, Deleted: 1}},
+	}
+	if !reflect.DeepEqual(ar, want) {
+		t.Errorf("got response %+v, want %+v", ar, want)
+	}
+
+	wantPatch := `--- a/a.go
++++ b/a.go
+@@ -1,3 +1,3 @@
+ package a
+ 
+-var foo = 1
++var baz = 1
+`
+	if got.Patch != wantPatch {
+		d, err := diff(wantPatch, got.Patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Errorf("unexpected patch:\n%s", d)
+	}
+	if got.BaseCommit != "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef" || got.TargetRef != "refs/sourcegraph/rewrites/test" || got.CommitInfo.Message != "Rename foo" {
+		t.Errorf("unexpected create commit request %+v", got)
+	}
+}
+
 func TestReplace_badrequest(t *testing.T) {
 	cases := []protocol.Request{
 		{
@@ -99,6 +300,24 @@ func TestReplace_badrequest(t *testing.T) {
 			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
 			// No MatchTemplate
 		},
+		{
+			Repo:   "foo",
+			URL:    "u",
+			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			RewriteSpecification: protocol.RewriteSpecification{
+				MatchTemplate: "foo",
+				Engine:        "sed",
+			},
+		},
+		{
+			Repo:   "foo",
+			URL:    "u",
+			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			RewriteSpecification: protocol.RewriteSpecification{
+				MatchTemplate: `\F`,
+				Engine:        protocol.EngineRegexp,
+			},
+		},
 	}
 
 	store, cleanup, err := newStore(nil)
@@ -129,6 +348,10 @@ func doReplace(u string, p *protocol.Request) (string, error) {
 		"MatchTemplate":   []string{p.RewriteSpecification.MatchTemplate},
 		"RewriteTemplate": []string{p.RewriteSpecification.RewriteTemplate},
 		"FileExtension":   []string{p.RewriteSpecification.FileExtension},
+		"Engine":          []string{p.RewriteSpecification.Engine},
+	}
+	if p.Diff {
+		form.Set("Diff", "true")
 	}
 	resp, err := http.PostForm(u, form)
 	if err != nil {
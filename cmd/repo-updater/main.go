@@ -63,6 +63,8 @@ func main() {
 		log.Fatalf("failed to initialize db store: %v", err)
 	}
 
+	dbStore := repos.NewDBStore(ctx, db, sql.TxOptions{Isolation: sql.LevelSerializable})
+
 	var store repos.Store
 	{
 		m := repos.NewStoreMetrics()
@@ -78,7 +80,7 @@ func main() {
 		}
 
 		store = repos.NewObservedStore(
-			repos.NewDBStore(ctx, db, sql.TxOptions{Isolation: sql.LevelSerializable}),
+			dbStore,
 			log15.Root(),
 			m,
 			trace.Tracer{Tracer: opentracing.GlobalTracer()},
@@ -143,6 +145,7 @@ func main() {
 	}
 
 	gps := repos.NewGitolitePhabricatorMetadataSyncer(store)
+	rgs := repos.NewRepoGroupSyncer(store, dbStore)
 
 	// Start new repo syncer updates scheduler relay thread.
 	go func() {
@@ -170,6 +173,12 @@ func main() {
 						log15.Error("GitolitePhabricatorMetadataSyncer", "error", err)
 					}
 				}()
+
+				go func() {
+					if err := rgs.Sync(ctx); err != nil {
+						log15.Error("RepoGroupSyncer", "error", err)
+					}
+				}()
 			}
 		}
 	}()
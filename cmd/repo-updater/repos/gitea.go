@@ -0,0 +1,279 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// A GiteaSource yields repositories from a single Gitea connection configured
+// in Sourcegraph via the external services configuration.
+type GiteaSource struct {
+	svc             *ExternalService
+	config          *schema.GiteaConnection
+	exclude         map[string]bool
+	excludePatterns []*regexp.Regexp
+	baseURL         *url.URL
+	client          *gitea.Client
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(svc *ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGiteaSource(svc, &c, cf)
+}
+
+func newGiteaSource(svc *ExternalService, c *schema.GiteaConnection, cf *httpcli.Factory) (*GiteaSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = NewHTTPClientFactory()
+	}
+
+	var opts []httpcli.Opt
+	if c.Certificate != "" {
+		pool, err := newCertPool(c.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, httpcli.NewCertPoolOpt(pool))
+	}
+
+	cli, err := cf.Doer(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[string]bool, len(c.Exclude))
+	var excludePatterns []*regexp.Regexp
+	for _, r := range c.Exclude {
+		if r.Name != "" {
+			exclude[strings.ToLower(r.Name)] = true
+		}
+
+		if r.Id != 0 {
+			exclude[strconv.Itoa(r.Id)] = true
+		}
+
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			excludePatterns = append(excludePatterns, re)
+		}
+	}
+
+	client := gitea.NewClient(baseURL, cli)
+	client.Token = c.Token
+
+	return &GiteaSource{
+		svc:             svc,
+		config:          c,
+		exclude:         exclude,
+		excludePatterns: excludePatterns,
+		baseURL:         baseURL,
+		client:          client,
+	}, nil
+}
+
+// ListRepos returns all Gitea repositories accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GiteaSource) ListRepos(ctx context.Context) (repos []*Repo, err error) {
+	rs, err := s.listAllRepos(ctx)
+	for _, r := range rs {
+		repos = append(repos, s.makeRepo(r))
+	}
+	return repos, err
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GiteaSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s GiteaSource) makeRepo(r *gitea.Repo) *Repo {
+	urn := s.svc.URN()
+	return &Repo{
+		Name: string(reposource.GiteaRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			r.FullName,
+		)),
+		URI: string(reposource.GiteaRepoName(
+			"",
+			s.baseURL.Hostname(),
+			r.FullName,
+		)),
+		ExternalRepo: *gitea.ExternalRepoSpec(r, *s.baseURL),
+		Description:  r.Description,
+		Fork:         r.Fork,
+		Enabled:      true,
+		Archived:     r.Archived,
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.authenticatedRemoteURL(r),
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// authenticatedRemoteURL returns the repository's Git remote URL with the configured
+// Gitea access token inserted in the URL userinfo.
+func (s *GiteaSource) authenticatedRemoteURL(r *gitea.Repo) string {
+	if s.config.GitURLType == "ssh" {
+		return r.SSHURL
+	}
+
+	u, err := url.Parse(r.CloneURL)
+	if err != nil {
+		log15.Warn("Error adding authentication to Gitea repository Git remote URL.", "url", r.CloneURL, "error", err)
+		return r.CloneURL
+	}
+	u.User = url.User(s.config.Token)
+	return u.String()
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repo) bool {
+	if s.exclude[strings.ToLower(r.FullName)] || s.exclude[strconv.Itoa(r.ID)] {
+		return true
+	}
+
+	for _, re := range s.excludePatterns {
+		if re.MatchString(r.FullName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *GiteaSource) listAllRepos(ctx context.Context) ([]*gitea.Repo, error) {
+	type batch struct {
+		repos []*gitea.Repo
+		err   error
+	}
+
+	ch := make(chan batch)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		repos := make([]*gitea.Repo, 0, len(s.config.Repos))
+		errs := new(multierror.Error)
+
+		for _, name := range s.config.Repos {
+			ps := strings.SplitN(name, "/", 2)
+			if len(ps) != 2 {
+				errs = multierror.Append(errs,
+					errors.Errorf("gitea.repos: name=%q", name))
+				continue
+			}
+
+			repo, err := s.client.Repo(ctx, ps[0], ps[1])
+			if err != nil {
+				if gitea.IsNotFound(err) {
+					log15.Warn("skipping missing gitea.repos entry:", "name", name, "err", err)
+					continue
+				}
+				errs = multierror.Append(errs,
+					errors.Wrapf(err, "gitea.repos: name: %q", name))
+			} else {
+				repos = append(repos, repo)
+			}
+		}
+
+		ch <- batch{repos: repos, err: errs.ErrorOrNil()}
+	}()
+
+	for _, q := range s.config.RepositoryQuery {
+		if q == "none" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(q string) {
+			defer wg.Done()
+
+			list := func(page int) ([]*gitea.Repo, error) {
+				if q == "affiliated" {
+					return s.client.AffiliatedRepos(ctx, page)
+				}
+				return s.client.SearchRepos(ctx, page, q)
+			}
+
+			// Gogs doesn't paginate some listings and returns all
+			// repositories for every page, so we also stop when a page
+			// doesn't contain any new repositories.
+			seen := make(map[int]bool)
+			for page := 1; ; page++ {
+				repos, err := list(page)
+				if err != nil {
+					ch <- batch{err: errors.Wrapf(err, "gitea.repositoryQuery: item=%q, page=%d", q, page)}
+					break
+				}
+
+				var fresh []*gitea.Repo
+				for _, r := range repos {
+					if !seen[r.ID] {
+						seen[r.ID] = true
+						fresh = append(fresh, r)
+					}
+				}
+				ch <- batch{repos: fresh}
+
+				if len(repos) < gitea.PageSize || len(fresh) == 0 {
+					break
+				}
+			}
+		}(q)
+	}
+
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	seen := make(map[int]bool)
+	errs := new(multierror.Error)
+	var repos []*gitea.Repo
+
+	for r := range ch {
+		if r.err != nil {
+			errs = multierror.Append(errs, r.err)
+		}
+
+		for _, repo := range r.repos {
+			if !seen[repo.ID] && !s.excludes(repo) {
+				repos = append(repos, repo)
+				seen[repo.ID] = true
+			}
+		}
+	}
+
+	return repos, errs.ErrorOrNil()
+}
@@ -0,0 +1,183 @@
+package repos
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGiteaSource_MakeRepo(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "gitea-repos.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repos []*gitea.Repo
+	if err := json.Unmarshal(b, &repos); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]*schema.GiteaConnection{
+		"simple": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+		},
+		"ssh": {
+			Url:        "https://gitea.example.com",
+			Token:      "secret",
+			GitURLType: "ssh",
+		},
+		"path-pattern": {
+			Url:                   "https://gitea.example.com",
+			Token:                 "secret",
+			RepositoryPathPattern: "gt/{nameWithOwner}",
+		},
+	}
+
+	svc := ExternalService{ID: 1, Kind: "GITEA"}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newGiteaSource(&svc, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*Repo
+			for _, r := range repos {
+				got = append(got, s.makeRepo(r))
+			}
+			actual, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "gitea-repos-"+name+".golden")
+			if update(name) {
+				err := ioutil.WriteFile(golden, actual, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expect, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(actual, expect) {
+				d, err := diff(actual, expect)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func TestGiteaSource_Exclude(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "gitea-repos.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var repos []*gitea.Repo
+	if err := json.Unmarshal(b, &repos); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]*schema.GiteaConnection{
+		"none": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+		},
+		"name": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{{
+				Name: "sourcegraph/python-langserver",
+			}, {
+				// Names are matched case insensitively.
+				Name: "Keegan/RGP",
+			}},
+		},
+		"id": {
+			Url:     "https://gitea.example.com",
+			Token:   "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{{Id: 4}},
+		},
+		"pattern": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			Exclude: []*schema.ExcludedGiteaRepo{{
+				Pattern: "^sourcegraph/python.*",
+			}, {
+				Pattern: "^keegan/",
+			}},
+		},
+		"both": {
+			Url:   "https://gitea.example.com",
+			Token: "secret",
+			// We match on the Gitea repo name, not the repository path pattern.
+			RepositoryPathPattern: "gt/{nameWithOwner}",
+			Exclude: []*schema.ExcludedGiteaRepo{{
+				Id: 1,
+			}, {
+				Name: "keegan/rgp",
+			}, {
+				Pattern: "-fork$",
+			}},
+		},
+	}
+
+	svc := ExternalService{ID: 1, Kind: "GITEA"}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newGiteaSource(&svc, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type output struct {
+				Include []string
+				Exclude []string
+			}
+			var got output
+			for _, r := range repos {
+				if s.excludes(r) {
+					got.Exclude = append(got.Exclude, r.FullName)
+				} else {
+					got.Include = append(got.Include, r.FullName)
+				}
+			}
+			actual, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "gitea-repos-exclude-"+name+".golden")
+			if update(name) {
+				err := ioutil.WriteFile(golden, actual, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expect, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(actual, expect) {
+				d, err := diff(actual, expect)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Error(d)
+			}
+		})
+	}
+}
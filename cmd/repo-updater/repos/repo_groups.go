@@ -0,0 +1,286 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"golang.org/x/sync/semaphore"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// A RepoGroup is a dynamic repository group, whose members are determined by
+// its rule. Repository groups are created by site admins in the frontend.
+type RepoGroup struct {
+	ID   int64
+	Name string
+	Rule api.RepoGroupRule
+}
+
+// A RepoGroupStore lists dynamic repository groups and stores their members.
+type RepoGroupStore interface {
+	ListDynamicRepoGroups(context.Context) ([]*RepoGroup, error)
+	SetRepoGroupMembers(ctx context.Context, id int64, repoIDs []uint32) error
+}
+
+// ListDynamicRepoGroups lists all repository groups that have a rule.
+func (s DBStore) ListDynamicRepoGroups(ctx context.Context) (groups []*RepoGroup, err error) {
+	q := sqlf.Sprintf(listDynamicRepoGroupsQuery)
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, err = scanAll(rows, func(sc scanner) (last, count int64, err error) {
+		var (
+			g    RepoGroup
+			rule json.RawMessage
+		)
+		if err = sc.Scan(&g.ID, &g.Name, &rule); err != nil {
+			return 0, 0, err
+		}
+		if err = json.Unmarshal(rule, &g.Rule); err != nil {
+			return 0, 0, errors.Wrapf(err, "repo group %q has an invalid rule", g.Name)
+		}
+		groups = append(groups, &g)
+		return g.ID, 1, nil
+	})
+
+	return groups, err
+}
+
+const listDynamicRepoGroupsQuery = `
+-- source: cmd/repo-updater/repos/repo_groups.go:DBStore.ListDynamicRepoGroups
+SELECT id, name, rule
+FROM repo_groups
+WHERE rule IS NOT NULL
+AND deleted_at IS NULL
+ORDER BY id ASC
+`
+
+// SetRepoGroupMembers replaces the members of the repository group with the
+// given ID.
+func (s DBStore) SetRepoGroupMembers(ctx context.Context, id int64, repoIDs []uint32) (err error) {
+	ids := make([]int64, len(repoIDs))
+	for i, repoID := range repoIDs {
+		ids[i] = int64(repoID)
+	}
+
+	q := sqlf.Sprintf(setRepoGroupMembersQuery, id, pq.Array(ids), id, pq.Array(ids))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	defer closeErr(rows, &err)
+	return rows.Err()
+}
+
+const setRepoGroupMembersQuery = `
+-- source: cmd/repo-updater/repos/repo_groups.go:DBStore.SetRepoGroupMembers
+WITH deleted AS (
+  DELETE FROM repo_group_members
+  WHERE repo_group_id = %s
+  AND NOT (repo_id = ANY(%s::integer[]))
+), inserted AS (
+  INSERT INTO repo_group_members (repo_group_id, repo_id)
+  SELECT %s, UNNEST(%s::integer[])
+  ON CONFLICT DO NOTHING
+)
+SELECT 1
+`
+
+// A RepoGroupSyncer re-evaluates the rules of dynamic repository groups
+// against the stored repos. It should run after each Syncer run.
+type RepoGroupSyncer struct {
+	sem    *semaphore.Weighted
+	repos  Store
+	groups RepoGroupStore
+}
+
+// NewRepoGroupSyncer returns a RepoGroupSyncer that reads repos from the
+// given Store and writes group members to the given RepoGroupStore.
+func NewRepoGroupSyncer(repos Store, groups RepoGroupStore) *RepoGroupSyncer {
+	return &RepoGroupSyncer{
+		sem:    semaphore.NewWeighted(1),
+		repos:  repos,
+		groups: groups,
+	}
+}
+
+// Sync recomputes the members of all dynamic repository groups.
+func (s *RepoGroupSyncer) Sync(ctx context.Context) error {
+	if !s.sem.TryAcquire(1) {
+		log15.Info("existing repo group sync still running, skipping")
+		return nil
+	}
+	defer s.sem.Release(1)
+
+	groups, err := s.groups.ListDynamicRepoGroups(ctx)
+	if err != nil {
+		return errors.Wrap(err, "repogroups.list-groups")
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	repos, err := s.repos.ListRepos(ctx, StoreListReposArgs{})
+	if err != nil {
+		return errors.Wrap(err, "repogroups.list-repos")
+	}
+
+	for _, g := range groups {
+		m, err := newRepoGroupMatcher(&g.Rule)
+		if err != nil {
+			log15.Error("repogroups: skipping group with invalid rule", "group", g.Name, "error", err)
+			continue
+		}
+
+		ids := []uint32{}
+		for _, r := range repos {
+			if m.match(r) {
+				ids = append(ids, r.ID)
+			}
+		}
+
+		if err := s.groups.SetRepoGroupMembers(ctx, g.ID, ids); err != nil {
+			return errors.Wrapf(err, "repogroups.set-members %q", g.Name)
+		}
+	}
+
+	return nil
+}
+
+// repoGroupMatcher matches repos against a compiled api.RepoGroupRule.
+type repoGroupMatcher struct {
+	name   *regexp.Regexp
+	svcs   map[int64]bool
+	topics map[string]bool // lowercase
+	owners map[string]bool // lowercase
+}
+
+func newRepoGroupMatcher(rule *api.RepoGroupRule) (*repoGroupMatcher, error) {
+	var m repoGroupMatcher
+
+	if rule.NamePattern != "" {
+		var err error
+		if m.name, err = regexp.Compile(rule.NamePattern); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rule.ExternalServiceIDs) > 0 {
+		m.svcs = make(map[int64]bool, len(rule.ExternalServiceIDs))
+		for _, id := range rule.ExternalServiceIDs {
+			m.svcs[id] = true
+		}
+	}
+
+	m.topics = lowercaseSet(rule.Topics)
+	m.owners = lowercaseSet(rule.Owners)
+
+	return &m, nil
+}
+
+func lowercaseSet(ss []string) map[string]bool {
+	if len(ss) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		set[strings.ToLower(s)] = true
+	}
+	return set
+}
+
+// match reports whether r satisfies all the criteria of the rule.
+func (m *repoGroupMatcher) match(r *Repo) bool {
+	if m.name != nil && !m.name.MatchString(r.Name) {
+		return false
+	}
+
+	if m.svcs != nil {
+		found := false
+		for _, id := range r.ExternalServiceIDs() {
+			if m.svcs[id] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if m.topics != nil {
+		found := false
+		for _, topic := range repoTopics(r) {
+			if m.topics[strings.ToLower(topic)] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if m.owners != nil {
+		owner := repoOwner(r)
+		if owner == "" || !m.owners[strings.ToLower(owner)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// repoTopics returns the topics of r on its code host, if the code host
+// supports them.
+func repoTopics(r *Repo) []string {
+	switch m := r.Metadata.(type) {
+	case *github.Repository:
+		return m.Topics
+	case *gitlab.Project:
+		return m.TagList
+	default:
+		return nil
+	}
+}
+
+// repoOwner returns the user, organization, group or project that owns r on
+// its code host, or the empty string if the code host has no such concept.
+func repoOwner(r *Repo) string {
+	switch m := r.Metadata.(type) {
+	case *github.Repository:
+		return namespace(m.NameWithOwner)
+	case *gitlab.Project:
+		return namespace(m.PathWithNamespace)
+	case *bitbucketserver.Repo:
+		if m.Project != nil {
+			return m.Project.Key
+		}
+	case *gitea.Repo:
+		if m.Owner != nil {
+			return m.Owner.Login
+		}
+	}
+	return ""
+}
+
+// namespace returns the part of a full repository name (such as "owner/name"
+// or "group/subgroup/name") before the last slash.
+func namespace(fullName string) string {
+	if i := strings.LastIndex(fullName, "/"); i >= 0 {
+		return fullName[:i]
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package repos_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+)
+
+type fakeRepoGroupStore struct {
+	groups  []*repos.RepoGroup
+	members map[int64][]uint32
+}
+
+func (s *fakeRepoGroupStore) ListDynamicRepoGroups(context.Context) ([]*repos.RepoGroup, error) {
+	return s.groups, nil
+}
+
+func (s *fakeRepoGroupStore) SetRepoGroupMembers(_ context.Context, id int64, repoIDs []uint32) error {
+	s.members[id] = repoIDs
+	return nil
+}
+
+func TestRepoGroupSyncer(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(repos.FakeStore)
+	stored := repos.Repos{
+		{
+			Name:     "github.com/foo/bar",
+			Sources:  map[string]*repos.SourceInfo{"extsvc:github:1": {ID: "extsvc:github:1"}},
+			Metadata: &github.Repository{NameWithOwner: "foo/bar", Topics: []string{"Go", "search"}},
+		},
+		{
+			Name:     "github.com/baz/qux",
+			Sources:  map[string]*repos.SourceInfo{"extsvc:github:1": {ID: "extsvc:github:1"}},
+			Metadata: &github.Repository{NameWithOwner: "baz/qux"},
+		},
+		{
+			Name:     "gitlab.com/foo/sub/bar",
+			Sources:  map[string]*repos.SourceInfo{"extsvc:gitlab:2": {ID: "extsvc:gitlab:2"}},
+			Metadata: &gitlab.Project{ProjectCommon: gitlab.ProjectCommon{PathWithNamespace: "foo/sub/bar"}, TagList: []string{"go"}},
+		},
+		{
+			Name:     "bitbucket.example.com/FOO/bar",
+			Sources:  map[string]*repos.SourceInfo{"extsvc:bitbucketserver:3": {ID: "extsvc:bitbucketserver:3"}},
+			Metadata: &bitbucketserver.Repo{Slug: "bar", Project: &bitbucketserver.Project{Key: "FOO"}},
+		},
+	}
+	if err := store.UpsertRepos(ctx, stored...); err != nil {
+		t.Fatal(err)
+	}
+
+	groups := &fakeRepoGroupStore{
+		groups: []*repos.RepoGroup{
+			{ID: 1, Name: "name", Rule: api.RepoGroupRule{NamePattern: "/bar$"}},
+			{ID: 2, Name: "external-service", Rule: api.RepoGroupRule{ExternalServiceIDs: []int64{1, 3}}},
+			{ID: 3, Name: "topic", Rule: api.RepoGroupRule{Topics: []string{"go"}}},
+			{ID: 4, Name: "owner", Rule: api.RepoGroupRule{Owners: []string{"foo"}}},
+			{ID: 5, Name: "all-criteria", Rule: api.RepoGroupRule{NamePattern: "^github", Topics: []string{"search"}, Owners: []string{"foo"}}},
+			{ID: 6, Name: "invalid", Rule: api.RepoGroupRule{NamePattern: "("}},
+		},
+		members: map[int64][]uint32{},
+	}
+
+	if err := repos.NewRepoGroupSyncer(store, groups).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	id := func(name string) uint32 {
+		for _, r := range stored {
+			if r.Name == name {
+				return r.ID
+			}
+		}
+		t.Fatalf("no repo named %q", name)
+		return 0
+	}
+
+	want := map[int64][]uint32{
+		1: {id("github.com/foo/bar"), id("gitlab.com/foo/sub/bar"), id("bitbucket.example.com/FOO/bar")},
+		2: {id("github.com/foo/bar"), id("github.com/baz/qux"), id("bitbucket.example.com/FOO/bar")},
+		3: {id("github.com/foo/bar"), id("gitlab.com/foo/sub/bar")},
+		4: {id("github.com/foo/bar"), id("bitbucket.example.com/FOO/bar")},
+		5: {id("github.com/foo/bar")},
+	}
+	if !reflect.DeepEqual(groups.members, want) {
+		t.Errorf("got members %v, want %v", groups.members, want)
+	}
+}
@@ -131,6 +131,11 @@ func TestSources_ListRepos(t *testing.T) {
 	//    --name="bitbucket"\
 	//    -d -p 7990:7990 -p 7999:7999 \
 	//    atlassian/bitbucket-server
+	//
+	// Likewise for Gitea, populated with the sourcegraph/go-langserver,
+	// sourcegraph/python-langserver, keegan/python-langserver-fork and
+	// keegan/rgp repositories.
+	// $ docker run --name=gitea -d -p 3000:3000 -p 2222:22 gitea/gitea
 
 	type testCase struct {
 		name   string
@@ -175,6 +180,14 @@ func TestSources_ListRepos(t *testing.T) {
 					},
 				}),
 			},
+			{
+				Kind: "GITEA",
+				Config: marshalJSON(t, &schema.GiteaConnection{
+					Url:             "http://127.0.0.1:3000",
+					Token:           os.Getenv("GITEA_TOKEN"),
+					RepositoryQuery: []string{"affiliated"},
+				}),
+			},
 			{
 				Kind: "GITOLITE",
 				Config: marshalJSON(t, &schema.GitoliteConnection{
@@ -282,6 +295,23 @@ func TestSources_ListRepos(t *testing.T) {
 					},
 				}),
 			},
+			{
+				Kind: "GITEA",
+				Config: marshalJSON(t, &schema.GiteaConnection{
+					Url:   "http://127.0.0.1:3000",
+					Token: os.Getenv("GITEA_TOKEN"),
+					Repos: []string{"sourcegraph/go-langserver"},
+					RepositoryQuery: []string{
+						"affiliated",
+						"?q=python",
+					},
+					Exclude: []*schema.ExcludedGiteaRepo{
+						{Name: "Sourcegraph/Go-Langserver"}, // test case insensitivity
+						{Id: 3},                             // keegan/python-langserver-fork id
+						{Pattern: "/rgp$"},                  // only matches keegan/rgp
+					},
+				}),
+			},
 			{
 				Kind: "AWSCODECOMMIT",
 				Config: marshalJSON(t, &schema.AWSCodeCommitConnection{
@@ -345,6 +375,10 @@ func TestSources_ListRepos(t *testing.T) {
 						for _, e := range cfg.Exclude {
 							ex = append(ex, excluded{name: e.Name, id: strconv.Itoa(e.Id), pattern: e.Pattern})
 						}
+					case *schema.GiteaConnection:
+						for _, e := range cfg.Exclude {
+							ex = append(ex, excluded{name: e.Name, id: strconv.Itoa(e.Id), pattern: e.Pattern})
+						}
 					case *schema.AWSCodeCommitConnection:
 						for _, e := range cfg.Exclude {
 							ex = append(ex, excluded{name: e.Name, id: e.Id})
@@ -363,7 +397,7 @@ func TestSources_ListRepos(t *testing.T) {
 					for _, e := range ex {
 						name := e.name
 						switch s.Kind {
-						case "GITHUB", "BITBUCKETSERVER":
+						case "GITHUB", "BITBUCKETSERVER", "GITEA":
 							name = strings.ToLower(name)
 						}
 						set[name], set[e.id] = true, true
@@ -432,6 +466,19 @@ func TestSources_ListRepos(t *testing.T) {
 					},
 				}),
 			},
+			{
+				Kind: "GITEA",
+				Config: marshalJSON(t, &schema.GiteaConnection{
+					Url:             "http://127.0.0.1:3000",
+					Token:           os.Getenv("GITEA_TOKEN"),
+					RepositoryQuery: []string{"none"},
+					Repos: []string{
+						"Sourcegraph/go-langserver",
+						"keegan/rgp",
+						"keegan/rgp-missing",
+					},
+				}),
+			},
 			{
 				Kind: "OTHER",
 				Config: marshalJSON(t, &schema.OtherExternalServiceConnection{
@@ -478,6 +525,11 @@ func TestSources_ListRepos(t *testing.T) {
 							"127.0.0.1/ORG/baz",
 							"127.0.0.1/ORG/foo",
 						}
+					case "GITEA":
+						want = []string{
+							"127.0.0.1/keegan/rgp",
+							"127.0.0.1/sourcegraph/go-langserver",
+						}
 					case "GITLAB":
 						want = []string{
 							"gitlab.com/gitlab-org/gitlab-ce",
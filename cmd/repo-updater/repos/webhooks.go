@@ -0,0 +1,238 @@
+package repos
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// maxWebhookPayloadSize is the maximum size of a webhook payload we read.
+const maxWebhookPayloadSize = 25 << 20
+
+var (
+	// ErrWebhooksDisabled is returned by ParseWebhook when the external
+	// service has no webhook secret configured.
+	ErrWebhooksDisabled = errors.New("webhooks are not enabled for this external service")
+
+	// ErrWebhookSignature is returned by ParseWebhook when the webhook
+	// signature doesn't match the configured webhook secret.
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// WebhookEventType is the type of a WebhookEvent.
+type WebhookEventType int
+
+const (
+	// WebhookIgnored is the type of events which don't require any action,
+	// such as pings.
+	WebhookIgnored WebhookEventType = iota
+
+	// WebhookPush is the type of events for pushes to a repository. The
+	// repository should be fetched.
+	WebhookPush
+
+	// WebhookRepoChange is the type of events for created, deleted, renamed
+	// and otherwise changed repositories. The external service should be
+	// synced.
+	WebhookRepoChange
+)
+
+// WebhookEvent is a webhook event sent by an external service.
+type WebhookEvent struct {
+	Type WebhookEventType
+
+	// ExternalRepo is the repository the event is about. It is only set
+	// for WebhookPush events.
+	ExternalRepo api.ExternalRepoSpec
+}
+
+// ParseWebhook verifies the signature of the webhook request r sent by the
+// external service svc and parses it. Only GitHub, GitLab and Bitbucket
+// Server webhooks are supported.
+func ParseWebhook(svc *ExternalService, r *http.Request) (*WebhookEvent, error) {
+	cfg, err := svc.Configuration()
+	if err != nil {
+		return nil, err
+	}
+
+	var secret, baseURL string
+	switch c := cfg.(type) {
+	case *schema.GitHubConnection:
+		secret, baseURL = c.WebhookSecret, c.Url
+	case *schema.GitLabConnection:
+		secret, baseURL = c.WebhookSecret, c.Url
+	case *schema.BitbucketServerConnection:
+		secret, baseURL = c.WebhookSecret, c.Url
+	default:
+		return nil, errors.Errorf("webhooks are not supported for external service kind %q", svc.Kind)
+	}
+
+	if secret == "" {
+		return nil, ErrWebhooksDisabled
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid external service URL %q", baseURL)
+	}
+	serviceID := NormalizeBaseURL(u).String()
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxWebhookPayloadSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read webhook payload")
+	}
+
+	switch cfg.(type) {
+	case *schema.GitHubConnection:
+		return parseGitHubWebhook(r.Header, payload, secret, serviceID)
+	case *schema.GitLabConnection:
+		return parseGitLabWebhook(r.Header, payload, secret, serviceID)
+	default:
+		return parseBitbucketServerWebhook(r.Header, payload, secret, serviceID)
+	}
+}
+
+func parseGitHubWebhook(h http.Header, payload []byte, secret, serviceID string) (*WebhookEvent, error) {
+	if sig := h.Get("X-Hub-Signature-256"); sig != "" {
+		if !validHMAC(sha256.New, "sha256=", sig, payload, secret) {
+			return nil, ErrWebhookSignature
+		}
+	} else if !validHMAC(sha1.New, "sha1=", h.Get("X-Hub-Signature"), payload, secret) {
+		return nil, ErrWebhookSignature
+	}
+
+	switch h.Get("X-GitHub-Event") {
+	case "push":
+		var p struct {
+			Repository struct {
+				NodeID string `json:"node_id"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, errors.Wrap(err, "invalid GitHub push payload")
+		}
+		if p.Repository.NodeID == "" {
+			return nil, errors.New("GitHub push payload without repository node_id")
+		}
+		return &WebhookEvent{
+			Type: WebhookPush,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          p.Repository.NodeID,
+				ServiceType: github.ServiceType,
+				ServiceID:   serviceID,
+			},
+		}, nil
+
+	case "repository":
+		// Sent when a repository is created, deleted, renamed, transferred,
+		// archived or has its visibility changed.
+		return &WebhookEvent{Type: WebhookRepoChange}, nil
+
+	default:
+		return &WebhookEvent{Type: WebhookIgnored}, nil
+	}
+}
+
+func parseGitLabWebhook(h http.Header, payload []byte, secret, serviceID string) (*WebhookEvent, error) {
+	if subtle.ConstantTimeCompare([]byte(h.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+		return nil, ErrWebhookSignature
+	}
+
+	var p struct {
+		EventName string `json:"event_name"`
+		ProjectID int    `json:"project_id"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, errors.Wrap(err, "invalid GitLab webhook payload")
+	}
+
+	// Project and group webhooks send the event name in the X-Gitlab-Event
+	// header. System hooks send "System Hook" in that header and the event
+	// name in the payload.
+	switch p.EventName {
+	case "push", "tag_push":
+		if p.ProjectID == 0 {
+			return nil, errors.New("GitLab push payload without project_id")
+		}
+		return &WebhookEvent{
+			Type: WebhookPush,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          strconv.Itoa(p.ProjectID),
+				ServiceType: gitlab.ServiceType,
+				ServiceID:   serviceID,
+			},
+		}, nil
+
+	case "project_create", "project_destroy", "project_rename", "project_transfer", "project_update":
+		return &WebhookEvent{Type: WebhookRepoChange}, nil
+
+	default:
+		return &WebhookEvent{Type: WebhookIgnored}, nil
+	}
+}
+
+func parseBitbucketServerWebhook(h http.Header, payload []byte, secret, serviceID string) (*WebhookEvent, error) {
+	if !validHMAC(sha256.New, "sha256=", h.Get("X-Hub-Signature"), payload, secret) {
+		return nil, ErrWebhookSignature
+	}
+
+	switch h.Get("X-Event-Key") {
+	case "repo:refs_changed":
+		var p struct {
+			Repository struct {
+				ID int `json:"id"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, errors.Wrap(err, "invalid Bitbucket Server push payload")
+		}
+		if p.Repository.ID == 0 {
+			return nil, errors.New("Bitbucket Server push payload without repository id")
+		}
+		return &WebhookEvent{
+			Type: WebhookPush,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          strconv.Itoa(p.Repository.ID),
+				ServiceType: bitbucketserver.ServiceType,
+				ServiceID:   serviceID,
+			},
+		}, nil
+
+	case "repo:modified", "repo:forked":
+		return &WebhookEvent{Type: WebhookRepoChange}, nil
+
+	default:
+		return &WebhookEvent{Type: WebhookIgnored}, nil
+	}
+}
+
+// validHMAC reports whether sig is prefix followed by the hex encoded HMAC
+// of payload keyed with secret.
+func validHMAC(h func() hash.Hash, prefix, sig string, payload []byte, secret string) bool {
+	if !strings.HasPrefix(sig, prefix) {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(sig, prefix))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,186 @@
+package repos
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestParseWebhook(t *testing.T) {
+	sign := func(h func() hash.Hash, prefix, payload string) string {
+		mac := hmac.New(h, []byte("s3cr3t"))
+		mac.Write([]byte(payload))
+		return prefix + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	github := &ExternalService{Kind: "GITHUB", Config: `{"url": "https://GitHub.com", "token": "t", "webhookSecret": "s3cr3t"}`}
+	gitlab := &ExternalService{Kind: "GITLAB", Config: `{"url": "https://gitlab.example.com", "token": "t", "webhookSecret": "s3cr3t"}`}
+	bbs := &ExternalService{Kind: "BITBUCKETSERVER", Config: `{"url": "https://bitbucket.example.com", "token": "t", "webhookSecret": "s3cr3t"}`}
+
+	githubPush := `{"repository": {"node_id": "MDEwOlJlcG9zaXRvcnkxMjk2MjY5"}}`
+	gitlabPush := `{"event_name": "push", "project_id": 42}`
+	bbsPush := `{"repository": {"id": 7}}`
+
+	for _, tc := range []struct {
+		name    string
+		svc     *ExternalService
+		headers map[string]string
+		payload string
+		want    *WebhookEvent
+		err     error
+	}{
+		{
+			name: "github push",
+			svc:  github,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": sign(sha1.New, "sha1=", githubPush),
+			},
+			payload: githubPush,
+			want: &WebhookEvent{Type: WebhookPush, ExternalRepo: api.ExternalRepoSpec{
+				ID:          "MDEwOlJlcG9zaXRvcnkxMjk2MjY5",
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			}},
+		},
+		{
+			name: "github push sha256",
+			svc:  github,
+			headers: map[string]string{
+				"X-GitHub-Event":      "push",
+				"X-Hub-Signature-256": sign(sha256.New, "sha256=", githubPush),
+			},
+			payload: githubPush,
+			want: &WebhookEvent{Type: WebhookPush, ExternalRepo: api.ExternalRepoSpec{
+				ID:          "MDEwOlJlcG9zaXRvcnkxMjk2MjY5",
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			}},
+		},
+		{
+			name: "github repository",
+			svc:  github,
+			headers: map[string]string{
+				"X-GitHub-Event":  "repository",
+				"X-Hub-Signature": sign(sha1.New, "sha1=", `{"action": "renamed"}`),
+			},
+			payload: `{"action": "renamed"}`,
+			want:    &WebhookEvent{Type: WebhookRepoChange},
+		},
+		{
+			name: "github ping",
+			svc:  github,
+			headers: map[string]string{
+				"X-GitHub-Event":  "ping",
+				"X-Hub-Signature": sign(sha1.New, "sha1=", `{}`),
+			},
+			payload: `{}`,
+			want:    &WebhookEvent{Type: WebhookIgnored},
+		},
+		{
+			name: "github bad signature",
+			svc:  github,
+			headers: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": sign(sha1.New, "sha1=", "something else"),
+			},
+			payload: githubPush,
+			err:     ErrWebhookSignature,
+		},
+		{
+			name:    "github missing signature",
+			svc:     github,
+			headers: map[string]string{"X-GitHub-Event": "push"},
+			payload: githubPush,
+			err:     ErrWebhookSignature,
+		},
+		{
+			name: "gitlab push",
+			svc:  gitlab,
+			headers: map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "s3cr3t",
+			},
+			payload: gitlabPush,
+			want: &WebhookEvent{Type: WebhookPush, ExternalRepo: api.ExternalRepoSpec{
+				ID:          "42",
+				ServiceType: "gitlab",
+				ServiceID:   "https://gitlab.example.com/",
+			}},
+		},
+		{
+			name: "gitlab system hook project rename",
+			svc:  gitlab,
+			headers: map[string]string{
+				"X-Gitlab-Event": "System Hook",
+				"X-Gitlab-Token": "s3cr3t",
+			},
+			payload: `{"event_name": "project_rename", "project_id": 42}`,
+			want:    &WebhookEvent{Type: WebhookRepoChange},
+		},
+		{
+			name: "gitlab bad token",
+			svc:  gitlab,
+			headers: map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "nope",
+			},
+			payload: gitlabPush,
+			err:     ErrWebhookSignature,
+		},
+		{
+			name: "bitbucket server push",
+			svc:  bbs,
+			headers: map[string]string{
+				"X-Event-Key":     "repo:refs_changed",
+				"X-Hub-Signature": sign(sha256.New, "sha256=", bbsPush),
+			},
+			payload: bbsPush,
+			want: &WebhookEvent{Type: WebhookPush, ExternalRepo: api.ExternalRepoSpec{
+				ID:          "7",
+				ServiceType: "bitbucketServer",
+				ServiceID:   "https://bitbucket.example.com/",
+			}},
+		},
+		{
+			name: "bitbucket server bad signature",
+			svc:  bbs,
+			headers: map[string]string{
+				"X-Event-Key":     "repo:refs_changed",
+				"X-Hub-Signature": sign(sha1.New, "sha1=", bbsPush),
+			},
+			payload: bbsPush,
+			err:     ErrWebhookSignature,
+		},
+		{
+			name:    "no webhook secret",
+			svc:     &ExternalService{Kind: "GITHUB", Config: `{"url": "https://github.com", "token": "t"}`},
+			headers: map[string]string{"X-GitHub-Event": "push"},
+			payload: githubPush,
+			err:     ErrWebhooksDisabled,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhooks/1", strings.NewReader(tc.payload))
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			ev, err := ParseWebhook(tc.svc, req)
+			if err != tc.err {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if !reflect.DeepEqual(ev, tc.want) {
+				t.Errorf("got event %+v, want %+v", ev, tc.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
@@ -40,6 +42,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
+	mux.HandleFunc("/webhooks/", s.handleWebhook)
 	return mux
 }
 
@@ -283,6 +286,90 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// handleWebhook handles webhooks sent by the code host of the external
+// service whose ID is the last path component. Pushes enqueue a high
+// priority update of the pushed to repository. Repository creations,
+// deletions and renames trigger a sync of the external service kind in the
+// background.
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/webhooks/"), 10, 64)
+	if err != nil {
+		respond(w, http.StatusBadRequest, errors.Errorf("invalid external service ID in path %q", r.URL.Path))
+		return
+	}
+
+	es, err := s.Store.ListExternalServices(r.Context(), repos.StoreListExternalServicesArgs{IDs: []int64{id}})
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "store.list-external-services"))
+		return
+	}
+	if len(es) == 0 {
+		respond(w, http.StatusNotFound, errors.Errorf("external service %d not found", id))
+		return
+	}
+	svc := es[0]
+
+	ev, err := repos.ParseWebhook(svc, r)
+	switch {
+	case err == repos.ErrWebhooksDisabled:
+		respond(w, http.StatusNotFound, err)
+		return
+	case err == repos.ErrWebhookSignature:
+		respond(w, http.StatusUnauthorized, err)
+		return
+	case err != nil:
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch ev.Type {
+	case repos.WebhookPush:
+		rs, err := s.Store.ListRepos(r.Context(), repos.StoreListReposArgs{
+			ExternalRepos: []api.ExternalRepoSpec{ev.ExternalRepo},
+		})
+		if err != nil {
+			respond(w, http.StatusInternalServerError, errors.Wrap(err, "store.list-repos"))
+			return
+		}
+
+		res := make([]*protocol.RepoUpdateResponse, 0, len(rs))
+		for _, repo := range rs {
+			// Prefer the clone URL of the external service which sent the
+			// webhook.
+			var url string
+			if src := repo.Sources[svc.URN()]; src != nil && src.CloneURL != "" {
+				url = src.CloneURL
+			} else if urls := repo.CloneURLs(); len(urls) > 0 {
+				url = urls[0]
+			}
+			repos.Scheduler.UpdateOnce(repo.ID, api.RepoName(repo.Name), url)
+			res = append(res, &protocol.RepoUpdateResponse{ID: repo.ID, Name: repo.Name})
+		}
+		log15.Debug("server.webhook", "push", ev.ExternalRepo, "enqueued", len(res))
+		respond(w, http.StatusOK, res)
+
+	case repos.WebhookRepoChange:
+		// Syncing can take much longer than code hosts wait for webhook
+		// responses, so it happens in the background.
+		if s.Syncer != nil {
+			go func() {
+				if _, err := s.Syncer.Sync(context.Background(), svc.Kind); err != nil {
+					log15.Error("server.webhook", "kind", svc.Kind, "error", err)
+				}
+			}()
+		}
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 var mockRepoLookup func(protocol.RepoLookupArgs) (*protocol.RepoLookupResult, error)
 
 func (s *Server) repoLookup(ctx context.Context, args protocol.RepoLookupArgs) (result *protocol.RepoLookupResult, err error) {
@@ -409,6 +496,14 @@ func newRepoInfo(r *repos.Repo) (*protocol.RepoInfo, error) {
 			Blob:   pathAppend(root, "/browse/{path}?at={rev}"),
 			Commit: pathAppend(root, "/commits/{commit}"),
 		}
+	case "gitea":
+		repo := r.Metadata.(*gitea.Repo)
+		info.Links = &protocol.RepoLinks{
+			Root:   repo.HTMLURL,
+			Tree:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Blob:   pathAppend(repo.HTMLURL, "/src/{rev}/{path}"),
+			Commit: pathAppend(repo.HTMLURL, "/commit/{commit}"),
+		}
 	case "awscodecommit":
 		repo := r.Metadata.(*awscodecommit.Repository)
 		if repo.ARN == "" {
@@ -3,6 +3,9 @@ package repoupdater
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -568,6 +571,92 @@ func apiExternalServices(es ...*repos.ExternalService) []api.ExternalService {
 	return svcs
 }
 
+func TestServer_Webhook(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(repos.FakeStore)
+	svc := &repos.ExternalService{
+		Kind:   "GITHUB",
+		Config: `{"url": "https://github.com", "token": "secret", "webhookSecret": "s3cr3t"}`,
+	}
+	must(store.UpsertExternalServices(ctx, svc))
+
+	repo := &repos.Repo{
+		Name: "github.com/foo/bar",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "bar",
+			ServiceType: "github",
+			ServiceID:   "https://github.com/",
+		},
+		Metadata: new(github.Repository),
+		Sources: map[string]*repos.SourceInfo{
+			svc.URN(): {
+				ID:       svc.URN(),
+				CloneURL: "https://secret@github.com/foo/bar",
+			},
+		},
+	}
+	must(store.UpsertRepos(ctx, repo))
+
+	s := &Server{Store: store}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	post := func(path, event, payload, signature string) (int, []*protocol.RepoUpdateResponse) {
+		t.Helper()
+		req, err := http.NewRequest("POST", srv.URL+path, strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature", signature)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var res []*protocol.RepoUpdateResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return resp.StatusCode, res
+	}
+	sign := func(payload string) string {
+		mac := hmac.New(sha1.New, []byte("s3cr3t"))
+		mac.Write([]byte(payload))
+		return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	path := fmt.Sprintf("/webhooks/%d", svc.ID)
+	push := `{"repository": {"node_id": "bar"}}`
+
+	if have, _ := post(path, "push", push, "sha1=00"); have != http.StatusUnauthorized {
+		t.Errorf("bad signature: have status %d, want %d", have, http.StatusUnauthorized)
+	}
+
+	have, res := post(path, "push", push, sign(push))
+	if have != http.StatusOK {
+		t.Errorf("push: have status %d, want %d", have, http.StatusOK)
+	}
+	want := []*protocol.RepoUpdateResponse{{ID: repo.ID, Name: repo.Name}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("push response: %s", cmp.Diff(res, want))
+	}
+	if info := repos.Scheduler.ScheduleInfo(repo.ID); info.Queue == nil {
+		t.Error("push did not enqueue a repo update")
+	}
+
+	if have, _ := post(path, "ping", `{}`, sign(`{}`)); have != http.StatusNoContent {
+		t.Errorf("ping: have status %d, want %d", have, http.StatusNoContent)
+	}
+	if have, _ := post("/webhooks/42", "push", push, sign(push)); have != http.StatusNotFound {
+		t.Errorf("unknown external service: have status %d, want %d", have, http.StatusNotFound)
+	}
+}
+
 func TestRepoLookup(t *testing.T) {
 	clock := repos.NewFakeClock(time.Now(), 0)
 	now := clock.Now()
@@ -39,6 +39,11 @@ type Request struct {
 	// The deadline for the search request.
 	// It is parsed with time.Time.UnmarshalText.
 	Deadline string
+
+	// Stream if true will make searcher respond with newline delimited JSON
+	// encoded StreamEvents. FileMatches are sent as soon as they are found
+	// rather than buffered into a single Response.
+	Stream bool
 }
 
 // GitserverRepo returns the repository information necessary to perform gitserver requests.
@@ -97,6 +102,11 @@ type PatternInfo struct {
 	// PatternMatchesPath is whether a file whose path matches Pattern (but whose contents don't) should be
 	// considered a match.
 	PatternMatchesPath bool
+
+	// CountOnly if true will only count the matches in each file: FileMatch.MatchCount is set to the
+	// number of matches in the file (which is not limited like the number of LineMatches is), and
+	// LineMatches is empty.
+	CountOnly bool
 }
 
 // AllIncludePatterns returns all include patterns (including the deprecated
@@ -125,6 +135,30 @@ type Response struct {
 	DeadlineHit bool
 }
 
+// StreamEvent is a single line of a streaming search response. Every event
+// except the last one has Match set. The last event has Done set and reports
+// the same information as the non-match fields of Response.
+type StreamEvent struct {
+	// Match is a file match found by the search.
+	Match *FileMatch `json:",omitempty"`
+
+	// Done is true for the final event of the stream.
+	Done bool `json:",omitempty"`
+
+	// LimitHit is true if the stream may not include all FileMatches because
+	// a match limit was hit. Only set on the final event.
+	LimitHit bool `json:",omitempty"`
+
+	// DeadlineHit is true if the stream may not include all FileMatches
+	// because a deadline was hit. Only set on the final event.
+	DeadlineHit bool `json:",omitempty"`
+
+	// Error is set on the final event if the search failed after the first
+	// event was sent. Errors that occur before any event is sent are reported
+	// with a non-200 HTTP status code, like non-streaming requests.
+	Error string `json:",omitempty"`
+}
+
 // FileMatch is the struct used by vscode to receive search results
 type FileMatch struct {
 	Path        string
@@ -132,6 +166,10 @@ type FileMatch struct {
 
 	// LimitHit is true if LineMatches may not include all LineMatches.
 	LimitHit bool
+
+	// MatchCount is the number of matches in the file. It is only set if the request's CountOnly is
+	// true.
+	MatchCount int `json:",omitempty"`
 }
 
 // LineMatch is the struct used by vscode to receive search results for a line.
@@ -33,6 +33,11 @@ const (
 	// maxFileMatches is the limit on number of matching files we return.
 	maxFileMatches = 1000
 
+	// maxCountOnlyFileMatches is the limit on number of matching files we
+	// return for requests that only count matches (whose file matches are
+	// much smaller).
+	maxCountOnlyFileMatches = 100000
+
 	// maxLineMatches is the limit on number of matches to return in a
 	// file.
 	maxLineMatches = 100
@@ -83,6 +88,10 @@ type readerGrep struct {
 	// re. It is the output of the longestLiteral function. It is only set if
 	// the regex has an empty LiteralPrefix.
 	literalSubstring []byte
+
+	// countOnly if true means we only count the matches in each file (see
+	// protocol.PatternInfo.CountOnly).
+	countOnly bool
 }
 
 // compile returns a readerGrep for matching p.
@@ -148,6 +157,7 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 		ignoreCase:       !p.IsCaseSensitive,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
+		countOnly:        p.CountOnly,
 	}, nil
 }
 
@@ -163,6 +173,7 @@ func (rg *readerGrep) Copy() *readerGrep {
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath.Copy(),
 		literalSubstring: rg.literalSubstring,
+		countOnly:        rg.countOnly,
 	}
 }
 
@@ -275,8 +286,58 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 	return matches, limitHit, nil
 }
 
-// FindZip is a convenience function to run Find on f.
+// Count returns the number of matches of rg in f. Unlike Find, it does not
+// limit the number of matches (but it skips lines that are too long, like
+// Find does).
+// NOTE: This is not safe to use concurrently.
+func (rg *readerGrep) Count(zf *store.ZipFile, f *store.SrcFile) (count int, err error) {
+	if rg.ignoreCase && rg.transformBuf == nil {
+		rg.transformBuf = make([]byte, zf.MaxLen)
+	}
+
+	fileBuf := zf.DataFor(f)
+	fileMatchBuf := fileBuf
+	if rg.ignoreCase {
+		fileMatchBuf = rg.transformBuf[:len(fileBuf)]
+		bytesToLowerASCII(fileMatchBuf, fileBuf)
+	}
+
+	// See Find for why we first run a single match over the whole file.
+	if !bytes.Contains(fileMatchBuf, rg.literalSubstring) {
+		return 0, nil
+	}
+	if rg.re.Find(fileMatchBuf) == nil {
+		return 0, nil
+	}
+
+	for {
+		advance, lineBuf, err := bufio.ScanLines(fileMatchBuf, true)
+		if err != nil {
+			// ScanLines should never return an err
+			return 0, err
+		}
+		if advance == 0 { // EOF
+			break
+		}
+		fileMatchBuf = fileMatchBuf[advance:]
+		if len(lineBuf) > maxLineSize {
+			continue
+		}
+		count += len(rg.re.FindAllIndex(lineBuf, -1))
+	}
+	return count, nil
+}
+
+// FindZip is a convenience function to run Find (or Count, if rg only counts
+// matches) on f.
 func (rg *readerGrep) FindZip(zf *store.ZipFile, f *store.SrcFile) (protocol.FileMatch, error) {
+	if rg.countOnly {
+		count, err := rg.Count(zf, f)
+		return protocol.FileMatch{
+			Path:       f.Name,
+			MatchCount: count,
+		}, err
+	}
 	lm, limitHit, err := rg.Find(zf, f)
 	return protocol.FileMatch{
 		Path:        f.Name,
@@ -286,7 +347,10 @@ func (rg *readerGrep) FindZip(zf *store.ZipFile, f *store.SrcFile) (protocol.Fil
 }
 
 // concurrentFind searches files in zr looking for matches using rg.
-func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMatchLimit int, patternMatchesContent, patternMatchesPaths bool) (fm []protocol.FileMatch, limitHit bool, err error) {
+//
+// If onMatch is non-nil it is called with each match as soon as it is
+// found. Calls to onMatch are serialized.
+func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMatchLimit int, patternMatchesContent, patternMatchesPaths bool, onMatch func(protocol.FileMatch)) (fm []protocol.FileMatch, limitHit bool, err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ConcurrentFind")
 	ext.Component.Set(span, "matcher")
 	if rg.re != nil {
@@ -305,8 +369,12 @@ func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, file
 		patternMatchesContent = true
 	}
 
-	if fileMatchLimit > maxFileMatches || fileMatchLimit <= 0 {
-		fileMatchLimit = maxFileMatches
+	maxMatches := maxFileMatches
+	if rg.countOnly {
+		maxMatches = maxCountOnlyFileMatches
+	}
+	if fileMatchLimit > maxMatches || fileMatchLimit <= 0 {
+		fileMatchLimit = maxMatches
 	}
 
 	// If we reach fileMatchLimit we use cancel to stop the search
@@ -334,7 +402,11 @@ func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, file
 		for _, f := range files {
 			if rg.matchPath.MatchPath(f.Name) && rg.matchString(f.Name) {
 				if len(matches) < fileMatchLimit {
-					matches = append(matches, protocol.FileMatch{Path: f.Name})
+					fm := protocol.FileMatch{Path: f.Name}
+					matches = append(matches, fm)
+					if onMatch != nil {
+						onMatch(fm)
+					}
 				} else {
 					limitHit = true
 					break
@@ -394,7 +466,7 @@ func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, file
 					})
 					return
 				}
-				match := len(fm.LineMatches) > 0
+				match := len(fm.LineMatches) > 0 || fm.MatchCount > 0
 				if !match && patternMatchesPaths {
 					// Try matching against the file path.
 					match = rg.matchString(f.Name)
@@ -406,6 +478,9 @@ func concurrentFind(ctx context.Context, rg *readerGrep, zf *store.ZipFile, file
 					matchesmu.Lock()
 					if len(matches) < fileMatchLimit {
 						matches = append(matches, fm)
+						if onMatch != nil {
+							onMatch(fm)
+						}
 					} else {
 						limitHit = true
 						cancel()
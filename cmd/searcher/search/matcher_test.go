@@ -10,6 +10,7 @@ import (
 	"regexp/syntax"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"testing/iotest"
 	"testing/quick"
@@ -264,7 +265,7 @@ func benchConcurrentFind(b *testing.B, p *protocol.Request) {
 	b.ResetTimer()
 
 	for n := 0; n < b.N; n++ {
-		_, _, err := concurrentFind(ctx, rg, zf, 0, p.PatternMatchesContent, p.PatternMatchesPath)
+		_, _, err := concurrentFind(ctx, rg, zf, 0, p.PatternMatchesContent, p.PatternMatchesPath, nil)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -501,7 +502,7 @@ func TestMaxMatches(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	fileMatches, limitHit, err := concurrentFind(context.Background(), rg, zf, 0, true, false)
+	fileMatches, limitHit, err := concurrentFind(context.Background(), rg, zf, 0, true, false, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -530,6 +531,52 @@ func TestMaxMatches(t *testing.T) {
 	}
 }
 
+// Tests that count-only searches count all matches in a file, regardless of
+// the limits on the number of line matches.
+func TestCountOnly(t *testing.T) {
+	many := strings.Repeat(strings.Repeat("Foo ", maxOffsets+1)+"\n", maxLineMatches+1)
+	zipData, err := createZip(map[string]string{
+		"many":    many,
+		"one":     "bar\nfoo\n",
+		"long":    strings.Repeat("x", maxLineSize) + "foo\n",
+		"nomatch": "bar\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, err := store.MockZipFile(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rg, err := compile(&protocol.PatternInfo{Pattern: "foo", CountOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileMatches, limitHit, err := concurrentFind(context.Background(), rg, zf, 0, true, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limitHit {
+		t.Error("got limitHit, want false")
+	}
+
+	got := map[string]int{}
+	for _, fm := range fileMatches {
+		if len(fm.LineMatches) > 0 || fm.LimitHit {
+			t.Errorf("%s: got line matches %v (limitHit %v), want only a count", fm.Path, fm.LineMatches, fm.LimitHit)
+		}
+		got[fm.Path] = fm.MatchCount
+	}
+	want := map[string]int{
+		"many": (maxOffsets + 1) * (maxLineMatches + 1),
+		"one":  1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got match counts %v, want %v", got, want)
+	}
+}
+
 // Tests that:
 //
 // - IncludePatterns can match the path in any order
@@ -561,7 +608,7 @@ func TestPathMatches(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	fileMatches, _, err := concurrentFind(context.Background(), rg, zf, 10, true, true)
+	fileMatches, _, err := concurrentFind(context.Background(), rg, zf, 10, true, true, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -85,17 +85,14 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	matches, limitHit, deadlineHit, err := s.search(ctx, &p)
+	if p.Stream {
+		s.serveStream(ctx, w, &p)
+		return
+	}
+
+	matches, limitHit, deadlineHit, err := s.search(ctx, &p, nil)
 	if err != nil {
-		code := http.StatusInternalServerError
-		if isBadRequest(err) || ctx.Err() == context.Canceled {
-			code = http.StatusBadRequest
-		} else if isTemporary(err) {
-			code = http.StatusServiceUnavailable
-		} else {
-			log.Printf("internal error serving %#+v: %s", p, err)
-		}
-		http.Error(w, err.Error(), code)
+		http.Error(w, err.Error(), errorStatusCode(ctx, &p, err))
 		return
 	}
 	if matches == nil {
@@ -116,7 +113,61 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(&resp)
 }
 
-func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []protocol.FileMatch, limitHit, deadlineHit bool, err error) {
+// serveStream runs the search described by p and writes each FileMatch to w
+// as soon as it is found. See protocol.StreamEvent for the format.
+func (s *Service) serveStream(ctx context.Context, w http.ResponseWriter, p *protocol.Request) {
+	var (
+		enc     = json.NewEncoder(w)
+		flusher = func() {}
+		started bool
+	)
+	if f, ok := w.(http.Flusher); ok {
+		flusher = f.Flush
+	}
+	send := func(ev *protocol.StreamEvent) {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			started = true
+		}
+		// As in ServeHTTP, the only reasonable error is the client going
+		// away, which will also cancel ctx and stop the search.
+		_ = enc.Encode(ev)
+		flusher()
+	}
+
+	_, limitHit, deadlineHit, err := s.search(ctx, p, func(fm protocol.FileMatch) {
+		send(&protocol.StreamEvent{Match: &fm})
+	})
+	if err != nil && !started {
+		http.Error(w, err.Error(), errorStatusCode(ctx, p, err))
+		return
+	}
+
+	done := protocol.StreamEvent{
+		Done:        true,
+		LimitHit:    limitHit,
+		DeadlineHit: deadlineHit,
+	}
+	if err != nil {
+		done.Error = err.Error()
+	}
+	send(&done)
+}
+
+// errorStatusCode returns the HTTP status code to respond with when the
+// search for p failed with err.
+func errorStatusCode(ctx context.Context, p *protocol.Request, err error) int {
+	if isBadRequest(err) || ctx.Err() == context.Canceled {
+		return http.StatusBadRequest
+	}
+	if isTemporary(err) {
+		return http.StatusServiceUnavailable
+	}
+	log.Printf("internal error serving %#+v: %s", *p, err)
+	return http.StatusInternalServerError
+}
+
+func (s *Service) search(ctx context.Context, p *protocol.Request, onMatch func(protocol.FileMatch)) (matches []protocol.FileMatch, limitHit, deadlineHit bool, err error) {
 	tr := trace.New("search", fmt.Sprintf("%s@%s", p.Repo, p.Commit))
 	tr.LazyPrintf("%s", p.Pattern)
 
@@ -135,6 +186,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 	span.SetTag("patternMatchesContent", p.PatternMatchesContent)
 	span.SetTag("patternMatchesPath", p.PatternMatchesPath)
 	span.SetTag("deadline", p.Deadline)
+	span.SetTag("stream", p.Stream)
 	defer func(start time.Time) {
 		code := "200"
 		// We often have canceled and timed out requests. We do not want to
@@ -211,7 +263,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 	archiveFiles.Observe(float64(nFiles))
 	archiveSize.Observe(float64(bytes))
 
-	matches, limitHit, err = concurrentFind(ctx, rg, zf, p.FileMatchLimit, p.PatternMatchesContent, p.PatternMatchesPath)
+	matches, limitHit, err = concurrentFind(ctx, rg, zf, p.FileMatchLimit, p.PatternMatchesContent, p.PatternMatchesPath, onMatch)
 	return matches, limitHit, false, err
 }
 
@@ -179,6 +179,24 @@ milton.png
 			continue
 		}
 
+		// The streaming API should return the same matches.
+		req.Stream = true
+		m, err = doSearch(ts.URL, &req)
+		if err != nil {
+			t.Errorf("%v failed to stream: %s", test.arg, err)
+			continue
+		}
+		sort.Sort(sortByPath(m))
+		got = toString(m)
+		if got != test.want {
+			d, err := diff(test.want, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Errorf("%v unexpected stream response:\n%s", test.arg, d)
+			continue
+		}
+
 		// we do not support those in query
 		if !test.arg.PathPatternsAreRegExps && (len(test.arg.IncludePatterns) > 0 || test.arg.IncludePattern != "" || test.arg.ExcludePattern != "") {
 			continue
@@ -323,12 +341,15 @@ func TestSearch_badrequest(t *testing.T) {
 
 	for _, p := range cases {
 		p.PatternInfo.PatternMatchesContent = true
-		_, err := doSearch(ts.URL, &p)
-		if err == nil {
-			t.Fatalf("%v expected to fail", p)
-		}
-		if !strings.HasPrefix(err.Error(), "non-200 response: code=400 ") {
-			t.Fatalf("%v expected to have HTTP 400 response. Got %s", p, err)
+		for _, stream := range []bool{false, true} {
+			p.Stream = stream
+			_, err := doSearch(ts.URL, &p)
+			if err == nil {
+				t.Fatalf("%v expected to fail", p)
+			}
+			if !strings.HasPrefix(err.Error(), "non-200 response: code=400 ") {
+				t.Fatalf("%v expected to have HTTP 400 response. Got %s", p, err)
+			}
 		}
 	}
 }
@@ -364,6 +385,9 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 	if p.PatternMatchesPath {
 		form.Set("PatternMatchesPath", "true")
 	}
+	if p.Stream {
+		form.Set("Stream", "true")
+	}
 	resp, err := http.PostForm(u, form)
 	if err != nil {
 		return nil, err
@@ -377,6 +401,10 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 		return nil, fmt.Errorf("non-200 response: code=%d body=%s", resp.StatusCode, string(body))
 	}
 
+	if p.Stream {
+		return decodeStream(body)
+	}
+
 	var r protocol.Response
 	err = json.Unmarshal(body, &r)
 	if err != nil {
@@ -385,6 +413,29 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 	return r.Matches, err
 }
 
+func decodeStream(body []byte) ([]protocol.FileMatch, error) {
+	var (
+		matches = []protocol.FileMatch{}
+		dec     = json.NewDecoder(bytes.NewReader(body))
+	)
+	for {
+		var ev protocol.StreamEvent
+		if err := dec.Decode(&ev); err != nil {
+			return nil, fmt.Errorf("stream ended without a done event: %s", err)
+		}
+		if ev.Done {
+			if ev.Error != "" {
+				return nil, errors.New(ev.Error)
+			}
+			return matches, nil
+		}
+		if ev.Match == nil {
+			return nil, errors.New("stream event without a match")
+		}
+		matches = append(matches, *ev.Match)
+	}
+}
+
 func newStore(files map[string]string) (*store.Store, func(), error) {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
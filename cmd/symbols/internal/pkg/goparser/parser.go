@@ -0,0 +1,216 @@
+// Package goparser provides a symbol parser for Go source files which is
+// built on go/parser. Unlike universal-ctags, it understands the full Go
+// syntax, eg methods on types declared in other files of the package.
+package goparser
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/pkg/ctags"
+)
+
+// NewParser returns a parser for Go source files. The entries it returns use
+// the same kinds as universal-ctags does for Go.
+func NewParser() (ctags.Parser, error) {
+	return goParser{}, nil
+}
+
+type goParser struct{}
+
+func (goParser) Close() {}
+
+func (goParser) Parse(path string, content []byte) ([]ctags.Entry, error) {
+	fset := token.NewFileSet()
+	// Parse as much as possible of files with syntax errors, like ctags.
+	file, err := parser.ParseFile(fset, path, content, 0)
+	if file == nil {
+		return nil, err
+	}
+
+	p := &fileParser{
+		fset:      fset,
+		path:      path,
+		content:   content,
+		lines:     bytes.Split(content, []byte("\n")),
+		typeKinds: map[string]string{},
+	}
+	p.add(file.Name, "package", "", "", "")
+
+	// Record the kinds of types first, so that methods declared before
+	// their receiver type get the right parent kind.
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				p.typeKinds[ts.Name.Name] = typeKind(ts)
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			p.funcDecl(decl)
+		case *ast.GenDecl:
+			p.genDecl(decl)
+		}
+	}
+	return p.entries, nil
+}
+
+type fileParser struct {
+	fset      *token.FileSet
+	path      string
+	content   []byte
+	lines     [][]byte
+	typeKinds map[string]string // type name -> kind of its entry
+	entries   []ctags.Entry
+}
+
+func (p *fileParser) funcDecl(decl *ast.FuncDecl) {
+	var parent, parentKind string
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		parent = receiverTypeName(decl.Recv.List[0].Type)
+		parentKind = p.typeKinds[parent]
+		if parentKind == "" {
+			// Declared in another file of the package.
+			parentKind = "type"
+		}
+	}
+	p.add(decl.Name, "func", parent, parentKind, p.source(decl.Type.Params))
+}
+
+func (p *fileParser) genDecl(decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.ImportSpec:
+			// ctags reports imports as references, not definitions.
+
+		case *ast.ValueSpec:
+			kind := "variable"
+			if decl.Tok == token.CONST {
+				kind = "constant"
+			}
+			for _, name := range spec.Names {
+				p.add(name, kind, "", "", "")
+			}
+
+		case *ast.TypeSpec:
+			kind := p.typeKinds[spec.Name.Name]
+			p.add(spec.Name, kind, "", "", "")
+			switch t := spec.Type.(type) {
+			case *ast.StructType:
+				p.fields(t.Fields, "member", spec.Name.Name, kind)
+			case *ast.InterfaceType:
+				p.fields(t.Methods, "methodSpec", spec.Name.Name, kind)
+			}
+		}
+	}
+}
+
+// fields adds entries for the named fields of a struct or the methods of an
+// interface. Embedded fields are reported as anonMember.
+func (p *fileParser) fields(fields *ast.FieldList, kind, parent, parentKind string) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			if kind == "member" {
+				if name := receiverTypeName(field.Type); name != "" {
+					p.addAt(field.Type.Pos(), name, "anonMember", parent, parentKind, "")
+				}
+			}
+			continue
+		}
+		signature := ""
+		if ft, ok := field.Type.(*ast.FuncType); ok {
+			signature = p.source(ft.Params)
+		}
+		for _, name := range field.Names {
+			p.add(name, kind, parent, parentKind, signature)
+		}
+	}
+}
+
+func (p *fileParser) add(name *ast.Ident, kind, parent, parentKind, signature string) {
+	p.addAt(name.Pos(), name.Name, kind, parent, parentKind, signature)
+}
+
+func (p *fileParser) addAt(pos token.Pos, name, kind, parent, parentKind, signature string) {
+	if name == "_" {
+		return
+	}
+	line := p.fset.Position(pos).Line
+	p.entries = append(p.entries, ctags.Entry{
+		Name:       name,
+		Path:       p.path,
+		Line:       line,
+		Kind:       kind,
+		Language:   "Go",
+		Parent:     parent,
+		ParentKind: parentKind,
+		Pattern:    p.pattern(line),
+		Signature:  signature,
+	})
+}
+
+// source returns the source code of node.
+func (p *fileParser) source(node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	start, end := p.fset.Position(node.Pos()).Offset, p.fset.Position(node.End()).Offset
+	if start < 0 || end > len(p.content) || start > end {
+		return ""
+	}
+	return string(p.content[start:end])
+}
+
+// pattern returns a ctags search pattern for the given 1-based line.
+func (p *fileParser) pattern(line int) string {
+	if line < 1 || line > len(p.lines) {
+		return ""
+	}
+	text := strings.TrimSuffix(string(p.lines[line-1]), "\r")
+	text = strings.NewReplacer(`\`, `\\`, `/`, `\/`).Replace(text)
+	return "/^" + text + "$/"
+}
+
+// typeKind returns the ctags kind of the type declared by ts.
+func typeKind(ts *ast.TypeSpec) string {
+	if ts.Assign.IsValid() {
+		return "talias"
+	}
+	switch ts.Type.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	default:
+		return "type"
+	}
+}
+
+// receiverTypeName returns the name of the type in a method receiver or an
+// embedded field, eg "T" for "*T" or "pkg.T".
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e.Name
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel.Name
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/pkg/ctags"
+)
+
+func TestParser(t *testing.T) {
+	p, err := NewParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	src := `package a
+
+import "io"
+
+const (
+	A = 1
+	_ = 2
+)
+
+var b, c int
+
+func (t *T) M(x int) error { return nil }
+
+type T struct {
+	io.Reader
+	F string
+}
+
+type I interface {
+	N(s string) bool
+}
+
+type Alias = T
+
+func f() {}
+`
+	got, err := p.Parse("a/a.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := func(name string, line int, kind, parent, parentKind, signature, text string) ctags.Entry {
+		return ctags.Entry{
+			Name:       name,
+			Path:       "a/a.go",
+			Line:       line,
+			Kind:       kind,
+			Language:   "Go",
+			Parent:     parent,
+			ParentKind: parentKind,
+			Signature:  signature,
+			Pattern:    "/^" + text + "$/",
+		}
+	}
+	want := []ctags.Entry{
+		entry("a", 1, "package", "", "", "", "package a"),
+		entry("A", 6, "constant", "", "", "", "\tA = 1"),
+		entry("b", 10, "variable", "", "", "", "var b, c int"),
+		entry("c", 10, "variable", "", "", "", "var b, c int"),
+		entry("M", 12, "func", "T", "struct", "(x int)", "func (t *T) M(x int) error { return nil }"),
+		entry("T", 14, "struct", "", "", "", "type T struct {"),
+		entry("Reader", 15, "anonMember", "T", "struct", "", "\tio.Reader"),
+		entry("F", 16, "member", "T", "struct", "", "\tF string"),
+		entry("I", 19, "interface", "", "", "", "type I interface {"),
+		entry("N", 20, "methodSpec", "I", "interface", "(s string)", "\tN(s string) bool"),
+		entry("Alias", 23, "talias", "", "", "", "type Alias = T"),
+		entry("f", 25, "func", "", "", "()", "func f() {}"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range got {
+			t.Logf("got[%d]  %+v", i, got[i])
+		}
+		for i := range want {
+			t.Logf("want[%d] %+v", i, want[i])
+		}
+		t.Error("unexpected entries")
+	}
+}
+
+func TestParser_syntaxError(t *testing.T) {
+	p, _ := NewParser()
+	got, err := p.Parse("a.go", []byte("package a\n\nfunc f() {\n\nfunc g() {}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Name != "a" {
+		t.Errorf("got %+v, want at least the package entry", got)
+	}
+}
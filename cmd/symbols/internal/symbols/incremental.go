@@ -0,0 +1,208 @@
+package symbols
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/jmoiron/sqlx"
+	"github.com/keegancsmith/sqlf"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxIncrementalChanges is the maximum number of changed files for which we
+// update a copy of another commit's database instead of parsing all files.
+const maxIncrementalChanges = 1000
+
+// writeChangedSymbolsToNewDB tries to write the symbols of repo@commitID
+// into the blank database file `dbFile` by copying the database of a
+// recently indexed commit of repo, and then only re-parsing the files which
+// changed between the two commits. It returns false if there is no suitable
+// database to start from, in which case the caller should parse all files.
+func (s *Service) writeChangedSymbolsToNewDB(ctx context.Context, dbFile string, repo api.RepoName, commitID api.CommitID) (ok bool, err error) {
+	if s.FetchTarPaths == nil || s.GitDiff == nil {
+		return false, nil
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "writeChangedSymbolsToNewDB")
+	span.SetTag("repo", string(repo))
+	span.SetTag("commit", string(commitID))
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(otlog.Error(err))
+		}
+		span.SetTag("ok", ok)
+		span.Finish()
+	}()
+
+	for _, base := range s.recentCommits.get(repo) {
+		if base == commitID {
+			continue
+		}
+
+		baseFile, err := s.cache.OpenCached(symbolsDBKey(repo, base))
+		if err != nil {
+			// Most likely evicted.
+			continue
+		}
+
+		changes, err := s.GitDiff(ctx, repo, base, commitID)
+		if err != nil {
+			baseFile.Close()
+			return false, err
+		}
+		changed := len(changes.Added) + len(changes.Modified) + len(changes.Deleted)
+		if changed > maxIncrementalChanges {
+			baseFile.Close()
+			continue
+		}
+
+		span.LogFields(otlog.String("base", string(base)), otlog.Int("changed", changed))
+		err = copyFile(dbFile, baseFile.File)
+		baseFile.Close()
+		if err != nil {
+			return false, err
+		}
+
+		if err := s.updateSymbolsDB(ctx, dbFile, repo, commitID, changes.Added, changes.Modified, changes.Deleted); err != nil {
+			return false, err
+		}
+		incrementalIndexes.Inc()
+		log15.Debug("Incrementally indexed symbols", "repo", repo, "base", base, "commit", commitID, "changed", changed)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// updateSymbolsDB removes the symbols of modified and deleted files from the
+// database `dbFile`, and then adds the symbols of added and modified files of
+// repo@commitID.
+func (s *Service) updateSymbolsDB(ctx context.Context, dbFile string, repo api.RepoName, commitID api.CommitID, added, modified, deleted []string) error {
+	db, err := sqlx.Open("sqlite3_with_pcre", dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Rollback is a no-op after a successful Commit.
+		_ = tx.Rollback()
+	}()
+
+	remove := append(append([]string{}, modified...), deleted...)
+	for len(remove) > 0 {
+		// sqlite3 limits the number of bound parameters per statement.
+		n := len(remove)
+		if n > 500 {
+			n = 500
+		}
+		paths := make([]*sqlf.Query, n)
+		for i, path := range remove[:n] {
+			paths[i] = sqlf.Sprintf("%s", path)
+		}
+		q := sqlf.Sprintf("DELETE FROM symbols WHERE path IN (%s)", sqlf.Join(paths, ","))
+		if _, err := tx.Exec(q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+			return err
+		}
+		remove = remove[n:]
+	}
+
+	parse := append(append([]string{}, added...), modified...)
+	if len(parse) > 0 {
+		insertStatement, err := prepareInsertSymbol(tx)
+		if err != nil {
+			return err
+		}
+		err = s.parseUncached(ctx, repo, commitID, parse, func(symbol symbolInDB) error {
+			_, err := insertStatement.Exec(&symbol)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// copyFile copies src into the existing file at path dst.
+func copyFile(dst string, src io.Reader) error {
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, src)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// recentCommitsPerRepo is the number of recently searched commits we
+// remember per repository.
+const recentCommitsPerRepo = 5
+
+// recentCommits remembers the most recently searched commits of each
+// repository. The zero value is ready to use.
+type recentCommits struct {
+	mu    sync.Mutex
+	repos *lru.Cache // api.RepoName -> []api.CommitID, most recent first
+}
+
+func (r *recentCommits) add(repo api.RepoName, commitID api.CommitID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.repos == nil {
+		r.repos = lru.New(10000)
+	}
+
+	commits := []api.CommitID{commitID}
+	if v, ok := r.repos.Get(repo); ok {
+		for _, c := range v.([]api.CommitID) {
+			if c != commitID && len(commits) < recentCommitsPerRepo {
+				commits = append(commits, c)
+			}
+		}
+	}
+	r.repos.Add(repo, commits)
+}
+
+// get returns the recently searched commits of repo, most recent first.
+func (r *recentCommits) get(repo api.RepoName) []api.CommitID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.repos == nil {
+		return nil
+	}
+	v, ok := r.repos.Get(repo)
+	if !ok {
+		return nil
+	}
+	return v.([]api.CommitID)
+}
+
+var incrementalIndexes = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "symbols",
+	Subsystem: "store",
+	Name:      "incremental_indexes",
+	Help:      "The total number of symbol databases created by updating the database of another commit.",
+})
+
+func init() {
+	prometheus.MustRegister(incrementalIndexes)
+}
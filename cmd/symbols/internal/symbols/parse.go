@@ -3,6 +3,7 @@ package symbols
 import (
 	"context"
 	"fmt"
+	"path"
 	"runtime"
 	"strings"
 	"sync"
@@ -19,6 +20,37 @@ import (
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
+// LanguageParser is a parser for the files of a single language.
+type LanguageParser struct {
+	// Name identifies the parser in the symbols database (eg "go").
+	Name string
+
+	// Extensions are the extensions (including the leading dot) of the
+	// files which the parser handles.
+	Extensions []string
+
+	// New creates a new instance of the parser.
+	New func() (ctags.Parser, error)
+}
+
+// fallbackParserName identifies symbols produced by Service.NewParser in the
+// symbols database.
+const fallbackParserName = "ctags"
+
+// parserSet is an element of the parser pool. It holds a fallback parser
+// and the language parsers which have been used so far.
+type parserSet struct {
+	fallback  ctags.Parser
+	languages map[string]ctags.Parser // LanguageParser.Name -> parser
+}
+
+func (p *parserSet) Close() {
+	p.fallback.Close()
+	for _, parser := range p.languages {
+		parser.Close()
+	}
+}
+
 // startParsers starts the parser process pool.
 func (s *Service) startParsers() error {
 	n := s.NumParserProcesses
@@ -26,18 +58,59 @@ func (s *Service) startParsers() error {
 		n = runtime.GOMAXPROCS(0)
 	}
 
-	s.parsers = make(chan ctags.Parser, n)
+	s.parsers = make(chan *parserSet, n)
 	for i := 0; i < n; i++ {
-		parser, err := s.NewParser()
+		parser, err := s.newParserSet()
 		if err != nil {
-			return errors.Wrap(err, "NewParser")
+			return err
 		}
 		s.parsers <- parser
 	}
 	return nil
 }
 
-func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, callback func(symbol protocol.Symbol) error) (err error) {
+func (s *Service) newParserSet() (*parserSet, error) {
+	fallback, err := s.NewParser()
+	if err != nil {
+		return nil, errors.Wrap(err, "NewParser")
+	}
+	return &parserSet{fallback: fallback, languages: map[string]ctags.Parser{}}, nil
+}
+
+// parserFor returns the parser in set which should parse filename,
+// and the name to record for the symbols it produces.
+func (s *Service) parserFor(set *parserSet, filename string) (ctags.Parser, string, error) {
+	ext := path.Ext(filename)
+	for _, lp := range s.LanguageParsers {
+		if !containsString(lp.Extensions, ext) {
+			continue
+		}
+		parser, ok := set.languages[lp.Name]
+		if !ok {
+			var err error
+			parser, err = lp.New()
+			if err != nil {
+				return nil, "", errors.Wrapf(err, "creating %s parser", lp.Name)
+			}
+			set.languages[lp.Name] = parser
+		}
+		return parser, lp.Name, nil
+	}
+	return set.fallback, fallbackParserName, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUncached parses the files of repo@commitID and calls callback for
+// every symbol found. If paths is non-empty, only those paths are parsed.
+func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, paths []string, callback func(symbol symbolInDB) error) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "parseUncached")
 	defer func() {
 		if err != nil {
@@ -48,9 +121,10 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 	span.SetTag("repo", string(repo))
 	span.SetTag("commit", string(commitID))
+	span.SetTag("paths", len(paths))
 
 	tr := trace.New("parseUncached", string(repo))
-	tr.LazyPrintf("commitID: %s", commitID)
+	tr.LazyPrintf("commitID: %s paths: %d", commitID, len(paths))
 
 	totalSymbols := 0
 	defer func() {
@@ -63,7 +137,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 
 	tr.LazyPrintf("fetch")
-	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID)
+	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID, paths)
 	tr.LazyPrintf("fetch (returned chans)")
 	if err != nil {
 		return err
@@ -96,7 +170,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 				wg.Done()
 				<-sem
 			}()
-			entries, parseErr := s.parse(ctx, req)
+			entries, parserName, parseErr := s.parse(ctx, req)
 			if parseErr != nil && parseErr != context.Canceled && parseErr != context.DeadlineExceeded {
 				log15.Error("Error parsing symbols.", "repo", repo, "commitID", commitID, "path", req.path, "dataSize", len(req.data), "error", parseErr)
 			}
@@ -108,7 +182,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 						continue
 					}
 					totalSymbols++
-					err = callback(entryToSymbol(e))
+					err = callback(symbolToSymbolInDB(entryToSymbol(e), parserName))
 					if err != nil {
 						log15.Error("Failed to add symbol", "symbol", e, "error", err)
 						return
@@ -124,7 +198,8 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 }
 
 // parse gets a parser from the pool and uses it to satisfy the parse request.
-func (s *Service) parse(ctx context.Context, req parseRequest) (entries []ctags.Entry, err error) {
+// It returns the name of the parser which produced the entries.
+func (s *Service) parse(ctx context.Context, req parseRequest) (entries []ctags.Entry, parserName string, err error) {
 	parseQueueSize.Inc()
 
 	select {
@@ -133,21 +208,21 @@ func (s *Service) parse(ctx context.Context, req parseRequest) (entries []ctags.
 		if ctx.Err() == context.DeadlineExceeded {
 			parseQueueTimeouts.Inc()
 		}
-		return nil, ctx.Err()
-	case parser, ok := <-s.parsers:
+		return nil, "", ctx.Err()
+	case set, ok := <-s.parsers:
 		parseQueueSize.Dec()
 
 		if !ok {
-			return nil, nil
+			return nil, "", nil
 		}
 
-		if parser == nil {
+		if set == nil {
 			// The parser failed for some previous receiver (who returned a nil parser to the channel). Try
 			// creating a parser.
 			var err error
-			parser, err = s.NewParser()
+			set, err = s.newParserSet()
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 		}
 
@@ -159,19 +234,26 @@ func (s *Service) parse(ctx context.Context, req parseRequest) (entries []ctags.
 			}
 			if err == nil {
 				// Return parser to pool.
-				s.parsers <- parser
+				s.parsers <- set
 			} else {
 				// Close parser and return nil to pool, indicating that the next receiver should create a new
 				// parser.
 				log15.Error("Closing failed parser and creating a new one.", "path", req.path, "error", err)
 				parseFailed.Inc()
-				parser.Close()
+				set.Close()
 				s.parsers <- nil
 			}
 		}()
+		var parser ctags.Parser
+		parser, parserName, err = s.parserFor(set, req.path)
+		if err != nil {
+			return nil, "", err
+		}
+
 		parsing.Inc()
 		defer parsing.Dec()
-		return parser.Parse(req.path, req.data)
+		entries, err = parser.Parse(req.path, req.data)
+		return entries, parserName, err
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"regexp/syntax"
 	"strings"
 	"time"
@@ -116,8 +117,22 @@ func (s *Service) search(ctx context.Context, args protocol.SearchArgs) (result
 // specified in `args`. If the database doesn't already exist in the disk cache,
 // it will create a new one and write all the symbols into it.
 func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (string, error) {
-	diskcacheFile, err := s.cache.OpenWithPath(ctx, fmt.Sprintf("%d-%s@%s", symbolsDBVersion, args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
-		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
+	diskcacheFile, err := s.cache.OpenWithPath(ctx, symbolsDBKey(args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
+		ok, err := s.writeChangedSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
+		if ok && err == nil {
+			return nil
+		}
+		if err != nil {
+			if fetcherCtx.Err() != nil {
+				return err
+			}
+			log15.Warn("Unable to incrementally index repository symbols, parsing all files", "repo", args.Repo, "commit", args.CommitID, "error", err)
+			if err := os.Truncate(tempDBFile, 0); err != nil {
+				return err
+			}
+		}
+
+		err = s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
 		if err != nil {
 			if err == context.Canceled {
 				log15.Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
@@ -131,9 +146,17 @@ func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (stri
 	}
 	defer diskcacheFile.File.Close()
 
+	s.recentCommits.add(args.Repo, args.CommitID)
+
 	return diskcacheFile.File.Name(), err
 }
 
+// symbolsDBKey returns the disk cache key of the sqlite3 database for
+// repo@commitID.
+func symbolsDBKey(repo api.RepoName, commitID api.CommitID) string {
+	return fmt.Sprintf("%d-%s@%s", symbolsDBVersion, repo, commitID)
+}
+
 // isLiteralEquality checks if the given regex matches literal strings exactly.
 // Returns whether or not the regex is exact, along with the literal string if
 // so.
@@ -202,12 +225,36 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 		return newConditions
 	}
 
+	// makeSetCondition returns a condition which matches rows where column
+	// is one of values, ignoring case.
+	makeSetCondition := func(column string, values []string) []*sqlf.Query {
+		if len(values) == 0 {
+			return nil
+		}
+		items := make([]*sqlf.Query, len(values))
+		for i, value := range values {
+			items[i] = sqlf.Sprintf("%s", strings.ToLower(value))
+		}
+		return []*sqlf.Query{sqlf.Sprintf("lower("+column+") IN (%s)", sqlf.Join(items, ","))}
+	}
+
 	var conditions []*sqlf.Query
 	conditions = append(conditions, makeCondition("name", args.Query)...)
 	for _, includePattern := range args.IncludePatterns {
 		conditions = append(conditions, makeCondition("path", includePattern)...)
 	}
 	conditions = append(conditions, negateAll(makeCondition("path", args.ExcludePattern))...)
+	conditions = append(conditions, makeSetCondition("kind", args.Kinds)...)
+	conditions = append(conditions, negateAll(makeSetCondition("kind", args.ExcludeKinds))...)
+	conditions = append(conditions, makeSetCondition("language", args.Languages)...)
+	if args.ParentQuery != "" {
+		// There is no parentlowercase column, so we can't use makeCondition.
+		regex := args.ParentQuery
+		if !args.IsCaseSensitive {
+			regex = "(?i:" + regex + ")"
+		}
+		conditions = append(conditions, sqlf.Sprintf("parent REGEXP %s", regex))
+	}
 
 	var sqlQuery *sqlf.Query
 	if len(conditions) == 0 {
@@ -234,11 +281,12 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 // filenames to prevent a newer version of the symbols service from attempting
 // to read from a database created by an older (and likely incompatible) symbols
 // service. Increment this when you change the database schema.
-const symbolsDBVersion = 2
+const symbolsDBVersion = 3
 
-// symbolInDB is the same as `protocol.Symbol`, but with two additional columns:
-// namelowercase and pathlowercase, which enable indexed case insensitive
-// queries.
+// symbolInDB is the same as `protocol.Symbol`, but with three additional
+// columns: namelowercase and pathlowercase, which enable indexed case
+// insensitive queries, and parser, which records the parser that produced the
+// symbol.
 type symbolInDB struct {
 	Name          string
 	NameLowercase string // derived from `Name`
@@ -251,11 +299,12 @@ type symbolInDB struct {
 	ParentKind    string
 	Signature     string
 	Pattern       string
+	Parser        string
 
 	FileLimited bool
 }
 
-func symbolToSymbolInDB(symbol protocol.Symbol) symbolInDB {
+func symbolToSymbolInDB(symbol protocol.Symbol, parser string) symbolInDB {
 	return symbolInDB{
 		Name:          symbol.Name,
 		NameLowercase: strings.ToLower(symbol.Name),
@@ -268,6 +317,7 @@ func symbolToSymbolInDB(symbol protocol.Symbol) symbolInDB {
 		ParentKind:    symbol.ParentKind,
 		Signature:     symbol.Signature,
 		Pattern:       symbol.Pattern,
+		Parser:        parser,
 
 		FileLimited: symbol.FileLimited,
 	}
@@ -304,10 +354,37 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
+	if err := createSymbolsTable(tx); err != nil {
+		return err
+	}
+
+	insertStatement, err := prepareInsertSymbol(tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.parseUncached(ctx, repoName, commitID, nil, func(symbol symbolInDB) error {
+		_, err := insertStatement.Exec(&symbol)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createSymbolsTable creates the symbols table and its indexes.
+func createSymbolsTable(tx *sqlx.Tx) error {
 	// The column names are the lowercase version of fields in `symbolInDB`
 	// because sqlx lowercases struct fields by default. See
 	// http://jmoiron.github.io/sqlx/#query
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		`CREATE TABLE IF NOT EXISTS symbols (
 			name VARCHAR(256) NOT NULL,
 			namelowercase VARCHAR(256) NOT NULL,
@@ -320,6 +397,7 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 			parentkind VARCHAR(255) NOT NULL,
 			signature VARCHAR(255) NOT NULL,
 			pattern VARCHAR(255) NOT NULL,
+			parser VARCHAR(255) NOT NULL,
 			filelimited BOOLEAN NOT NULL
 		)`)
 	if err != nil {
@@ -343,32 +421,15 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 	}
 
 	_, err = tx.Exec(`CREATE INDEX pathlowercase_index ON symbols(pathlowercase);`)
-	if err != nil {
-		return err
-	}
+	return err
+}
 
-	insertStatement, err := tx.PrepareNamed(
+// prepareInsertSymbol returns a statement which inserts a `symbolInDB` into
+// the symbols table.
+func prepareInsertSymbol(tx *sqlx.Tx) (*sqlx.NamedStmt, error) {
+	return tx.PrepareNamed(
 		fmt.Sprintf(
 			"INSERT INTO symbols %s VALUES %s",
-			"( name,  namelowercase,  path,  pathlowercase,  line,  kind,  language,  parent,  parentkind,  signature,  pattern,  filelimited)",
-			"(:name, :namelowercase, :path, :pathlowercase, :line, :kind, :language, :parent, :parentkind, :signature, :pattern, :filelimited)"))
-	if err != nil {
-		return err
-	}
-
-	err = s.parseUncached(ctx, repoName, commitID, func(symbol protocol.Symbol) error {
-		symbolInDBValue := symbolToSymbolInDB(symbol)
-		_, err := insertStatement.Exec(&symbolInDBValue)
-		return err
-	})
-	if err != nil {
-		return err
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return err
-	}
-
-	return nil
+			"( name,  namelowercase,  path,  pathlowercase,  line,  kind,  language,  parent,  parentkind,  signature,  pattern,  parser,  filelimited)",
+			"(:name, :namelowercase, :path, :pathlowercase, :line, :kind, :language, :parent, :parentkind, :signature, :pattern, :parser, :filelimited)"))
 }
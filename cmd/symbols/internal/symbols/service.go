@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/diskcache"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
 )
 
 // Service is the symbols service.
@@ -22,12 +23,29 @@ type Service struct {
 	// determine if the error is a bad request (eg invalid repo).
 	FetchTar func(context.Context, gitserver.Repo, api.CommitID) (io.ReadCloser, error)
 
+	// FetchTarPaths is like FetchTar, but the archive only contains the
+	// given paths. It is optional; if set together with GitDiff, symbols
+	// for a new commit are computed by only parsing the files which changed
+	// since a recently indexed commit.
+	FetchTarPaths func(context.Context, gitserver.Repo, api.CommitID, []string) (io.ReadCloser, error)
+
+	// GitDiff returns the paths which changed between two commits of a
+	// repository. It is optional, see FetchTarPaths.
+	GitDiff func(ctx context.Context, repo api.RepoName, a, b api.CommitID) (*git.Changes, error)
+
 	// MaxConcurrentFetchTar is the maximum number of concurrent calls allowed
-	// to FetchTar. It defaults to 15.
+	// to FetchTar and FetchTarPaths. It defaults to 15.
 	MaxConcurrentFetchTar int
 
+	// NewParser creates the fallback parser, which parses the files that no
+	// parser in LanguageParsers handles. It is usually universal-ctags.
 	NewParser func() (ctags.Parser, error)
 
+	// LanguageParsers are parsers for specific languages. They take
+	// precedence over NewParser, and a file is parsed by the first of them
+	// that handles its extension. It is optional.
+	LanguageParsers []LanguageParser
+
 	// NumParserProcesses is the maximum number of ctags parser child processes to run.
 	NumParserProcesses int
 
@@ -47,8 +65,13 @@ type Service struct {
 	// semaphore size is controlled by MaxConcurrentFetchTar
 	fetchSem chan int
 
-	// pool of ctags parser child processes
-	parsers chan ctags.Parser
+	// pool of parsers, each with its own ctags parser child process
+	parsers chan *parserSet
+
+	// recentCommits are the most recently searched commits of each
+	// repository. Their databases are used as the base for incrementally
+	// indexing new commits.
+	recentCommits recentCommits
 }
 
 // Start must be called before any requests are handled.
@@ -13,14 +13,18 @@ import (
 	"path"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/pkg/ctags"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
 	symbolsclient "github.com/sourcegraph/sourcegraph/pkg/symbols"
 	"github.com/sourcegraph/sourcegraph/pkg/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
 )
 
 func init() {
@@ -72,8 +76,14 @@ func TestIsLiteralEquality(t *testing.T) {
 	}
 }
 
+var registerSqlite3Once sync.Once
+
+func registerSqlite3() {
+	registerSqlite3Once.Do(MustRegisterSqlite3WithPcre)
+}
+
 func TestService(t *testing.T) {
-	MustRegisterSqlite3WithPcre()
+	registerSqlite3()
 
 	tmpDir, err := ioutil.TempDir("", "")
 	if err != nil {
@@ -98,8 +108,8 @@ func TestService(t *testing.T) {
 	server := httptest.NewServer(service.Handler())
 	defer server.Close()
 	client := symbolsclient.Client{URL: server.URL}
-	x := protocol.Symbol{Name: "x", Path: "a.js"}
-	y := protocol.Symbol{Name: "y", Path: "a.js"}
+	x := protocol.Symbol{Name: "x", Path: "a.js", Kind: "function", Language: "JavaScript"}
+	y := protocol.Symbol{Name: "y", Path: "a.js", Kind: "variable", Language: "JavaScript", Parent: "x", ParentKind: "function"}
 
 	tests := map[string]struct {
 		args protocol.SearchArgs
@@ -145,6 +155,34 @@ func TestService(t *testing.T) {
 			args: protocol.SearchArgs{ExcludePattern: "a.js", IsCaseSensitive: true, First: 10},
 			want: protocol.SearchResult{},
 		},
+		"kinds": {
+			args: protocol.SearchArgs{Kinds: []string{"Function", "class"}, First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{x}},
+		},
+		"excludekinds": {
+			args: protocol.SearchArgs{ExcludeKinds: []string{"function"}, First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{y}},
+		},
+		"languages": {
+			args: protocol.SearchArgs{Languages: []string{"javascript"}, First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{x, y}},
+		},
+		"nolanguagematch": {
+			args: protocol.SearchArgs{Languages: []string{"go"}, First: 10},
+			want: protocol.SearchResult{},
+		},
+		"parent": {
+			args: protocol.SearchArgs{ParentQuery: "^X$", First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{y}},
+		},
+		"casesensitiveparent": {
+			args: protocol.SearchArgs{ParentQuery: "^X$", IsCaseSensitive: true, First: 10},
+			want: protocol.SearchResult{},
+		},
+		"kindandparent": {
+			args: protocol.SearchArgs{Query: "y", Kinds: []string{"variable"}, ParentQuery: "x", First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{y}},
+		},
 	}
 	for label, test := range tests {
 		t.Run(label, func(t *testing.T) {
@@ -159,6 +197,149 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestService_incremental(t *testing.T) {
+	registerSqlite3()
+
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.RemoveAll(tmpDir) }()
+
+	commits := map[api.CommitID]map[string]string{
+		"a": {"a.js": "x", "b.js": "y", "c.js": "z"},
+		"b": {"a.js": "x", "b.js": "y2", "d.js": "w"},
+	}
+	var fetchedPaths [][]string
+	service := Service{
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+			fetchedPaths = append(fetchedPaths, nil)
+			return createTar(commits[commit])
+		},
+		FetchTarPaths: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			fetchedPaths = append(fetchedPaths, paths)
+			files := map[string]string{}
+			for _, path := range paths {
+				files[path] = commits[commit][path]
+			}
+			return createTar(files)
+		},
+		GitDiff: func(ctx context.Context, repo api.RepoName, a, b api.CommitID) (*git.Changes, error) {
+			if a != "a" || b != "b" {
+				return nil, fmt.Errorf("unexpected diff %s..%s", a, b)
+			}
+			return &git.Changes{
+				Added:    []string{"d.js"},
+				Modified: []string{"b.js"},
+				Deleted:  []string{"c.js"},
+			}, nil
+		},
+		NewParser: func() (ctags.Parser, error) {
+			return contentParser{}, nil
+		},
+		Path: tmpDir,
+	}
+
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(service.Handler())
+	defer server.Close()
+	client := symbolsclient.Client{URL: server.URL}
+
+	search := func(commitID api.CommitID) []string {
+		result, err := client.Search(context.Background(), protocol.SearchArgs{Repo: "r", CommitID: commitID, First: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, symbol := range result.Symbols {
+			got = append(got, symbol.Path+":"+symbol.Name)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	if got, want := search("a"), []string{"a.js:x", "b.js:y", "c.js:z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := search("b"), []string{"a.js:x", "b.js:y2", "d.js:w"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Only the changed files of b should have been fetched.
+	if len(fetchedPaths) != 2 {
+		t.Fatalf("got %d fetches, want 2", len(fetchedPaths))
+	}
+	sort.Strings(fetchedPaths[1])
+	if want := []string{"b.js", "d.js"}; !reflect.DeepEqual(fetchedPaths[1], want) {
+		t.Errorf("got fetched paths %v, want %v", fetchedPaths[1], want)
+	}
+}
+
+func TestService_languageParsers(t *testing.T) {
+	registerSqlite3()
+
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.RemoveAll(tmpDir) }()
+
+	files := map[string]string{"a.js": "x", "b.go": "y", "c.txt": "z"}
+	service := Service{
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+			return createTar(files)
+		},
+		NewParser: func() (ctags.Parser, error) {
+			return contentParser{}, nil
+		},
+		LanguageParsers: []LanguageParser{
+			{
+				Name:       "go",
+				Extensions: []string{".go"},
+				New: func() (ctags.Parser, error) {
+					return mockParser{"g"}, nil
+				},
+			},
+			// The first parser that handles an extension takes precedence.
+			{
+				Name:       "other",
+				Extensions: []string{".go", ".js"},
+				New: func() (ctags.Parser, error) {
+					return mockParser{"o"}, nil
+				},
+			},
+		},
+		Path: tmpDir,
+	}
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	dbFile, err := service.getDBFile(context.Background(), protocol.SearchArgs{Repo: "r", CommitID: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlx.Open("sqlite3_with_pcre", dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var rows []struct{ Name, Parser string }
+	if err := db.Select(&rows, "SELECT name, parser FROM symbols ORDER BY name"); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, len(rows))
+	for i, row := range rows {
+		got[i] = row.Name + ":" + row.Parser
+	}
+	if want := []string{"g:go", "o:other", "z:ctags"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
 func createTar(files map[string]string) (io.ReadCloser, error) {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
@@ -185,12 +366,28 @@ func createTar(files map[string]string) (io.ReadCloser, error) {
 
 type mockParser []string
 
+// Parse returns a function entry for the first name, and variable entries
+// inside that function for the remaining names.
 func (m mockParser) Parse(name string, content []byte) ([]ctags.Entry, error) {
 	entries := make([]ctags.Entry, len(m))
 	for i, name := range m {
-		entries[i] = ctags.Entry{Name: name, Path: "a.js"}
+		entries[i] = ctags.Entry{Name: name, Path: "a.js", Kind: "function", Language: "JavaScript"}
+		if i > 0 {
+			entries[i].Kind = "variable"
+			entries[i].Parent = m[0]
+			entries[i].ParentKind = "function"
+		}
 	}
 	return entries, nil
 }
 
 func (mockParser) Close() {}
+
+// contentParser returns a symbol named after the content of each file.
+type contentParser struct{}
+
+func (contentParser) Parse(name string, content []byte) ([]ctags.Entry, error) {
+	return []ctags.Entry{{Name: string(content), Path: name}}, nil
+}
+
+func (contentParser) Close() {}
@@ -0,0 +1,85 @@
+package authz
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	permbbs "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func bitbucketServerProviders(ctx context.Context, conns []*schema.BitbucketServerConnection) (
+	authzProviders []authz.Provider,
+	seriousProblems []string,
+	warnings []string,
+) {
+	for _, c := range conns {
+		p, err := bitbucketServerProvider(c)
+		if err != nil {
+			seriousProblems = append(seriousProblems, err.Error())
+			continue
+		}
+		if p != nil {
+			authzProviders = append(authzProviders, p)
+		}
+	}
+	return authzProviders, seriousProblems, warnings
+}
+
+func bitbucketServerProvider(c *schema.BitbucketServerConnection) (authz.Provider, error) {
+	a := c.Authorization
+	if a == nil {
+		return nil, nil
+	}
+
+	if a.IdentityProvider.Username == nil {
+		return nil, fmt.Errorf("No identityProvider was specified for Bitbucket Server instance %q", c.Url)
+	}
+
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse URL for Bitbucket Server instance %q: %s", c.Url, err)
+	}
+
+	ttl, err := parseTTL(a.Ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	oauth, err := bitbucketserver.NewOAuth(a.Oauth.ConsumerKey, a.Oauth.SigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("authorization.oauth: %s", err)
+	}
+
+	var opts []httpcli.Opt
+	if c.Certificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.Certificate)) {
+			return nil, fmt.Errorf("Invalid certificate for Bitbucket Server instance %q", c.Url)
+		}
+		opts = append(opts, httpcli.NewCertPoolOpt(pool))
+	}
+	// Responses must not be cached by the HTTP client, since they depend on the impersonated user.
+	cf := httpcli.NewFactory(httpcli.NewMiddleware(httpcli.ContextErrorMiddleware), httpcli.TracedTransportOpt)
+	doer, err := cf.Doer(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cli := bitbucketserver.NewClient(baseURL, doer)
+	cli.OAuth = oauth
+
+	return permbbs.NewProvider(cli, ttl, nil), nil
+}
+
+// ValidateBitbucketServerAuthz validates the authorization fields of the given Bitbucket Server
+// external service config.
+func ValidateBitbucketServerAuthz(c *schema.BitbucketServerConnection) error {
+	_, err := bitbucketServerProvider(c)
+	return err
+}
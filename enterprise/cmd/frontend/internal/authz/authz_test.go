@@ -2,7 +2,12 @@ package authz
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"net/url"
 	"reflect"
 	"testing"
@@ -11,10 +16,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/explicit"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitlab"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -324,6 +331,16 @@ func Test_providersFromConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "explicit permissions enabled",
+			cfg: conf.Unified{
+				SiteConfiguration: schema.SiteConfiguration{
+					PermissionsExplicit: &schema.PermissionsExplicit{Enabled: true, ServiceTypes: []string{"other"}},
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders:            []authz.Provider{explicit.NewProvider([]string{"other"})},
+		},
 	}
 
 	for _, test := range tests {
@@ -360,8 +377,9 @@ func asJSON(t *testing.T, v interface{}) string {
 }
 
 type fakeStore struct {
-	gitlabs []*schema.GitLabConnection
-	githubs []*schema.GitHubConnection
+	gitlabs          []*schema.GitLabConnection
+	githubs          []*schema.GitHubConnection
+	bitbucketServers []*schema.BitbucketServerConnection
 }
 
 func (s fakeStore) ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error) {
@@ -371,3 +389,49 @@ func (s fakeStore) ListGitHubConnections(context.Context) ([]*schema.GitHubConne
 func (s fakeStore) ListGitLabConnections(context.Context) ([]*schema.GitLabConnection, error) {
 	return s.gitlabs, nil
 }
+
+func (s fakeStore) ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error) {
+	return s.bitbucketServers, nil
+}
+
+func Test_providersFromConfig_bitbucketServer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	store := fakeStore{bitbucketServers: []*schema.BitbucketServerConnection{
+		{
+			Url:      "https://bitbucket.example.com",
+			Username: "admin",
+			Token:    "asdf",
+			Authorization: &schema.BitbucketServerAuthorization{
+				IdentityProvider: schema.BitbucketServerIdentityProvider{Username: &schema.BitbucketServerUsernameIdentity{Type: "username"}},
+				Oauth:            schema.BitbucketServerOAuth{ConsumerKey: "sourcegraph", SigningKey: signingKey},
+			},
+		},
+		{
+			// Without an authorization block, no authz provider is created.
+			Url:      "https://bitbucket2.example.com",
+			Username: "admin",
+			Token:    "asdf",
+		},
+	}}
+	allowAccessByDefault, authzProviders, seriousProblems, _ := ProvidersFromConfig(context.Background(), &conf.Unified{}, &store)
+	if !allowAccessByDefault {
+		t.Error("got allowAccessByDefault false, want true")
+	}
+	if len(seriousProblems) != 0 {
+		t.Errorf("got serious problems %v, want none", seriousProblems)
+	}
+	if len(authzProviders) != 1 {
+		t.Fatalf("got %d authz providers, want 1", len(authzProviders))
+	}
+	if got, want := authzProviders[0].ServiceID(), "https://bitbucket.example.com/"; got != want {
+		t.Errorf("got ServiceID %q, want %q", got, want)
+	}
+	if got, want := authzProviders[0].ServiceType(), bitbucketserver.ServiceType; got != want {
+		t.Errorf("got ServiceType %q, want %q", got, want)
+	}
+}
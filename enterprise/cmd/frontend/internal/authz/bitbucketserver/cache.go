@@ -0,0 +1,56 @@
+package bitbucketserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, b []byte)
+	Delete(key string)
+}
+
+// userReposCacheKey returns the key for caching the IDs of the Bitbucket Server repositories that
+// the given Bitbucket Server user can read. This key must be unique among *all* Provider cache keys,
+// including the one for public repositories (see publicReposCacheKey).
+func userReposCacheKey(username string) string {
+	return fmt.Sprintf("userRepos:%s", username)
+}
+
+// publicReposCacheKey is the key for caching the IDs of the public Bitbucket Server repositories.
+const publicReposCacheKey = "publicRepos"
+
+type reposCacheVal struct {
+	// IDs are the Bitbucket Server repository IDs.
+	IDs []int
+
+	TTL time.Duration
+}
+
+func cacheGetRepos(c cache, key string, ttl time.Duration) (v reposCacheVal, exists bool) {
+	b, exists := c.Get(key)
+	if !exists {
+		return reposCacheVal{}, false
+	}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		c.Delete(key)
+		return reposCacheVal{}, false
+	}
+	if v.TTL != ttl {
+		c.Delete(key)
+		return reposCacheVal{}, false
+	}
+	return v, true
+}
+
+func cacheSetRepos(c cache, key string, v reposCacheVal) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.Set(key, b)
+	return nil
+}
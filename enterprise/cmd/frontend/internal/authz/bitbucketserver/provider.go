@@ -0,0 +1,229 @@
+// Package bitbucketserver contains an authorization provider for Bitbucket Server.
+package bitbucketserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+)
+
+// Provider is an implementation of authz.Provider that provides repository permissions as
+// determined from a Bitbucket Server instance API.
+//
+// It queries the API with the OAuth credentials of an application link that allows user
+// impersonation, on behalf of the Bitbucket Server user whose username is the same as the
+// Sourcegraph user's username.
+type Provider struct {
+	client   *bitbucketserver.Client
+	codeHost *bitbucketserver.CodeHost
+	cache    cache
+	cacheTTL time.Duration
+}
+
+var _ authz.Provider = ((*Provider)(nil))
+
+// reposPageSize is the number of repositories to list at a time from the Bitbucket Server API.
+const reposPageSize = 1000
+
+// NewProvider returns a new Bitbucket Server authorization provider that uses the given client,
+// which must have OAuth credentials set (see bitbucketserver.NewOAuth). If mockCache is non-nil, it
+// replaces the default Redis-based cache and should only be used in tests.
+func NewProvider(cli *bitbucketserver.Client, cacheTTL time.Duration, mockCache cache) *Provider {
+	p := &Provider{
+		client:   cli,
+		codeHost: bitbucketserver.NewCodeHost(cli.URL),
+		cache:    mockCache,
+		cacheTTL: cacheTTL,
+	}
+	// Note: this will use the same underlying Redis instance and key namespace for every instance
+	// of Provider.  This is by design, so that different instances, even in different processes,
+	// will share cache entries.
+	if p.cache == nil {
+		p.cache = rcache.NewWithTTL(fmt.Sprintf("bitbucketServerAuthz:%s", p.codeHost.ServiceID()), int(math.Ceil(cacheTTL.Seconds())))
+	}
+	return p
+}
+
+func (p *Provider) Validate() (problems []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := p.client.Users(ctx, &bitbucketserver.PageToken{Limit: 1}, ""); err != nil {
+		if err == ctx.Err() {
+			problems = append(problems, fmt.Sprintf("Bitbucket Server API did not respond within 5s (%s)", err.Error()))
+		} else {
+			problems = append(problems, fmt.Sprintf("Bitbucket Server API rejected the application link OAuth credentials (%s)", err.Error()))
+		}
+	}
+	return problems
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID()
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType()
+}
+
+// Repos implements the authz.Provider interface.
+func (p *Provider) Repos(ctx context.Context, repos map[authz.Repo]struct{}) (mine map[authz.Repo]struct{}, others map[authz.Repo]struct{}) {
+	return authz.GetCodeHostRepos(p.codeHost, repos)
+}
+
+// RepoPerms implements the authz.Provider interface.
+//
+// A user can read a repository if the repository is public or if the Bitbucket Server API lists it
+// among the repositories that the user has REPO_READ permission on. Both lists of repositories are
+// cached (per user and globally, respectively) for the configured TTL.
+func (p *Provider) RepoPerms(ctx context.Context, account *extsvc.ExternalAccount, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	remaining, _ := p.Repos(ctx, repos)
+	if len(remaining) == 0 {
+		return nil, nil
+	}
+
+	publicIDs, err := p.repoIDs(ctx, "", publicReposCacheKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing public Bitbucket Server repositories")
+	}
+
+	var userIDs map[string]struct{}
+	if account != nil && account.ServiceID == p.codeHost.ServiceID() && account.ServiceType == p.codeHost.ServiceType() {
+		var user bitbucketserver.User
+		if err := account.GetAccountData(&user); err != nil {
+			return nil, err
+		}
+		if user.Name != "" {
+			userIDs, err = p.repoIDs(ctx, user.Name, userReposCacheKey(user.Name))
+			if err != nil {
+				return nil, errors.Wrapf(err, "listing Bitbucket Server repositories readable by user %q", user.Name)
+			}
+		}
+	}
+
+	perms := make(map[api.RepoName]map[authz.Perm]bool, len(remaining))
+	for repo := range remaining {
+		_, public := publicIDs[repo.ExternalRepoSpec.ID]
+		_, readable := userIDs[repo.ExternalRepoSpec.ID]
+		if public || readable {
+			perms[repo.RepoName] = map[authz.Perm]bool{authz.Read: true}
+		}
+	}
+	return perms, nil
+}
+
+// repoIDs returns the set of external IDs (stringified Bitbucket Server repository IDs) of the
+// repositories readable by the Bitbucket Server user with the given username, or of the public
+// repositories if username is empty. It consults and updates the cache entry with the given key.
+func (p *Provider) repoIDs(ctx context.Context, username, cacheKey string) (map[string]struct{}, error) {
+	val, exists := cacheGetRepos(p.cache, cacheKey, p.cacheTTL)
+	if !exists {
+		ids, err := p.fetchRepoIDs(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		val = reposCacheVal{IDs: ids, TTL: p.cacheTTL}
+		if err := cacheSetRepos(p.cache, cacheKey, val); err != nil {
+			return nil, errors.Wrap(err, "could not set cached repos")
+		}
+	}
+
+	set := make(map[string]struct{}, len(val.IDs))
+	for _, id := range val.IDs {
+		set[strconv.Itoa(id)] = struct{}{}
+	}
+	return set, nil
+}
+
+// fetchRepoIDs lists the IDs of the repositories readable by the Bitbucket Server user with the
+// given username (by impersonating the user), or of the public repositories if username is empty.
+func (p *Provider) fetchRepoIDs(ctx context.Context, username string) ([]int, error) {
+	cli, query := p.client, "visibility=public"
+	if username != "" {
+		var err error
+		if cli, err = p.client.Sudo(username); err != nil {
+			return nil, err
+		}
+		query = "permission=REPO_READ"
+	}
+
+	var ids []int
+	t := &bitbucketserver.PageToken{Limit: reposPageSize}
+	for {
+		repos, next, err := cli.Repos(ctx, t, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			ids = append(ids, r.ID)
+		}
+		if next == nil || !next.HasMore() {
+			return ids, nil
+		}
+		t = next
+	}
+}
+
+// FetchAccount satisfies the authz.Provider interface. It returns the Bitbucket Server user whose
+// username is the same as the Sourcegraph user's username, or nil if there is no such user.
+//
+// 🚨 SECURITY: This relies on Sourcegraph usernames being the same as Bitbucket Server usernames,
+// which is only secure if users can't freely choose their Sourcegraph username (e.g., because
+// Sourcegraph users are created by an SSO authentication provider that Bitbucket Server also uses).
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.ExternalAccount) (mine *extsvc.ExternalAccount, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	bbUser, err := p.fetchUserByUsername(ctx, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	if bbUser == nil {
+		return nil, nil
+	}
+
+	var accountData extsvc.ExternalAccountData
+	accountData.SetAccountData(bbUser)
+
+	return &extsvc.ExternalAccount{
+		UserID: user.ID,
+		ExternalAccountSpec: extsvc.ExternalAccountSpec{
+			ServiceType: p.codeHost.ServiceType(),
+			ServiceID:   p.codeHost.ServiceID(),
+			AccountID:   strconv.Itoa(bbUser.ID),
+		},
+		ExternalAccountData: accountData,
+	}, nil
+}
+
+// fetchUserByUsername returns the Bitbucket Server user with the given username, or nil if there is
+// no such user. The Bitbucket Server API only supports substring filtering of users, so it looks
+// for an exact match among the filtered users.
+func (p *Provider) fetchUserByUsername(ctx context.Context, username string) (*bitbucketserver.User, error) {
+	t := &bitbucketserver.PageToken{Limit: reposPageSize}
+	for {
+		users, next, err := p.client.Users(ctx, t, username)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if u.Name == username {
+				return u, nil
+			}
+		}
+		if next == nil || !next.HasMore() {
+			return nil, nil
+		}
+		t = next
+	}
+}
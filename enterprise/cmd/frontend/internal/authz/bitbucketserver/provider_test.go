@@ -0,0 +1,224 @@
+package bitbucketserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/httptestutil"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p, save := newProvider(t, "FetchAccount", nil)
+	defer save()
+
+	for _, tc := range []struct {
+		username string
+		wantID   string
+	}{
+		// The Bitbucket Server API also returns "alice-smith" and "alice2", which must not match.
+		{username: "alice", wantID: "2"},
+		{username: "bob", wantID: ""},
+	} {
+		t.Run(tc.username, func(t *testing.T) {
+			acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: tc.username}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantID == "" {
+				if acct != nil {
+					t.Fatalf("got account %+v, want nil", acct)
+				}
+				return
+			}
+			if acct == nil {
+				t.Fatal("got nil account")
+			}
+			if want := (extsvc.ExternalAccountSpec{
+				ServiceType: bitbucketserver.ServiceType,
+				ServiceID:   "http://127.0.0.1:7990/",
+				AccountID:   tc.wantID,
+			}); acct.ExternalAccountSpec != want {
+				t.Errorf("got account spec %+v, want %+v", acct.ExternalAccountSpec, want)
+			}
+			var user bitbucketserver.User
+			if err := acct.GetAccountData(&user); err != nil {
+				t.Fatal(err)
+			}
+			if user.Name != tc.username {
+				t.Errorf("got account data username %q, want %q", user.Name, tc.username)
+			}
+		})
+	}
+}
+
+func TestProvider_RepoPerms(t *testing.T) {
+	cache := mockCache{}
+	p, save := newProvider(t, "RepoPerms", cache)
+	defer save()
+
+	repo := func(id string) authz.Repo {
+		return authz.Repo{
+			RepoName: api.RepoName("127.0.0.1/" + id),
+			ExternalRepoSpec: api.ExternalRepoSpec{
+				ID:          id,
+				ServiceType: bitbucketserver.ServiceType,
+				ServiceID:   "http://127.0.0.1:7990/",
+			},
+		}
+	}
+	repos := map[authz.Repo]struct{}{
+		repo("1"): {}, // public
+		repo("2"): {}, // readable by alice
+		repo("3"): {}, // private
+		{RepoName: "github.com/foo/bar", ExternalRepoSpec: api.ExternalRepoSpec{ID: "1", ServiceType: "github", ServiceID: "https://github.com/"}}: {},
+	}
+
+	var alice extsvc.ExternalAccount
+	alice.ServiceType = bitbucketserver.ServiceType
+	alice.ServiceID = "http://127.0.0.1:7990/"
+	alice.AccountID = "2"
+	alice.SetAccountData(&bitbucketserver.User{ID: 2, Name: "alice", Slug: "alice"})
+
+	for _, tc := range []struct {
+		name      string
+		account   *extsvc.ExternalAccount
+		wantPerms map[api.RepoName]map[authz.Perm]bool
+	}{
+		{
+			name:    "anonymous",
+			account: nil,
+			wantPerms: map[api.RepoName]map[authz.Perm]bool{
+				"127.0.0.1/1": {authz.Read: true},
+			},
+		},
+		{
+			name:    "alice",
+			account: &alice,
+			wantPerms: map[api.RepoName]map[authz.Perm]bool{
+				"127.0.0.1/1": {authz.Read: true},
+				"127.0.0.1/2": {authz.Read: true},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			perms, err := p.RepoPerms(context.Background(), tc.account, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(perms, tc.wantPerms) {
+				t.Errorf("got perms %v, want %v", perms, tc.wantPerms)
+			}
+		})
+	}
+
+	for key, want := range map[string][]int{
+		publicReposCacheKey:        {1},
+		userReposCacheKey("alice"): {2},
+	} {
+		v, ok := cacheGetRepos(cache, key, p.cacheTTL)
+		if !ok {
+			t.Errorf("cache key %q not set", key)
+			continue
+		}
+		if !reflect.DeepEqual(v.IDs, want) {
+			t.Errorf("cache key %q: got repo IDs %v, want %v", key, v.IDs, want)
+		}
+	}
+
+	// A cache entry with a different TTL is ignored and deleted.
+	if _, ok := cacheGetRepos(cache, publicReposCacheKey, time.Minute); ok {
+		t.Error("got cache entry with mismatched TTL")
+	}
+	if _, ok := cache[publicReposCacheKey]; ok {
+		t.Error("cache entry with mismatched TTL was not deleted")
+	}
+}
+
+// newProvider returns a Provider whose HTTP requests are recorded in (or replayed from)
+// testdata/vcr/<name>.yaml.
+//
+// Updating the testdata with the -update flag requires a local Bitbucket Server instance (see
+// TestSources_ListRepos in cmd/repo-updater/repos) with users "alice", "alice-smith" and
+// "alice2" and repositories 1 (public), 2 (readable by alice) and 3 (private), and an
+// application link whose incoming authentication uses BITBUCKET_SERVER_CONSUMER_KEY and the public
+// key of BITBUCKET_SERVER_SIGNING_KEY and allows user impersonation.
+func newProvider(t testing.TB, name string, c cache) (*Provider, func()) {
+	t.Helper()
+
+	cassete := filepath.Join("testdata/vcr/", strings.Replace(name, " ", "-", -1))
+	rec, err := httptestutil.NewRecorder(cassete, *update)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hc, err := httpcli.NewFactory(nil, httptestutil.NewRecorderOpt(rec)).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consumerKey, signingKey := "sourcegraph", newSigningKey(t)
+	if *update {
+		consumerKey = os.Getenv("BITBUCKET_SERVER_CONSUMER_KEY")
+		signingKey = os.Getenv("BITBUCKET_SERVER_SIGNING_KEY")
+	}
+
+	u, err := url.Parse("http://127.0.0.1:7990")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := bitbucketserver.NewClient(u, hc)
+	if cli.OAuth, err = bitbucketserver.NewOAuth(consumerKey, signingKey); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewProvider(cli, time.Hour, c), func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("failed to update test data: %s", err)
+		}
+	}
+}
+
+// newSigningKey returns a base64-encoded PEM RSA private key, which is only used to sign (and not
+// verify) requests when replaying recorded HTTP interactions.
+func newSigningKey(t testing.TB) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return base64.StdEncoding.EncodeToString(pemKey)
+}
+
+type mockCache map[string]string
+
+func (m mockCache) Get(key string) ([]byte, bool) {
+	v, ok := m[key]
+	return []byte(v), ok
+}
+func (m mockCache) Set(key string, b []byte) {
+	m[key] = string(b)
+}
+func (m mockCache) Delete(key string) {
+	delete(m, key)
+}
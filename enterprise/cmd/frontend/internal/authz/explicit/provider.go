@@ -0,0 +1,117 @@
+// Package explicit contains an authorization provider for repository permissions that site admins
+// explicitly grant on Sourcegraph.
+package explicit
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+)
+
+const (
+	// ServiceType is the service type of the explicit permissions authz provider.
+	ServiceType = "explicit"
+
+	// ServiceID is the service ID of the explicit permissions authz provider. There is only one such
+	// provider, because the permissions are stored in Sourcegraph's own database.
+	ServiceID = "sourcegraph"
+)
+
+// DefaultServiceTypes are the service types of the repositories whose permissions are explicitly
+// granted if none are configured. Repositories of these service types have no other authz provider.
+var DefaultServiceTypes = []string{"gitolite", "other"}
+
+// Provider is an implementation of authz.UserPermsProvider that provides repository permissions as
+// explicitly granted by site admins to users and organizations (see db.ExplicitRepoPermissions).
+//
+// It is the source of permissions for all repositories of the configured external service types,
+// so it must come after all code host authz providers.
+type Provider struct {
+	serviceTypes map[string]struct{}
+}
+
+var _ authz.UserPermsProvider = ((*Provider)(nil))
+
+// NewProvider returns a new explicit permissions authorization provider for the repositories of the
+// given external service types (or DefaultServiceTypes if none are given).
+func NewProvider(serviceTypes []string) *Provider {
+	if len(serviceTypes) == 0 {
+		serviceTypes = DefaultServiceTypes
+	}
+	p := &Provider{serviceTypes: make(map[string]struct{}, len(serviceTypes))}
+	for _, t := range serviceTypes {
+		p.serviceTypes[t] = struct{}{}
+	}
+	return p
+}
+
+func (p *Provider) Validate() (problems []string) {
+	return nil
+}
+
+func (p *Provider) ServiceID() string {
+	return ServiceID
+}
+
+func (p *Provider) ServiceType() string {
+	return ServiceType
+}
+
+// Repos implements the authz.Provider interface. It claims all repositories of the configured
+// external service types.
+func (p *Provider) Repos(ctx context.Context, repos map[authz.Repo]struct{}) (mine map[authz.Repo]struct{}, others map[authz.Repo]struct{}) {
+	mine, others = make(map[authz.Repo]struct{}), make(map[authz.Repo]struct{})
+	for repo := range repos {
+		if _, ok := p.serviceTypes[repo.ExternalRepoSpec.ServiceType]; ok {
+			mine[repo] = struct{}{}
+		} else {
+			others[repo] = struct{}{}
+		}
+	}
+	return mine, others
+}
+
+// UserRepoPerms implements the authz.UserPermsProvider interface.
+//
+// A user can read a repository if the user (or an organization that the user is a member of) was
+// granted read permission on the repository or on a repository name pattern that matches it.
+// Unauthenticated users can't read any of the repositories.
+func (p *Provider) UserRepoPerms(ctx context.Context, user *types.User, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	remaining, _ := p.Repos(ctx, repos)
+	if user == nil || len(remaining) == 0 {
+		return nil, nil
+	}
+
+	grants, err := db.ExplicitRepoPermissions.ListForUser(ctx, user.ID, authz.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing explicit repository permissions")
+	}
+
+	perms := make(map[api.RepoName]map[authz.Perm]bool)
+	for repo := range remaining {
+		for _, grant := range grants {
+			if grant.Matches(repo.RepoName) {
+				perms[repo.RepoName] = map[authz.Perm]bool{authz.Read: true}
+				break
+			}
+		}
+	}
+	return perms, nil
+}
+
+// RepoPerms implements the authz.Provider interface. It is never called, because callers call
+// UserRepoPerms instead.
+func (p *Provider) RepoPerms(ctx context.Context, account *extsvc.ExternalAccount, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	return nil, nil
+}
+
+// FetchAccount implements the authz.Provider interface. It always returns nil, because the
+// permissions apply to Sourcegraph users themselves (and not to external accounts).
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.ExternalAccount) (mine *extsvc.ExternalAccount, err error) {
+	return nil, nil
+}
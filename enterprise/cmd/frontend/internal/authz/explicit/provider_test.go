@@ -0,0 +1,79 @@
+package explicit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestProvider_UserRepoPerms(t *testing.T) {
+	db.Mocks.ExplicitRepoPermissions.ListForUser = func(ctx context.Context, userID int32, perm authz.Perm) ([]*db.ExplicitRepoPermission, error) {
+		if userID != 1 {
+			return nil, nil
+		}
+		return []*db.ExplicitRepoPermission{
+			{UserID: 1, RepoID: 1, RepoName: "gitolite.example.com/a", Perm: authz.Read},
+			{OrgID: 1, RepoPattern: "git.example.com/team/*", Perm: authz.Read},
+		}, nil
+	}
+	defer func() { db.Mocks.ExplicitRepoPermissions.ListForUser = nil }()
+
+	repo := func(name api.RepoName, serviceType string) authz.Repo {
+		return authz.Repo{
+			RepoName:         name,
+			ExternalRepoSpec: api.ExternalRepoSpec{ID: string(name), ServiceType: serviceType, ServiceID: "https://" + serviceType + ".example.com/"},
+		}
+	}
+	repos := map[authz.Repo]struct{}{
+		repo("gitolite.example.com/a", "gitolite"): {},
+		repo("gitolite.example.com/b", "gitolite"): {},
+		repo("git.example.com/team/c", "other"):    {},
+		repo("github.com/team/d", "github"):        {}, // not claimed by the provider
+	}
+
+	p := NewProvider(nil)
+	for _, tc := range []struct {
+		name      string
+		user      *types.User
+		wantPerms map[api.RepoName]map[authz.Perm]bool
+	}{
+		{
+			name:      "unauthenticated",
+			user:      nil,
+			wantPerms: nil,
+		},
+		{
+			name: "granted",
+			user: &types.User{ID: 1},
+			wantPerms: map[api.RepoName]map[authz.Perm]bool{
+				"gitolite.example.com/a": {authz.Read: true},
+				"git.example.com/team/c": {authz.Read: true},
+			},
+		},
+		{
+			name:      "not granted",
+			user:      &types.User{ID: 2},
+			wantPerms: map[api.RepoName]map[authz.Perm]bool{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			perms, err := p.UserRepoPerms(context.Background(), tc.user, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(perms, tc.wantPerms) {
+				t.Errorf("got perms %v, want %v", perms, tc.wantPerms)
+			}
+		})
+	}
+
+	mine, others := NewProvider([]string{"github"}).Repos(context.Background(), repos)
+	if len(mine) != 1 || len(others) != 3 {
+		t.Errorf("got %d claimed and %d other repos, want 1 and 3", len(mine), len(others))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/explicit"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/schema"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -13,6 +14,7 @@ import (
 type ExternalServicesStore interface {
 	ListGitLabConnections(context.Context) ([]*schema.GitLabConnection, error)
 	ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error)
+	ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error)
 }
 
 // ProvidersFromConfig returns the set of permission-related providers derived from the site config.
@@ -55,5 +57,20 @@ func ProvidersFromConfig(
 		warnings = append(warnings, ghwarnings...)
 	}
 
+	if bitbucketServers, err := s.ListBitbucketServerConnections(ctx); err != nil {
+		seriousProblems = append(seriousProblems, fmt.Sprintf("Could not load Bitbucket Server external service configs: %s", err))
+	} else {
+		bbsp, bbsproblems, bbswarnings := bitbucketServerProviders(ctx, bitbucketServers)
+		authzProviders = append(authzProviders, bbsp...)
+		seriousProblems = append(seriousProblems, bbsproblems...)
+		warnings = append(warnings, bbswarnings...)
+	}
+
+	// The explicit permissions authz provider must come last, so that code host authz providers are
+	// the source of permissions for the repositories they claim.
+	if c := cfg.PermissionsExplicit; c != nil && c.Enabled {
+		authzProviders = append(authzProviders, explicit.NewProvider(c.ServiceTypes))
+	}
+
 	return allowAccessByDefault, authzProviders, seriousProblems, warnings
 }
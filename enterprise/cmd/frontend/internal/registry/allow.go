@@ -25,20 +25,32 @@ func init() {
 
 	frontendregistry.FilterRemoteExtensions = func(extensions []*registry.Extension) []*registry.Extension {
 		allowedExtensions := getAllowedExtensionsFromSiteConfig()
-		if allowedExtensions == nil {
+		requireSigned := signedExtensionsRequired()
+		if allowedExtensions == nil && !requireSigned {
 			// Default is to allow all extensions.
 			return extensions
 		}
 
-		allow := make(map[string]interface{})
-		for _, id := range allowedExtensions {
-			allow[id] = struct{}{}
+		var allow map[string]interface{}
+		if allowedExtensions != nil {
+			allow = make(map[string]interface{})
+			for _, id := range allowedExtensions {
+				allow[id] = struct{}{}
+			}
 		}
 		var keep []*registry.Extension
 		for _, x := range extensions {
-			if _, ok := allow[x.ExtensionID]; ok {
-				keep = append(keep, x)
+			if allow != nil {
+				if _, ok := allow[x.ExtensionID]; !ok {
+					continue
+				}
+			}
+			if requireSigned {
+				if _, err := frontendregistry.VerifyRemoteExtensionSignature(x); err != nil {
+					continue
+				}
 			}
+			keep = append(keep, x)
 		}
 		return keep
 	}
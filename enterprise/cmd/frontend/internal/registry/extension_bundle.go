@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,6 +16,7 @@ import (
 	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 func init() {
@@ -47,6 +50,10 @@ func handleRegistryExtensionBundle(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if status, err := checkExtensionBundle(r.Context(), releaseID, bundle); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
 
 	// 🚨 SECURITY: Prevent this URL from being rendered as an HTML page by browsers (to prevent an
 	// XSS attack). That would let attackers upload an HTML file with inline JavaScript and then
@@ -105,6 +112,36 @@ func handleRegistryExtensionBundle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkExtensionBundle checks that the release's bundle matches the SHA-256 hash that was recorded
+// when it was published (if any) and, if the site configuration requires signed extensions, that
+// the release is signed by a trusted publisher key. If the check fails, it returns the HTTP status
+// code to respond with.
+func checkExtensionBundle(ctx context.Context, releaseID int64, bundle []byte) (status int, err error) {
+	release, err := dbReleases{}.GetByID(ctx, releaseID, false)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	hash := bundleSHA256(string(bundle))
+	if release.BundleSHA256 != nil && *release.BundleSHA256 != hash {
+		log15.Error("Extension bundle does not match its recorded SHA-256 hash.", "release", releaseID, "want", *release.BundleSHA256, "got", hash)
+		return http.StatusInternalServerError, errors.New("extension bundle does not match its recorded SHA-256 hash")
+	}
+
+	// 🚨 SECURITY: Don't serve bundles of releases that are not signed by a trusted key (if the
+	// site configuration requires signed extensions).
+	if signedExtensionsRequired() {
+		x, err := dbExtensions{}.GetByID(ctx, release.RegistryExtensionID)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if _, err := checkReleaseSignature(release.Signature, x.NonCanonicalExtensionID, hash); err != nil {
+			return http.StatusForbidden, err
+		}
+	}
+	return 0, nil
+}
+
 // parseExtensionBundleFilename parses the release ID from the extension bundle's filename, which is
 // of the form "1234-publisher-extension-id.js" or ".map". The part of the filename after the "-"
 // and before the extension is ignored; it exists to help distinguish log messages from different
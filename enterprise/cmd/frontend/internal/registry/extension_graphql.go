@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
+	pkgregistry "github.com/sourcegraph/sourcegraph/pkg/registry"
 )
 
 // extensionDBResolver implements the GraphQL type RegistryExtension.
@@ -35,7 +36,7 @@ func (r *extensionDBResolver) Publisher(ctx context.Context) (graphqlbackend.Reg
 
 func (r *extensionDBResolver) Name() string { return r.v.Name }
 func (r *extensionDBResolver) Manifest(ctx context.Context) (graphqlbackend.ExtensionManifest, error) {
-	manifest, _, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release")
+	manifest, _, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release", pkgregistry.ReleaseQuery{})
 	if err != nil {
 		return nil, err
 	}
@@ -51,10 +52,14 @@ func (r *extensionDBResolver) UpdatedAt() *string {
 }
 
 func (r *extensionDBResolver) PublishedAt(ctx context.Context) (*string, error) {
-	_, publishedAt, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release")
+	_, release, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release", pkgregistry.ReleaseQuery{})
 	if err != nil {
 		return nil, err
 	}
+	var publishedAt time.Time
+	if release != nil {
+		publishedAt = release.CreatedAt
+	}
 	return strptr(publishedAt.Format(time.RFC3339)), nil
 }
 
@@ -73,6 +78,21 @@ func (r *extensionDBResolver) IsWorkInProgress() bool {
 	return r.v.NonCanonicalIsWorkInProgress
 }
 
+func (r *extensionDBResolver) Signature(ctx context.Context) (graphqlbackend.ExtensionReleaseSignature, error) {
+	_, release, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release", pkgregistry.ReleaseQuery{})
+	if err != nil {
+		return nil, err
+	}
+	if release == nil || release.Signature == nil || release.BundleSHA256 == nil {
+		return nil, nil
+	}
+	publicKey, err := verifyReleaseSignature(*release.Signature, r.v.NonCanonicalExtensionID, *release.BundleSHA256)
+	if err != nil {
+		return nil, err
+	}
+	return newReleaseSignatureResolver(*release.BundleSHA256, publicKey), nil
+}
+
 func (r *extensionDBResolver) ViewerCanAdminister(ctx context.Context) (bool, error) {
 	err := toRegistryPublisherID(r.v).viewerCanAdminister(ctx)
 	if err == backend.ErrMustBeSiteAdmin || err == backend.ErrNotAnOrgMember || err == backend.ErrNotAuthenticated {
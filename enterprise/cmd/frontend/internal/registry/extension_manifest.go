@@ -8,35 +8,94 @@ import (
 	"path"
 	"regexp"
 	"strconv"
-	"time"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"github.com/xeipuuv/gojsonschema"
 )
 
-// validateExtensionManifest validates a JSON extension manifest for syntax.
-//
-// TODO(sqs): Also validate it against the JSON Schema.
+// validateExtensionManifest validates a JSON extension manifest for syntax and against the
+// extension manifest JSON Schema. The error lists all problems with their locations (as JSON key
+// paths, such as "contributes.actions.0.command").
 func validateExtensionManifest(text string) error {
-	var o interface{}
-	return jsonc.Unmarshal(text, &o)
+	data, err := jsonc.Parse(text)
+	if err != nil {
+		return err
+	}
+
+	s, err := gojsonschema.NewSchema(extensionSchemaLoader{gojsonschema.NewStringLoader(schema.ExtensionSchemaJSON)})
+	if err != nil {
+		return errors.Wrap(err, "loading extension manifest JSON Schema")
+	}
+	res, err := s.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return err
+	}
+	if res.Valid() {
+		return nil
+	}
+	problems := make([]string, 0, len(res.Errors()))
+	for _, e := range res.Errors() {
+		var keyPath string
+		if c := e.Context(); c != nil {
+			keyPath = strings.TrimPrefix(c.String("."), "(root)")
+			keyPath = strings.TrimPrefix(keyPath, ".")
+		}
+		if keyPath == "" {
+			keyPath = "(root)"
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s", keyPath, e.Description()))
+	}
+	return fmt.Errorf("%d problem(s) found:\n- %s", len(problems), strings.Join(problems, "\n- "))
+}
+
+// extensionSchemaLoader loads the schemas referenced by the extension manifest JSON Schema from
+// memory instead of over the network.
+type extensionSchemaLoader struct {
+	gojsonschema.JSONLoader
+}
+
+func (l extensionSchemaLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return extensionSchemaLoaderFactory{}
+}
+
+type extensionSchemaLoaderFactory struct{}
+
+func (extensionSchemaLoaderFactory) New(source string) gojsonschema.JSONLoader {
+	switch strings.TrimSuffix(source, "#") {
+	case "http://json-schema.org/draft-07/schema":
+		return gojsonschema.NewStringLoader(schema.JSONSchemaDraft07SchemaJSON)
+	case "https://raw.githubusercontent.com/sourcegraph/sourcegraph/master/shared/src/schema/extension.schema.json":
+		return gojsonschema.NewStringLoader(schema.ExtensionSchemaJSON)
+	}
+	return nil
 }
 
 // getExtensionManifestWithBundleURL returns the extension manifest as JSON. If there are no
 // releases, it returns a nil manifest. If the manifest has no "url" field itself, a "url" field
-// pointing to the extension's bundle is inserted. It also returns the date that the release was
-// published.
-func getExtensionManifestWithBundleURL(ctx context.Context, extensionID string, registryExtensionID int32, releaseTag string) (manifest *string, publishedAt time.Time, err error) {
-	release, err := dbReleases{}.GetLatest(ctx, registryExtensionID, releaseTag, false)
+// pointing to the extension's bundle is inserted. It also returns the release whose manifest is
+// returned (or nil).
+//
+// The release is the newest release that matches the release query.
+func getExtensionManifestWithBundleURL(ctx context.Context, extensionID string, registryExtensionID int32, releaseTag string, query registry.ReleaseQuery) (manifest *string, release *dbRelease, err error) {
+	if query.IsZero() {
+		release, err = dbReleases{}.GetLatest(ctx, registryExtensionID, releaseTag, false)
+	} else {
+		release, err = dbReleases{}.GetLatestMatching(ctx, registryExtensionID, releaseTag, query, false)
+	}
 	if err != nil && !errcode.IsNotFound(err) {
-		return nil, time.Time{}, err
+		return nil, nil, err
 	}
 	if release != nil {
 		// Add URL to bundle if necessary.
 		var o map[string]interface{}
 		if err := jsonc.Unmarshal(release.Manifest, &o); err != nil {
-			return nil, time.Time{}, fmt.Errorf("parsing extension manifest for extension with ID %d (release tag %q): %s", registryExtensionID, releaseTag, err)
+			return nil, nil, fmt.Errorf("parsing extension manifest for extension with ID %d (release tag %q): %s", registryExtensionID, releaseTag, err)
 		}
 		if o == nil {
 			o = map[string]interface{}{}
@@ -46,21 +105,20 @@ func getExtensionManifestWithBundleURL(ctx context.Context, extensionID string,
 			// Insert "url" field with link to bundle file on this site.
 			bundleURL, err := makeExtensionBundleURL(release.ID, release.CreatedAt.UnixNano(), extensionID)
 			if err != nil {
-				return nil, time.Time{}, err
+				return nil, nil, err
 			}
 			o["url"] = bundleURL
 			b, err := json.MarshalIndent(o, "", "  ")
 			if err != nil {
-				return nil, time.Time{}, err
+				return nil, nil, err
 			}
 			release.Manifest = string(b)
 		}
 
 		manifest = &release.Manifest
-		publishedAt = release.CreatedAt
 	}
 
-	return manifest, publishedAt, nil
+	return manifest, release, nil
 }
 
 var nonLettersDigits = regexp.MustCompile(`[^a-zA-Z0-9-]`)
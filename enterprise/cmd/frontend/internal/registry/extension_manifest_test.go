@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
 )
 
 func TestGetExtensionManifestWithBundleURL(t *testing.T) {
@@ -28,15 +31,15 @@ func TestGetExtensionManifestWithBundleURL(t *testing.T) {
 			}, nil
 		}
 		defer func() { mocks.releases.GetLatest = nil }()
-		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t")
+		manifest, release, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t", registry.ReleaseQuery{})
 		if err != nil {
 			t.Fatal(err)
 		}
 		if want := `{"name":"x","url":"u"}`; manifest == nil || !jsonDeepEqual(*manifest, want) {
 			t.Errorf("got %q, want %q", nilOrEmpty(manifest), want)
 		}
-		if publishedAt != t0 {
-			t.Errorf("got %v, want %v", publishedAt, t0)
+		if release == nil || release.CreatedAt != t0 {
+			t.Errorf("got release %+v, want CreatedAt %v", release, t0)
 		}
 	})
 
@@ -48,15 +51,62 @@ func TestGetExtensionManifestWithBundleURL(t *testing.T) {
 			}, nil
 		}
 		defer func() { mocks.releases.GetLatest = nil }()
-		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t")
+		manifest, release, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t", registry.ReleaseQuery{})
 		if err != nil {
 			t.Fatal(err)
 		}
 		if want := `{"name":"x","url":"/-/static/extension/0-x.js?fqw3qlts--x"}`; manifest == nil || !jsonDeepEqual(*manifest, want) {
 			t.Errorf("got %q, want %q", nilOrEmpty(manifest), want)
 		}
-		if publishedAt != t0 {
-			t.Errorf("got %v, want %v", publishedAt, t0)
+		if release == nil || release.CreatedAt != t0 {
+			t.Errorf("got release %+v, want CreatedAt %v", release, t0)
+		}
+	})
+}
+
+func TestValidateExtensionManifest(t *testing.T) {
+	tests := map[string]struct {
+		manifest     string
+		wantProblems []string
+	}{
+		"valid": {
+			manifest: `{"publisher": "alice", "activationEvents": ["*"], "url": "https://example.com/x.js"}`,
+		},
+		"valid with comments": {
+			manifest: `{"publisher": "alice", /* c */ "activationEvents": ["*"],}`,
+		},
+		"missing required properties": {
+			manifest:     `{}`,
+			wantProblems: []string{"(root): activationEvents is required", "(root): publisher is required"},
+		},
+		"invalid nested property": {
+			manifest:     `{"publisher": "alice", "activationEvents": ["*"], "repository": {"url": 1}}`,
+			wantProblems: []string{"repository.url: Invalid type. Expected: string, given: integer"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateExtensionManifest(test.manifest)
+			if len(test.wantProblems) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("got nil error, want problems")
+			}
+			for _, problem := range test.wantProblems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("got error %q, want it to contain %q", err, problem)
+				}
+			}
+		})
+	}
+
+	t.Run("syntax error", func(t *testing.T) {
+		if err := validateExtensionManifest(`{"publisher":`); err == nil {
+			t.Fatal("got nil error, want syntax error")
 		}
 	})
 }
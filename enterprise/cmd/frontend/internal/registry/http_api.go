@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
@@ -33,7 +34,7 @@ var (
 		}
 		xs := make([]*registry.Extension, 0, len(vs))
 		for _, v := range vs {
-			x, err := toRegistryAPIExtension(ctx, v)
+			x, err := toRegistryAPIExtension(ctx, v, registry.ReleaseQuery{})
 			if err != nil {
 				continue
 			}
@@ -52,28 +53,45 @@ var (
 		return xs, nil
 	}
 
-	registryGetByUUID = func(ctx context.Context, uuid string) (*registry.Extension, error) {
+	registryGetByUUID = func(ctx context.Context, uuid string, release registry.ReleaseQuery) (*registry.Extension, error) {
 		x, err := dbExtensions{}.GetByUUID(ctx, uuid)
 		if err != nil {
 			return nil, err
 		}
-		return toRegistryAPIExtension(ctx, x)
+		return toRegistryAPIExtension(ctx, x, release)
 	}
 
-	registryGetByExtensionID = func(ctx context.Context, extensionID string) (*registry.Extension, error) {
+	registryGetByExtensionID = func(ctx context.Context, extensionID string, release registry.ReleaseQuery) (*registry.Extension, error) {
 		x, err := dbExtensions{}.GetByExtensionID(ctx, extensionID)
 		if err != nil {
 			return nil, err
 		}
-		return toRegistryAPIExtension(ctx, x)
+		return toRegistryAPIExtension(ctx, x, release)
 	}
 )
 
-func toRegistryAPIExtension(ctx context.Context, v *dbExtension) (*registry.Extension, error) {
-	manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, v.NonCanonicalExtensionID, v.ID, "release")
+// toRegistryAPIExtension converts the extension to its external form. The manifest is that of the
+// newest release matching the release query. If no release matches, the manifest is nil.
+func toRegistryAPIExtension(ctx context.Context, v *dbExtension, query registry.ReleaseQuery) (*registry.Extension, error) {
+	manifest, release, err := getExtensionManifestWithBundleURL(ctx, v.NonCanonicalExtensionID, v.ID, "release", query)
 	if err != nil {
 		return nil, err
 	}
+	var (
+		publishedAt     time.Time
+		version         *string
+		channel         string
+		hash, signature string
+	)
+	if release != nil {
+		publishedAt, version, channel = release.CreatedAt, release.ReleaseVersion, release.Channel
+		if release.BundleSHA256 != nil {
+			hash = *release.BundleSHA256
+		}
+		if release.Signature != nil {
+			signature = *release.Signature
+		}
+	}
 
 	baseURL := strings.TrimSuffix(conf.Get().Critical.ExternalURL, "/")
 	return &registry.Extension{
@@ -83,12 +101,16 @@ func toRegistryAPIExtension(ctx context.Context, v *dbExtension) (*registry.Exte
 			Name: v.Publisher.NonCanonicalName,
 			URL:  baseURL + frontendregistry.PublisherExtensionsURL(v.Publisher.UserID != 0, v.Publisher.OrgID != 0, v.Publisher.NonCanonicalName),
 		},
-		Name:        v.Name,
-		Manifest:    manifest,
-		CreatedAt:   v.CreatedAt,
-		UpdatedAt:   v.UpdatedAt,
-		PublishedAt: publishedAt,
-		URL:         baseURL + frontendregistry.ExtensionURL(v.NonCanonicalExtensionID),
+		Name:         v.Name,
+		Manifest:     manifest,
+		CreatedAt:    v.CreatedAt,
+		UpdatedAt:    v.UpdatedAt,
+		PublishedAt:  publishedAt,
+		URL:          baseURL + frontendregistry.ExtensionURL(v.NonCanonicalExtensionID),
+		Version:      version,
+		Channel:      channel,
+		BundleSHA256: hash,
+		Signature:    signature,
 	}, nil
 }
 
@@ -150,15 +172,31 @@ func handleRegistry(w http.ResponseWriter, r *http.Request) (err error) {
 
 	case strings.HasPrefix(urlPath, extensionsPath+"/"):
 		var (
-			spec = strings.TrimPrefix(urlPath, extensionsPath+"/")
-			x    *registry.Extension
-			err  error
+			spec    = strings.TrimPrefix(urlPath, extensionsPath+"/")
+			release = registry.ReleaseQuery{
+				Channel: r.URL.Query().Get("channel"),
+				Version: r.URL.Query().Get("version"),
+			}
+			x   *registry.Extension
+			err error
 		)
+		if release.Channel != "" {
+			if err := validateReleaseChannel(release.Channel); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return nil
+			}
+		}
+		if _, err := parseVersionRange(release.Version); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
+		ev.AddField("channel", release.Channel)
+		ev.AddField("version", release.Version)
 		switch {
 		case strings.HasPrefix(spec, "uuid/"):
-			x, err = registryGetByUUID(r.Context(), strings.TrimPrefix(spec, "uuid/"))
+			x, err = registryGetByUUID(r.Context(), strings.TrimPrefix(spec, "uuid/"), release)
 		case strings.HasPrefix(spec, "extension-id/"):
-			x, err = registryGetByExtensionID(r.Context(), strings.TrimPrefix(spec, "extension-id/"))
+			x, err = registryGetByExtensionID(r.Context(), strings.TrimPrefix(spec, "extension-id/"), release)
 		default:
 			w.WriteHeader(http.StatusNotFound)
 			return nil
@@ -171,6 +209,11 @@ func handleRegistry(w http.ResponseWriter, r *http.Request) (err error) {
 			}
 			return err
 		}
+		if x.Manifest == nil && !release.IsZero() {
+			w.Header().Set("Cache-Control", "max-age=5, private")
+			http.Error(w, "no extension release matches the requested channel and version", http.StatusNotFound)
+			return nil
+		}
 		ev.AddField("extension-id", x.ExtensionID)
 		result = x
 
@@ -237,14 +280,14 @@ func init() {
 		}
 		return frontendregistry.FilterRegistryExtensions(xs, opt.Query), nil
 	}
-	registryGetByUUID = func(ctx context.Context, uuid string) (*registry.Extension, error) {
+	registryGetByUUID = func(ctx context.Context, uuid string, release registry.ReleaseQuery) (*registry.Extension, error) {
 		xs, err := readFakeExtensions()
 		if err != nil {
 			return nil, err
 		}
 		return frontendregistry.FindRegistryExtension(xs, "uuid", uuid), nil
 	}
-	registryGetByExtensionID = func(ctx context.Context, extensionID string) (*registry.Extension, error) {
+	registryGetByExtensionID = func(ctx context.Context, extensionID string, release registry.ReleaseQuery) (*registry.Extension, error) {
 		xs, err := readFakeExtensions()
 		if err != nil {
 			return nil, err
@@ -11,6 +11,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	"golang.org/x/crypto/ssh"
 )
 
 func init() {
@@ -174,13 +176,54 @@ func extensionRegistryPublishExtension(ctx context.Context, args *graphqlbackend
 		}
 	}
 
+	// Determine the version and channel of the release.
+	version := args.Version
+	if version != nil {
+		v, err := parseReleaseVersion(*version)
+		if err != nil {
+			return nil, err
+		}
+		normalized := v.String()
+		version = &normalized
+	}
+	channel := registry.ChannelStable
+	if args.Channel != nil {
+		channel = *args.Channel
+		if err := validateReleaseChannel(channel); err != nil {
+			return nil, err
+		}
+	}
+
+	// 🚨 SECURITY: Verify the release signature and check that it is signed by a trusted key (if
+	// the site configuration requires signed extensions).
+	if args.Signature != nil && args.Bundle == nil {
+		return nil, errors.New("unable to sign an extension release without a bundle")
+	}
+	var hash string
+	if args.Bundle != nil {
+		hash = bundleSHA256(*args.Bundle)
+	}
+	publicKey, err := checkReleaseSignature(args.Signature, args.ExtensionID, hash)
+	if err != nil {
+		return nil, err
+	}
+	var fingerprint *string
+	if publicKey != nil {
+		f := ssh.FingerprintSHA256(publicKey)
+		fingerprint = &f
+	}
+
 	release := dbRelease{
-		RegistryExtensionID: id.LocalID,
-		CreatorUserID:       actor.FromContext(ctx).UID,
-		ReleaseTag:          "release",
-		Manifest:            args.Manifest,
-		Bundle:              args.Bundle,
-		SourceMap:           args.SourceMap,
+		RegistryExtensionID:     id.LocalID,
+		CreatorUserID:           actor.FromContext(ctx).UID,
+		ReleaseVersion:          version,
+		ReleaseTag:              "release",
+		Channel:                 channel,
+		Manifest:                args.Manifest,
+		Bundle:                  args.Bundle,
+		SourceMap:               args.SourceMap,
+		Signature:               args.Signature,
+		SignatureKeyFingerprint: fingerprint,
 	}
 	if _, err := (dbReleases{}).Create(ctx, &release); err != nil {
 		return nil, err
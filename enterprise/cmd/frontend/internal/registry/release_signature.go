@@ -0,0 +1,341 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+func init() {
+	frontendregistry.VerifyRemoteExtensionSignature = func(x *registry.Extension) (graphqlbackend.ExtensionReleaseSignature, error) {
+		var signature *string
+		if x.Signature != "" {
+			signature = &x.Signature
+		}
+		publicKey, err := checkReleaseSignature(signature, x.ExtensionID, x.BundleSHA256)
+		if publicKey == nil || err != nil {
+			return nil, err
+		}
+		return newReleaseSignatureResolver(x.BundleSHA256, publicKey), nil
+	}
+	frontendregistry.VerifyRemoteExtensionBundle = verifyRemoteExtensionBundle
+}
+
+// Extension release signatures use the same scheme as license keys (see
+// github.com/sourcegraph/sourcegraph/enterprise/pkg/license): the publisher signs the encoded
+// release information with an SSH private key, and the signature is the base64url-encoded JSON of
+// the SSH signature, the encoded information, and (unlike license keys, which are always verified
+// with Sourcegraph's public key) the signer's public key.
+//
+// The signature proves that the holder of the private key published the bundle with the given
+// hash for the given extension. Whether to trust the key is decided by the site configuration
+// ("extensions.trustedPublisherKeys").
+//
+// For remote extensions, the hash is reported by the remote registry, so a valid signature only
+// proves that the bundle that the manifest refers to is the signed bundle once that bundle's hash
+// is checked too (see verifyRemoteExtensionBundle).
+
+// releaseSignatureInfo is the information about an extension release that is signed by its
+// publisher.
+type releaseSignatureInfo struct {
+	Version      int    `json:"v"` // version number of the signature info format
+	ExtensionID  string `json:"extensionID"`
+	BundleSHA256 string `json:"bundleSHA256"`
+}
+
+const releaseSignatureFormatVersion = 1 // (releaseSignatureInfo).Version value
+
+type signedReleaseInfo struct {
+	Signature   *ssh.Signature `json:"sig"`
+	PublicKey   []byte         `json:"key"` // in SSH wire format
+	EncodedInfo []byte         `json:"info"`
+}
+
+// bundleSHA256 returns the hex-encoded SHA-256 hash of an extension's bundled JavaScript source
+// file. The `//# sourceMappingURL` directives are omitted from the hash, because registries rewrite
+// them when serving the bundle (see handleRegistryExtensionBundle). This lets the hash of a bundle
+// that was fetched from a registry be compared to the hash of the published bundle.
+func bundleSHA256(bundle string) string {
+	sum := sha256.Sum256(sourceMappingURLLineRegex.ReplaceAll([]byte(bundle), nil))
+	return hex.EncodeToString(sum[:])
+}
+
+// maxRemoteBundleSize is the maximum size in bytes of a remote extension bundle that is fetched to
+// verify its hash.
+const maxRemoteBundleSize = 50 * 1024 * 1024
+
+// verifiedRemoteBundles records the remote bundles (by URL and hash) that were verified, so that
+// they are not fetched and hashed every time the remote extension is used. Bundles are immutable,
+// except that the registry serving them may be changed or compromised, which changes the hash
+// reported for them too.
+var verifiedRemoteBundles = struct {
+	sync.Mutex
+	m map[string]struct{}
+}{m: map[string]struct{}{}}
+
+// verifyRemoteExtensionBundle fetches the bundle that the remote extension's manifest refers to and
+// checks that it matches the hash in the extension's release information (which
+// VerifyRemoteExtensionSignature checks the signature of), if the site configuration requires
+// signed extensions.
+func verifyRemoteExtensionBundle(ctx context.Context, x *registry.Extension) error {
+	if !signedExtensionsRequired() {
+		return nil
+	}
+
+	// 🚨 SECURITY: The remote registry reports the hash of the bundle, so it could pair a valid
+	// signature (and the hash of the signed bundle) with a manifest that refers to a different
+	// bundle. Check the bundle that clients load.
+	if x.BundleSHA256 == "" {
+		return fmt.Errorf("extension %q release has no bundle hash (the site configuration requires signed extensions)", x.ExtensionID)
+	}
+	bundleURL, err := remoteBundleURL(x)
+	if err != nil {
+		return err
+	}
+	key := bundleURL + " " + x.BundleSHA256
+	verifiedRemoteBundles.Lock()
+	_, verified := verifiedRemoteBundles.m[key]
+	verifiedRemoteBundles.Unlock()
+	if verified {
+		return nil
+	}
+
+	bundle, err := fetchRemoteBundle(ctx, bundleURL)
+	if err != nil {
+		return errors.Wrapf(err, "fetching extension %q bundle", x.ExtensionID)
+	}
+	if hash := bundleSHA256(string(bundle)); hash != x.BundleSHA256 {
+		return fmt.Errorf("extension %q bundle has SHA-256 hash %q, not %q as in its signed release", x.ExtensionID, hash, x.BundleSHA256)
+	}
+
+	verifiedRemoteBundles.Lock()
+	if len(verifiedRemoteBundles.m) >= 1000 {
+		verifiedRemoteBundles.m = map[string]struct{}{} // bound memory use
+	}
+	verifiedRemoteBundles.m[key] = struct{}{}
+	verifiedRemoteBundles.Unlock()
+	return nil
+}
+
+// remoteBundleURL returns the URL of the remote extension's bundle, from its manifest.
+func remoteBundleURL(x *registry.Extension) (string, error) {
+	if x.Manifest == nil {
+		return "", fmt.Errorf("extension %q has no manifest", x.ExtensionID)
+	}
+	var manifest struct {
+		URL string `json:"url"`
+	}
+	if err := jsonc.Unmarshal(*x.Manifest, &manifest); err != nil {
+		return "", errors.Wrapf(err, "parsing extension %q manifest", x.ExtensionID)
+	}
+	u, err := url.Parse(manifest.URL)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing extension %q bundle URL", x.ExtensionID)
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("extension %q bundle URL %q is not absolute", x.ExtensionID, manifest.URL)
+	}
+	return u.String(), nil
+}
+
+func fetchRemoteBundle(ctx context.Context, bundleURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", bundleURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ctxhttp.Do(ctx, registry.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP error %d", resp.StatusCode)
+	}
+	bundle, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRemoteBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle) > maxRemoteBundleSize {
+		return nil, fmt.Errorf("bundle is larger than %d bytes", maxRemoteBundleSize)
+	}
+	return bundle, nil
+}
+
+// signedExtensionID returns the extension ID that is signed in release signatures. It omits the
+// registry name (if any), so that the signature remains valid when the extension is used from
+// another registry.
+func signedExtensionID(extensionID string) (string, error) {
+	_, publisher, name, err := frontendregistry.SplitExtensionID(extensionID)
+	if err != nil {
+		return "", err
+	}
+	return publisher + "/" + name, nil
+}
+
+// generateReleaseSignature signs the release of the extension's bundle (with the given hash) using
+// the private key.
+func generateReleaseSignature(extensionID, bundleSHA256 string, privateKey ssh.Signer) (string, error) {
+	extensionID, err := signedExtensionID(extensionID)
+	if err != nil {
+		return "", err
+	}
+	encodedInfo, err := json.Marshal(releaseSignatureInfo{
+		Version:      releaseSignatureFormatVersion,
+		ExtensionID:  extensionID,
+		BundleSHA256: bundleSHA256,
+	})
+	if err != nil {
+		return "", err
+	}
+	sig, err := privateKey.Sign(rand.Reader, encodedInfo)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(signedReleaseInfo{
+		Signature:   sig,
+		PublicKey:   privateKey.PublicKey().Marshal(),
+		EncodedInfo: encodedInfo,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// verifyReleaseSignature verifies that the signature is a valid signature of the release of the
+// extension's bundle (with the given hash). It returns the public key that made the signature. The
+// caller must check whether the key is trusted.
+func verifyReleaseSignature(signature, extensionID, bundleSHA256 string) (ssh.PublicKey, error) {
+	// Ignore whitespace, in case the signature was (e.g.) wrapped.
+	signature = strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) {
+			return -1 // drop
+		}
+		return c
+	}, signature)
+
+	data, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid release signature encoding")
+	}
+	var signed signedReleaseInfo
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, errors.Wrap(err, "invalid release signature")
+	}
+	if signed.Signature == nil {
+		return nil, errors.New("invalid release signature (no signature)")
+	}
+	publicKey, err := ssh.ParsePublicKey(signed.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid release signature public key")
+	}
+	if err := publicKey.Verify(signed.EncodedInfo, signed.Signature); err != nil {
+		return nil, errors.Wrap(err, "release signature verification failed")
+	}
+
+	var info releaseSignatureInfo
+	if err := json.Unmarshal(signed.EncodedInfo, &info); err != nil {
+		return nil, errors.Wrap(err, "invalid release signature info")
+	}
+	if info.Version != releaseSignatureFormatVersion {
+		return nil, fmt.Errorf("release signature format is version %d, expected version %d", info.Version, releaseSignatureFormatVersion)
+	}
+	wantExtensionID, err := signedExtensionID(extensionID)
+	if err != nil {
+		return nil, err
+	}
+	if info.ExtensionID != wantExtensionID {
+		return nil, fmt.Errorf("release signature is for extension %q, not %q", info.ExtensionID, wantExtensionID)
+	}
+	if info.BundleSHA256 != bundleSHA256 {
+		return nil, fmt.Errorf("release signature is for a bundle with SHA-256 hash %q, not %q", info.BundleSHA256, bundleSHA256)
+	}
+	return publicKey, nil
+}
+
+// signedExtensionsRequired reports whether the site configuration requires extension releases to
+// be signed by a trusted publisher key.
+func signedExtensionsRequired() bool {
+	c := conf.Get().Extensions
+	return c != nil && c.RequireSignedExtensions
+}
+
+// isTrustedPublisherKey reports whether the public key is one of the trusted publisher keys in
+// site configuration. Invalid keys in site configuration are ignored.
+func isTrustedPublisherKey(publicKey ssh.PublicKey) bool {
+	c := conf.Get().Extensions
+	if c == nil {
+		return false
+	}
+	fingerprint := ssh.FingerprintSHA256(publicKey)
+	for _, s := range c.TrustedPublisherKeys {
+		trustedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s))
+		if err != nil {
+			continue
+		}
+		if ssh.FingerprintSHA256(trustedKey) == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReleaseSignature verifies the release signature (if any) and, if the site configuration
+// requires signed extensions, checks that the release is signed by a trusted publisher key. It
+// returns the public key that signed the release, or nil if the release is unsigned.
+func checkReleaseSignature(signature *string, extensionID, bundleSHA256 string) (ssh.PublicKey, error) {
+	if signature == nil {
+		if signedExtensionsRequired() {
+			return nil, fmt.Errorf("extension %q release is not signed (the site configuration requires signed extensions)", extensionID)
+		}
+		return nil, nil
+	}
+
+	publicKey, err := verifyReleaseSignature(*signature, extensionID, bundleSHA256)
+	if err != nil {
+		return nil, err
+	}
+	if signedExtensionsRequired() && !isTrustedPublisherKey(publicKey) {
+		return nil, fmt.Errorf("extension %q release is signed by an untrusted key %s (the site configuration requires signed extensions from trusted publisher keys)", extensionID, ssh.FingerprintSHA256(publicKey))
+	}
+	return publicKey, nil
+}
+
+// releaseSignatureResolver implements the GraphQL type ExtensionReleaseSignature.
+type releaseSignatureResolver struct {
+	bundleSHA256   string
+	keyFingerprint string
+	trusted        bool
+}
+
+func newReleaseSignatureResolver(bundleSHA256 string, publicKey ssh.PublicKey) *releaseSignatureResolver {
+	return &releaseSignatureResolver{
+		bundleSHA256:   bundleSHA256,
+		keyFingerprint: ssh.FingerprintSHA256(publicKey),
+		trusted:        isTrustedPublisherKey(publicKey),
+	}
+}
+
+func (r *releaseSignatureResolver) BundleSHA256() string   { return r.bundleSHA256 }
+func (r *releaseSignatureResolver) KeyFingerprint() string { return r.keyFingerprint }
+func (r *releaseSignatureResolver) IsTrusted() bool        { return r.trusted }
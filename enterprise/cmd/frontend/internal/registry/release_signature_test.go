@@ -0,0 +1,180 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestReleaseSignature(t *testing.T) {
+	signer := newTestSigner(t)
+	hash := bundleSHA256("b")
+	signature, err := generateReleaseSignature("alice/x", hash, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		for _, extensionID := range []string{"alice/x", "sourcegraph.example.com/alice/x"} {
+			publicKey, err := verifyReleaseSignature(signature, extensionID, hash)
+			if err != nil {
+				t.Fatalf("%s: %s", extensionID, err)
+			}
+			if got, want := ssh.FingerprintSHA256(publicKey), ssh.FingerprintSHA256(signer.PublicKey()); got != want {
+				t.Errorf("%s: got key %s, want %s", extensionID, got, want)
+			}
+		}
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		if _, err := verifyReleaseSignature(signature[:10]+"\n  "+signature[10:], "alice/x", hash); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	tests := map[string]struct {
+		signature, extensionID, bundleSHA256 string
+		wantErr                              string
+	}{
+		"other extension": {signature: signature, extensionID: "alice/y", bundleSHA256: hash, wantErr: "is for extension"},
+		"other bundle":    {signature: signature, extensionID: "alice/x", bundleSHA256: bundleSHA256("c"), wantErr: "is for a bundle"},
+		"invalid":         {signature: "abc", extensionID: "alice/x", bundleSHA256: hash, wantErr: "invalid release signature"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifyReleaseSignature(test.signature, test.extensionID, test.bundleSHA256)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("got error %v, want it to contain %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckReleaseSignature(t *testing.T) {
+	trustedSigner := newTestSigner(t)
+	untrustedSigner := newTestSigner(t)
+	hash := bundleSHA256("b")
+	sign := func(signer ssh.Signer) *string {
+		signature, err := generateReleaseSignature("alice/x", hash, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &signature
+	}
+	trusted, untrusted := sign(trustedSigner), sign(untrustedSigner)
+
+	mockConf := func(requireSigned bool) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Extensions: &schema.Extensions{
+			RequireSignedExtensions: requireSigned,
+			TrustedPublisherKeys:    []string{"invalid", string(ssh.MarshalAuthorizedKey(trustedSigner.PublicKey()))},
+		}}})
+	}
+	defer conf.Mock(nil)
+
+	tests := []struct {
+		requireSigned bool
+		signature     *string
+		wantKey       bool
+		wantErr       bool
+	}{
+		{requireSigned: false, signature: nil},
+		{requireSigned: false, signature: untrusted, wantKey: true},
+		{requireSigned: false, signature: trusted, wantKey: true},
+		{requireSigned: true, signature: nil, wantErr: true},
+		{requireSigned: true, signature: untrusted, wantErr: true},
+		{requireSigned: true, signature: trusted, wantKey: true},
+	}
+	for i, test := range tests {
+		mockConf(test.requireSigned)
+		publicKey, err := checkReleaseSignature(test.signature, "alice/x", hash)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%d: got error %v, want error %v", i, err, test.wantErr)
+		}
+		if (publicKey != nil) != test.wantKey {
+			t.Errorf("%d: got key %v, want key %v", i, publicKey != nil, test.wantKey)
+		}
+	}
+
+	mockConf(false)
+	if !isTrustedPublisherKey(trustedSigner.PublicKey()) {
+		t.Error("want trusted key to be trusted")
+	}
+	if isTrustedPublisherKey(untrustedSigner.PublicKey()) {
+		t.Error("want untrusted key to not be trusted")
+	}
+}
+
+func TestVerifyRemoteExtensionBundle(t *testing.T) {
+	bundles := map[string]string{
+		// Registries rewrite the source map directive when serving bundles.
+		"/good.js":     "b\n//# sourceMappingURL=https://registry.example.com/1.map",
+		"/tampered.js": "evil",
+	}
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		bundle, ok := bundles[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(bundle))
+	}))
+	defer ts.Close()
+	defer func(c *http.Client) { registry.HTTPClient = c }(registry.HTTPClient)
+	registry.HTTPClient = ts.Client()
+
+	extension := func(bundlePath string) *registry.Extension {
+		manifest := fmt.Sprintf(`{"url": %q}`, ts.URL+bundlePath)
+		return &registry.Extension{ExtensionID: "alice/x", Manifest: &manifest, BundleSHA256: bundleSHA256("b\n//# sourceMappingURL=b.map")}
+	}
+
+	ctx := context.Background()
+	defer conf.Mock(nil)
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Extensions: &schema.Extensions{}}})
+	if err := verifyRemoteExtensionBundle(ctx, extension("/tampered.js")); err != nil {
+		t.Errorf("got error %v without requiring signed extensions, want nil", err)
+	}
+	if requests != 0 {
+		t.Errorf("got %d bundle requests without requiring signed extensions, want 0", requests)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Extensions: &schema.Extensions{RequireSignedExtensions: true}}})
+	for i := 0; i < 2; i++ {
+		if err := verifyRemoteExtensionBundle(ctx, extension("/good.js")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("got %d bundle requests, want 1 (the verified bundle should be remembered)", requests)
+	}
+	if err := verifyRemoteExtensionBundle(ctx, extension("/tampered.js")); err == nil || !strings.Contains(err.Error(), "as in its signed release") {
+		t.Errorf("got error %v, want hash mismatch", err)
+	}
+	if err := verifyRemoteExtensionBundle(ctx, extension("/missing.js")); err == nil {
+		t.Error("got nil error for missing bundle")
+	}
+}
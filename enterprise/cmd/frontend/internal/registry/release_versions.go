@@ -0,0 +1,241 @@
+package registry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+)
+
+// parseReleaseVersion parses the semantic version of an extension release, such as "1.2.3" or
+// "1.3.0-beta.1". A leading "v" is allowed.
+func parseReleaseVersion(s string) (*semver.Version, error) {
+	v, err := semver.NewVersion(strings.TrimPrefix(strings.TrimSpace(s), "v"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid release version %q (must be a semantic version, such as \"1.2.3\")", s)
+	}
+	return v, nil
+}
+
+// validateReleaseChannel returns an error if the channel is not a known release channel.
+func validateReleaseChannel(channel string) error {
+	if channel != registry.ChannelStable && channel != registry.ChannelBeta {
+		return fmt.Errorf("invalid release channel %q (must be %q or %q)", channel, registry.ChannelStable, registry.ChannelBeta)
+	}
+	return nil
+}
+
+// releaseChannels returns the release channels whose releases are served to clients that request
+// the given channel. The beta channel also includes stable releases.
+func releaseChannels(channel string) []string {
+	if channel == registry.ChannelBeta {
+		return []string{registry.ChannelStable, registry.ChannelBeta}
+	}
+	return []string{registry.ChannelStable}
+}
+
+// versionRange is a set of semantic versions, such as "^1.2.0", "~1.2", "1.x" or
+// ">=1.0.0 <2.0.0 || >=3.0.0". It uses the same syntax as npm version ranges (except hyphen
+// ranges). A version is in the range if it satisfies all comparators of any of the
+// "||"-separated sets.
+type versionRange [][]versionComparator
+
+type versionComparator struct {
+	op string // "<", "<=", ">", ">=" or "="
+	v  semver.Version
+}
+
+func (c versionComparator) matches(v semver.Version) bool {
+	cmp := v.Compare(c.v)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// parseVersionRange parses a semantic version range. The empty string and "*" match all
+// versions.
+func parseVersionRange(s string) (versionRange, error) {
+	var r versionRange
+	for _, set := range strings.Split(s, "||") {
+		var comparators []versionComparator
+		for _, field := range strings.Fields(set) {
+			cs, err := parseVersionComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %s", s, err)
+			}
+			comparators = append(comparators, cs...)
+		}
+		r = append(r, comparators)
+	}
+	return r, nil
+}
+
+// parseVersionComparator parses a single comparator, such as ">=1.2.0", "^1.2" or "1.x". The
+// result is a list of primitive comparators that must all be satisfied.
+func parseVersionComparator(s string) ([]versionComparator, error) {
+	var op string
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, prefix) {
+			op = prefix
+			s = s[len(prefix):]
+			break
+		}
+	}
+	s = strings.TrimPrefix(s, "v")
+
+	// Parse the (possibly partial) version. n is the number of version components given (not
+	// counting wildcards).
+	var (
+		parts      [3]int64
+		n          int
+		prerelease string
+	)
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, prerelease = s[:i], s[i+1:]
+	}
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i] // ignore build metadata
+	}
+	components := strings.Split(s, ".")
+	if len(components) > 3 {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+	for i, c := range components {
+		if c == "x" || c == "X" || c == "*" {
+			continue
+		}
+		if i != n {
+			return nil, fmt.Errorf("invalid version %q (wildcards must be last)", s)
+		}
+		v, err := strconv.ParseInt(c, 10, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		parts[i] = v
+		n++
+	}
+	if prerelease != "" && n < 3 {
+		return nil, fmt.Errorf("invalid version %q (prerelease requires major, minor and patch versions)", s)
+	}
+
+	version := func(major, minor, patch int64, prerelease string) semver.Version {
+		return semver.Version{Major: major, Minor: minor, Patch: patch, PreRelease: semver.PreRelease(prerelease)}
+	}
+	lower := version(parts[0], parts[1], parts[2], prerelease)
+	// upper returns the smallest version (including prereleases) that is greater than all
+	// versions that share the first i components with lower.
+	upper := func(i int) semver.Version {
+		switch i {
+		case 1:
+			return version(parts[0]+1, 0, 0, "0")
+		case 2:
+			return version(parts[0], parts[1]+1, 0, "0")
+		default:
+			return version(parts[0], parts[1], parts[2]+1, "0")
+		}
+	}
+
+	switch op {
+	case "", "=":
+		if n == 0 {
+			return nil, nil // matches all versions
+		}
+		if n == 3 {
+			return []versionComparator{{op: "=", v: lower}}, nil
+		}
+		return []versionComparator{{op: ">=", v: lower}, {op: "<", v: upper(n)}}, nil
+
+	case "^":
+		if n == 0 {
+			return nil, nil
+		}
+		// Allow changes that do not modify the left-most non-zero component.
+		i := 1
+		switch {
+		case parts[0] == 0 && n >= 2 && parts[1] == 0 && n == 3:
+			i = 3
+		case parts[0] == 0 && n >= 2:
+			i = 2
+		}
+		return []versionComparator{{op: ">=", v: lower}, {op: "<", v: upper(i)}}, nil
+
+	case "~":
+		if n == 0 {
+			return nil, nil
+		}
+		// Allow patch-level changes (or minor-level changes if only the major version is given).
+		i := 2
+		if n == 1 {
+			i = 1
+		}
+		return []versionComparator{{op: ">=", v: lower}, {op: "<", v: upper(i)}}, nil
+
+	case ">=", "<":
+		if n == 0 {
+			if op == "<" {
+				return nil, fmt.Errorf("invalid comparator %q (matches no versions)", op+s)
+			}
+			return nil, nil
+		}
+		return []versionComparator{{op: op, v: lower}}, nil
+
+	case ">", "<=":
+		if n == 0 {
+			if op == ">" {
+				return nil, fmt.Errorf("invalid comparator %q (matches no versions)", op+s)
+			}
+			return nil, nil
+		}
+		if n == 3 {
+			return []versionComparator{{op: op, v: lower}}, nil
+		}
+		// ">1.2" means ">=1.3.0-0", and "<=1.2" means "<1.3.0-0".
+		if op == ">" {
+			return []versionComparator{{op: ">=", v: upper(n)}}, nil
+		}
+		return []versionComparator{{op: "<", v: upper(n)}}, nil
+	}
+	panic("unreachable")
+}
+
+// contains reports whether the version is in the range.
+//
+// If includePrerelease is false, prerelease versions (such as "1.3.0-beta.1") are only in the
+// range if a comparator explicitly refers to a prerelease of the same major, minor and patch
+// version (as in npm).
+func (r versionRange) contains(v semver.Version, includePrerelease bool) bool {
+	for _, comparators := range r {
+		if matchesAll(comparators, v, includePrerelease) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(comparators []versionComparator, v semver.Version, includePrerelease bool) bool {
+	for _, c := range comparators {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if v.PreRelease == "" || includePrerelease {
+		return true
+	}
+	for _, c := range comparators {
+		if c.v.PreRelease != "" && c.v.PreRelease != "0" && c.v.Major == v.Major && c.v.Minor == v.Minor && c.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+func TestVersionRange(t *testing.T) {
+	tests := []struct {
+		rng               string
+		includePrerelease bool
+		in, out           []string
+	}{
+		{rng: "", in: []string{"0.0.1", "1.2.3", "10.0.0"}, out: []string{"1.3.0-beta.1"}},
+		{rng: "*", in: []string{"0.0.1", "1.2.3"}},
+		{rng: "1.2.3", in: []string{"1.2.3"}, out: []string{"1.2.4", "1.2.2"}},
+		{rng: "=v1.2.3", in: []string{"1.2.3"}, out: []string{"1.2.4"}},
+		{rng: "1.2", in: []string{"1.2.0", "1.2.9"}, out: []string{"1.3.0", "1.1.9"}},
+		{rng: "1.x", in: []string{"1.0.0", "1.9.9"}, out: []string{"2.0.0", "0.9.0"}},
+		{rng: "^1.2.3", in: []string{"1.2.3", "1.9.0"}, out: []string{"1.2.2", "2.0.0", "2.0.0-beta.1"}},
+		{rng: "^0.2.3", in: []string{"0.2.3", "0.2.9"}, out: []string{"0.3.0"}},
+		{rng: "^0.0.3", in: []string{"0.0.3"}, out: []string{"0.0.4"}},
+		{rng: "~1.2.3", in: []string{"1.2.3", "1.2.9"}, out: []string{"1.3.0"}},
+		{rng: "~1", in: []string{"1.0.0", "1.9.0"}, out: []string{"2.0.0"}},
+		{rng: ">=1.0.0 <2.0.0", in: []string{"1.0.0", "1.9.9"}, out: []string{"0.9.9", "2.0.0"}},
+		{rng: ">1.2", in: []string{"1.3.0"}, out: []string{"1.2.9"}},
+		{rng: "<=1.2", in: []string{"1.2.9"}, out: []string{"1.3.0"}},
+		{rng: "1.x || >=3.0.0", in: []string{"1.5.0", "3.1.0"}, out: []string{"2.0.0"}},
+
+		// Prereleases.
+		{rng: "^1.2.0", out: []string{"1.3.0-beta.1"}},
+		{rng: "^1.2.0", includePrerelease: true, in: []string{"1.3.0-beta.1"}, out: []string{"2.0.0-beta.1"}},
+		{rng: ">=1.3.0-beta.1", in: []string{"1.3.0-beta.2", "1.3.0"}, out: []string{"1.4.0-beta.1"}},
+	}
+	for _, test := range tests {
+		r, err := parseVersionRange(test.rng)
+		if err != nil {
+			t.Errorf("%q: %s", test.rng, err)
+			continue
+		}
+		for _, v := range test.in {
+			if !r.contains(*semver.New(v), test.includePrerelease) {
+				t.Errorf("%q (includePrerelease=%v): want %s in range", test.rng, test.includePrerelease, v)
+			}
+		}
+		for _, v := range test.out {
+			if r.contains(*semver.New(v), test.includePrerelease) {
+				t.Errorf("%q (includePrerelease=%v): want %s not in range", test.rng, test.includePrerelease, v)
+			}
+		}
+	}
+}
+
+func TestParseVersionRange_invalid(t *testing.T) {
+	for _, rng := range []string{"abc", "1.2.3.4", "1.x.3", "1.2-beta", "<*"} {
+		if _, err := parseVersionRange(rng); err == nil {
+			t.Errorf("%q: got nil error, want error", rng)
+		}
+	}
+}
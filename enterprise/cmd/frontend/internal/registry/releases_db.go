@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/coreos/go-semver/semver"
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
 )
 
 // dbRelease describes a release of an extension in the extension registry.
@@ -17,12 +19,23 @@ type dbRelease struct {
 	ID                  int64
 	RegistryExtensionID int32
 	CreatorUserID       int32
-	ReleaseVersion      *string
+	ReleaseVersion      *string // semantic version (e.g., "1.2.3"), or nil if the release has no version
 	ReleaseTag          string
+	Channel             string // "stable" or "beta"
 	Manifest            string
 	Bundle              *string
 	SourceMap           *string
 	CreatedAt           time.Time
+
+	// BundleSHA256 is the hex-encoded SHA-256 hash of the bundle, or nil if the release has no
+	// bundle (or was published before hashes were recorded).
+	BundleSHA256 *string
+
+	// Signature is the publisher's signature of the release (see verifyReleaseSignature), and
+	// SignatureKeyFingerprint is the SHA-256 fingerprint of the key that made it. Both are nil if
+	// the release is unsigned.
+	Signature               *string
+	SignatureKeyFingerprint *string
 }
 
 type dbReleases struct{}
@@ -40,58 +53,216 @@ func (err releaseNotFoundError) Error() string {
 	return fmt.Sprintf("registry extension release not found: %v", err.args)
 }
 
-var errInvalidJSONInManifest = errors.New("invalid syntax in extension manifest JSON")
+var (
+	errInvalidJSONInManifest = errors.New("invalid syntax in extension manifest JSON")
+	errReleaseVersionExists  = errors.New("a release with this version already exists")
+)
 
-// Create creates a new release of an extension in the extension registry. The release.ID and
-// release.CreatedAt fields are ignored (they are populated automatically by the database).
+// Create creates a new release of an extension in the extension registry. The release.ID,
+// release.CreatedAt and release.BundleSHA256 fields are ignored (they are populated automatically).
+// If release.Channel is empty, the release is created in the stable channel.
+//
+// The caller must verify release.Signature (if any) and set release.SignatureKeyFingerprint.
 func (dbReleases) Create(ctx context.Context, release *dbRelease) (id int64, err error) {
 	if mocks.releases.Create != nil {
 		return mocks.releases.Create(release)
 	}
 
+	channel := release.Channel
+	if channel == "" {
+		channel = registry.ChannelStable
+	}
+	if err := validateReleaseChannel(channel); err != nil {
+		return 0, err
+	}
+	if release.ReleaseVersion != nil {
+		if _, err := parseReleaseVersion(*release.ReleaseVersion); err != nil {
+			return 0, err
+		}
+	}
+	var hash *string
+	if release.Bundle != nil {
+		h := bundleSHA256(*release.Bundle)
+		hash = &h
+	}
+	if release.Signature != nil && (hash == nil || release.SignatureKeyFingerprint == nil) {
+		return 0, errors.New("a signed extension release must have a bundle and a signature key fingerprint")
+	}
+
 	if err := dbconn.Global.QueryRowContext(ctx,
 		`
-INSERT INTO registry_extension_releases(registry_extension_id, creator_user_id, release_version, release_tag, manifest, bundle, source_map)
-VALUES($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO registry_extension_releases(registry_extension_id, creator_user_id, release_version, release_tag, channel, manifest, bundle, source_map, bundle_sha256, signature, signature_key_fingerprint)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING id
 `,
-		release.RegistryExtensionID, release.CreatorUserID, release.ReleaseVersion, release.ReleaseTag, release.Manifest, release.Bundle, release.SourceMap,
+		release.RegistryExtensionID, release.CreatorUserID, release.ReleaseVersion, release.ReleaseTag, channel, release.Manifest, release.Bundle, release.SourceMap, hash, release.Signature, release.SignatureKeyFingerprint,
 	).Scan(&id); err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Message == "invalid input syntax for type json" {
 				return 0, errInvalidJSONInManifest
 			}
+			if pqErr.Constraint == "registry_extension_releases_version" {
+				return 0, errReleaseVersionExists
+			}
 		}
 		return 0, err
 	}
 	return id, nil
 }
 
-// GetLatest gets the latest release for the extension with the given release tag (e.g.,
-// "release"). If includeArtifacts is true, it populates the
-// (*dbRelease).{Bundle,SourceMap} fields, which may be large.
+// GetLatest gets the latest stable release for the extension with the given release tag (e.g.,
+// "release"). If includeArtifacts is true, it populates the (*dbRelease).{Bundle,SourceMap}
+// fields, which may be large.
 func (dbReleases) GetLatest(ctx context.Context, registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error) {
 	if mocks.releases.GetLatest != nil {
 		return mocks.releases.GetLatest(registryExtensionID, releaseTag, includeArtifacts)
 	}
 
 	q := sqlf.Sprintf(`
-SELECT id, registry_extension_id, creator_user_id, release_version, release_tag, manifest, CASE WHEN %v::boolean THEN bundle ELSE null END AS bundle, CASE WHEN %v::boolean THEN source_map ELSE null END AS source_map, created_at
+SELECT `+releaseColumns+`
 FROM registry_extension_releases
-WHERE registry_extension_id=%d AND release_tag=%s AND deleted_at IS NULL
+WHERE registry_extension_id=%d AND release_tag=%s AND channel=%s AND deleted_at IS NULL
 ORDER BY created_at DESC
-LIMIT 1`, includeArtifacts, includeArtifacts, registryExtensionID, releaseTag)
-	var r dbRelease
-	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&r.ID, &r.RegistryExtensionID, &r.CreatorUserID, &r.ReleaseVersion, &r.ReleaseTag, &r.Manifest, &r.Bundle, &r.SourceMap, &r.CreatedAt)
+LIMIT 1`, includeArtifacts, includeArtifacts, registryExtensionID, releaseTag, registry.ChannelStable)
+	r, err := scanRelease(dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, releaseNotFoundError{[]interface{}{fmt.Sprintf("latest for registry extension ID %d tag %q", registryExtensionID, releaseTag)}}
 		}
 		return nil, err
 	}
+	return r, nil
+}
+
+// GetLatestMatching gets the newest release for the extension with the given release tag that
+// matches the release query.
+//
+// Releases in the query's channel (and, for the beta channel, the stable channel) are
+// considered. If the query has a version range, the release with the greatest semantic version in
+// the range is returned (releases without a version never match). Otherwise, the most recently
+// published release is returned.
+func (dbReleases) GetLatestMatching(ctx context.Context, registryExtensionID int32, releaseTag string, query registry.ReleaseQuery, includeArtifacts bool) (*dbRelease, error) {
+	if mocks.releases.GetLatestMatching != nil {
+		return mocks.releases.GetLatestMatching(registryExtensionID, releaseTag, query, includeArtifacts)
+	}
+
+	channel := query.Channel
+	if channel == "" {
+		channel = registry.ChannelStable
+	}
+	if err := validateReleaseChannel(channel); err != nil {
+		return nil, err
+	}
+	vr, err := parseVersionRange(query.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	notFound := releaseNotFoundError{[]interface{}{fmt.Sprintf("latest for registry extension ID %d tag %q channel %q version %q", registryExtensionID, releaseTag, channel, query.Version)}}
+
+	channels := releaseChannels(channel)
+	if query.Version == "" {
+		q := sqlf.Sprintf(`
+SELECT `+releaseColumns+`
+FROM registry_extension_releases
+WHERE registry_extension_id=%d AND release_tag=%s AND channel = ANY(%s) AND deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT 1`, includeArtifacts, includeArtifacts, registryExtensionID, releaseTag, pq.Array(channels))
+		r, err := scanRelease(dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, notFound
+			}
+			return nil, err
+		}
+		return r, nil
+	}
+
+	// Find the greatest version in the range. Only the versions are loaded here, because there
+	// may be many releases.
+	q := sqlf.Sprintf(`
+SELECT id, release_version
+FROM registry_extension_releases
+WHERE registry_extension_id=%d AND release_tag=%s AND channel = ANY(%s) AND release_version IS NOT NULL AND deleted_at IS NULL`, registryExtensionID, releaseTag, pq.Array(channels))
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var (
+		bestID      int64
+		bestVersion *semver.Version
+	)
+	for rows.Next() {
+		var (
+			id            int64
+			versionString string
+		)
+		if err := rows.Scan(&id, &versionString); err != nil {
+			return nil, err
+		}
+		version, err := parseReleaseVersion(versionString)
+		if err != nil {
+			continue // ignore releases with invalid versions
+		}
+		if vr.contains(*version, channel == registry.ChannelBeta) && (bestVersion == nil || bestVersion.LessThan(*version)) {
+			bestID, bestVersion = id, version
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if bestVersion == nil {
+		return nil, notFound
+	}
+
+	q = sqlf.Sprintf(`
+SELECT `+releaseColumns+`
+FROM registry_extension_releases
+WHERE id=%d AND deleted_at IS NULL`, includeArtifacts, includeArtifacts, bestID)
+	r, err := scanRelease(dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// releaseColumns is the list of columns that scanRelease scans. It has 2 bool format arguments
+// for whether to include the bundle and source map.
+const releaseColumns = `id, registry_extension_id, creator_user_id, release_version, release_tag, channel, manifest, CASE WHEN %v::boolean THEN bundle ELSE null END AS bundle, CASE WHEN %v::boolean THEN source_map ELSE null END AS source_map, created_at, bundle_sha256, signature, signature_key_fingerprint`
+
+func scanRelease(row *sql.Row) (*dbRelease, error) {
+	var r dbRelease
+	if err := row.Scan(&r.ID, &r.RegistryExtensionID, &r.CreatorUserID, &r.ReleaseVersion, &r.ReleaseTag, &r.Channel, &r.Manifest, &r.Bundle, &r.SourceMap, &r.CreatedAt, &r.BundleSHA256, &r.Signature, &r.SignatureKeyFingerprint); err != nil {
+		return nil, err
+	}
 	return &r, nil
 }
 
+// GetByID gets the release (by ID). If includeArtifacts is true, it populates the
+// (*dbRelease).{Bundle,SourceMap} fields, which may be large.
+func (dbReleases) GetByID(ctx context.Context, id int64, includeArtifacts bool) (*dbRelease, error) {
+	if mocks.releases.GetByID != nil {
+		return mocks.releases.GetByID(id, includeArtifacts)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT `+releaseColumns+`
+FROM registry_extension_releases
+WHERE id=%d AND deleted_at IS NULL`, includeArtifacts, includeArtifacts, id)
+	r, err := scanRelease(dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, releaseNotFoundError{[]interface{}{fmt.Sprintf("registry extension release %d", id)}}
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
 // GetArtifacts gets the bundled JavaScript source file contents and the source map for a release
 // (by ID).
 func (dbReleases) GetArtifacts(ctx context.Context, id int64) (bundle, sourcemap []byte, err error) {
@@ -113,6 +284,8 @@ WHERE id=%d AND deleted_at IS NULL`, id)
 
 // mockReleases mocks the registry extension releases store.
 type mockReleases struct {
-	Create    func(release *dbRelease) (int64, error)
-	GetLatest func(registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error)
+	Create            func(release *dbRelease) (int64, error)
+	GetLatest         func(registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error)
+	GetLatestMatching func(registryExtensionID int32, releaseTag string, query registry.ReleaseQuery, includeArtifacts bool) (*dbRelease, error)
+	GetByID           func(id int64, includeArtifacts bool) (*dbRelease, error)
 }
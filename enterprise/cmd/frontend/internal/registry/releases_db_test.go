@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
 )
 
 func TestRegistryExtensionReleases(t *testing.T) {
@@ -64,6 +65,8 @@ func TestRegistryExtensionReleases(t *testing.T) {
 			t.Fatal(err)
 		}
 		input.ID = id
+		input.Channel = registry.ChannelStable
+		input.BundleSHA256 = strptr(bundleSHA256("b"))
 
 		t.Run("GetArtifacts", func(t *testing.T) {
 			bundle, sourcemap, err := dbReleases{}.GetArtifacts(ctx, id)
@@ -78,6 +81,17 @@ func TestRegistryExtensionReleases(t *testing.T) {
 			}
 		})
 
+		t.Run("GetByID", func(t *testing.T) {
+			r, err := dbReleases{}.GetByID(ctx, id, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			norm(r)
+			if !reflect.DeepEqual(*r, input) {
+				t.Errorf("got %+v, want %+v", r, input)
+			}
+		})
+
 		t.Run("GetLatest for 1st release", func(t *testing.T) {
 			r1, err := dbReleases{}.GetLatest(ctx, extensionID, "release", true)
 			if err != nil {
@@ -111,6 +125,8 @@ func TestRegistryExtensionReleases(t *testing.T) {
 			t.Fatal(err)
 		}
 		input2.ID = id2
+		input2.Channel = registry.ChannelStable
+		input2.BundleSHA256 = strptr(bundleSHA256("b2"))
 
 		r2, err := dbReleases{}.GetLatest(ctx, extensionID, "release", true)
 		if err != nil {
@@ -122,6 +138,41 @@ func TestRegistryExtensionReleases(t *testing.T) {
 		}
 	})
 
+	t.Run("Create signed release", func(t *testing.T) {
+		input := dbRelease{
+			RegistryExtensionID:     extensionID,
+			CreatorUserID:           user.ID,
+			ReleaseTag:              "signed",
+			Manifest:                `{"m": true}`,
+			Bundle:                  strptr("b"),
+			Signature:               strptr("s"),
+			SignatureKeyFingerprint: strptr("SHA256:f"),
+		}
+		id, err := dbReleases{}.Create(ctx, &input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := dbReleases{}.GetByID(ctx, id, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Signature == nil || *r.Signature != "s" || r.SignatureKeyFingerprint == nil || *r.SignatureKeyFingerprint != "SHA256:f" {
+			t.Errorf("got signature %v (key %v), want %q (key %q)", r.Signature, r.SignatureKeyFingerprint, "s", "SHA256:f")
+		}
+
+		input.Bundle = nil
+		if _, err := (dbReleases{}).Create(ctx, &input); err == nil {
+			t.Error("want error creating signed release without bundle")
+		}
+	})
+
+	t.Run("GetByID with no release", func(t *testing.T) {
+		_, err := dbReleases{}.GetByID(ctx, 9999 /* doesn't exist */, false)
+		if !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+	})
+
 	t.Run("Create fails on invalid JSON", func(t *testing.T) {
 		_, err := dbReleases{}.Create(ctx, &dbRelease{
 			RegistryExtensionID: extensionID,
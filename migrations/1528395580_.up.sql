@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE repo_groups (
+    id serial PRIMARY KEY,
+    name citext NOT NULL,
+    description text NOT NULL DEFAULT '',
+    rule jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    deleted_at timestamp with time zone,
+    CONSTRAINT repo_groups_name_not_empty CHECK (name <> '')
+);
+
+CREATE UNIQUE INDEX repo_groups_name_unique ON repo_groups(name) WHERE deleted_at IS NULL;
+
+CREATE TABLE repo_group_members (
+    repo_group_id integer NOT NULL REFERENCES repo_groups(id) ON DELETE CASCADE,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    PRIMARY KEY (repo_group_id, repo_id)
+);
+
+CREATE INDEX repo_group_members_repo_id ON repo_group_members(repo_id);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE saved_searches ADD COLUMN notify_webhook boolean NOT NULL DEFAULT false;
+ALTER TABLE saved_searches ADD COLUMN webhook_url text;
+ALTER TABLE saved_searches ADD COLUMN webhook_secret text;
+
+CREATE TABLE saved_search_webhook_deliveries (
+    id serial PRIMARY KEY,
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    url text NOT NULL,
+    test boolean NOT NULL DEFAULT false,
+    result_count integer NOT NULL,
+    attempts integer NOT NULL,
+    status_code integer,
+    error text,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX saved_search_webhook_deliveries_saved_search_id ON saved_search_webhook_deliveries(saved_search_id, created_at);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS discussion_comment_reactions;
+
+DROP INDEX IF EXISTS discussion_threads_assignee_user_ids_idx;
+ALTER TABLE discussion_threads DROP COLUMN IF EXISTS resolved_at;
+ALTER TABLE discussion_threads DROP COLUMN IF EXISTS resolved_by_user_id;
+ALTER TABLE discussion_threads DROP COLUMN IF EXISTS assignee_user_ids;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE discussion_threads ADD COLUMN resolved_at timestamp with time zone;
+ALTER TABLE discussion_threads ADD COLUMN resolved_by_user_id integer REFERENCES users(id) ON DELETE RESTRICT;
+ALTER TABLE discussion_threads ADD COLUMN assignee_user_ids integer[] NOT NULL DEFAULT '{}';
+CREATE INDEX discussion_threads_assignee_user_ids_idx ON discussion_threads USING GIN (assignee_user_ids);
+
+CREATE TABLE discussion_comment_reactions (
+    comment_id bigint NOT NULL REFERENCES discussion_comments(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (comment_id, user_id, emoji)
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX registry_extension_releases_registry_extension_id;
+CREATE INDEX registry_extension_releases_registry_extension_id ON registry_extension_releases(registry_extension_id, release_tag, created_at DESC) WHERE deleted_at IS NULL;
+
+ALTER TABLE registry_extension_releases DROP COLUMN channel;
+
+COMMIT;
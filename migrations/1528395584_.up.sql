@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE registry_extension_releases ADD COLUMN channel citext NOT NULL DEFAULT 'stable';
+ALTER TABLE registry_extension_releases ADD CONSTRAINT registry_extension_releases_channel_check CHECK (channel IN ('stable', 'beta'));
+
+DROP INDEX registry_extension_releases_registry_extension_id;
+CREATE INDEX registry_extension_releases_registry_extension_id ON registry_extension_releases(registry_extension_id, release_tag, channel, created_at DESC) WHERE deleted_at IS NULL;
+
+COMMIT;
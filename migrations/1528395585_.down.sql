@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE registry_extension_releases DROP COLUMN signature_key_fingerprint;
+ALTER TABLE registry_extension_releases DROP COLUMN signature;
+ALTER TABLE registry_extension_releases DROP COLUMN bundle_sha256;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE registry_extension_releases ADD COLUMN bundle_sha256 text;
+ALTER TABLE registry_extension_releases ADD COLUMN signature text;
+ALTER TABLE registry_extension_releases ADD COLUMN signature_key_fingerprint text;
+ALTER TABLE registry_extension_releases ADD CONSTRAINT registry_extension_releases_signature_check CHECK (signature IS NULL OR (bundle_sha256 IS NOT NULL AND signature_key_fingerprint IS NOT NULL));
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE critical_and_site_config DROP COLUMN author_user_id;
+
+COMMIT;
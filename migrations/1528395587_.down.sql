@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE access_tokens DROP COLUMN expires_at;
+ALTER TABLE access_tokens DROP COLUMN repo_allowlist;
+
+COMMIT;
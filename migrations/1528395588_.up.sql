@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE user_permissions (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission text NOT NULL,
+    synced_at timestamp with time zone,
+    sync_started_at timestamp with time zone,
+    PRIMARY KEY (user_id, permission)
+);
+
+CREATE TABLE user_repo_permissions (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    permission text NOT NULL,
+    PRIMARY KEY (user_id, permission, repo_id)
+);
+
+CREATE INDEX user_repo_permissions_repo_id ON user_repo_permissions(repo_id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE explicit_repo_permissions (
+    id serial PRIMARY KEY,
+    user_id integer REFERENCES users(id) ON DELETE CASCADE,
+    org_id integer REFERENCES orgs(id) ON DELETE CASCADE,
+    repo_id integer REFERENCES repo(id) ON DELETE CASCADE,
+    repo_pattern text,
+    permission text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT explicit_repo_permissions_grantee_check CHECK ((user_id IS NULL) <> (org_id IS NULL)),
+    CONSTRAINT explicit_repo_permissions_repo_check CHECK ((repo_id IS NULL) <> (repo_pattern IS NULL)),
+    CONSTRAINT explicit_repo_permissions_repo_pattern_check CHECK (repo_pattern <> '')
+);
+
+CREATE UNIQUE INDEX explicit_repo_permissions_unique ON explicit_repo_permissions(COALESCE(user_id, 0), COALESCE(org_id, 0), COALESCE(repo_id, 0), COALESCE(repo_pattern, ''), permission);
+CREATE INDEX explicit_repo_permissions_user_id ON explicit_repo_permissions(user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX explicit_repo_permissions_org_id ON explicit_repo_permissions(org_id) WHERE org_id IS NOT NULL;
+
+COMMIT;
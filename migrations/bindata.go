@@ -221,6 +221,26 @@
 // 1528395578_.up.sql (714B)
 // 1528395579_.down.sql (35B)
 // 1528395579_.up.sql (175B)
+// 1528395580_.down.sql (92B)
+// 1528395580_.up.sql (781B)
+// 1528395581_.down.sql (264B)
+// 1528395581_.up.sql (742B)
+// 1528395582_.down.sql (91B)
+// 1528395582_.up.sql (87B)
+// 1528395583_.down.sql (344B)
+// 1528395583_.up.sql (744B)
+// 1528395584_.down.sql (314B)
+// 1528395584_.up.sql (491B)
+// 1528395585_.down.sql (226B)
+// 1528395585_.up.sql (437B)
+// 1528395586_.down.sql (82B)
+// 1528395586_.up.sql (129B)
+// 1528395587_.down.sql (121B)
+// 1528395587_.up.sql (151B)
+// 1528395588_.down.sql (100B)
+// 1528395588_.up.sql (609B)
+// 1528395589_.down.sql (65B)
+// 1528395589_.up.sql (1.086kB)
 
 package migrations
 
@@ -4709,6 +4729,406 @@ func _1528395579_UpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395580_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\x4f\x2f\xca\x2f\x2d\x88\xcf\x4d\xcd\x4d\x4a\x2d\x2a\xb6\x26\xa4\x10\xa8\x82\xcb\xd9\xdf\xd7\xd7\x33\xc4\x9a\x0b\x00\x86\x22\xe8\x38\x5c\x00\x00\x00")
+
+func _1528395580_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395580_DownSql,
+		"1528395580_.down.sql",
+	)
+}
+
+func _1528395580_DownSql() (*asset, error) {
+	bytes, err := _1528395580_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395580_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3d, 0x4e, 0x46, 0xc, 0xf5, 0xaa, 0x21, 0x91, 0x42, 0x64, 0xcb, 0x9, 0x6, 0xd6, 0x34, 0xb6, 0xf9, 0x2a, 0xa1, 0x2f, 0x8a, 0x2d, 0x80, 0xb8, 0x86, 0xd8, 0x57, 0xf4, 0x48, 0x84, 0x5a, 0x7a}}
+	return a, nil
+}
+
+var __1528395580_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x92\xdb\x6e\x83\x30\x0c\x86\xef\x79\x0a\xdf\x15\xa4\xbe\x41\xa7\x49\x14\xdc\x15\x95\x86\x8d\x83\xb6\x5e\x45\x14\xac\x2e\x53\x09\x2c\x04\x75\xdb\xd3\x8f\x82\x50\x61\xd5\x0e\xd2\x72\x67\xfd\xf6\x67\xfb\x77\x96\x78\xe7\xb1\x85\x61\x38\x21\xda\x31\x42\x6c\x2f\x7d\x04\x45\x55\xc9\x0f\xaa\x6c\xaa\x1a\x4c\x03\xda\x27\x72\xa8\x49\x89\xf4\x08\xf7\xa1\xb7\xb5\xc3\x1d\x6c\x70\x37\xef\x24\x99\x16\x04\x99\xd0\xf4\xa6\x81\x05\x31\xb0\xc4\xf7\x7b\x25\xa7\x3a\x53\xa2\xd2\xa2\x94\x30\x91\xc1\xc5\x95\x9d\xf8\x31\xcc\x66\x7d\xa6\x6a\x8e\x04\x2f\x75\x29\xf7\x7d\x9c\x29\x4a\x35\xe5\x3c\xd5\xa0\x45\x41\xb5\x4e\x8b\x0a\x4e\x42\x3f\x77\x21\x7c\x94\x92\xae\x61\xb2\x3c\x99\x56\x5f\xdf\x54\xf9\xbf\xea\x73\x3a\xd2\x2f\xf5\x7d\xa2\x13\xb0\x28\x0e\x6d\x8f\xc5\x63\xd3\xf8\xd9\x13\x2e\x4b\xcd\xa9\xa8\xf4\x3b\x38\x6b\x74\x36\x60\x76\x4e\xdd\xdc\xb6\x5b\x5b\x86\x75\xf1\x3c\x61\xde\x43\x82\xe0\x31\x17\x9f\xae\x29\x8d\x14\xaf\x0d\x41\xc0\xc6\x52\x87\xb2\xe0\x71\x8d\x21\x8e\x87\xf5\xa2\x6e\xa5\xef\xef\xc9\x0b\x2a\xf6\xa4\x86\xb3\x8e\x84\xf6\xc2\x42\x6a\x3a\x90\xba\x38\x13\xe2\xaa\x6d\xc0\x1c\x8c\x26\xcd\x45\x6e\x9d\xe7\x71\xd1\xc7\xb6\x85\x63\x47\x8e\xed\xe2\xfc\x02\xfc\x03\xea\x27\xc6\xe8\x87\x81\x39\x99\x70\x3e\xf0\x27\xfe\x7d\x35\x6e\xd8\x91\x0f\xc3\x4c\xbc\x1b\x54\x73\x40\x9d\x41\xc1\x76\xeb\xc5\x0b\xe3\x13\x08\x7e\x4f\xbe\x0d\x03\x00\x00")
+
+func _1528395580_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395580_UpSql,
+		"1528395580_.up.sql",
+	)
+}
+
+func _1528395580_UpSql() (*asset, error) {
+	bytes, err := _1528395580_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395580_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1a, 0x42, 0x47, 0x68, 0xa8, 0x85, 0xe8, 0xca, 0x29, 0xc6, 0x3e, 0x50, 0x5c, 0xa2, 0x1b, 0xcf, 0x2e, 0x5d, 0xc5, 0xbb, 0xba, 0xe8, 0x9b, 0xff, 0xe8, 0xa4, 0x20, 0x4b, 0x6a, 0x29, 0x9c, 0xd8}}
+	return a, nil
+}
+
+var __1528395581_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4e\x2c\x4b\x4d\x89\x2f\x4e\x4d\x2c\x4a\xce\x88\x2f\x4f\x4d\xca\xc8\xcf\xcf\x8e\x4f\x49\xcd\xc9\x2c\x4b\x2d\xca\x4c\x2d\x06\x6a\x73\xf4\x09\x71\x0d\x82\xea\x43\x56\x9d\x5a\xac\x00\x36\xd1\xd9\xdf\x27\xd4\xd7\x0f\xc9\xc8\xbc\xfc\x92\xcc\xb4\x4a\x98\x61\xd6\xa4\x1b\x00\x73\x46\x69\x51\x0e\x05\xba\x8b\x53\x93\x8b\x52\x4b\x80\x1e\x70\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\xc9\x29\xf8\xd3\x08\x01\x00\x00")
+
+func _1528395581_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395581_DownSql,
+		"1528395581_.down.sql",
+	)
+}
+
+func _1528395581_DownSql() (*asset, error) {
+	bytes, err := _1528395581_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395581_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe7, 0x30, 0x20, 0xe0, 0xb3, 0xa8, 0x5b, 0x59, 0xb6, 0x6b, 0x97, 0xf8, 0xb3, 0x8e, 0xc4, 0x6d, 0x94, 0xd, 0xf4, 0x5, 0xe6, 0x61, 0x86, 0xe2, 0xd2, 0x33, 0xac, 0x44, 0xa1, 0x59, 0x66, 0xcd}}
+	return a, nil
+}
+
+var __1528395581_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x91\xc1\x6e\xc2\x30\x0c\x86\xef\x7d\x0a\x1f\x8b\xc4\x1b\x70\x0a\xad\x99\xaa\xb5\xe9\x54\x8a\x34\x4e\x55\xa0\x66\x44\x2b\x0d\x4a\x5c\xd8\xf6\xf4\x8b\x5a\x18\x1b\xdb\xc4\x96\x9b\x63\xff\x7f\x3e\xff\x99\xe2\x5d\x22\x27\x41\x20\xd2\x12\x0b\x28\xc5\x34\x45\x70\xea\x40\x75\xe5\x48\xd9\xf5\x96\x1c\x88\x38\x86\x28\x4f\x17\x99\x84\xd6\xb0\xde\xbc\x56\x47\x5a\x6d\x8d\x79\x86\x95\x31\x0d\xa9\x16\x64\x5e\x82\x5c\xa4\x29\xc4\x38\x13\x8b\xb4\x84\x8d\x6a\x1c\x4d\xfe\x68\x7a\x72\xab\x3a\xdb\x00\xd3\x0b\xff\x57\xe7\x68\x6d\x89\x4f\xd2\x20\x2a\x50\x94\xf8\x83\xf8\x4c\x5d\xd5\xd4\xe8\x03\x59\xed\xdd\xc2\x00\xfc\xd1\x35\x38\x5f\xab\x06\x1e\x8a\x24\x13\xc5\x12\xee\x71\x39\xee\x5b\x5f\x0c\xfc\x9c\x6e\x99\x9e\xc8\x5e\x36\x2e\x70\x86\x05\xca\x08\xe7\x57\xa4\xa1\xae\x47\x90\x4b\x1f\x49\x8a\x9e\x27\x12\xf3\x48\xc4\x38\xb8\x9e\x37\xfd\xb0\x19\xae\x99\x1c\xdf\xc8\x74\x18\xb4\xe4\xba\x86\xab\xb5\xe9\x5a\xfe\x86\x34\x8c\x28\x66\xda\xed\xd9\xfd\xd2\x76\xac\xb8\x73\xde\xa1\xa6\xf3\xc4\xd0\x20\x6b\x8d\xed\xe1\x86\xda\x47\xab\xd8\xaf\xa5\x7c\xc0\x7a\xe7\x01\xd5\x6e\x0f\x47\xcd\xdb\xbe\x84\x37\xd3\xd2\x77\xd4\xd6\x1c\xc3\x51\x30\xba\xfc\x46\x22\x63\x7c\xbc\xf5\x1b\xd5\x75\xd8\x3e\xbd\x1b\x92\xf0\x4a\x32\xfe\xc4\xdb\x3f\x9f\x67\x59\x52\x4e\x82\x77\x40\xe1\x2d\xa3\xe6\x02\x00\x00")
+
+func _1528395581_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395581_UpSql,
+		"1528395581_.up.sql",
+	)
+}
+
+func _1528395581_UpSql() (*asset, error) {
+	bytes, err := _1528395581_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395581_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x61, 0x6e, 0xb2, 0x65, 0x0, 0x47, 0x67, 0xff, 0x20, 0xab, 0x30, 0x9e, 0x4f, 0xe1, 0x9, 0x9e, 0xcc, 0xa3, 0xcf, 0x81, 0x8, 0x7d, 0xad, 0xf9, 0xbf, 0x6c, 0xe1, 0x4f, 0x85, 0x95, 0x2a, 0xea}}
+	return a, nil
+}
+
+var __1528395582_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0d\xcc\x4b\x0a\x80\x20\x10\x00\xd0\xbd\xa7\x98\x7b\xb4\x2a\x9b\x42\xf0\x13\x66\xd0\x4e\x5a\x4c\x11\x84\xd4\xa8\x8b\x6e\x5f\xef\x00\xaf\xc3\x51\xd9\x46\x88\x56\x07\xf4\x10\xda\x4e\x23\x3c\x95\xf8\x8d\x5c\x53\x22\x8e\xb9\x6c\x85\xa0\xf7\x6e\x02\xe9\xf4\x62\x2c\xa8\x01\x70\x55\x73\x98\x81\x29\xd7\xab\xc4\xfd\x4c\x07\xf1\xcd\x67\x2a\xf9\xaf\xa4\x33\x46\x85\x46\x7c\x3f\xc1\x05\x0b\x5b\x00\x00\x00")
+
+func _1528395582_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395582_DownSql,
+		"1528395582_.down.sql",
+	)
+}
+
+func _1528395582_DownSql() (*asset, error) {
+	bytes, err := _1528395582_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395582_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x70, 0x42, 0x71, 0x6d, 0xf2, 0xd1, 0x4, 0x22, 0xfa, 0x99, 0x80, 0xbd, 0x20, 0xc2, 0xcc, 0x67, 0x9a, 0xcc, 0x49, 0xfb, 0x73, 0x75, 0x8a, 0x8a, 0x90, 0xda, 0xc5, 0xd, 0xb5, 0xf, 0x84, 0xc}}
+	return a, nil
+}
+
+var __1528395582_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x2c\x4d\x2d\xaa\x8c\x2f\x2a\xcd\xcb\x4b\x2d\x8a\x2f\x2e\x49\x2c\x49\x55\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x28\x4a\x2d\x2e\xcd\x29\x89\x4f\xcb\xcc\x4b\x4f\x2d\x2a\x28\xca\xcc\x2b\x29\x56\x28\x49\xad\x28\x89\x8e\x05\x1a\xe3\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\xb0\xf1\x8e\x00\x57\x00\x00\x00")
+
+func _1528395582_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395582_UpSql,
+		"1528395582_.up.sql",
+	)
+}
+
+func _1528395582_UpSql() (*asset, error) {
+	bytes, err := _1528395582_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395582_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdc, 0x49, 0x3c, 0x15, 0x71, 0xe, 0x13, 0x4, 0xb4, 0xaf, 0xb1, 0xac, 0x4c, 0xae, 0x93, 0x37, 0x2a, 0x9, 0x75, 0xa5, 0x6a, 0x9f, 0x7a, 0x1b, 0x26, 0x32, 0x57, 0xd5, 0x48, 0xd8, 0x75, 0x9c}}
+	return a, nil
+}
+
+var __1528395583_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x90\xcb\x0a\xc2\x30\x10\x45\xf7\xf9\x8a\xfc\x47\x57\x7d\x44\x09\x24\x8d\xb4\x11\xba\x0b\x31\x19\x34\x60\x5b\xc8\xa4\xa2\x7f\x6f\x10\x0b\x42\x5d\xe9\x72\x2e\x73\xce\x3c\x2a\xb6\xe7\x6d\x41\x48\xd3\xa9\x03\xd5\x65\x25\x18\xe5\x3b\xca\x06\xde\xeb\x9e\xfa\x80\x6e\x41\x0c\xf3\x64\xdc\x3c\x8e\x30\x25\x13\xc1\xba\x94\x03\x5c\x19\xde\x36\x6c\xf8\xce\xa4\x4b\xee\xf6\x68\x6c\x2e\xcf\x13\x80\x59\x10\xa2\x09\x39\x09\xfe\x5e\x90\x52\x68\xd6\xbd\x67\x6e\x29\xfa\xb2\xd7\x4a\x1c\x65\xfb\xa1\x8f\x80\xf3\xf5\x06\xde\xd8\xf4\xaf\xe1\xf4\x58\x17\xfa\xd1\xb4\xb9\x2b\xff\xa4\x56\x52\x72\x5d\x90\x27\xb0\xd7\x5a\x7d\x58\x01\x00\x00")
+
+func _1528395583_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395583_DownSql,
+		"1528395583_.down.sql",
+	)
+}
+
+func _1528395583_DownSql() (*asset, error) {
+	bytes, err := _1528395583_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395583_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6a, 0xf, 0xbe, 0x5a, 0xfd, 0x1f, 0x1, 0x75, 0x37, 0xa5, 0x77, 0x8c, 0x97, 0x8a, 0x41, 0x66, 0x64, 0xf1, 0x3b, 0x4f, 0xe8, 0x2c, 0xc9, 0x99, 0xad, 0xee, 0x3d, 0xbd, 0x8a, 0xdb, 0x64, 0x21}}
+	return a, nil
+}
+
+var __1528395583_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x92\xd1\x6a\x83\x30\x14\x86\xef\x7d\x8a\x73\x57\x85\xbe\x41\xaf\x52\x3d\x2d\xb2\x18\x47\x54\x58\x19\x43\xac\x86\x36\x63\xea\x30\xe9\xda\x6d\xec\xdd\x77\xda\x4e\xba\x55\x19\x6c\x81\x5c\x24\x87\x7c\xff\xf9\xff\x93\x39\x2e\x43\x31\x73\x1c\xc6\x53\x94\x90\xb2\x39\x47\xa8\xb4\x29\x77\xc6\xe8\xb6\xc9\xed\xb6\x53\x45\x65\x80\x05\x01\xf8\x31\xcf\x22\x01\x9d\x32\xed\xd3\x8b\xaa\xf2\xc2\x82\xd5\xb5\x32\xb6\xa8\x9f\x61\xaf\xed\xf6\x74\x84\xb7\xb6\x51\xb3\xff\xf0\xd6\xaf\xf9\xce\xa8\x2e\xd7\x15\xe8\xc6\xaa\x8d\xea\x40\xe2\x02\x25\x0a\x1f\x13\x38\x96\x8c\xab\x2b\x0f\x62\x01\x01\x72\x4c\x91\xca\x49\x2a\x43\x3f\xfd\x8b\x5c\x41\x95\x4d\xa3\x54\xaf\x65\x7a\xb1\xfb\x07\x10\x71\x0a\x22\xe3\x9c\xf8\x0b\x96\xf1\x14\x26\xef\x1f\x93\x99\xe3\x4b\x64\xa4\x16\x8a\x00\xef\x46\xe8\xf9\x00\x49\xfb\x70\x6c\x73\xa4\x93\x2c\x09\xc5\x12\x28\x73\x70\x07\xcf\x3c\x9a\xc3\x97\xd6\xc0\x49\xd9\xd6\xb5\x6a\x6c\x4e\x94\xd2\xd2\x85\x01\xd7\x01\x5a\xfd\x3d\x85\xb6\xd6\x1b\xb2\x72\x31\xf1\x2d\xbc\x21\xe8\x3a\x4a\x9f\x25\x3e\x0b\x70\x7a\x82\x5e\x8f\x61\x0c\x39\x36\x8f\x1f\x10\x55\xb7\x8f\x1a\xac\x3a\x5c\x5a\x3a\x17\x4a\xf2\x60\x7f\xff\x3f\xc3\x49\x34\xed\xde\xf5\xce\xef\x6f\x65\x18\x31\xb9\x82\x1b\x5c\x81\x7b\xf1\x3f\xed\xdb\x9e\x9e\xa5\x3d\xe7\x94\x67\x1c\x45\x21\x7d\x90\x4f\x75\x18\x46\xbc\xe8\x02\x00\x00")
+
+func _1528395583_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395583_UpSql,
+		"1528395583_.up.sql",
+	)
+}
+
+func _1528395583_UpSql() (*asset, error) {
+	bytes, err := _1528395583_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395583_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x79, 0x8e, 0x1f, 0x22, 0x24, 0x2e, 0x35, 0x36, 0x92, 0x3a, 0x2f, 0xb5, 0x59, 0x41, 0x23, 0x82, 0xf5, 0x28, 0xc0, 0x15, 0x65, 0x21, 0xd3, 0xe6, 0xb0, 0xb4, 0xc8, 0xe7, 0x2c, 0xc8, 0xde, 0xbf}}
+	return a, nil
+}
+
+var __1528395584_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x90\xb1\x0a\x83\x30\x18\x84\xf7\x3c\xc5\x3f\xb6\xe0\x1b\x38\x69\xfc\x69\x03\x31\x29\x31\xd2\x6e\x21\xe8\x8f\x15\x24\x85\x24\x43\xfb\xf6\x95\xe2\x28\x0e\x5d\xef\xee\xe3\xb8\xab\xf1\x22\x54\xc9\x58\x63\xf4\x0d\x84\x6a\xf0\x01\x91\xa6\x39\xe5\xf8\x71\xf4\xce\x14\xd2\xfc\x0a\x2e\xd2\x42\x3e\x51\x72\x3b\xde\x3c\x96\x8c\x1b\xac\x2c\xfe\xcb\x83\x56\x47\xd0\x69\x17\x2a\x60\xf3\x5d\xf6\x53\x01\x43\x24\x9f\x69\x74\x3e\x43\x83\x1d\x3f\xc3\xfd\x8a\x06\x61\x5c\x33\x9b\x2c\x3a\x50\xbd\x94\xeb\xd6\x4a\x5a\x34\x60\xab\x5a\xe2\x51\x2f\xfc\x3e\xe1\x5a\xf6\xad\x82\xe1\xe9\x43\xa0\x65\xa5\xb9\x6e\x5b\x61\x4b\xf6\x05\x13\xbd\x1a\x92\x3a\x01\x00\x00")
+
+func _1528395584_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395584_DownSql,
+		"1528395584_.down.sql",
+	)
+}
+
+func _1528395584_DownSql() (*asset, error) {
+	bytes, err := _1528395584_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395584_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3a, 0x1a, 0x31, 0x61, 0xbb, 0x87, 0x32, 0x37, 0x6b, 0x45, 0x8d, 0xa1, 0x18, 0x7a, 0xe5, 0x93, 0xf6, 0x69, 0x3e, 0x93, 0x39, 0x18, 0xe8, 0xdd, 0x28, 0x74, 0xf, 0xc5, 0xfa, 0xca, 0x2, 0xac}}
+	return a, nil
+}
+
+var __1528395584_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x51\x4d\x6b\x84\x30\x10\xbd\xfb\x2b\xe6\xa6\x82\xff\xc0\x53\x36\x99\x76\xc3\xc6\xa4\x68\xa4\xbd\x85\xac\x0e\x5b\xa9\xb8\x60\x72\xd8\xfe\xfb\x86\xa2\xb7\x45\x68\x4f\x33\xcc\x7b\x6f\xde\x7c\x9c\xf0\x55\xea\x3a\xcb\x98\xb2\xd8\x82\x65\x27\x85\xb0\xd2\x6d\x0a\x71\xfd\x76\xf4\x88\xb4\x84\xe9\xbe\xb8\x95\x66\xf2\x81\x02\x30\x21\x80\x1b\xd5\x37\x1a\x86\x4f\xbf\x2c\x34\xc3\x30\xc5\x44\x04\x6d\x2c\xe8\x5e\x29\x10\xf8\xc2\x7a\x65\x21\x0f\xd1\x5f\x67\xca\xeb\x3f\x36\xd7\x9d\x6d\x99\xd4\xf6\x88\xea\x36\xf3\x14\x69\xf8\x02\x7e\x46\x7e\x81\x62\x9f\x48\x6a\x28\x76\xf7\x0a\xf2\x2b\x45\x9f\x97\x65\xda\x52\xb4\xe6\x2d\xa1\x02\x3f\x0e\x9b\x3f\xc1\xa6\xb1\xce\x78\x8b\xcc\xe2\x7f\xf5\x60\xf4\x91\xa8\x78\x2a\xaa\x60\xc3\x5d\xf4\xb7\x6a\x3f\x79\x4a\x56\xf2\x91\x46\xe7\x63\x3a\x77\xc7\x4b\x78\x3f\x63\x8b\x30\x26\xf2\x56\x96\xdd\xef\x37\xd2\xd2\xdc\x34\x8d\xb4\x75\xf6\x03\xda\xd0\xa7\xdc\xeb\x01\x00\x00")
+
+func _1528395584_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395584_UpSql,
+		"1528395584_.up.sql",
+	)
+}
+
+func _1528395584_UpSql() (*asset, error) {
+	bytes, err := _1528395584_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395584_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x75, 0xcf, 0x2b, 0x42, 0xf1, 0x17, 0x57, 0x22, 0xbf, 0x6e, 0xb1, 0x51, 0xf7, 0x5a, 0xb0, 0xec, 0xed, 0xd8, 0xfb, 0x5f, 0x88, 0xf8, 0x7f, 0xb8, 0x61, 0xb5, 0xe5, 0xb1, 0xac, 0x23, 0xdf, 0x5a}}
+	return a, nil
+}
+
+var __1528395585_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4a\x4d\xcf\x2c\x2e\x29\xaa\x8c\x4f\xad\x28\x49\xcd\x2b\xce\xcc\xcf\x8b\x2f\x4a\xcd\x49\x4d\x2c\x4e\x2d\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x28\xce\x4c\xcf\x4b\x2c\x29\x2d\x4a\x8d\xcf\x4e\xad\x8c\x4f\xcb\xcc\x4b\x4f\x2d\x2a\x28\xca\xcc\x2b\xb1\xa6\xcc\x38\xf2\xb4\x27\x95\xe6\xa5\xe4\xa4\xc6\x17\x67\x24\x1a\x99\x9a\x01\x7d\xe4\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\x84\x24\x3a\x7d\xe2\x00\x00\x00")
+
+func _1528395585_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395585_DownSql,
+		"1528395585_.down.sql",
+	)
+}
+
+func _1528395585_DownSql() (*asset, error) {
+	bytes, err := _1528395585_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395585_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe4, 0x9f, 0x1a, 0x8f, 0xec, 0xd, 0xff, 0x76, 0xa5, 0x6d, 0xc6, 0x62, 0xf4, 0x4d, 0x98, 0x79, 0x20, 0xaf, 0xad, 0xf9, 0x86, 0x14, 0x59, 0x1a, 0x19, 0xd6, 0x5f, 0xe5, 0x35, 0x9f, 0x8d, 0x21}}
+	return a, nil
+}
+
+var __1528395585_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x91\x3d\x0f\x82\x30\x14\x45\x77\x7e\xc5\x1b\x61\x35\xd1\x85\xa9\x7c\x44\x1b\x4b\x49\xa0\xcc\x0d\xe2\x13\x1a\x48\x35\x6d\x49\xe4\xdf\x8b\x71\x40\x17\x06\xe2\x7c\x4f\xce\x7d\x1f\x51\x7a\xa4\x3c\xf4\x3c\xc2\x44\x5a\x80\x20\x11\x4b\xc1\x60\xab\xac\x33\x93\xc4\xa7\x43\x6d\xd5\x5d\x4b\x83\x03\xd6\x16\x2d\x90\x24\x81\x38\x67\x55\xc6\xe1\x32\xea\xeb\x80\xd2\x76\xf5\x6e\x7f\x00\x37\xc3\xe1\x16\x8d\x55\xad\xae\xdd\x68\xf0\x0f\x0a\xd9\xe3\x24\x6f\x4a\xb7\x68\x1e\x46\x69\xb7\x49\xc9\x4b\x51\x10\xca\xc5\x1a\x2a\x97\xca\xa6\xc3\xa6\x87\xf8\x94\xc6\x67\xf0\x97\x65\x68\x09\xbc\x62\x0c\xf2\x02\xfc\xdf\x4b\xbd\x93\x5c\x7c\x52\xc2\x93\x95\xe9\xbf\xc8\x20\x98\x9f\x14\xe7\x59\x46\x45\xe8\xbd\x00\x87\xea\x07\xbb\xb5\x01\x00\x00")
+
+func _1528395585_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395585_UpSql,
+		"1528395585_.up.sql",
+	)
+}
+
+func _1528395585_UpSql() (*asset, error) {
+	bytes, err := _1528395585_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395585_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x69, 0x57, 0xea, 0xdb, 0xb5, 0x95, 0x65, 0x6, 0xc, 0xe8, 0xdd, 0xe, 0x2, 0xb8, 0xb9, 0x6f, 0xfd, 0x5b, 0x4d, 0x82, 0x80, 0x68, 0xdb, 0x89, 0x5f, 0xce, 0xba, 0xfd, 0x4d, 0xc6, 0x7d, 0x8c}}
+	return a, nil
+}
+
+var __1528395586_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x2e\xca\x2c\xc9\x4c\x4e\xcc\x89\x4f\xcc\x4b\x89\x2f\xce\x2c\x49\x8d\x4f\xce\xcf\x4b\xcb\x4c\x57\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\x2c\x2d\xc9\xc8\x2f\x8a\x2f\x2d\x4e\x2d\x8a\xcf\x4c\x01\x9a\xe0\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\x0b\x35\x81\x46\x52\x00\x00\x00")
+
+func _1528395586_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395586_DownSql,
+		"1528395586_.down.sql",
+	)
+}
+
+func _1528395586_DownSql() (*asset, error) {
+	bytes, err := _1528395586_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395586_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf3, 0xb4, 0xf7, 0x5a, 0x55, 0xd8, 0x49, 0x0, 0xfb, 0x9b, 0xcb, 0x8b, 0x76, 0xdc, 0x76, 0xa2, 0xe6, 0x90, 0xf6, 0xe8, 0xd7, 0x8a, 0x4f, 0x10, 0xe9, 0x87, 0x26, 0xc6, 0x62, 0xab, 0x49, 0xd9}}
+	return a, nil
+}
+
+var __1528395586_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\xcd\xb1\x0a\xc3\x20\x10\x00\xd0\xdd\xaf\xb8\xb1\xfd\x86\x4c\x46\xaf\x25\x70\x51\x30\x66\x16\x51\x9b\x1e\x14\x03\x6a\xfe\xbf\xed\xfc\x86\x37\xe3\x73\x31\x93\x10\x92\x3c\x3a\xf0\x72\x26\x84\xd4\x78\x70\x8a\x9f\x10\x6b\x0e\x9d\x47\x09\xe9\xac\x2f\x3e\x40\x6a\x0d\xca\xd2\xbe\x1a\x88\xd7\x78\x9f\x2d\x5c\xbd\xb4\xc0\x19\xb8\x8e\x72\x94\x06\x0e\x1f\xe8\xd0\x28\xdc\xe0\x4f\xfd\xc6\xf9\x0e\xd6\x80\x46\x42\x8f\xb0\xa1\x07\xb3\x13\xfd\x42\x65\xd7\x75\xf1\x93\xf8\x02\xd7\x5a\x9b\xaa\x81\x00\x00\x00")
+
+func _1528395586_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395586_UpSql,
+		"1528395586_.up.sql",
+	)
+}
+
+func _1528395586_UpSql() (*asset, error) {
+	bytes, err := _1528395586_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395586_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x93, 0xa3, 0xc7, 0x68, 0x49, 0x1a, 0xff, 0x79, 0xc5, 0xfe, 0x4d, 0x28, 0xd5, 0x43, 0x20, 0x34, 0xe1, 0x68, 0xed, 0x50, 0x11, 0x86, 0xea, 0xcb, 0x70, 0x2c, 0x63, 0x4c, 0xf6, 0x54, 0xee, 0xe2}}
+	return a, nil
+}
+
+var __1528395587_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4c\x4e\x4e\x2d\x2e\x8e\x2f\xc9\xcf\x4e\xcd\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\xad\x28\xc8\x2c\x4a\x2d\x8e\x4f\x2c\xb1\x26\x52\x47\x51\x6a\x41\x7e\x7c\x62\x4e\x4e\x7e\x79\x4e\x66\x31\x50\x17\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x00\x40\xc4\x3b\xc5\x79\x00\x00\x00")
+
+func _1528395587_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395587_DownSql,
+		"1528395587_.down.sql",
+	)
+}
+
+func _1528395587_DownSql() (*asset, error) {
+	bytes, err := _1528395587_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395587_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x96, 0x23, 0x72, 0x18, 0xad, 0x6c, 0x39, 0x1e, 0xa8, 0x4e, 0xd3, 0x67, 0x1, 0x5a, 0x50, 0x23, 0x78, 0x3f, 0x7f, 0xda, 0x53, 0x98, 0x8f, 0x7d, 0x79, 0x50, 0xe8, 0x5c, 0x95, 0x23, 0xae, 0xc2}}
+	return a, nil
+}
+
+var __1528395587_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\xce\x41\x0a\x85\x20\x10\x80\xe1\xbd\xa7\x98\x7b\xb8\xb2\x92\x08\xb4\x20\x6c\xf5\x08\x91\x18\x78\x92\xa5\x34\x03\xc5\x3b\x7d\xd1\x09\xde\xf2\xdf\x7c\xfc\x95\x6e\xbb\x5e\x0a\xa1\x8c\xd3\x23\x38\x55\x19\x0d\x61\x59\x90\xc8\x73\x5e\x71\x27\x50\x4d\x03\xf5\x60\x26\xdb\x03\x5e\x25\x1e\x48\x3e\x30\x70\xdc\x90\x38\x6c\x05\xce\xc8\xdf\x37\xe1\x97\x77\x94\xff\x49\x07\x96\xec\x43\x4a\xf9\x4c\x91\x1e\x0d\x2f\xfe\xcc\xcf\x46\x3d\x58\xdb\x39\x29\x6e\x07\x64\xd8\xe2\x97\x00\x00\x00")
+
+func _1528395587_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395587_UpSql,
+		"1528395587_.up.sql",
+	)
+}
+
+func _1528395587_UpSql() (*asset, error) {
+	bytes, err := _1528395587_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395587_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcc, 0x41, 0xcb, 0x49, 0xc1, 0x34, 0x93, 0xa3, 0xee, 0xb6, 0x79, 0x82, 0x80, 0x49, 0xf4, 0x64, 0xde, 0x6f, 0x93, 0xa8, 0x8, 0x3b, 0x83, 0x25, 0xef, 0x3d, 0x1c, 0x64, 0x81, 0x92, 0xec, 0x10}}
+	return a, nil
+}
+
+var __1528395588_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x2d\x4e\x2d\x8a\x2f\x4a\x2d\xc8\x8f\x2f\x48\x2d\xca\xcd\x2c\x2e\xce\xcc\xcf\x2b\xb6\xc6\xa3\x16\x45\x19\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x00\x65\xa7\x84\x1c\x64\x00\x00\x00")
+
+func _1528395588_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395588_DownSql,
+		"1528395588_.down.sql",
+	)
+}
+
+func _1528395588_DownSql() (*asset, error) {
+	bytes, err := _1528395588_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395588_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x20, 0x34, 0xb5, 0x2, 0xbd, 0x8b, 0x7, 0xd3, 0x44, 0x3f, 0xfa, 0xb5, 0x8a, 0xda, 0x8, 0xfd, 0xca, 0xc, 0x82, 0x34, 0xae, 0x1c, 0x91, 0x1b, 0x40, 0xe5, 0xc7, 0x5d, 0xb0, 0x82, 0xdc, 0x23}}
+	return a, nil
+}
+
+var __1528395588_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x90\xcd\x6a\x84\x40\x10\x84\xef\xf3\x14\x7d\x54\xf0\x0d\x3c\xcd\x6a\x27\x48\x74\x0c\xa3\x81\x78\x12\x59\x9b\xcd\x1c\xfc\x61\x66\x96\xfd\x79\xfa\xd5\x49\x8c\x59\x10\x36\x04\xd2\xb7\xee\x2e\x3e\xaa\x6a\x87\xcf\x89\x08\x19\x8b\x24\xf2\x12\xa1\xe4\xbb\x14\xe1\x68\x48\xd7\x23\xe9\x4e\x19\xa3\x86\xde\x80\xc7\x60\x1a\x77\x56\x2d\xa8\xde\xd2\x81\x34\x88\xbc\x04\xf1\x96\xa6\x20\xf1\x09\x25\x8a\x08\x0b\xa7\x31\x9e\x6a\x7d\xc8\x05\xc4\x98\xe2\x04\x8d\x78\x11\xf1\x18\x03\x07\x59\xb1\x60\xe9\x6c\xbf\x21\x9f\x5f\x73\xe9\xf7\xd4\xd6\x8d\x05\xab\x3a\x32\xb6\xe9\x46\x38\x29\xfb\xe1\x56\xb8\x0e\x3d\xad\xba\x7a\x7a\x6b\xfb\x1b\xf5\xab\x4c\x32\x2e\x2b\x78\xc1\x0a\xbc\xaf\x14\xc1\x0f\x27\x3e\xf3\x37\x2b\xd0\x34\x0e\xff\xd5\x83\x63\x3f\x80\xcc\x9a\xbf\x77\xf9\x28\x75\xb0\x78\xb8\x8b\x9f\x88\x18\xdf\xb7\xe3\xd7\x8b\xe7\xc9\xce\xa6\xc0\x5b\x80\x33\x2e\xcf\xb2\xa4\x0c\xd9\x0d\xd8\x33\xd9\x46\x61\x02\x00\x00")
+
+func _1528395588_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395588_UpSql,
+		"1528395588_.up.sql",
+	)
+}
+
+func _1528395588_UpSql() (*asset, error) {
+	bytes, err := _1528395588_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395588_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x4e, 0x33, 0x82, 0xf8, 0x1c, 0xf7, 0x3d, 0xe6, 0x1e, 0x67, 0x3e, 0x5f, 0x5, 0x58, 0x65, 0xbc, 0xc5, 0x8b, 0xec, 0xa0, 0xa5, 0x1e, 0x61, 0xaf, 0xa8, 0x50, 0x3e, 0x72, 0xc7, 0x14, 0xc7}}
+	return a, nil
+}
+
+var __1528395589_DownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xad\x28\xc8\xc9\x4c\xce\x2c\x89\x2f\x4a\x2d\xc8\x8f\x2f\x48\x2d\xca\xcd\x2c\x2e\xce\xcc\xcf\x2b\x06\x6a\x70\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x3f\x46\x96\x67\x41\x00\x00\x00")
+
+func _1528395589_DownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395589_DownSql,
+		"1528395589_.down.sql",
+	)
+}
+
+func _1528395589_DownSql() (*asset, error) {
+	bytes, err := _1528395589_DownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395589_.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xea, 0x8e, 0x80, 0xdb, 0x37, 0x15, 0x3b, 0x42, 0xa, 0xab, 0x69, 0x20, 0x52, 0x73, 0x9, 0xec, 0x13, 0xfd, 0x7b, 0x65, 0x75, 0xa, 0x28, 0x39, 0x78, 0x56, 0x9d, 0x45, 0xdf, 0xf8, 0x98, 0xac}}
+	return a, nil
+}
+
+var __1528395589_UpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x52\x4d\x6f\x82\x40\x14\xbc\xf3\x2b\xde\x4d\x48\x38\xf4\x6e\xd3\x64\x5d\x9f\x95\x88\x4b\xcb\x47\x5a\x4f\x84\xe0\x46\x37\x55\xa0\xb0\x46\xd3\x5f\xdf\x15\x16\x95\x46\xb1\x2d\x37\x66\x76\xde\xbc\x9d\x9d\x11\x3e\x3b\x6c\x68\x18\xd4\x47\x12\x22\x84\x64\xe4\x22\xf0\x43\xb1\x11\xa9\x90\x71\xc9\x8b\x3c\x2e\x78\xb9\x15\x55\x25\xf2\xac\x02\xd3\x00\xf5\x89\x25\x54\xbc\x14\xc9\x06\x5e\x7c\x67\x4e\xfc\x05\xcc\x70\x61\xd7\xd4\x4e\x11\xb1\xe2\x45\x26\xf9\x8a\x97\xe0\xe3\x04\x7d\x64\x14\x83\x9a\xaa\x4c\xb1\xb4\xc0\x63\x30\x46\x17\x95\x1f\x25\x01\x25\x63\x6c\xb4\x79\xb9\xba\x21\x55\x4c\xaf\xb2\xde\xf3\xba\xf4\x48\xdd\x95\x16\x89\x94\xbc\xcc\x40\xf2\x83\x6c\xe0\xf3\xa5\x6b\x10\x98\x17\x02\x8b\x5c\xb7\x61\xd3\x92\x27\x92\x2f\xe3\x44\x82\x14\x5b\x5e\xc9\x64\x5b\xc0\x5e\xc8\x75\xfd\x0b\x5f\x79\xc6\x4f\x0a\xe5\x3a\x21\x91\x1b\x42\x96\xef\x4d\xab\xd1\x53\x8f\x05\xa1\x4f\x1c\x16\xde\xce\x3a\x5e\x95\x89\xba\x0e\x8f\xd3\x35\x4f\x3f\x80\x4e\x91\xce\xc0\x34\xdb\x80\x9d\xa0\x9e\x6e\xc1\xe3\x13\x98\x3a\xb9\x16\xfb\x8b\x4b\x0d\x74\x2d\xda\x34\x3b\x16\x9d\x9c\xfe\x6d\xa4\x07\x74\x0d\x3b\xa3\x95\xd7\x60\x60\x19\xd6\xb9\x93\x11\x73\x5e\x23\x04\x87\x8d\xf1\xbd\x67\xfe\x2e\x13\x9f\x3b\x7e\x7c\xe6\x9b\x67\x4c\xea\x11\x17\x03\x8a\x6d\x8a\x36\x3c\x58\x36\x9c\xd0\x26\xc6\x1f\xa0\x4e\xe3\x1a\xaa\x77\xb6\x8f\x1b\xdb\x17\x95\x51\xcb\xeb\xdd\xef\x2e\xad\x5f\xb3\x77\x6b\x7d\xc8\x82\xb7\xa9\x6a\x35\x5c\x36\x40\x77\xec\xd7\x7e\xba\x28\xbd\x76\xcd\x99\xd6\xed\xa2\x5a\x27\x33\x83\x7a\xf3\xb9\x13\x0e\x8d\x6f\xb5\xff\xbd\x50\x3e\x04\x00\x00")
+
+func _1528395589_UpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395589_UpSql,
+		"1528395589_.up.sql",
+	)
+}
+
+func _1528395589_UpSql() (*asset, error) {
+	bytes, err := _1528395589_UpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395589_.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x38, 0x21, 0x86, 0x32, 0x20, 0xd1, 0xde, 0x2e, 0x6b, 0xfa, 0xfe, 0xa6, 0x40, 0xc1, 0x58, 0x89, 0xc1, 0x5f, 0x3, 0x25, 0xc3, 0x69, 0x97, 0xc9, 0xb1, 0x37, 0xc4, 0x49, 0x0, 0x44, 0xa5, 0xab}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -5241,6 +5661,46 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395579_.down.sql": _1528395579_DownSql,
 
 	"1528395579_.up.sql": _1528395579_UpSql,
+
+	"1528395580_.down.sql": _1528395580_DownSql,
+
+	"1528395580_.up.sql": _1528395580_UpSql,
+
+	"1528395581_.down.sql": _1528395581_DownSql,
+
+	"1528395581_.up.sql": _1528395581_UpSql,
+
+	"1528395582_.down.sql": _1528395582_DownSql,
+
+	"1528395582_.up.sql": _1528395582_UpSql,
+
+	"1528395583_.down.sql": _1528395583_DownSql,
+
+	"1528395583_.up.sql": _1528395583_UpSql,
+
+	"1528395584_.down.sql": _1528395584_DownSql,
+
+	"1528395584_.up.sql": _1528395584_UpSql,
+
+	"1528395585_.down.sql": _1528395585_DownSql,
+
+	"1528395585_.up.sql": _1528395585_UpSql,
+
+	"1528395586_.down.sql": _1528395586_DownSql,
+
+	"1528395586_.up.sql": _1528395586_UpSql,
+
+	"1528395587_.down.sql": _1528395587_DownSql,
+
+	"1528395587_.up.sql": _1528395587_UpSql,
+
+	"1528395588_.down.sql": _1528395588_DownSql,
+
+	"1528395588_.up.sql": _1528395588_UpSql,
+
+	"1528395589_.down.sql": _1528395589_DownSql,
+
+	"1528395589_.up.sql": _1528395589_UpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -5505,6 +5965,26 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395578_.up.sql":                                          {_1528395578_UpSql, map[string]*bintree{}},
 	"1528395579_.down.sql":                                        {_1528395579_DownSql, map[string]*bintree{}},
 	"1528395579_.up.sql":                                          {_1528395579_UpSql, map[string]*bintree{}},
+	"1528395580_.down.sql":                                        {_1528395580_DownSql, map[string]*bintree{}},
+	"1528395580_.up.sql":                                          {_1528395580_UpSql, map[string]*bintree{}},
+	"1528395581_.down.sql":                                        {_1528395581_DownSql, map[string]*bintree{}},
+	"1528395581_.up.sql":                                          {_1528395581_UpSql, map[string]*bintree{}},
+	"1528395582_.down.sql":                                        {_1528395582_DownSql, map[string]*bintree{}},
+	"1528395582_.up.sql":                                          {_1528395582_UpSql, map[string]*bintree{}},
+	"1528395583_.down.sql":                                        {_1528395583_DownSql, map[string]*bintree{}},
+	"1528395583_.up.sql":                                          {_1528395583_UpSql, map[string]*bintree{}},
+	"1528395584_.down.sql":                                        {_1528395584_DownSql, map[string]*bintree{}},
+	"1528395584_.up.sql":                                          {_1528395584_UpSql, map[string]*bintree{}},
+	"1528395585_.down.sql":                                        {_1528395585_DownSql, map[string]*bintree{}},
+	"1528395585_.up.sql":                                          {_1528395585_UpSql, map[string]*bintree{}},
+	"1528395586_.down.sql":                                        {_1528395586_DownSql, map[string]*bintree{}},
+	"1528395586_.up.sql":                                          {_1528395586_UpSql, map[string]*bintree{}},
+	"1528395587_.down.sql":                                        {_1528395587_DownSql, map[string]*bintree{}},
+	"1528395587_.up.sql":                                          {_1528395587_UpSql, map[string]*bintree{}},
+	"1528395588_.down.sql":                                        {_1528395588_DownSql, map[string]*bintree{}},
+	"1528395588_.up.sql":                                          {_1528395588_UpSql, map[string]*bintree{}},
+	"1528395589_.down.sql":                                        {_1528395589_DownSql, map[string]*bintree{}},
+	"1528395589_.up.sql":                                          {_1528395589_UpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
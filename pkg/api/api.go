@@ -146,6 +146,16 @@ type ExternalService struct {
 	DeletedAt   *time.Time
 }
 
+// RepoGroupRule determines the members of a dynamic repository group. It is
+// stored as JSON by the frontend and evaluated by repo-updater after each
+// sync. A repository is a member if it satisfies every non-empty criterion.
+type RepoGroupRule struct {
+	NamePattern        string   `json:"namePattern,omitempty"`        // regexp the repository name must match
+	ExternalServiceIDs []int64  `json:"externalServiceIDs,omitempty"` // external services, one of which must yield the repository
+	Topics             []string `json:"topics,omitempty"`             // code host topics, one of which the repository must have
+	Owners             []string `json:"owners,omitempty"`             // code host owners (users, organizations, groups or projects), one of which must own the repository
+}
+
 func cmp(a, b string) int {
 	switch {
 	case a < b:
@@ -86,6 +86,9 @@ type ConfigSavedQuery struct {
 	UserID          *int32  `json:"userID"`
 	OrgID           *int32  `json:"orgID"`
 	SlackWebhookURL *string `json:"slackWebhookURL"`
+	NotifyWebhook   bool    `json:"notifyWebhook,omitempty"`
+	WebhookURL      *string `json:"webhookURL"`
+	WebhookSecret   *string `json:"webhookSecret"`
 }
 
 func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
@@ -136,6 +139,11 @@ type SavedQueryInfo struct {
 
 	// ExecDuration is the amount of time it took for the query to execute.
 	ExecDuration time.Duration
+
+	// ResultFingerprints identifies the matches of the last execution of the
+	// query, so that the next execution can determine which matches are new.
+	// It is nil if the matches are not known.
+	ResultFingerprints []string
 }
 
 // SavedQueriesGetInfo gets the info from the DB for the given saved query. nil
@@ -158,6 +166,39 @@ func (c *internalClient) SavedQueriesDeleteInfo(ctx context.Context, query strin
 	return c.postInternal(ctx, "saved-queries/delete-info", query, nil)
 }
 
+// SavedQueryWebhookDelivery records an attempt to deliver a webhook
+// notification for a saved query.
+type SavedQueryWebhookDelivery struct {
+	// Key is the key of the saved query (see ConfigSavedQuery.Key).
+	Key string
+
+	// URL is the webhook URL that the notification was sent to.
+	URL string
+
+	// Test is whether this was a test notification.
+	Test bool
+
+	// ResultCount is the number of new results in the notification.
+	ResultCount int
+
+	// Attempts is the number of times delivery was attempted.
+	Attempts int
+
+	// StatusCode is the HTTP status code of the last attempt, or 0 if no
+	// response was received.
+	StatusCode int
+
+	// Error is the error of the last attempt, or the empty string if the
+	// notification was delivered.
+	Error string
+}
+
+// SavedQueriesRecordWebhookDelivery records the delivery of a webhook
+// notification in the saved query's delivery history.
+func (c *internalClient) SavedQueriesRecordWebhookDelivery(ctx context.Context, delivery *SavedQueryWebhookDelivery) error {
+	return c.postInternal(ctx, "saved-queries/record-webhook-delivery", delivery, nil)
+}
+
 func (c *internalClient) SettingsGetForSubject(ctx context.Context, subject SettingsSubject) (parsed *schema.Settings, settings *Settings, err error) {
 	err = c.postInternal(ctx, "settings/get-for-subject", subject, &settings)
 	if err == nil {
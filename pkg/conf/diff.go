@@ -3,8 +3,10 @@ package conf
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -21,6 +23,56 @@ func diff(before, after *Unified) (fields map[string]struct{}) {
 	return diff
 }
 
+// FieldDiff describes a configuration field whose value differs between two
+// configurations.
+type FieldDiff struct {
+	// Field is the JSON name of the field, such as "externalURL" for site
+	// configuration fields and "critical::externalURL" for critical
+	// configuration fields.
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DiffRaw parses the two configurations and returns the fields whose values
+// differ between them, sorted by field name.
+func DiffRaw(before, after conftypes.RawUnified) ([]FieldDiff, error) {
+	beforeParsed, err := ParseConfig(before)
+	if err != nil {
+		return nil, err
+	}
+	afterParsed, err := ParseConfig(after)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeFields := unifiedJSONFields(beforeParsed)
+	afterFields := unifiedJSONFields(afterParsed)
+	var diffs []FieldDiff
+	for field := range diff(beforeParsed, afterParsed) {
+		diffs = append(diffs, FieldDiff{
+			Field:  field,
+			Before: beforeFields[field],
+			After:  afterFields[field],
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs, nil
+}
+
+// unifiedJSONFields returns the values of all fields of the configuration,
+// keyed by the same field names that diff returns.
+func unifiedJSONFields(c *Unified) map[string]interface{} {
+	fields := getJSONFields(c.SiteConfiguration, "")
+	for k, v := range getJSONFields(c.Critical, "critical::") {
+		fields[k] = v
+	}
+	for k, v := range getJSONFields(c.ServiceConnections, "serviceConnections::") {
+		fields[k] = v
+	}
+	return fields
+}
+
 func diffStruct(before, after interface{}, prefix string) (fields map[string]struct{}) {
 	fields = make(map[string]struct{})
 	beforeFields := getJSONFields(before, prefix)
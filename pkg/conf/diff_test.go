@@ -5,6 +5,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -96,3 +97,33 @@ func toSlice(m map[string]struct{}) []string {
 	}
 	return s
 }
+
+func TestDiffRaw(t *testing.T) {
+	before := conftypes.RawUnified{
+		Critical: `{"externalURL": "https://a.example.com"}`,
+		Site:     `{"maxReposToSearch": 10, "experimentalFeatures": {"discussions": "enabled"}}`,
+	}
+	after := conftypes.RawUnified{
+		Critical: `{"externalURL": "https://b.example.com"}`,
+		Site: `{
+			// Comments are ignored.
+			"maxReposToSearch": 10,
+			"experimentalFeatures": {"discussions": "disabled"}
+		}`,
+	}
+	got, err := DiffRaw(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FieldDiff{
+		{Field: "critical::externalURL", Before: "https://a.example.com", After: "https://b.example.com"},
+		{Field: "experimentalFeatures::discussions", Before: "enabled", After: "disabled"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := DiffRaw(before, conftypes.RawUnified{Site: `[]`}); err == nil {
+		t.Error("want error for invalid configuration")
+	}
+}
@@ -11,17 +11,19 @@ import (
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 )
 
 // Config contains the contents of a critical/site config along with associated metadata.
 type Config struct {
-	ID        int32     // the unique ID of this config
-	Type      string    // either "critical" or "site"
-	Contents  string    // the raw JSON content (with comments and trailing commas allowed)
-	CreatedAt time.Time // the date when this config was created
-	UpdatedAt time.Time // the date when this config was updated
+	ID           int32     // the unique ID of this config
+	Type         string    // either "critical" or "site"
+	Contents     string    // the raw JSON content (with comments and trailing commas allowed)
+	AuthorUserID *int32    // the user who saved this config, or nil if it was not saved by a user (e.g., the default config or an edit in the management console)
+	CreatedAt    time.Time // the date when this config was created
+	UpdatedAt    time.Time // the date when this config was updated
 }
 
 // SiteConfig contains the contents of a site config along with associated metadata.
@@ -37,6 +39,7 @@ var ErrNewerEdit = errors.New("someone else has already applied a newer edit")
 
 // SiteCreateIfUpToDate saves the given site config "contents" to the database iff the
 // supplied "lastID" is equal to the one that was most recently saved to the database.
+// The actor in ctx (if any) is recorded as the author of the new config.
 //
 // The site config that was most recently saved to the database is returned.
 // An error is returned if "contents" is invalid JSON.
@@ -65,6 +68,7 @@ func SiteCreateIfUpToDate(ctx context.Context, lastID *int32, contents string) (
 // CriticalCreateIfUpToDate saves the given critical config "contents" to the
 // database iff the supplied "lastID" is equal to the one that was most
 // recently saved to the database (i.e. SiteGetlatest's ID field).
+// The actor in ctx (if any) is recorded as the author of the new config.
 //
 // The critical config that was most recently saved to the database is returned.
 // An error is returned if "contents" is invalid JSON.
@@ -177,6 +181,10 @@ func createIfUpToDate(ctx context.Context, tx queryable, configType configType,
 	new := Config{
 		Contents: contents,
 	}
+	if a := actor.FromContext(ctx); a.IsAuthenticated() {
+		uid := a.UID
+		new.AuthorUserID = &uid
+	}
 
 	latest, err = getLatest(ctx, tx, configType)
 	if err != nil {
@@ -188,8 +196,8 @@ func createIfUpToDate(ctx context.Context, tx queryable, configType configType,
 
 	err = tx.QueryRowContext(
 		ctx,
-		"INSERT INTO critical_and_site_config(type, contents) VALUES($1, $2) RETURNING id, created_at, updated_at",
-		configType, new.Contents,
+		"INSERT INTO critical_and_site_config(type, contents, author_user_id) VALUES($1, $2, $3) RETURNING id, created_at, updated_at",
+		configType, new.Contents, new.AuthorUserID,
 	).Scan(&new.ID, &new.CreatedAt, &new.UpdatedAt)
 	if err != nil {
 		return nil, err
@@ -198,7 +206,7 @@ func createIfUpToDate(ctx context.Context, tx queryable, configType configType,
 }
 
 func getLatest(ctx context.Context, tx queryable, configType configType) (*Config, error) {
-	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.created_at, s.updated_at FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC LIMIT 1", configType)
+	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.author_user_id, s.created_at, s.updated_at FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC LIMIT 1", configType)
 	rows, err := tx.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
 		return nil, err
@@ -219,7 +227,7 @@ func parseQueryRows(ctx context.Context, rows *sql.Rows) ([]*Config, error) {
 	defer rows.Close()
 	for rows.Next() {
 		f := Config{}
-		err := rows.Scan(&f.ID, &f.Type, &f.Contents, &f.CreatedAt, &f.UpdatedAt)
+		err := rows.Scan(&f.ID, &f.Type, &f.Contents, &f.AuthorUserID, &f.CreatedAt, &f.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -194,3 +194,64 @@ func TestCriticalCreateIfUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestCriticalHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+
+	// Creates the default config.
+	latest, err := CriticalGetLatest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, contents := range []string{`{"a": 1}`, `{"a": 2}`} {
+		latest, err = CriticalCreateIfUpToDate(ctx, &latest.ID, contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := CriticalCountVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 3; count != want {
+		t.Errorf("got count %d, want %d", count, want)
+	}
+
+	versions, err := CriticalListVersions(ctx, ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, v := range versions {
+		contents = append(contents, v.Contents)
+	}
+	if len(contents) != 3 || contents[0] != `{"a": 2}` || contents[1] != `{"a": 1}` {
+		t.Errorf("got versions %q, want newest first", contents)
+	}
+
+	versions, err = CriticalListVersions(ctx, ListOptions{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Contents != `{"a": 1}` {
+		t.Errorf("got %+v, want only the second newest version", versions)
+	}
+
+	version, err := CriticalGetByID(ctx, versions[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Contents != `{"a": 1}` {
+		t.Errorf("got contents %q, want %q", version.Contents, `{"a": 1}`)
+	}
+
+	// Site config versions are not critical config versions.
+	if _, err := SiteGetByID(ctx, versions[0].ID); err != ErrVersionNotFound {
+		t.Errorf("got error %v, want %v", err, ErrVersionNotFound)
+	}
+}
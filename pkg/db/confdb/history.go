@@ -0,0 +1,120 @@
+package confdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// ErrVersionNotFound is returned by SiteGetByID and CriticalGetByID when there
+// is no config of the requested type with the given ID.
+var ErrVersionNotFound = errors.New("configuration version not found")
+
+// ListOptions specifies the options for listing past versions of a config.
+type ListOptions struct {
+	Limit  int // the maximum number of versions to return (0 means no limit)
+	Offset int // the number of (newest) versions to skip
+}
+
+// SiteListVersions returns the versions of the site config that were saved to
+// the database, newest first.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func SiteListVersions(ctx context.Context, opt ListOptions) ([]*SiteConfig, error) {
+	versions, err := listVersions(ctx, typeSite, opt)
+	if err != nil {
+		return nil, err
+	}
+	site := make([]*SiteConfig, len(versions))
+	for i, v := range versions {
+		site[i] = (*SiteConfig)(v)
+	}
+	return site, nil
+}
+
+// CriticalListVersions returns the versions of the critical config that were
+// saved to the database, newest first.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func CriticalListVersions(ctx context.Context, opt ListOptions) ([]*CriticalConfig, error) {
+	versions, err := listVersions(ctx, typeCritical, opt)
+	if err != nil {
+		return nil, err
+	}
+	critical := make([]*CriticalConfig, len(versions))
+	for i, v := range versions {
+		critical[i] = (*CriticalConfig)(v)
+	}
+	return critical, nil
+}
+
+// SiteCountVersions returns the number of versions of the site config that
+// were saved to the database.
+func SiteCountVersions(ctx context.Context) (int, error) {
+	return countVersions(ctx, typeSite)
+}
+
+// CriticalCountVersions returns the number of versions of the critical config
+// that were saved to the database.
+func CriticalCountVersions(ctx context.Context) (int, error) {
+	return countVersions(ctx, typeCritical)
+}
+
+// SiteGetByID returns the version of the site config with the given ID. It
+// returns ErrVersionNotFound if there is none.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func SiteGetByID(ctx context.Context, id int32) (*SiteConfig, error) {
+	site, err := getByID(ctx, typeSite, id)
+	return (*SiteConfig)(site), err
+}
+
+// CriticalGetByID returns the version of the critical config with the given
+// ID. It returns ErrVersionNotFound if there is none.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func CriticalGetByID(ctx context.Context, id int32) (*CriticalConfig, error) {
+	critical, err := getByID(ctx, typeCritical, id)
+	return (*CriticalConfig)(critical), err
+}
+
+func listVersions(ctx context.Context, configType configType, opt ListOptions) ([]*Config, error) {
+	limit := sqlf.Sprintf("")
+	if opt.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %d", opt.Limit)
+	}
+	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.author_user_id, s.created_at, s.updated_at FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC %s OFFSET %d", configType, limit, opt.Offset)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	return parseQueryRows(ctx, rows)
+}
+
+func countVersions(ctx context.Context, configType configType) (count int, err error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM critical_and_site_config WHERE type=%s", configType)
+	err = dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count)
+	return count, err
+}
+
+func getByID(ctx context.Context, configType configType, id int32) (*Config, error) {
+	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.author_user_id, s.created_at, s.updated_at FROM critical_and_site_config s WHERE type=%s AND id=%d", configType, id)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseQueryRows(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) != 1 {
+		return nil, ErrVersionNotFound
+	}
+	return versions[0], nil
+}
@@ -137,6 +137,18 @@ func (s *Store) OpenWithPath(ctx context.Context, key string, fetcher FetcherWit
 	}
 }
 
+// OpenCached will open a file from the local cache with key. Unlike Open, it
+// does not fetch missing items. If key is not in the cache, the returned
+// error satisfies os.IsNotExist.
+func (s *Store) OpenCached(key string) (*File, error) {
+	path := s.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &File{File: f, Path: path}, nil
+}
+
 // path returns the path for key.
 func (s *Store) path(key string) string {
 	// path uses a sha256 hash of the key since we want to use it for the
@@ -43,6 +43,9 @@ func TestOpen(t *testing.T) {
 	}
 
 	// Cache should be empty
+	if _, err := store.OpenCached("key"); !os.IsNotExist(err) {
+		t.Fatalf("Expected OpenCached to return a not exist error on empty cache, got %v", err)
+	}
 	_, usedCache := do()
 	if usedCache {
 		t.Fatal("Expected fetcher to be called on empty cache")
@@ -53,6 +56,14 @@ func TestOpen(t *testing.T) {
 	if !usedCache {
 		t.Fatal("Expected fetcher to not be called when cached")
 	}
+	cf, err := store.OpenCached("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf.Close()
+	if cf.Path != f.Path {
+		t.Fatalf("OpenCached returned path %q, want %q", cf.Path, f.Path)
+	}
 
 	// Evict, then we should not use the cache
 	os.Remove(f.Path)
@@ -56,9 +56,16 @@ type Client struct {
 	// version 5.4 and older). If both Token and Username/Password are specified, Token is used.
 	Username, Password string
 
+	// OAuth is the OAuth credentials of a Bitbucket Server application link for accessing the
+	// server. If set, it is used instead of Token and Username/Password.
+	OAuth *OAuth
+
 	// RateLimit is the self-imposed rate limiter (since Bitbucket does not have a concept
 	// of rate limiting in HTTP response headers).
 	RateLimit *rate.Limiter
+
+	// sudo is the username of the Bitbucket Server user to impersonate (see Sudo).
+	sudo string
 }
 
 // NewClient returns a new Bitbucket Server API client at url. If a nil
@@ -79,6 +86,42 @@ func NewClient(url *url.URL, httpClient httpcli.Doer) *Client {
 	}
 }
 
+// Sudo returns a copy of the client that makes requests on behalf of the Bitbucket Server user with
+// the given username. It requires the client to use OAuth credentials of an application link that
+// allows user impersonation.
+func (c *Client) Sudo(username string) (*Client, error) {
+	if c.OAuth == nil {
+		return nil, errors.New("bitbucketserver.Client: user impersonation requires OAuth credentials")
+	}
+	sudo := *c
+	sudo.sudo = username
+	return &sudo, nil
+}
+
+// Users returns the Bitbucket Server users whose username, name or email address contains filter
+// (or all users if filter is empty).
+func (c *Client) Users(ctx context.Context, pageToken *PageToken, filter string) ([]*User, *PageToken, error) {
+	qry := pageToken.Values()
+	if filter != "" {
+		qry.Set("filter", filter)
+	}
+
+	u := fmt.Sprintf("rest/api/1.0/users?%s", qry.Encode())
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp struct {
+		*PageToken
+		Values []*User
+	}
+	err = c.do(ctx, req, &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Values, resp.PageToken, nil
+}
+
 func (c *Client) Repo(ctx context.Context, projectKey, repoSlug string) (*Repo, error) {
 	u := fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s", projectKey, repoSlug)
 	req, err := http.NewRequest("GET", u, nil)
@@ -145,8 +188,17 @@ func (c *Client) do(ctx context.Context, req *http.Request, result interface{})
 	req.URL = c.URL.ResolveReference(req.URL)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	// Authenticate request, preferring token.
-	if c.Token != "" {
+	// Authenticate request, preferring OAuth, then token.
+	if c.OAuth != nil {
+		if c.sudo != "" {
+			qry := req.URL.Query()
+			qry.Set("user_id", c.sudo)
+			req.URL.RawQuery = qry.Encode()
+		}
+		if err := c.OAuth.sign(req); err != nil {
+			return err
+		}
+	} else if c.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 	} else if c.Username != "" || c.Password != "" {
 		req.SetBasicAuth(c.Username, c.Password)
@@ -286,6 +338,16 @@ type Project struct {
 	} `json:"links"`
 }
 
+type User struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	ID           int    `json:"id"`
+	DisplayName  string `json:"displayName"`
+	Active       bool   `json:"active"`
+	Slug         string `json:"slug"`
+	Type         string `json:"type"`
+}
+
 // IsNotFound reports whether err is a Bitbucket Server API not found error.
 func IsNotFound(err error) bool {
 	switch e := errors.Cause(err).(type) {
@@ -0,0 +1,151 @@
+package bitbucketserver
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OAuth holds the credentials of a Bitbucket Server application link configured for incoming
+// authentication (OAuth 1.0a with RSA-SHA1 signatures). If the application link allows user
+// impersonation, requests can be made on behalf of any Bitbucket Server user (see (*Client).Sudo).
+//
+// See https://confluence.atlassian.com/bitbucketserver/linking-bitbucket-server-with-atlassian-applications-776640400.html.
+type OAuth struct {
+	// ConsumerKey is the consumer key of the application link.
+	ConsumerKey string
+
+	// SigningKey is the RSA private key whose public key is configured in the application link.
+	SigningKey *rsa.PrivateKey
+}
+
+// NewOAuth returns the OAuth credentials for the given consumer key and base64-encoded PEM
+// (PKCS#1 or PKCS#8) RSA private key.
+func NewOAuth(consumerKey, signingKey string) (*OAuth, error) {
+	if consumerKey == "" {
+		return nil, errors.New("empty OAuth consumer key")
+	}
+	pemKey, err := base64.StdEncoding.DecodeString(signingKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "OAuth signing key is not valid base64")
+	}
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("OAuth signing key is not a PEM-encoded private key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		pkcs8Key, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return nil, errors.Wrap(err, "parsing OAuth signing key")
+		}
+		rsaKey, ok := pkcs8Key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("OAuth signing key is not an RSA private key")
+		}
+		key = rsaKey
+	}
+	return &OAuth{ConsumerKey: consumerKey, SigningKey: key}, nil
+}
+
+// sign sets the OAuth 1.0a Authorization header of the request, which must have an absolute URL
+// and no form-encoded body. As is required for 2-legged OAuth with Bitbucket Server, the access
+// token is empty.
+func (o *OAuth) sign(req *http.Request) error {
+	nonce, timestamp := oauthNonceAndTimestamp()
+
+	params := map[string]string{
+		"oauth_consumer_key":     o.ConsumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "RSA-SHA1",
+		"oauth_timestamp":        timestamp,
+		"oauth_token":            "",
+		"oauth_version":          "1.0",
+	}
+
+	hash := sha1.Sum([]byte(oauthSignatureBase(req.Method, req.URL, params)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, o.SigningKey, crypto.SHA1, hash[:])
+	if err != nil {
+		return errors.Wrap(err, "signing OAuth request")
+	}
+	params["oauth_signature"] = base64.StdEncoding.EncodeToString(signature)
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	header := make([]string, len(keys))
+	for i, k := range keys {
+		header[i] = fmt.Sprintf("%s=%q", k, oauthEscape(params[k]))
+	}
+	req.Header.Set("Authorization", "OAuth "+strings.Join(header, ", "))
+	return nil
+}
+
+func oauthNonceAndTimestamp() (nonce, timestamp string) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b), strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+// oauthSignatureBase returns the OAuth 1.0a signature base string of a request with the given
+// method, URL and OAuth protocol parameters (see https://tools.ietf.org/html/rfc5849#section-3.4.1).
+func oauthSignatureBase(method string, u *url.URL, oauthParams map[string]string) string {
+	type param struct{ key, value string }
+	var params []param
+	for k, vs := range u.Query() {
+		for _, v := range vs {
+			params = append(params, param{oauthEscape(k), oauthEscape(v)})
+		}
+	}
+	for k, v := range oauthParams {
+		params = append(params, param{oauthEscape(k), oauthEscape(v)})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].key != params[j].key {
+			return params[i].key < params[j].key
+		}
+		return params[i].value < params[j].value
+	})
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = p.key + "=" + p.value
+	}
+
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	if scheme == "http" && strings.HasSuffix(host, ":80") || scheme == "https" && strings.HasSuffix(host, ":443") {
+		host = host[:strings.LastIndex(host, ":")] // default ports are omitted
+	}
+	baseURL := scheme + "://" + host + u.EscapedPath()
+	return strings.ToUpper(method) + "&" + oauthEscape(baseURL) + "&" + oauthEscape(strings.Join(pairs, "&"))
+}
+
+// oauthEscape percent-encodes s as required by OAuth 1.0a (see
+// https://tools.ietf.org/html/rfc5849#section-3.6).
+func oauthEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,206 @@
+// Package gitea implements a Gitea API client. It also works with Gogs, whose API Gitea's is
+// derived from.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/metrics"
+)
+
+var requestCounter = metrics.NewRequestCounter("gitea", "Total number of requests sent to the Gitea API.")
+
+// PageSize is the number of repositories requested per page. It is the default maximum page size
+// of Gitea (the MAX_RESPONSE_ITEMS setting).
+const PageSize = 50
+
+// Client accesses a Gitea instance via the REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Gitea.
+	URL *url.URL
+
+	// Token is the access token for accessing the API.
+	// https://gitea.example.com/user/settings/applications
+	Token string
+}
+
+// NewClient returns a new Gitea API client at url. If a nil httpClient is provided,
+// http.DefaultClient will be used. To use API methods which require authentication, set the Token
+// field of the returned client.
+func NewClient(url *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		httpClient: requestCounter.Doer(httpClient, categorize),
+		URL:        url,
+	}
+}
+
+// Repo returns the repository with the given owner and name.
+func (c *Client) Repo(ctx context.Context, owner, name string) (*Repo, error) {
+	u := fmt.Sprintf("api/v1/repos/%s/%s", url.PathEscape(owner), url.PathEscape(name))
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp Repo
+	err = c.do(ctx, req, &resp)
+	return &resp, err
+}
+
+// AffiliatedRepos returns the given page (starting at 1) of the repositories the authenticated user
+// owns or collaborates on.
+func (c *Client) AffiliatedRepos(ctx context.Context, page int) ([]*Repo, error) {
+	u := fmt.Sprintf("api/v1/user/repos?%s", pageValues(page).Encode())
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp []*Repo
+	err = c.do(ctx, req, &resp)
+	return resp, err
+}
+
+// SearchRepos returns the given page (starting at 1) of the repositories matching query, a URL
+// query string with parameters of the repository search API (such as "?q=foo&uid=42").
+func (c *Client) SearchRepos(ctx context.Context, page int, query string) ([]*Repo, error) {
+	qry, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range pageValues(page) {
+		qry[k] = vs
+	}
+
+	u := fmt.Sprintf("api/v1/repos/search?%s", qry.Encode())
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		OK   bool    `json:"ok"`
+		Data []*Repo `json:"data"`
+	}
+	if err = c.do(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func pageValues(page int) url.Values {
+	return url.Values{
+		"page":  {strconv.Itoa(page)},
+		"limit": {strconv.Itoa(PageSize)},
+	}
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	req, ht := nethttp.TraceRequest(opentracing.GlobalTracer(), req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.WithStack(&httpError{URL: req.URL, StatusCode: resp.StatusCode})
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// categorize returns a category for an API URL. Used by metrics.
+func categorize(u *url.URL) string {
+	// API to URL mapping looks like this:
+	//
+	// 	Repo -> api/v1/repos/%s/%s
+	// 	AffiliatedRepos -> api/v1/user/repos?%s
+	// 	SearchRepos -> api/v1/repos/search?%s
+	switch {
+	case strings.HasSuffix(u.Path, "/api/v1/user/repos"):
+		return "AffiliatedRepos"
+	case strings.HasSuffix(u.Path, "/api/v1/repos/search"):
+		return "SearchRepos"
+	case strings.Contains(u.Path, "/api/v1/repos/"):
+		return "Repo"
+	default:
+		// don't return the path directly as that could introduce too much dimensionality
+		return "unknown"
+	}
+}
+
+// Repo is a Gitea repository.
+type Repo struct {
+	ID            int    `json:"id"`
+	Owner         *User  `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	Empty         bool   `json:"empty"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	Mirror        bool   `json:"mirror"`
+	Archived      bool   `json:"archived"`
+	HTMLURL       string `json:"html_url"`
+	SSHURL        string `json:"ssh_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// User is a Gitea user or organization.
+type User struct {
+	ID       int    `json:"id"`
+	Login    string `json:"login"`
+	Username string `json:"username"`
+}
+
+// IsNotFound reports whether err is a Gitea API not found error.
+func IsNotFound(err error) bool {
+	switch e := errors.Cause(err).(type) {
+	case *httpError:
+		return e.NotFound()
+	}
+	return false
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("unexpected %d response from Gitea API at %s", e.StatusCode, e.URL)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -237,6 +237,9 @@ func (c *Client) requestGet(ctx context.Context, token, requestURI string, resul
 	// Include node_id (GraphQL ID) in response. See
 	// https://developer.github.com/changes/2017-12-19-graphql-node-id/.
 	req.Header.Add("Accept", "application/vnd.github.jean-grey-preview+json")
+	// Include topics in repository responses. See
+	// https://developer.github.com/v3/repos/#list-all-topics-for-a-repository.
+	req.Header.Add("Accept", "application/vnd.github.mercy-preview+json")
 
 	return c.do(ctx, token, req, result)
 }
@@ -25,15 +25,16 @@ func SplitRepositoryNameWithOwner(nameWithOwner string) (owner, repo string, err
 
 // Repository is a GitHub repository.
 type Repository struct {
-	ID               string // ID of repository (GitHub GraphQL ID, not GitHub database ID)
-	DatabaseID       int64  // The integer database id
-	NameWithOwner    string // full name of repository ("owner/name")
-	Description      string // description of repository
-	URL              string // the web URL of this repository ("https://github.com/foo/bar")
-	IsPrivate        bool   // whether the repository is private
-	IsFork           bool   // whether the repository is a fork of another repository
-	IsArchived       bool   // whether the repository is archived on the code host
-	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this.
+	ID               string   // ID of repository (GitHub GraphQL ID, not GitHub database ID)
+	DatabaseID       int64    // The integer database id
+	NameWithOwner    string   // full name of repository ("owner/name")
+	Description      string   // description of repository
+	URL              string   // the web URL of this repository ("https://github.com/foo/bar")
+	IsPrivate        bool     // whether the repository is private
+	IsFork           bool     // whether the repository is a fork of another repository
+	IsArchived       bool     // whether the repository is archived on the code host
+	ViewerPermission string   // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this.
+	Topics           []string // the repository's topics
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -50,6 +51,9 @@ fragment RepositoryFields on Repository {
 	isFork
 	isArchived
 	viewerPermission
+	repositoryTopics(first: 100) {
+		nodes { topic { name } }
+	}
 }
 	`
 	}
@@ -65,10 +69,34 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	repositoryTopics(first: 100) {
+		nodes { topic { name } }
+	}
 }
 	`
 }
 
+// graphqlRepository is a repository as returned by the GraphQL API, which
+// nests the names of the repository's topics.
+type graphqlRepository struct {
+	Repository
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string
+			}
+		}
+	}
+}
+
+func (r *graphqlRepository) convert() *Repository {
+	repo := r.Repository
+	for _, node := range r.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, node.Topic.Name)
+	}
+	return &repo
+}
+
 func ownerNameCacheKey(owner, name string) string       { return "0:" + owner + "/" + name }
 func nameWithOwnerCacheKey(nameWithOwner string) string { return "0:" + nameWithOwner }
 func nodeIDCacheKey(id string) string                   { return "1:" + id }
@@ -243,6 +271,7 @@ type restRepository struct {
 	Private     bool
 	Fork        bool
 	Archived    bool
+	Topics      []string
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -273,6 +302,7 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsPrivate:     restRepo.Private,
 		IsFork:        restRepo.Fork,
 		IsArchived:    restRepo.Archived,
+		Topics:        restRepo.Topics,
 	}
 }
 
@@ -301,7 +331,7 @@ func (c *Client) getPublicRepositories(ctx context.Context, sinceRepoID int64) (
 // API without use of the redis cache.
 func (c *Client) getRepositoryByNodeIDFromAPI(ctx context.Context, token, id string) (*Repository, error) {
 	var result struct {
-		Node *Repository `json:"node"`
+		Node *graphqlRepository `json:"node"`
 	}
 	if err := c.requestGraphQL(ctx, token, `
 query Repository($id: ID!) {
@@ -326,7 +356,7 @@ query Repository($id: ID!) {
 	if result.Node == nil {
 		return nil, ErrNotFound
 	}
-	return result.Node, nil
+	return result.Node.convert(), nil
 }
 
 // MaxNodeIDs is the maximum number of repository nodes that can be queried in one call to the
@@ -347,7 +377,7 @@ func (c *Client) GetRepositoriesByNodeIDFromAPI(ctx context.Context, token strin
 	}
 
 	var result struct {
-		Nodes []*Repository
+		Nodes []*graphqlRepository
 	}
 	err := c.requestGraphQL(ctx, token, `
 query Repositories($ids: [ID!]!) {
@@ -374,7 +404,7 @@ query Repositories($ids: [ID!]!) {
 	repos := make(map[string]*Repository)
 	for _, r := range result.Nodes {
 		if r != nil {
-			repos[r.ID] = r
+			repos[r.ID] = r.convert()
 		}
 	}
 	return repos, nil
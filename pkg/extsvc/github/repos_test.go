@@ -293,7 +293,10 @@ func TestClient_GetRepositoryByNodeID(t *testing.T) {
 			"nameWithOwner": "o/r",
 			"description": "d",
 			"url": "https://github.example.com/o/r",
-			"isFork": true
+			"isFork": true,
+			"repositoryTopics": {
+				"nodes": [{"topic": {"name": "go"}}, {"topic": {"name": "search"}}]
+			}
 		}
 	}
 }
@@ -306,6 +309,7 @@ func TestClient_GetRepositoryByNodeID(t *testing.T) {
 		Description:   "d",
 		URL:           "https://github.example.com/o/r",
 		IsFork:        true,
+		Topics:        []string{"go", "search"},
 	}
 
 	repo, err := c.GetRepositoryByNodeID(context.Background(), "", "i")
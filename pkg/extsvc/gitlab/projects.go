@@ -27,6 +27,7 @@ type Project struct {
 	Visibility        Visibility     `json:"visibility"`                    // "private", "internal", or "public"
 	ForkedFromProject *ProjectCommon `json:"forked_from_project,omitempty"` // If non-nil, the project from which this project was forked
 	Archived          bool           `json:"archived"`
+	TagList           []string       `json:"tag_list,omitempty"` // the project's tags (shown as topics in the GitLab UI)
 }
 
 type ProjectCommon struct {
@@ -69,6 +69,10 @@ type NotFoundPayload struct {
 
 	// CloneProgress is a progress message from the running clone command.
 	CloneProgress string `json:"cloneProgress,omitempty"`
+
+	// TooLarge is true if the repository is not cloned because it is larger
+	// than its size cap.
+	TooLarge bool `json:"tooLarge,omitempty"`
 }
 
 // IsRepoCloneableRequest is a request to determine if a repo is cloneable.
@@ -108,6 +112,7 @@ type DeprecatedRepoInfoResponse struct {
 	CloneInProgress bool       // whether the repository is currently being cloned
 	CloneProgress   string     // a progress message from the running clone command.
 	Cloned          bool       // whether the repository has been cloned successfully
+	TooLarge        bool       // whether the repository is not cloned because it is larger than its size cap
 	LastFetched     *time.Time // when the last `git remote update` or `git fetch` occurred
 	LastChanged     *time.Time // timestamp of the most recent ref in the git repository
 
@@ -136,6 +141,7 @@ type RepoInfo struct {
 	CloneInProgress bool       // whether the repository is currently being cloned
 	CloneProgress   string     // a progress message from the running clone command.
 	Cloned          bool       // whether the repository has been cloned successfully
+	TooLarge        bool       // whether the repository is not cloned because it is larger than its size cap
 	LastFetched     *time.Time // when the last `git remote update` or `git fetch` occurred
 	LastChanged     *time.Time // timestamp of the most recent ref in the git repository
 
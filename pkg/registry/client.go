@@ -48,26 +48,41 @@ func List(ctx context.Context, registry *url.URL, query string) ([]*Extension, e
 
 // GetByUUID gets the extension from the remote registry with the given UUID. If the remote registry reports
 // that the extension is not found, the returned error implements errcode.NotFounder.
-func GetByUUID(ctx context.Context, registry *url.URL, uuidStr string) (*Extension, error) {
+//
+// The extension's manifest is that of the release selected by the release query.
+func GetByUUID(ctx context.Context, registry *url.URL, uuidStr string, release ReleaseQuery) (*Extension, error) {
 	// Loosely validate the UUID here, to avoid potential security risks if it contains a ".." path
 	// component. Note that this does not normalize the UUID; for example, a UUID with prefix
 	// "urn:uuid:" would be accepted (and it would be harmless and result in a not-found error).
 	if _, err := uuid.Parse(uuidStr); err != nil {
 		return nil, err
 	}
-	return getBy(ctx, registry, "registry.GetByUUID", "uuid", uuidStr)
+	return getBy(ctx, registry, "registry.GetByUUID", "uuid", uuidStr, release)
 }
 
 // GetByExtensionID gets the extension from the remote registry with the given extension ID. If the
 // remote registry reports that the extension is not found, the returned error implements
 // errcode.NotFounder.
-func GetByExtensionID(ctx context.Context, registry *url.URL, extensionID string) (*Extension, error) {
-	return getBy(ctx, registry, "registry.GetByExtensionID", "extension-id", extensionID)
+//
+// The extension's manifest is that of the release selected by the release query.
+func GetByExtensionID(ctx context.Context, registry *url.URL, extensionID string, release ReleaseQuery) (*Extension, error) {
+	return getBy(ctx, registry, "registry.GetByExtensionID", "extension-id", extensionID, release)
 }
 
-func getBy(ctx context.Context, registry *url.URL, op, field, value string) (*Extension, error) {
+func getBy(ctx context.Context, registry *url.URL, op, field, value string, release ReleaseQuery) (*Extension, error) {
+	var q url.Values
+	if !release.IsZero() {
+		q = url.Values{}
+		if release.Channel != "" {
+			q.Set("channel", release.Channel)
+		}
+		if release.Version != "" {
+			q.Set("version", release.Version)
+		}
+	}
+
 	var x *Extension
-	if err := httpGet(ctx, op, toURL(registry, path.Join("extensions", field, value), nil), &x); err != nil {
+	if err := httpGet(ctx, op, toURL(registry, path.Join("extensions", field, value), q), &x); err != nil {
 		if e, ok := err.(*url.Error); ok && e.Err == httpError(http.StatusNotFound) {
 			err = &notFoundError{field: field, value: value}
 		}
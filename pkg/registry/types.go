@@ -19,6 +19,18 @@ type Extension struct {
 	PublishedAt time.Time `json:"publishedAt"`
 	URL         string    `json:"url"`
 
+	// Version is the semantic version of the release whose manifest is returned, if the release
+	// has a version. Channel is its release channel ("stable" or "beta").
+	Version *string `json:"version,omitempty"`
+	Channel string  `json:"channel,omitempty"`
+
+	// BundleSHA256 is the hex-encoded SHA-256 hash of the release's bundled JavaScript source file
+	// (without its `//# sourceMappingURL` directives, which the registry rewrites when serving it),
+	// if the release has a bundle.
+	// Signature is the publisher's signature of the release, if it is signed.
+	BundleSHA256 string `json:"bundleSHA256,omitempty"`
+	Signature    string `json:"signature,omitempty"`
+
 	// RegistryURL is the URL of the remote registry that this extension was retrieved from. It is
 	// not set by package registry.
 	RegistryURL string `json:"-"`
@@ -29,3 +41,28 @@ type Publisher struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
 }
+
+// Release channels of extension releases.
+const (
+	// ChannelStable is the default release channel.
+	ChannelStable = "stable"
+	// ChannelBeta is the release channel for prereleases. Clients that request the beta channel
+	// also receive stable releases (if they are newer).
+	ChannelBeta = "beta"
+)
+
+// ReleaseQuery selects which release of an extension the registry returns.
+type ReleaseQuery struct {
+	// Channel is the release channel ("stable" or "beta"). If empty, the stable channel is used.
+	Channel string
+
+	// Version is a semantic version range, such as "^1.2.0" or ">=1.0.0 <2.0.0". The newest
+	// release whose version is in the range is returned. If empty, the most recently published
+	// release is returned.
+	Version string
+}
+
+// IsZero reports whether q selects the default release (the most recently published stable release).
+func (q ReleaseQuery) IsZero() bool {
+	return (q.Channel == "" || q.Channel == ChannelStable) && q.Version == ""
+}
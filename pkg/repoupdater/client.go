@@ -257,6 +257,47 @@ func (c *Client) ExcludeRepo(ctx context.Context, id uint32) (*protocol.ExcludeR
 	return &res, nil
 }
 
+// webhookHeaders are the (canonical) names of the headers of webhook requests
+// that are forwarded to repo-updater.
+var webhookHeaders = []string{
+	"Content-Type",
+	// GitHub
+	"X-Github-Event",
+	"X-Github-Delivery",
+	"X-Hub-Signature",
+	"X-Hub-Signature-256",
+	// GitLab
+	"X-Gitlab-Event",
+	"X-Gitlab-Token",
+	// Bitbucket Server (which also uses X-Hub-Signature)
+	"X-Event-Key",
+	"X-Request-Id",
+}
+
+// Webhook forwards the webhook request r, which was sent by the code host of
+// the external service with the given ID, to repo-updater. The caller must
+// close the response body.
+func (c *Client) Webhook(ctx context.Context, externalServiceID int64, r *http.Request) (*http.Response, error) {
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/webhooks/%d", c.URL, externalServiceID), r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Code hosts send the event type and signature in headers. Other headers
+	// (such as Cookie and Authorization) are not forwarded.
+	for _, k := range webhookHeaders {
+		if v, ok := r.Header[k]; ok {
+			req.Header[k] = v
+		}
+	}
+	req = req.WithContext(ctx)
+
+	if c.HTTPClient != nil {
+		return c.HTTPClient.Do(req)
+	}
+	return http.DefaultClient.Do(req)
+}
+
 func (c *Client) httpPost(ctx context.Context, method string, payload interface{}) (resp *http.Response, err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "Client.httpPost")
 	defer func() {
@@ -35,6 +35,25 @@ type SearchArgs struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
+	// Kinds, if non-empty, restricts the results to symbols whose kind
+	// (eg "function" or "class") is one of Kinds. Kinds are compared case
+	// insensitively.
+	Kinds []string
+
+	// ExcludeKinds excludes symbols whose kind is one of ExcludeKinds from
+	// the results. Kinds are compared case insensitively.
+	ExcludeKinds []string
+
+	// Languages, if non-empty, restricts the results to symbols whose
+	// language (eg "Go") is one of Languages. Languages are compared case
+	// insensitively.
+	Languages []string
+
+	// ParentQuery is an optional regex that the name of the symbol's parent
+	// (eg the type a method is defined on) needs to match. It respects
+	// IsCaseSensitive like Query.
+	ParentQuery string
+
 	// First indicates that only the first n symbols should be returned.
 	First int
 }
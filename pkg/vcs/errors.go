@@ -11,6 +11,10 @@ type RepoNotExistError struct {
 
 	// CloneProgress is a progress message from the running clone command.
 	CloneProgress string
+
+	// TooLarge reports whether the repository is not cloned because it is
+	// larger than its size cap.
+	TooLarge bool
 }
 
 func (RepoNotExistError) NotFound() bool { return true }
@@ -19,6 +23,9 @@ func (e *RepoNotExistError) Error() string {
 	if e.CloneInProgress {
 		return "repository does not exist (clone in progress): " + string(e.Repo)
 	}
+	if e.TooLarge {
+		return "repository not cloned (too large): " + string(e.Repo)
+	}
 	return "repository does not exist: " + string(e.Repo)
 }
 
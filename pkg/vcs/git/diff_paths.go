@@ -0,0 +1,73 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+)
+
+// Changes are the paths which differ between two commits. Renames are
+// reported as a deletion of the old path and an addition of the new path.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+}
+
+// DiffPaths returns the paths which changed between commits a and b.
+func DiffPaths(ctx context.Context, repo gitserver.Repo, a, b api.CommitID) (*Changes, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Git: DiffPaths")
+	span.SetTag("A", a)
+	span.SetTag("B", b)
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(string(a)); err != nil {
+		return nil, err
+	}
+	if err := checkSpecArgSafety(string(b)); err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "diff", "--name-status", "--no-renames", "-z", string(a), string(b), "--")
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+	return parseDiffNameStatus(out)
+}
+
+// parseDiffNameStatus parses the output of git diff --name-status -z
+// --no-renames.
+func parseDiffNameStatus(out []byte) (*Changes, error) {
+	var changes Changes
+	if len(out) == 0 {
+		return &changes, nil
+	}
+	fields := bytes.Split(bytes.TrimSuffix(out, []byte{0}), []byte{0})
+	if len(fields)%2 != 0 {
+		return nil, errors.Errorf("unexpected git diff --name-status output: %q", out)
+	}
+	for i := 0; i < len(fields); i += 2 {
+		status, path := fields[i], string(fields[i+1])
+		if len(status) == 0 {
+			return nil, errors.Errorf("unexpected git diff --name-status output: %q", out)
+		}
+		switch status[0] {
+		case 'A':
+			changes.Added = append(changes.Added, path)
+		case 'D':
+			changes.Deleted = append(changes.Deleted, path)
+		case 'M', 'T':
+			changes.Modified = append(changes.Modified, path)
+		default:
+			return nil, errors.Errorf("unexpected git diff --name-status status %q for %q", status, path)
+		}
+	}
+	return &changes, nil
+}
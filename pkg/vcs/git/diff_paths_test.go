@@ -0,0 +1,79 @@
+package git_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
+)
+
+func TestDiffPaths(t *testing.T) {
+	t.Parallel()
+
+	cmds := []string{
+		"echo line1 > f",
+		"echo line1 > g",
+		"echo line1 > h",
+		"git add f g h",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git tag base",
+		"echo line2 >> f",
+		"git rm g",
+		"git mv h i",
+		"echo line1 > 'j k'",
+		"git add f 'j k'",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	}
+	repo := makeGitRepository(t, cmds...)
+
+	a, err := git.ResolveRevision(ctx, repo, nil, "base", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := git.ResolveRevision(ctx, repo, nil, "HEAD", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := git.DiffPaths(ctx, repo, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &git.Changes{
+		Added:    []string{"i", "j k"},
+		Modified: []string{"f"},
+		Deleted:  []string{"g", "h"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+
+	changes, err = git.DiffPaths(ctx, repo, b, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changes, &git.Changes{}) {
+		t.Errorf("got %+v, want no changes", changes)
+	}
+}
+
+func TestDiffPaths_invalidRevision(t *testing.T) {
+	t.Parallel()
+
+	repo := makeGitRepository(t, "GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z")
+	head, err := git.ResolveRevision(ctx, repo, nil, "HEAD", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][2]api.CommitID{
+		{"--output=/tmp/diff", head},
+		{head, "--output=/tmp/diff"},
+	} {
+		if _, err := git.DiffPaths(ctx, repo, args[0], args[1]); err == nil || !strings.Contains(err.Error(), "invalid git revision spec") {
+			t.Errorf("DiffPaths(%q, %q): got error %v, want invalid git revision spec error", args[0], args[1], err)
+		}
+	}
+}
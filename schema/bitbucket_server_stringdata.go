@@ -58,6 +58,11 @@ const BitbucketServerSchemaJSON = `{
       "default": "http",
       "examples": ["ssh"]
     },
+    "webhookSecret": {
+      "description": "The secret of the webhooks sent by this Bitbucket Server instance to Sourcegraph. When set, Sourcegraph fetches repositories as soon as they are pushed to, and picks up renamed repositories without waiting for the next sync. Configure a webhook for \"Repository: Push\" and \"Repository: Modified\" events with the URL https://sourcegraph.example.com/.api/webhooks/ID, where ID is the ID of this external service, and this secret (requires Bitbucket Server 5.4 or newer).",
+      "type": "string",
+      "minLength": 1
+    },
     "certificate": {
       "description": "TLS certificate of the Bitbucket Server instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `",
       "type": "string",
@@ -127,11 +132,73 @@ const BitbucketServerSchemaJSON = `{
         [{ "name": "myproject/myrepo" }, { "name": "myproject/myotherrepo" }, { "pattern": "^topsecretproject/.*" }]
       ]
     },
+    "authorization": {
+      "title": "BitbucketServerAuthorization",
+      "description": "If non-null, enforces Bitbucket Server repository permissions.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["identityProvider", "oauth"],
+      "properties": {
+        "identityProvider": {
+          "title": "BitbucketServerIdentityProvider",
+          "description": "The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server identity to use for a given Sourcegraph user.",
+          "type": "object",
+          "required": ["type"],
+          "properties": {
+            "type": {
+              "type": "string",
+              "enum": ["username"]
+            }
+          },
+          "oneOf": [{ "$ref": "#/definitions/BitbucketServerUsernameIdentity" }],
+          "!go": {
+            "taggedUnionType": true
+          }
+        },
+        "oauth": {
+          "title": "BitbucketServerOAuth",
+          "description": "OAuth configuration of an application link (with incoming authentication and user impersonation enabled) on the Bitbucket Server instance, used to query repository permissions on behalf of Sourcegraph users.",
+          "type": "object",
+          "additionalProperties": false,
+          "required": ["consumerKey", "signingKey"],
+          "properties": {
+            "consumerKey": {
+              "description": "The OAuth consumer key specified when creating the application link in Bitbucket Server.",
+              "type": "string",
+              "minLength": 1
+            },
+            "signingKey": {
+              "description": "Base64 encoding of the PEM-encoded RSA private key whose public key was specified when creating the application link in Bitbucket Server.",
+              "type": "string",
+              "minLength": 1
+            }
+          }
+        },
+        "ttl": {
+          "description": "The TTL of how long to cache permissions data. This is 3 hours by default.\n\nDecreasing the TTL will increase the load on the code host API. If you have X repositories on your instance, it will take ~X/1000 API requests to fetch the complete list for 1 user. If you have Y users, you will incur X*Y/1000 API requests per cache refresh period.\n\nIf set to zero, Sourcegraph will sync a user's entire accessible repository list on every request (NOT recommended).",
+          "type": "string",
+          "default": "3h"
+        }
+      }
+    },
     "initialRepositoryEnablement": {
       "description": "Defines whether repositories from this Bitbucket Server instance should be enabled and cloned when they are first seen by Sourcegraph. If false, the site admin must explicitly enable Bitbucket Server repositories (in the site admin area) to clone them and make them searchable on Sourcegraph. If true, they will be enabled and cloned immediately (subject to rate limiting by Bitbucket Server); site admins can still disable them explicitly, and they'll remain disabled.",
       "type": "boolean",
       "default": false
     }
+  },
+  "definitions": {
+    "BitbucketServerUsernameIdentity": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["type"],
+      "properties": {
+        "type": {
+          "type": "string",
+          "const": "username"
+        }
+      }
+    }
   }
 }
 `
@@ -35,6 +35,11 @@ const GitHubSchemaJSON = `{
       "type": "string",
       "minLength": 1
     },
+    "webhookSecret": {
+      "description": "The secret of the webhooks sent by this GitHub instance to Sourcegraph. When set, Sourcegraph fetches repositories as soon as they are pushed to, and picks up created, deleted and renamed repositories without waiting for the next sync. Configure a webhook for \"push\" and \"repository\" events with the payload URL https://sourcegraph.example.com/.api/webhooks/ID, where ID is the ID of this external service, content type \"application/json\" and this secret.",
+      "type": "string",
+      "minLength": 1
+    },
     "certificate": {
       "description": "TLS certificate of the GitHub Enterprise instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `",
       "type": "string",
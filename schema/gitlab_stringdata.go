@@ -35,6 +35,11 @@ const GitLabSchemaJSON = `{
       "enum": ["http", "ssh"],
       "default": "http"
     },
+    "webhookSecret": {
+      "description": "The secret token of the webhooks sent by this GitLab instance to Sourcegraph. When set, Sourcegraph fetches repositories as soon as they are pushed to. Configure a project or group webhook for push events, or a system hook to also pick up created, deleted and renamed projects, with the URL https://sourcegraph.example.com/.api/webhooks/ID, where ID is the ID of this external service, and this secret token.",
+      "type": "string",
+      "minLength": 1
+    },
     "certificate": {
       "description": "TLS certificate of the GitLab instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `",
       "type": "string",
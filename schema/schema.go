@@ -91,8 +91,16 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
 }
 
+// BitbucketServerAuthorization description: If non-null, enforces Bitbucket Server repository permissions.
+type BitbucketServerAuthorization struct {
+	IdentityProvider BitbucketServerIdentityProvider `json:"identityProvider"`
+	Oauth            BitbucketServerOAuth            `json:"oauth"`
+	Ttl              string                          `json:"ttl,omitempty"`
+}
+
 // BitbucketServerConnection description: Configuration for a connection to Bitbucket Server.
 type BitbucketServerConnection struct {
+	Authorization               *BitbucketServerAuthorization  `json:"authorization,omitempty"`
 	Certificate                 string                         `json:"certificate,omitempty"`
 	Exclude                     []*ExcludedBitbucketServerRepo `json:"exclude,omitempty"`
 	ExcludePersonalRepositories bool                           `json:"excludePersonalRepositories,omitempty"`
@@ -105,6 +113,41 @@ type BitbucketServerConnection struct {
 	Token                       string                         `json:"token,omitempty"`
 	Url                         string                         `json:"url"`
 	Username                    string                         `json:"username"`
+	WebhookSecret               string                         `json:"webhookSecret,omitempty"`
+}
+
+// BitbucketServerIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server identity to use for a given Sourcegraph user.
+type BitbucketServerIdentityProvider struct {
+	Username *BitbucketServerUsernameIdentity
+}
+
+func (v BitbucketServerIdentityProvider) MarshalJSON() ([]byte, error) {
+	if v.Username != nil {
+		return json.Marshal(v.Username)
+	}
+	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
+}
+func (v *BitbucketServerIdentityProvider) UnmarshalJSON(data []byte) error {
+	var d struct {
+		DiscriminantProperty string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	switch d.DiscriminantProperty {
+	case "username":
+		return json.Unmarshal(data, &v.Username)
+	}
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"username"})
+}
+
+// BitbucketServerOAuth description: OAuth configuration of an application link (with incoming authentication and user impersonation enabled) on the Bitbucket Server instance, used to query repository permissions on behalf of Sourcegraph users.
+type BitbucketServerOAuth struct {
+	ConsumerKey string `json:"consumerKey"`
+	SigningKey  string `json:"signingKey"`
+}
+type BitbucketServerUsernameIdentity struct {
+	Type string `json:"type"`
 }
 type BrandAssets struct {
 	Logo   string `json:"logo,omitempty"`
@@ -175,6 +218,11 @@ type ExcludedGitLabProject struct {
 	Id   int    `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	Id      int    `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	Name string `json:"name,omitempty"`
 }
@@ -186,9 +234,13 @@ type ExperimentalFeatures struct {
 
 // Extensions description: Configures Sourcegraph extensions.
 type Extensions struct {
-	AllowRemoteExtensions []string    `json:"allowRemoteExtensions,omitempty"`
-	Disabled              *bool       `json:"disabled,omitempty"`
-	RemoteRegistry        interface{} `json:"remoteRegistry,omitempty"`
+	AllowRemoteExtensions   []string          `json:"allowRemoteExtensions,omitempty"`
+	BetaExtensions          []string          `json:"betaExtensions,omitempty"`
+	Disabled                *bool             `json:"disabled,omitempty"`
+	PinnedVersions          map[string]string `json:"pinnedVersions,omitempty"`
+	RemoteRegistry          interface{}       `json:"remoteRegistry,omitempty"`
+	RequireSignedExtensions bool              `json:"requireSignedExtensions,omitempty"`
+	TrustedPublisherKeys    []string          `json:"trustedPublisherKeys,omitempty"`
 }
 type ExternalIdentity struct {
 	AuthProviderID   string `json:"authProviderID"`
@@ -224,6 +276,7 @@ type GitHubConnection struct {
 	RepositoryQuery             []string              `json:"repositoryQuery"`
 	Token                       string                `json:"token"`
 	Url                         string                `json:"url"`
+	WebhookSecret               string                `json:"webhookSecret,omitempty"`
 }
 
 // GitLabAuthProvider description: Configures the GitLab OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitLab instance: https://docs.gitlab.com/ee/integration/oauth_provider.html. The application should have `api` and `read_user` scopes and the callback URL set to the concatenation of your Sourcegraph instance URL and "/.auth/gitlab/callback".
@@ -253,12 +306,25 @@ type GitLabConnection struct {
 	RepositoryPathPattern       string                   `json:"repositoryPathPattern,omitempty"`
 	Token                       string                   `json:"token"`
 	Url                         string                   `json:"url"`
+	WebhookSecret               string                   `json:"webhookSecret,omitempty"`
 }
 type GitLabProject struct {
 	Id   int    `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
 }
 
+// GiteaConnection description: Configuration for a connection to Gitea (or another code host with a Gitea or Gogs compatible API).
+type GiteaConnection struct {
+	Certificate           string               `json:"certificate,omitempty"`
+	Exclude               []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	GitURLType            string               `json:"gitURLType,omitempty"`
+	Repos                 []string             `json:"repos,omitempty"`
+	RepositoryPathPattern string               `json:"repositoryPathPattern,omitempty"`
+	RepositoryQuery       []string             `json:"repositoryQuery"`
+	Token                 string               `json:"token"`
+	Url                   string               `json:"url"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
 	Blacklist                  string                  `json:"blacklist,omitempty"`
@@ -356,6 +422,22 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PermissionsBackgroundSync description: Syncs repository permissions from authorization providers (such as GitHub and GitLab) into the database in the background. When enabled, repository permissions checks use the synced permissions instead of querying the authorization providers on each request, which is faster and avoids code host API rate limits for users with access to many repositories.
+//
+// Only available in Sourcegraph Enterprise.
+type PermissionsBackgroundSync struct {
+	Enabled  bool `json:"enabled,omitempty"`
+	Interval int  `json:"interval,omitempty"`
+}
+
+// PermissionsExplicit description: Enforces repository permissions that site admins explicitly grant to users and organizations (with the GraphQL API or the bulk import endpoint), for repositories whose code host has no authorization provider (such as Gitolite and other Git hosts). Users who are not site admins may only access such repositories if they (or an organization they are a member of) are granted read access to the repository or to a matching repository name pattern.
+//
+// Only available in Sourcegraph Enterprise.
+type PermissionsExplicit struct {
+	Enabled      bool     `json:"enabled,omitempty"`
+	ServiceTypes []string `json:"serviceTypes,omitempty"`
+}
+
 // Phabricator description: Phabricator instance that integrates with this Gitolite instance
 type Phabricator struct {
 	CallsignCommand string `json:"callsignCommand"`
@@ -453,6 +535,8 @@ type SiteConfiguration struct {
 	GithubClientSecret                string                      `json:"githubClientSecret,omitempty"`
 	MaxReposToSearch                  int                         `json:"maxReposToSearch,omitempty"`
 	ParentSourcegraph                 *ParentSourcegraph          `json:"parentSourcegraph,omitempty"`
+	PermissionsBackgroundSync         *PermissionsBackgroundSync  `json:"permissions.backgroundSync,omitempty"`
+	PermissionsExplicit               *PermissionsExplicit        `json:"permissions.explicit,omitempty"`
 	RepoListUpdateInterval            int                         `json:"repoListUpdateInterval,omitempty"`
 	SearchIndexEnabled                *bool                       `json:"search.index.enabled,omitempty"`
 	SearchLargeFiles                  []string                    `json:"search.largeFiles,omitempty"`
@@ -156,6 +156,49 @@ const SiteSchemaJSON = `{
       ],
       "group": "Security"
     },
+    "permissions.backgroundSync": {
+      "description": "Syncs repository permissions from authorization providers (such as GitHub and GitLab) into the database in the background. When enabled, repository permissions checks use the synced permissions instead of querying the authorization providers on each request, which is faster and avoids code host API rate limits for users with access to many repositories.\n\nOnly available in Sourcegraph Enterprise.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether to sync repository permissions in the background.",
+          "type": "boolean",
+          "default": false
+        },
+        "interval": {
+          "description": "Interval (in minutes) between syncs of each user's repository permissions. A user's permissions are also synced when the user signs in.",
+          "type": "integer",
+          "minimum": 1,
+          "default": 60
+        }
+      },
+      "examples": [{ "enabled": true, "interval": 180 }],
+      "group": "Security"
+    },
+    "permissions.explicit": {
+      "description": "Enforces repository permissions that site admins explicitly grant to users and organizations (with the GraphQL API or the bulk import endpoint), for repositories whose code host has no authorization provider (such as Gitolite and other Git hosts). Users who are not site admins may only access such repositories if they (or an organization they are a member of) are granted read access to the repository or to a matching repository name pattern.\n\nOnly available in Sourcegraph Enterprise.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether to enforce explicitly granted repository permissions.",
+          "type": "boolean",
+          "default": false
+        },
+        "serviceTypes": {
+          "description": "The external service types (such as \"gitolite\" and \"other\") of the repositories whose permissions are explicitly granted. Repositories of other service types are not affected.",
+          "type": "array",
+          "items": {
+            "type": "string",
+            "minLength": 1
+          },
+          "default": ["gitolite", "other"]
+        }
+      },
+      "examples": [{ "enabled": true, "serviceTypes": ["gitolite", "other"] }],
+      "group": "Security"
+    },
     "branding": {
       "description": "Customize Sourcegraph homepage logo and search icon.\n\nOnly available in Sourcegraph Enterprise.",
       "type": "object",
@@ -295,6 +338,32 @@ const SiteSchemaJSON = `{
           "items": {
             "type": "string"
           }
+        },
+        "pinnedVersions": {
+          "description": "Pins remote extensions (by extension ID, such as \"alice/myextension\") to a semantic version range, such as \"^1.2.0\" or \">=1.0.0 <2.0.0\". The newest release of the extension in the range is used. Extensions that are not listed use their latest release.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "betaExtensions": {
+          "description": "Use the beta release channel for the listed remote extensions (by extension ID, such as \"alice/myextension\"). The beta channel includes prereleases as well as stable releases. Other extensions use the stable release channel.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "requireSignedExtensions": {
+          "description": "Require extension releases to be signed by one of the keys in ` + "`" + `trustedPublisherKeys` + "`" + `. Unsigned releases (and releases signed by other keys) can't be published to this site's extension registry, and such remote extensions are not used.\n\nOnly available in Sourcegraph Enterprise.",
+          "type": "boolean",
+          "default": false
+        },
+        "trustedPublisherKeys": {
+          "description": "The SSH public keys (in authorized_keys format, such as \"ssh-ed25519 AAAA... alice@example.com\") of trusted extension publishers. Extension releases signed by these keys are reported as trusted.\n\nOnly available in Sourcegraph Enterprise.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
         }
       },
       "default": {
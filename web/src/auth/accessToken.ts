@@ -4,4 +4,8 @@
 export enum AccessTokenScopes {
     UserAll = 'user:all',
     SiteAdminSudo = 'site-admin:sudo',
+    SearchRead = 'search:read',
+    RepoRead = 'repo:read',
+    SettingsWrite = 'settings:write',
+    DiscussionsWrite = 'discussions:write',
 }
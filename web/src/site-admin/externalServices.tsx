@@ -10,6 +10,7 @@ import React from 'react'
 import { Link } from 'react-router-dom'
 import awsCodeCommitSchemaJSON from '../../../schema/aws_codecommit.schema.json'
 import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.json'
+import giteaSchemaJSON from '../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
@@ -585,6 +586,52 @@ export const ALL_EXTERNAL_SERVICES: Record<GQL.ExternalServiceKind, ExternalServ
             },
         ],
     },
+    [GQL.ExternalServiceKind.GITEA]: {
+        title: 'Gitea repositories',
+        icon: <GitIcon size={ICON_SIZE} />,
+        iconBrandColor: 'git',
+        shortDescription: 'Add Gitea (or Gogs) repositories.',
+        jsonSchema: giteaSchemaJSON,
+        defaultDisplayName: 'Gitea',
+        defaultConfig: `{
+  // Use Ctrl+Space for completion, and hover over JSON properties for documentation.
+  // Configuration options are documented here:
+  // https://docs.sourcegraph.com/admin/external_service/gitea#configuration
+
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "repositoryQuery": ["affiliated"]
+}`,
+        editorActions: [
+            {
+                id: 'setAccessToken',
+                label: 'Set access token',
+                run: config => {
+                    const value = '<access token>'
+                    const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                    return { edits, selectText: value }
+                },
+            },
+            {
+                id: 'addRepo',
+                label: 'Add a repository',
+                run: config => {
+                    const value = '<owner>/<repository>'
+                    const edits = setProperty(config, ['repos', -1], value, defaultFormattingOptions)
+                    return { edits, selectText: value }
+                },
+            },
+            {
+                id: 'excludeRepo',
+                label: 'Exclude a repository',
+                run: config => {
+                    const value = { name: '<owner>/<repository>' }
+                    const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                    return { edits, selectText: '{"name": "<owner>/<repository>"}' }
+                },
+            },
+        ],
+    },
     [GQL.ExternalServiceKind.GITOLITE]: {
         title: 'Gitolite repositories',
         icon: <GitIcon size={ICON_SIZE} />,